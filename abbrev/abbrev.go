@@ -0,0 +1,198 @@
+// Package abbrev abbreviates sign text so it fits a line, using a dictionary
+// of abbreviations such as the MUTCD-style Default one, optionally merged
+// with an agency's own pack loaded from JSON.
+//
+// A pack is a JSON object mapping words or phrases to their abbreviation:
+//
+//	{"INTERCHANGE": "INTCHG", "EXPRESSWAY": "EXPY", "TRAFFIC": ""}
+//
+// An empty abbreviation removes the word from the dictionary it is merged
+// into. Matching ignores case; abbreviations are used as written.
+package abbrev
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Dictionary maps words or phrases, in upper case, to their abbreviation.
+type Dictionary map[string]string
+
+// Default returns a new dictionary of the abbreviations acceptable on
+// traffic signs and changeable message signs (MUTCD Tables 1A-1 and 1A-2).
+func Default() Dictionary {
+	return Dictionary{
+		"ALTERNATE":       "ALT",
+		"AVENUE":          "AVE",
+		"BOULEVARD":       "BLVD",
+		"BRIDGE":          "BR",
+		"CENTER":          "CNTR",
+		"CIRCLE":          "CIR",
+		"CONDITION":       "COND",
+		"CONSTRUCTION":    "CONST",
+		"COURT":           "CT",
+		"CROSSING":        "XING",
+		"DRIVE":           "DR",
+		"EASTBOUND":       "EB",
+		"EMERGENCY":       "EMER",
+		"ENTRANCE":        "ENT",
+		"EXPRESSWAY":      "EXPWY",
+		"FREEWAY":         "FRWY",
+		"HAZARDOUS":       "HAZ",
+		"HIGHWAY":         "HWY",
+		"INFORMATION":     "INFO",
+		"JUNCTION":        "JCT",
+		"LANE":            "LN",
+		"LEFT":            "LFT",
+		"MAINTENANCE":     "MAINT",
+		"MAXIMUM":         "MAX",
+		"MILES":           "MI",
+		"MILES PER HOUR":  "MPH",
+		"MINIMUM":         "MIN",
+		"MINUTES":         "MIN",
+		"NORTHBOUND":      "NB",
+		"PARKING":         "PKING",
+		"PARKWAY":         "PKWY",
+		"PEDESTRIAN":      "PED",
+		"PLACE":           "PL",
+		"PREPARE":         "PREP",
+		"RIGHT":           "RT",
+		"ROAD":            "RD",
+		"ROUTE":           "RTE",
+		"SERVICE":         "SERV",
+		"SHOULDER":        "SHLDR",
+		"SOUTHBOUND":      "SB",
+		"SPEED":           "SPD",
+		"STREET":          "ST",
+		"TEMPORARY":       "TEMP",
+		"TRAFFIC":         "TRAF",
+		"TURNPIKE":        "TPK",
+		"VEHICLES":        "VEH",
+		"WARNING":         "WARN",
+		"WESTBOUND":       "WB",
+		"INTERSECTION":    "INTERSECT",
+		"ACCIDENT":        "ACCDNT",
+		"BICYCLE":         "BIKE",
+		"FRONTAGE":        "FRNTG",
+		"MILE":            "MI",
+		"MINUTE":          "MIN",
+		"VEHICLE":         "VEH",
+		"HOUR":            "HR",
+		"HOURS":           "HRS",
+		"LANES":           "LNS",
+		"NORTH":           "N",
+		"SOUTH":           "S",
+		"EAST":            "E",
+		"WEST":            "W",
+		"CLOSED AHEAD":    "CLOSED AHD",
+		"RIGHT LANE":      "RT LN",
+		"LEFT LANE":       "LFT LN",
+		"ROAD WORK AHEAD": "RD WORK AHD",
+	}
+}
+
+// Load reads a pack from r.
+func Load(r io.Reader) (Dictionary, error) {
+	var pack map[string]string
+	if err := json.NewDecoder(r).Decode(&pack); err != nil {
+		return nil, fmt.Errorf("abbrev: %v", err)
+	}
+	dictionary := Dictionary{}
+	for phrase, abbreviation := range pack {
+		phrase = normalize(phrase)
+		if phrase == "" {
+			return nil, fmt.Errorf("abbrev: empty phrase for %q", abbreviation)
+		}
+		dictionary[phrase] = abbreviation
+	}
+	return dictionary, nil
+}
+
+// Merge returns a new dictionary with the entries of d overridden by those of
+// pack; entries of pack with an empty abbreviation are removed.
+func (d Dictionary) Merge(pack Dictionary) Dictionary {
+	merged := Dictionary{}
+	for phrase, abbreviation := range d {
+		merged[phrase] = abbreviation
+	}
+	for phrase, abbreviation := range pack {
+		if abbreviation == "" {
+			delete(merged, normalize(phrase))
+		} else {
+			merged[normalize(phrase)] = abbreviation
+		}
+	}
+	return merged
+}
+
+// Substitution is an abbreviation applied by Abbreviate.
+type Substitution struct {
+	Phrase       string // the words replaced, as written in the text
+	Abbreviation string
+}
+
+// Abbreviate shortens text, plain words separated by spaces, until it is at
+// most maxLength characters long or as short as the dictionary allows. The
+// abbreviation saving the most characters is applied first, longer phrases
+// winning ties, so text is changed no more than needed; a maxLength of 0
+// applies every abbreviation. It returns the text and the substitutions made, and can be
+// used to preview how a phrase will be abbreviated.
+func (d Dictionary) Abbreviate(text string, maxLength int) (string, []Substitution) {
+	words := strings.Fields(text)
+	var substitutions []Substitution
+	for maxLength == 0 || length(words) > maxLength {
+		start, end, abbreviation, saving := d.best(words)
+		if saving <= 0 {
+			break
+		}
+		substitutions = append(substitutions, Substitution{Phrase: strings.Join(words[start:end], " "), Abbreviation: abbreviation})
+		words = append(words[:start:start], append(strings.Fields(abbreviation), words[end:]...)...)
+	}
+	return strings.Join(words, " "), substitutions
+}
+
+// best finds the abbreviation that saves the most characters, preferring
+// longer phrases and earlier positions.
+func (d Dictionary) best(words []string) (start, end int, abbreviation string, saving int) {
+	phraseLengths := map[int]bool{}
+	for phrase := range d {
+		phraseLengths[len(strings.Fields(phrase))] = true
+	}
+	var lengths []int
+	for n := range phraseLengths {
+		lengths = append(lengths, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+
+	for _, n := range lengths {
+		for i := 0; i+n <= len(words); i++ {
+			phrase := strings.Join(words[i:i+n], " ")
+			candidate, ok := d[normalize(phrase)]
+			if !ok {
+				continue
+			}
+			if s := len(phrase) - len(candidate); s > saving {
+				start, end, abbreviation, saving = i, i+n, candidate, s
+			}
+		}
+	}
+	return start, end, abbreviation, saving
+}
+
+func normalize(phrase string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(phrase), " "))
+}
+
+func length(words []string) int {
+	n := len(words) - 1
+	for _, word := range words {
+		n += len(word)
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package abbrev
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAbbreviate(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maxLength int
+		want      string
+		wantSubs  []Substitution
+	}{
+		{name: "fits", text: "Right lane closed", maxLength: 20, want: "Right lane closed"},
+		{
+			name: "longest saving first", text: "Northbound right lane closed", maxLength: 22,
+			want:     "NB right lane closed",
+			wantSubs: []Substitution{{Phrase: "Northbound", Abbreviation: "NB"}},
+		},
+		{
+			name: "phrase", text: "Road work ahead", maxLength: 12,
+			want:     "RD WORK AHD",
+			wantSubs: []Substitution{{Phrase: "Road work ahead", Abbreviation: "RD WORK AHD"}},
+		},
+		{
+			name: "all", text: "EASTBOUND TRAFFIC", maxLength: 0,
+			want: "EB TRAF",
+			wantSubs: []Substitution{
+				{Phrase: "EASTBOUND", Abbreviation: "EB"},
+				{Phrase: "TRAFFIC", Abbreviation: "TRAF"},
+			},
+		},
+		{name: "too long", text: "ZZZZZZ", maxLength: 3, want: "ZZZZZZ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, subs := Default().Abbreviate(tt.text, tt.maxLength)
+			if got != tt.want || !reflect.DeepEqual(subs, tt.wantSubs) {
+				t.Errorf("Abbreviate() = %q, %+v, want %q, %+v", got, subs, tt.want, tt.wantSubs)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	pack, err := Load(strings.NewReader(`{"interchange": "INTCHG", "traffic": ""}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dictionary := Default().Merge(pack)
+	got, _ := dictionary.Abbreviate("TRAFFIC AT INTERCHANGE", 0)
+	if got != "TRAFFIC AT INTCHG" {
+		t.Errorf("Abbreviate() with the pack = %q", got)
+	}
+	if _, ok := Default()["TRAFFIC"]; !ok {
+		t.Error("Merge() modified the default dictionary")
+	}
+
+	if _, err := Load(strings.NewReader(`["not", "a", "pack"]`)); err == nil {
+		t.Error("Load() accepted a JSON array")
+	}
+}
@@ -0,0 +1,180 @@
+// Package bdf reads bitmap fonts in the Glyph Bitmap Distribution Format
+// (BDF 2.1) and converts them into NTCIP 1203 fonts, ready to be downloaded
+// with the ConfiguringFont dialog.
+//
+// The font height is FONT_ASCENT plus FONT_DESCENT, or the height of the
+// FONTBOUNDINGBOX if those properties are missing. Every glyph is placed on
+// the baseline of a cell of that height; the character width is the glyph's
+// DWIDTH, so the spacing built into the glyphs is kept and the font's
+// character spacing is 0. Glyphs are numbered by their ENCODING; glyphs
+// without an encoding are skipped.
+package bdf
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jacobleehei/godms/crc"
+	"github.com/jacobleehei/godms/dialogs"
+)
+
+// maxNameLength is the size of fontName.
+const maxNameLength = 64
+
+type glyph struct {
+	line     int
+	encoding int
+	width    int // DWIDTH
+	box      [4]int
+	rows     [][]byte
+}
+
+// Read reads a BDF font from r. The Number of the returned font is 0; set it
+// to the font number the MULTI [fo] tags should refer to.
+func Read(r io.Reader) (dialogs.Font, error) {
+	var (
+		font             dialogs.Font
+		box              [4]int
+		ascent, descent  = -1, -1
+		glyphs           []glyph
+		current          *glyph
+		bitmap, seenFont bool
+	)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if bitmap && fields[0] != "ENDCHAR" {
+			row, err := hex.DecodeString(fields[0])
+			if err != nil {
+				return font, fmt.Errorf("bdf: line %d: invalid bitmap row %q", line, fields[0])
+			}
+			current.rows = append(current.rows, row)
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "STARTFONT":
+			seenFont = true
+		case "FONT":
+			font.Name = strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "FONT"))
+		case "FAMILY_NAME":
+			font.Name = strings.Trim(strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "FAMILY_NAME")), `"`)
+		case "FONTBOUNDINGBOX":
+			err = ints(fields[1:], &box[0], &box[1], &box[2], &box[3])
+		case "FONT_ASCENT":
+			err = ints(fields[1:], &ascent)
+		case "FONT_DESCENT":
+			err = ints(fields[1:], &descent)
+		case "STARTCHAR":
+			glyphs = append(glyphs, glyph{line: line, encoding: -1, box: box})
+			current = &glyphs[len(glyphs)-1]
+		case "ENCODING":
+			if current != nil {
+				err = ints(fields[1:], &current.encoding)
+			}
+		case "DWIDTH":
+			if current != nil {
+				err = ints(fields[1:], &current.width)
+			}
+		case "BBX":
+			if current != nil {
+				err = ints(fields[1:], &current.box[0], &current.box[1], &current.box[2], &current.box[3])
+			}
+		case "BITMAP":
+			if current == nil {
+				return font, fmt.Errorf("bdf: line %d: BITMAP outside a character", line)
+			}
+			bitmap = true
+		case "ENDCHAR":
+			bitmap, current = false, nil
+		}
+		if err != nil {
+			return font, fmt.Errorf("bdf: line %d: %s: %v", line, fields[0], err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return font, err
+	}
+	if !seenFont {
+		return font, fmt.Errorf("bdf: missing STARTFONT")
+	}
+
+	font.Height = box[1]
+	if ascent >= 0 && descent >= 0 {
+		font.Height = ascent + descent
+	} else {
+		ascent = box[1] + box[3]
+	}
+	if font.Height <= 0 || font.Height > 255 {
+		return font, fmt.Errorf("bdf: font height %d out of range 1-255", font.Height)
+	}
+	if len(font.Name) > maxNameLength {
+		font.Name = font.Name[:maxNameLength]
+	}
+
+	for _, g := range glyphs {
+		if g.encoding < 1 || g.encoding > 65535 {
+			continue
+		}
+		if g.width < 0 || g.width > 255 {
+			return font, fmt.Errorf("bdf: line %d: character width %d out of range 0-255", g.line, g.width)
+		}
+		if g.width == 0 {
+			continue
+		}
+		font.Characters = append(font.Characters, crc.Character{
+			Number: g.encoding,
+			Width:  g.width,
+			Bitmap: g.bitmap(font.Height, ascent),
+		})
+	}
+	return font, nil
+}
+
+// bitmap draws the glyph into a width by height cell whose baseline is
+// ascent rows from the top, and packs the cell row by row, without padding
+// between rows, as characterBitmap requires. Pixels outside the cell are
+// dropped.
+func (g glyph) bitmap(height, ascent int) []byte {
+	bits := make([]byte, (g.width*height+7)/8)
+	w, h, xOffset, yOffset := g.box[0], g.box[1], g.box[2], g.box[3]
+	top := ascent - (yOffset + h)
+	for row := 0; row < h && row < len(g.rows); row++ {
+		y := top + row
+		if y < 0 || y >= height {
+			continue
+		}
+		for column := 0; column < w && column/8 < len(g.rows[row]); column++ {
+			x := xOffset + column
+			if x < 0 || x >= g.width || g.rows[row][column/8]&(0x80>>(column%8)) == 0 {
+				continue
+			}
+			bit := y*g.width + x
+			bits[bit/8] |= 0x80 >> (bit % 8)
+		}
+	}
+	return bits
+}
+
+// ints parses the leading fields into targets.
+func ints(fields []string, targets ...*int) error {
+	if len(fields) < len(targets) {
+		return fmt.Errorf("want %d values, got %d", len(targets), len(fields))
+	}
+	for i, target := range targets {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return err
+		}
+		*target = n
+	}
+	return nil
+}
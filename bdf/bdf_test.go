@@ -0,0 +1,98 @@
+package bdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacobleehei/godms/crc"
+	"github.com/jacobleehei/godms/dialogs"
+)
+
+const sample = `STARTFONT 2.1
+FONT -misc-sample-medium-r-normal--4-40-75-75-c-40-iso10646-1
+SIZE 4 75 75
+FONTBOUNDINGBOX 3 4 0 -1
+STARTPROPERTIES 3
+FAMILY_NAME "Sample"
+FONT_ASCENT 3
+FONT_DESCENT 1
+ENDPROPERTIES
+CHARS 3
+STARTCHAR space
+ENCODING 32
+SWIDTH 500 0
+DWIDTH 2 0
+BBX 1 1 0 0
+BITMAP
+00
+ENDCHAR
+STARTCHAR i
+ENCODING 105
+SWIDTH 500 0
+DWIDTH 2 0
+BBX 1 3 0 0
+BITMAP
+80
+00
+80
+ENDCHAR
+STARTCHAR j
+ENCODING 106
+SWIDTH 750 0
+DWIDTH 3 0
+BBX 2 4 0 -1
+BITMAP
+40
+00
+40
+80
+ENDCHAR
+STARTCHAR unencoded
+ENCODING -1
+DWIDTH 3 0
+BBX 1 1 0 0
+BITMAP
+80
+ENDCHAR
+ENDFONT
+`
+
+func TestRead(t *testing.T) {
+	got, err := Read(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := dialogs.Font{
+		Name:   "Sample",
+		Height: 4,
+		Characters: []crc.Character{
+			// 2x4 cells, rows packed without padding.
+			{Number: ' ', Width: 2, Bitmap: []byte{0x00}},
+			{Number: 'i', Width: 2, Bitmap: []byte{0x88}}, // #. .. #. ..
+			// 3x4: .#. ... .#. #..
+			{Number: 'j', Width: 3, Bitmap: []byte{0x41, 0x40}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRead_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "not BDF", input: "hello\n"},
+		{name: "bad bitmap", input: "STARTFONT 2.1\nFONTBOUNDINGBOX 1 1 0 0\nSTARTCHAR a\nENCODING 97\nDWIDTH 1 0\nBBX 1 1 0 0\nBITMAP\nZZ\nENDCHAR\n"},
+		{name: "no height", input: "STARTFONT 2.1\nENDFONT\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(tt.input)); err == nil {
+				t.Error("Read() error = nil")
+			}
+		})
+	}
+}
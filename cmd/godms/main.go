@@ -0,0 +1,131 @@
+// Command godms runs NTCIP 1203 dialogs against a sign from the command line.
+//
+// Usage:
+//
+//	godms selftest [flags] <sign>
+//	godms font [flags] <sign> <font.bdf>
+//
+// selftest checks that the sign can be controlled centrally without changing
+// what it displays, and exits with status 1 if a step fails.
+//
+// font downloads a BDF font into the sign's font table.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/jacobleehei/godms/bdf"
+	"github.com/jacobleehei/godms/dialogs"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "selftest":
+		os.Exit(selftest(os.Args[2:]))
+	case "font":
+		os.Exit(font(os.Args[2:]))
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: godms selftest [flags] <sign>")
+	fmt.Fprintln(os.Stderr, "       godms font [flags] <sign> <font.bdf>")
+	os.Exit(2)
+}
+
+// signFlags adds the flags that address a sign to flags.
+func signFlags(flags *flag.FlagSet) func(target string) *gosnmp.GoSNMP {
+	community := flags.String("community", "public", "SNMP community")
+	port := flags.Uint("port", 161, "SNMP port")
+	version := flags.String("version", "1", "SNMP version, 1 or 2c")
+	timeout := flags.Duration("timeout", 2*time.Second, "SNMP request timeout")
+	return func(target string) *gosnmp.GoSNMP {
+		dms := &gosnmp.GoSNMP{
+			Target:    target,
+			Port:      uint16(*port),
+			Transport: "udp",
+			Community: *community,
+			Version:   gosnmp.Version1,
+			Timeout:   *timeout,
+			MaxOids:   gosnmp.MaxOids,
+		}
+		if *version == "2c" {
+			dms.Version = gosnmp.Version2c
+		}
+		return dms
+	}
+}
+
+func selftest(args []string) int {
+	flags := flag.NewFlagSet("selftest", flag.ExitOnError)
+	sign := signFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+	dms := sign(flags.Arg(0))
+
+	report, err := dialogs.SelfTest(dms)
+	for _, step := range report.Steps {
+		if step.Err != nil {
+			fmt.Printf("FAIL  %s: %v\n", step.Name, step.Err)
+		} else {
+			fmt.Printf("PASS  %s\n", step.Name)
+		}
+	}
+	if err != nil {
+		return 1
+	}
+	fmt.Printf("sign %s can be controlled centrally\n", dms.Target)
+	return 0
+}
+
+func font(args []string) int {
+	flags := flag.NewFlagSet("font", flag.ExitOnError)
+	sign := signFlags(flags)
+	index := flags.Int("index", 0, "fontTable row, 0 for the first unused row")
+	number := flags.Int("number", 0, "font number used by [fo] tags, 0 for the row index")
+	charSpacing := flags.Int("charspacing", 0, "pixels between characters, in addition to the BDF widths")
+	lineSpacing := flags.Int("linespacing", 1, "pixels between lines")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		usage()
+	}
+
+	file, err := os.Open(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	f, err := bdf.Read(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	f.Number, f.CharSpacing, f.LineSpacing = *number, *charSpacing, *lineSpacing
+	if f.Number == 0 {
+		f.Number = *index
+	}
+	if f.Number == 0 {
+		fmt.Fprintln(os.Stderr, "font: -number is required with an unused row")
+		return 2
+	}
+
+	result, err := dialogs.ConfiguringFont(sign(flags.Arg(0)), *index, f)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("font %d %q stored in row %d, fontVersionID %04X\n", f.Number, f.Name, result.FontIndex, result.FontVersionID)
+	return 0
+}
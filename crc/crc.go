@@ -0,0 +1,129 @@
+// Package crc implements the CRC-16 used by NTCIP 1203 for dmsMessageCRC,
+// fontVersionID and dmsGraphicID.
+//
+// The CRC is the frame check sequence of ISO/IEC 3309 (CRC-16/X.25): the
+// reflected CCITT polynomial 0x8408, an initial value of 0xFFFF and a final
+// complement. ISO/IEC 3309 transmits the FCS least significant byte first;
+// the NTCIP values returned here hold the two bytes in that order, read as a
+// big-endian integer, which is how they appear in a MessageIDCode or
+// MessageActivationCode.
+package crc
+
+var table = func() (t [256]uint16) {
+	for i := range t {
+		fcs := uint16(i)
+		for bit := 0; bit < 8; bit++ {
+			if fcs&1 != 0 {
+				fcs = fcs>>1 ^ 0x8408
+			} else {
+				fcs >>= 1
+			}
+		}
+		t[i] = fcs
+	}
+	return t
+}()
+
+// Checksum returns the ISO/IEC 3309 FCS of data.
+func Checksum(data []byte) uint16 {
+	fcs := uint16(0xffff)
+	for _, b := range data {
+		fcs = fcs>>8 ^ table[byte(fcs)^b]
+	}
+	return ^fcs
+}
+
+// ntcip returns fcs with its bytes in transmission order.
+func ntcip(fcs uint16) uint16 { return fcs<<8 | fcs>>8 }
+
+// MessageCRC returns the dmsMessageCRC of a message: the CRC of its MULTI
+// string followed by the dmsMessageBeacon and dmsMessagePixelService values.
+// Use zero for objects the sign does not support.
+func MessageCRC(multiString string, beacon, pixelService int) uint16 {
+	data := append([]byte(multiString), byte(beacon), byte(pixelService))
+	return ntcip(Checksum(data))
+}
+
+// Character is a character of a font, as stored in the characterTable.
+type Character struct {
+	Number int    // characterNumber
+	Width  int    // characterWidth in pixels, zero if the character is not defined
+	Bitmap []byte // characterBitmap
+}
+
+// FontVersionID returns the fontVersionID of a font: the CRC of the OER
+// encoding of its font number, height, character spacing, line spacing and
+// the defined characters (non-zero width), in the order given.
+func FontVersionID(fontNumber, fontHeight, charSpacing, lineSpacing int, characters []Character) uint16 {
+	data := []byte{byte(fontNumber), byte(fontHeight), byte(charSpacing), byte(lineSpacing)}
+	var defined []Character
+	for _, character := range characters {
+		if character.Width > 0 {
+			defined = append(defined, character)
+		}
+	}
+	data = appendQuantity(data, len(defined))
+	for _, character := range defined {
+		data = append(data, byte(character.Number>>8), byte(character.Number))
+		data = append(data, byte(character.Width))
+		data = appendOctetString(data, character.Bitmap)
+	}
+	return ntcip(Checksum(data))
+}
+
+// Graphic is a graphic definition of the dmsGraphicTable.
+type Graphic struct {
+	Number             int    // dmsGraphicNumber
+	Height             int    // dmsGraphicHeight in pixels
+	Width              int    // dmsGraphicWidth in pixels
+	Type               int    // dmsGraphicType, e.g. 1 (monochrome1bit)
+	TransparentEnabled bool   // dmsGraphicTransparentEnabled
+	TransparentColor   []byte // dmsGraphicTransparentColor
+	Bitmap             []byte // the dmsGraphicBlockBitmap blocks, concatenated
+}
+
+// GraphicID returns the dmsGraphicID of a graphic: the CRC of the OER
+// encoding of its number, height, width, type, transparency settings and
+// bitmap.
+func GraphicID(graphic Graphic) uint16 {
+	data := []byte{byte(graphic.Number), byte(graphic.Height)}
+	data = append(data, byte(graphic.Width>>8), byte(graphic.Width))
+	data = append(data, byte(graphic.Type))
+	if graphic.TransparentEnabled {
+		data = append(data, 0xff)
+	} else {
+		data = append(data, 0x00)
+	}
+	data = appendOctetString(data, graphic.TransparentColor)
+	data = appendOctetString(data, graphic.Bitmap)
+	return ntcip(Checksum(data))
+}
+
+// appendLength appends an OER length determinant.
+func appendLength(data []byte, length int) []byte {
+	if length < 0x80 {
+		return append(data, byte(length))
+	}
+	var octets []byte
+	for ; length > 0; length >>= 8 {
+		octets = append([]byte{byte(length)}, octets...)
+	}
+	return append(append(data, 0x80|byte(len(octets))), octets...)
+}
+
+// appendOctetString appends an OER OCTET STRING of variable size.
+func appendOctetString(data, value []byte) []byte {
+	return append(appendLength(data, len(value)), value...)
+}
+
+// appendQuantity appends the OER quantity field of a SEQUENCE OF.
+func appendQuantity(data []byte, quantity int) []byte {
+	var octets []byte
+	for ; quantity > 0; quantity >>= 8 {
+		octets = append([]byte{byte(quantity)}, octets...)
+	}
+	if len(octets) == 0 {
+		octets = []byte{0}
+	}
+	return append(append(data, byte(len(octets))), octets...)
+}
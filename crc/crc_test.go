@@ -0,0 +1,59 @@
+package crc
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	// Check value of CRC-16/X.25.
+	if got := Checksum([]byte("123456789")); got != 0x906E {
+		t.Errorf("Checksum() = %04X, want 906E", got)
+	}
+}
+
+func TestMessageCRC(t *testing.T) {
+	if got := MessageCRC("[jp3]TEST [fl]Flashing[/fl]", 0, 0); got != 0x95F9 {
+		t.Errorf("MessageCRC() = %04X, want 95F9", got)
+	}
+}
+
+func TestFontVersionID(t *testing.T) {
+	characters := []Character{
+		{Number: 65, Width: 5, Bitmap: []byte{0x74, 0x63, 0xf8, 0xc6, 0x20}},
+		{Number: 66, Width: 0},
+	}
+	got := FontVersionID(1, 7, 1, 3, characters)
+	if want := FontVersionID(1, 7, 1, 3, characters[:1]); got != want {
+		t.Errorf("FontVersionID() = %04X, want %04X: undefined characters are not part of the font", got, want)
+	}
+	if other := FontVersionID(2, 7, 1, 3, characters); got == other {
+		t.Errorf("FontVersionID() does not depend on the font number")
+	}
+}
+
+func TestGraphicID(t *testing.T) {
+	graphic := Graphic{Number: 1, Height: 2, Width: 8, Type: 1, Bitmap: []byte{0xff, 0x81}}
+	got := GraphicID(graphic)
+	graphic.TransparentEnabled = true
+	if GraphicID(graphic) == got {
+		t.Errorf("GraphicID() does not depend on dmsGraphicTransparentEnabled")
+	}
+}
+
+func Test_appendLength(t *testing.T) {
+	tests := []struct {
+		length int
+		want   string
+	}{
+		{length: 5, want: "05"},
+		{length: 127, want: "7f"},
+		{length: 128, want: "8180"},
+		{length: 300, want: "82012c"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(appendLength(nil, tt.length)); got != tt.want {
+			t.Errorf("appendLength(%d) = %s, want %s", tt.length, got, tt.want)
+		}
+	}
+}
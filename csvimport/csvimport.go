@@ -0,0 +1,97 @@
+// Package csvimport reads planned message activations from CSV files, such
+// as construction messaging plans kept in spreadsheets.
+//
+// The first row names the columns: sign, message, priority, start and end,
+// in any order and any case. Start and end are RFC 3339 times; an empty end
+// means the activation has no planned end. Message is either the name of a
+// stored message or a MULTI string, as the caller resolves it.
+package csvimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Activation is a planned message activation read from a CSV row.
+type Activation struct {
+	Line     int // line of the row in the CSV file
+	SignID   string
+	Message  string
+	Priority int
+	Start    time.Time
+	End      time.Time // zero if the activation has no planned end
+}
+
+var columns = []string{"sign", "message", "priority", "start", "end"}
+
+// ReadActivations reads all rows of r. It fails on the first malformed row,
+// reporting its line.
+func ReadActivations(r io.Reader) ([]Activation, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("csvimport: missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range columns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("csvimport: missing column %q", name)
+		}
+	}
+
+	var activations []Activation
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return activations, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		activation, err := parseRecord(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("csvimport: line %d: %w", line, err)
+		}
+		activation.Line = line
+		activations = append(activations, activation)
+	}
+}
+
+func parseRecord(record []string, index map[string]int) (activation Activation, err error) {
+	field := func(name string) string { return strings.TrimSpace(record[index[name]]) }
+
+	if activation.SignID = field("sign"); activation.SignID == "" {
+		return activation, fmt.Errorf("empty sign")
+	}
+	if activation.Message = field("message"); activation.Message == "" {
+		return activation, fmt.Errorf("empty message")
+	}
+	if activation.Priority, err = strconv.Atoi(field("priority")); err != nil || activation.Priority < 1 || activation.Priority > 255 {
+		return activation, fmt.Errorf("priority %q is not in 1-255", field("priority"))
+	}
+	if activation.Start, err = time.Parse(time.RFC3339, field("start")); err != nil {
+		return activation, fmt.Errorf("start: %w", err)
+	}
+	if end := field("end"); end != "" {
+		if activation.End, err = time.Parse(time.RFC3339, end); err != nil {
+			return activation, fmt.Errorf("end: %w", err)
+		}
+		if !activation.End.After(activation.Start) {
+			return activation, fmt.Errorf("end %s is not after start %s", end, field("start"))
+		}
+	}
+	return activation, nil
+}
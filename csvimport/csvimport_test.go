@@ -0,0 +1,68 @@
+package csvimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadActivations(t *testing.T) {
+	input := `Sign,Priority,Message,Start,End
+dms-101,100,[jp3]ROAD WORK,2026-11-02T21:00:00Z,2026-11-03T05:00:00Z
+dms-102,50,NIGHT CLOSURE,2026-11-02T21:00:00Z,
+`
+	got, err := ReadActivations(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadActivations() error = %v", err)
+	}
+	want := []Activation{
+		{
+			Line:     2,
+			SignID:   "dms-101",
+			Message:  "[jp3]ROAD WORK",
+			Priority: 100,
+			Start:    time.Date(2026, 11, 2, 21, 0, 0, 0, time.UTC),
+			End:      time.Date(2026, 11, 3, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			Line:     3,
+			SignID:   "dms-102",
+			Message:  "NIGHT CLOSURE",
+			Priority: 50,
+			Start:    time.Date(2026, 11, 2, 21, 0, 0, 0, time.UTC),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadActivations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadActivations_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: "missing header row"},
+		{name: "missing column", input: "sign,message,priority,start\n", want: `missing column "end"`},
+		{
+			name:  "bad priority",
+			input: "sign,message,priority,start,end\ndms-101,TEST,300,2026-11-02T21:00:00Z,\n",
+			want:  `line 2: priority "300" is not in 1-255`,
+		},
+		{
+			name:  "end before start",
+			input: "sign,message,priority,start,end\ndms-101,TEST,1,2026-11-02T21:00:00Z,2026-11-02T20:00:00Z\n",
+			want:  "line 2: end 2026-11-02T20:00:00Z is not after start",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadActivations(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadActivations() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
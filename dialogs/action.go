@@ -0,0 +1,221 @@
+package dialogs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// ErrActionMessage is returned by SetAction when the message an action
+// activates is not valid in the message table of the sign, or its
+// dmsMessageCRC does not match the one of the action. The sign would fail
+// the activation when the action runs, and keep displaying what it was.
+var ErrActionMessage = errors.New("action message not valid on the sign")
+
+// ErrActionInUse is returned by DeleteAction for an action an event of a day
+// plan still runs.
+var ErrActionInUse = errors.New("action run by a day plan event")
+
+// ScheduleAction is a row of the dmsActionTable: the message activation an
+// event of a day plan runs.
+type ScheduleAction struct {
+	Index int    // dmsActionIndex
+	Code  []byte // dmsActionMsgCode, a MessageActivationCode; see NewScheduleAction
+}
+
+// NewScheduleAction returns the action index activating message for
+// duration minutes (65535 until another message replaces it) at priority,
+// with the MessageActivationCode of EncodeActivateMessageCode. The CRC of a
+// blank message is 0.
+func NewScheduleAction(index int, message d.Message, duration, priority int, sourceAddress string) (ScheduleAction, error) {
+	var code []byte
+	var err error
+	if message.MemoryType == d.MemoryBlank {
+		code, err = encodeActivateMessageCode(duration, priority, message.MemoryType.Int(), message.Number, 0, sourceAddress)
+	} else {
+		code, err = EncodeActivateMessageCode(message.MULTI, message.Beacon, message.PixelService,
+			message.MemoryType.Int(), duration, priority, message.Number, sourceAddress)
+	}
+	return ScheduleAction{Index: index, Code: code}, err
+}
+
+// Equal reports whether a and other are the same row with the same code.
+func (a ScheduleAction) Equal(other ScheduleAction) bool {
+	return a.Index == other.Index && bytes.Equal(a.Code, other.Code)
+}
+
+// Empty reports whether the action activates no message, as left by
+// DeleteAction.
+func (a ScheduleAction) Empty() bool {
+	return len(a.Code) == 0 || bytes.Equal(a.Code, make([]byte, len(a.Code)))
+}
+
+// Message returns the message the action activates.
+func (a ScheduleAction) Message() (MessageID, error) {
+	var id MessageID
+	var err error
+	_, _, id.MessageMemoryType, id.MessageNumber, id.MessageCRC, _, err = DecodeActivateMessageCode(a.Code)
+	return id, err
+}
+
+// Validate checks that the action is a row of a dmsActionTable of size rows
+// and that its code is a MessageActivationCode.
+func (a ScheduleAction) Validate(size int) error {
+	if a.Index < 1 || a.Index > size {
+		return errors.Errorf("action %d out of range 1-%d", a.Index, size)
+	}
+	if len(a.Code) != 12 {
+		return errors.Errorf("action %d: MessageActivationCode has %d bytes, expect 12", a.Index, len(a.Code))
+	}
+	return nil
+}
+
+// GetActions reads every row of the dmsActionTable of the sign.
+func GetActions(dms *gosnmp.GoSNMP, options ...Option) ([]ScheduleAction, error) {
+	s := newSession(dms, "GetActions", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	size, err := getInt(s, "dmsActionTableEntries", d.DmsActionTableEntries.Identifier(0))
+	if err != nil {
+		return nil, err
+	}
+	actions := make([]ScheduleAction, 0, size)
+	for index := 1; index <= size; index++ {
+		action, err := getAction(s, index)
+		if err != nil {
+			return actions, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// GetAction reads the row index of the dmsActionTable of the sign.
+func GetAction(dms *gosnmp.GoSNMP, index int, options ...Option) (ScheduleAction, error) {
+	s := newSession(dms, "GetAction", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return ScheduleAction{}, err
+	}
+	return getAction(s, index)
+}
+
+func getAction(s *session, index int) (ScheduleAction, error) {
+	action := ScheduleAction{Index: index}
+	getResult, err := s.getSingle(fmt.Sprintf("get dmsActionMsgCode %d", index), d.DmsActionMsgCode.Identifier(index))
+	if err != nil {
+		return action, err
+	}
+	code, _ := getResult.Value.([]byte)
+	action.Code = append([]byte{}, code...)
+	return action, nil
+}
+
+// SetAction checks that the message of action is valid in the message table
+// of the sign with the CRC of the action, otherwise it returns
+// ErrActionMessage, then sets the row of the dmsActionTable and reads it
+// back. A blank message is always valid.
+func SetAction(dms *gosnmp.GoSNMP, action ScheduleAction, options ...Option) (ScheduleAction, error) {
+	s := newSession(dms, "SetAction", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return ScheduleAction{}, err
+	}
+	return setAction(s, action)
+}
+
+func setAction(s *session, action ScheduleAction) (ScheduleAction, error) {
+	size, err := getInt(s, "dmsActionTableEntries", d.DmsActionTableEntries.Identifier(0))
+	if err != nil {
+		return ScheduleAction{}, err
+	}
+	if err := action.Validate(size); err != nil {
+		return ScheduleAction{}, s.wrap(err, "validate action")
+	}
+	if err := checkActionMessage(s, action); err != nil {
+		return ScheduleAction{}, err
+	}
+	return writeAction(s, action)
+}
+
+// checkActionMessage returns ErrActionMessage if the message of action is
+// not valid in the message table with the CRC of the action.
+func checkActionMessage(s *session, action ScheduleAction) error {
+	id, err := action.Message()
+	if err != nil {
+		return s.wrap(err, "decode dmsActionMsgCode")
+	}
+	if id.MessageMemoryType == d.MemoryBlank.Int() {
+		return nil
+	}
+	entry, err := readMessageEntry(s, id.MessageMemoryType, id.MessageNumber)
+	if errors.Is(err, ErrNoSuchName) {
+		return s.wrap(errors.Wrapf(ErrActionMessage, "action %d: no message %d of memory type %d", action.Index, id.MessageNumber, id.MessageMemoryType), "check action message")
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case entry.Status != d.Valid.Int():
+		return s.wrap(errors.Wrapf(ErrActionMessage, "action %d: message %d of memory type %d has status %d", action.Index, id.MessageNumber, id.MessageMemoryType, entry.Status), "check action message")
+	case entry.MessageCRC != id.MessageCRC:
+		return s.wrap(errors.Wrapf(ErrActionMessage, "action %d: message %d of memory type %d has CRC %#04x, action %#04x", action.Index, id.MessageNumber, id.MessageMemoryType, entry.MessageCRC, id.MessageCRC), "check action message")
+	}
+	return nil
+}
+
+// writeAction sets the row of action and reads it back.
+func writeAction(s *session, action ScheduleAction) (ScheduleAction, error) {
+	if _, err := s.set(fmt.Sprintf("set dmsActionMsgCode %d", action.Index), gosnmp.SnmpPDU{
+		Name: d.DmsActionMsgCode.Identifier(action.Index), Type: d.DmsActionMsgCode.Syntax(), Value: action.Code,
+	}); err != nil {
+		return ScheduleAction{}, err
+	}
+	current, err := getAction(s, action.Index)
+	if err != nil {
+		return current, err
+	}
+	if !current.Equal(action) {
+		return current, s.wrap(errors.Errorf("sign reports code %x, want %x", current.Code, action.Code), "verify action")
+	}
+	return current, nil
+}
+
+// DeleteAction sets the row index of the dmsActionTable to a
+// MessageActivationCode of zeros, which activates no message; the rows of
+// the table always exist. It returns ErrActionInUse, without changing
+// anything, if an event of a day plan runs the action.
+func DeleteAction(dms *gosnmp.GoSNMP, index int, options ...Option) error {
+	s := newSession(dms, "DeleteAction", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return err
+	}
+	size, err := getScheduleSize(s)
+	if err != nil {
+		return err
+	}
+	if index < 1 || index > size.Actions {
+		return s.wrap(errors.Errorf("action %d out of range 1-%d", index, size.Actions), "validate action")
+	}
+	oid := ActionOID(index)
+	for plan := 1; plan <= size.DayPlans; plan++ {
+		for number := 1; number <= size.DayEvents; number++ {
+			event, err := getDayPlanEvent(s, plan, number)
+			if err != nil {
+				return err
+			}
+			if strings.TrimPrefix(event.Action, ".") == oid {
+				return s.wrap(errors.Wrapf(ErrActionInUse, "action %d run by day plan %d event %d", index, plan, number), "check action use")
+			}
+		}
+	}
+	_, err = writeAction(s, ScheduleAction{Index: index, Code: make([]byte, 12)})
+	return err
+}
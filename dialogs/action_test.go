@@ -0,0 +1,72 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestSetAction(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	message := d.Message{MemoryType: d.MemoryChangeable, Number: 1, MULTI: "[jp3]ROAD WORK"}
+	if _, err := client.DefiningMessage(message.MemoryType.Int(), message.Number, message.MULTI, "10.0.0.5", 100, 0, 0); err != nil {
+		t.Fatalf("DefiningMessage() error = %v", err)
+	}
+
+	action, err := NewScheduleAction(3, message, 60, 100, "10.0.0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.SetAction(action)
+	if err != nil {
+		t.Fatalf("SetAction() error = %v", err)
+	}
+	if id, err := got.Message(); err != nil || id != MessageIDOf(message) {
+		t.Errorf("SetAction() message = %+v, %v, want %+v", id, err, MessageIDOf(message))
+	}
+
+	changed := message
+	changed.MULTI = "[jp3]ROAD CLOSED"
+	stale, _ := NewScheduleAction(4, changed, 60, 100, "10.0.0.5")
+	if _, err := client.SetAction(stale); !errors.Is(err, ErrActionMessage) {
+		t.Errorf("SetAction() with a stale CRC error = %v, want ErrActionMessage", err)
+	}
+	undefined, _ := NewScheduleAction(4, d.Message{MemoryType: d.MemoryChangeable, Number: 2, MULTI: "X"}, 60, 100, "10.0.0.5")
+	if _, err := client.SetAction(undefined); !errors.Is(err, ErrActionMessage) {
+		t.Errorf("SetAction() with an undefined message error = %v, want ErrActionMessage", err)
+	}
+	if _, err := client.SetAction(ScheduleAction{Index: simulator.DmsActionTableEntries + 1, Code: action.Code}); err == nil {
+		t.Error("SetAction() beyond the table error = nil")
+	}
+
+	actions, err := client.GetActions()
+	if err != nil || len(actions) != simulator.DmsActionTableEntries || !actions[2].Equal(action) || !actions[3].Empty() {
+		t.Fatalf("GetActions() = %+v, %v", actions, err)
+	}
+
+	event := DayPlanEvent{DayPlan: 1, Event: 1, Hour: 6, Action: ActionOID(3)}
+	if _, err := client.SetSchedule(Schedule{Events: []DayPlanEvent{event}}); err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+	if err := client.DeleteAction(3); !errors.Is(err, ErrActionInUse) {
+		t.Errorf("DeleteAction() of a scheduled action error = %v, want ErrActionInUse", err)
+	}
+	event.Action = ""
+	if _, err := client.SetSchedule(Schedule{Events: []DayPlanEvent{event}}); err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+	if err := client.DeleteAction(3); err != nil {
+		t.Fatalf("DeleteAction() error = %v", err)
+	}
+	if got, err := client.GetAction(3); err != nil || !got.Empty() {
+		t.Errorf("GetAction() after DeleteAction() = %+v, %v", got, err)
+	}
+}
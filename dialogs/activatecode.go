@@ -4,8 +4,9 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
-	"strconv"
-	"strings"
+	"net"
+
+	"github.com/jacobleehei/godms/crc"
 )
 
 func EncodeActivateMessageCode(
@@ -16,43 +17,66 @@ func EncodeActivateMessageCode(
 	messageMemoryType, duration, priority, messageNumber int,
 	requestIPAddress string,
 ) ([]byte, error) {
-	ipAddresses := strings.Split(requestIPAddress, ".")
-	ipAddressesInt1, _ := strconv.Atoi(fmt.Sprintf("%s", ipAddresses[0]))
-	ipAddressesInt2, _ := strconv.Atoi(fmt.Sprintf("%s", ipAddresses[1]))
-	ipAddressesInt3, _ := strconv.Atoi(fmt.Sprintf("%s", ipAddresses[2]))
-	ipAddressesInt4, _ := strconv.Atoi(fmt.Sprintf("%s", ipAddresses[3]))
-	checkSumOfMultiString := calcChecksum(multiString, beacon, pixelService)
+	return encodeActivateMessageCode(
+		duration, priority, messageMemoryType, messageNumber,
+		calcChecksum(multiString, beacon, pixelService),
+		requestIPAddress,
+	)
+}
+
+// encodeActivateMessageCode encodes a MessageActivationCode with a known
+// message CRC, e.g. 0x0000 for the blank messages.
+func encodeActivateMessageCode(
+	duration, priority, messageMemoryType, messageNumber, messageCRC int,
+	requestIPAddress string,
+) ([]byte, error) {
+	ip := net.ParseIP(requestIPAddress).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("source address %q is not an IPv4 address", requestIPAddress)
+	}
 	activateMessageCode, _ := hex.DecodeString(fmt.Sprintf("%04X", duration) +
 		fmt.Sprintf("%02X", priority) +
 		fmt.Sprintf("%02X", messageMemoryType) +
 		fmt.Sprintf("%04X", messageNumber) +
-		fmt.Sprintf("%04X", checkSumOfMultiString) +
-		fmt.Sprintf("%02X", ipAddressesInt1) +
-		fmt.Sprintf("%02X", ipAddressesInt2) +
-		fmt.Sprintf("%02X", ipAddressesInt3) +
-		fmt.Sprintf("%02X", ipAddressesInt4))
+		fmt.Sprintf("%04X", messageCRC) +
+		fmt.Sprintf("%02X", []byte(ip)))
 
 	return activateMessageCode, nil
 }
 
-func calcChecksum(multiString string, beacon int, pixelService int) int {
-	data := []byte(multiString)
-
-	additionalData := []byte{byte(beacon), byte(pixelService)}
-	data = append(data, additionalData...)
+// DecodeActivateMessageCode splits a MessageActivationCode, e.g. the value
+// of dmsActivateMessage.0 or dmsActivateErrorMsgCode.0, into its fields.
+func DecodeActivateMessageCode(code []byte) (
+	duration, priority, messageMemoryType, messageNumber, messageCRC int,
+	requestIPAddress string,
+	err error,
+) {
+	if len(code) != 12 {
+		return 0, 0, 0, 0, 0, "", fmt.Errorf("MessageActivationCode has %d bytes, expect 12", len(code))
+	}
+	duration = int(binary.BigEndian.Uint16(code[0:2]))
+	priority = int(code[2])
+	messageMemoryType, messageNumber, messageCRC, _ = decodeMessageIDCode(code[3:8])
+	requestIPAddress = net.IP(code[8:12]).String()
+	return duration, priority, messageMemoryType, messageNumber, messageCRC, requestIPAddress, nil
+}
 
-	b := make([]byte, 2)
-	fcs := uint16(0xffff)
-	data = []byte(data)
-	for i, l := 0, len(data); i < l; i++ {
-		fcs = (fcs >> 8) ^ MbTable[(fcs^uint16(data[i]))&0xff]
+// decodeMessageIDCode splits a MessageIDCode (e.g. dmsMsgTableSource.0) into
+// its message memory type, message number and message CRC.
+func decodeMessageIDCode(code []byte) (messageMemoryType, messageNumber, messageCRC int, err error) {
+	if len(code) != 5 {
+		return 0, 0, 0, fmt.Errorf("MessageIDCode has %d bytes, expect 5", len(code))
 	}
+	return int(code[0]), int(binary.BigEndian.Uint16(code[1:3])), int(binary.BigEndian.Uint16(code[3:5])), nil
+}
 
-	binary.LittleEndian.PutUint16(b, ^fcs)
-	crcGen, _ := strconv.ParseInt(fmt.Sprintf("%X", b), 16, 64)
-	return int(crcGen)
+func calcChecksum(multiString string, beacon int, pixelService int) int {
+	return int(crc.MessageCRC(multiString, beacon, pixelService))
 }
 
+// MbTable is the lookup table of the ISO/IEC 3309 CRC-16.
+//
+// Deprecated: use package crc.
 var MbTable = [...]uint16{
 	0x0000, 0x1189, 0x2312, 0x329b, 0x4624, 0x57ad, 0x6536, 0x74bf,
 	0x8c48, 0x9dc1, 0xaf5a, 0xbed3, 0xca6c, 0xdbe5, 0xe97e, 0xf8f7,
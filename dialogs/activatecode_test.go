@@ -3,6 +3,7 @@ package dialogs
 import (
 	"encoding/hex"
 	"log"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +39,30 @@ func TestEncodeActivateMessageCode(t *testing.T) {
 			},
 			want: "010B3704000595F96708090A",
 		},
+		{
+			name: "ipv6 source address",
+			args: args{
+				multiString:      "[jp3]TEST [fl]Flashing[/fl]",
+				messageType:      4,
+				duration:         267,
+				priority:         55,
+				messageNumber:    5,
+				requestIPAddress: "2001:db8::1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid source address",
+			args: args{
+				multiString:      "[jp3]TEST [fl]Flashing[/fl]",
+				messageType:      4,
+				duration:         267,
+				priority:         55,
+				messageNumber:    5,
+				requestIPAddress: "localhost",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -54,6 +79,43 @@ func TestEncodeActivateMessageCode(t *testing.T) {
 	}
 }
 
+func TestDecodeActivateMessageCode(t *testing.T) {
+	code, _ := hex.DecodeString("010B3704000595F96708090A")
+	duration, priority, messageType, messageNumber, crc, address, err := DecodeActivateMessageCode(code)
+	if err != nil {
+		t.Fatalf("DecodeActivateMessageCode() error = %v", err)
+	}
+	if duration != 267 || priority != 55 || messageType != 4 || messageNumber != 5 || crc != 0x95F9 || address != "103.8.9.10" {
+		t.Errorf("DecodeActivateMessageCode() = %d, %d, %d, %d, %X, %s", duration, priority, messageType, messageNumber, crc, address)
+	}
+	if _, _, _, _, _, _, err := DecodeActivateMessageCode(code[:8]); err == nil {
+		t.Errorf("DecodeActivateMessageCode() expected an error for a short code")
+	}
+}
+
+func Test_encodeActivateMessageCode_blank(t *testing.T) {
+	got, err := encodeActivateMessageCode(65535, 10, 7, 10, 0, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("encodeActivateMessageCode() error = %v", err)
+	}
+	if want := "FFFF0A07000A00007F000001"; hex.EncodeToString(got) != strings.ToLower(want) {
+		t.Errorf("encodeActivateMessageCode() = %X, want %v", got, want)
+	}
+}
+
+func Test_decodeMessageIDCode(t *testing.T) {
+	memoryType, number, crc, err := decodeMessageIDCode([]byte{0x03, 0x00, 0x05, 0x95, 0xF9})
+	if err != nil {
+		t.Fatalf("decodeMessageIDCode() error = %v", err)
+	}
+	if memoryType != 3 || number != 5 || crc != 0x95F9 {
+		t.Errorf("decodeMessageIDCode() = %d, %d, %X, want 3, 5, 95F9", memoryType, number, crc)
+	}
+	if _, _, _, err := decodeMessageIDCode([]byte{0x03}); err == nil {
+		t.Errorf("decodeMessageIDCode() expected an error for a short code")
+	}
+}
+
 func Test_calcChecksum(t *testing.T) {
 	type args struct {
 		multiString  string
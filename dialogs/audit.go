@@ -0,0 +1,98 @@
+package dialogs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// AuditRecord is a SET sent by a dialog, passed to an AuditSink.
+type AuditRecord struct {
+	Time   time.Time // when the SET was sent
+	Target string    // address of the sign, host:port
+	Dialog string    // e.g. "ActivatingMessage"
+	Step   string    // e.g. "set dmsActivateMessage"
+	Owner  string    // dmsMessageOwner of the SET if any, else the management station address if known, see WithSourceAddress
+	Values []AuditValue
+	Err    error // nil if the sign accepted the SET
+}
+
+// AuditValue is an object written by a SET.
+type AuditValue struct {
+	OID string
+	Old interface{} // the value read earlier in the dialog, nil if not known
+	New interface{}
+}
+
+// AuditSink records the SETs of the dialogs, e.g. to trace the activation
+// of messages.
+type AuditSink interface {
+	Audit(record AuditRecord)
+}
+
+// AuditSinkFunc adapts a function to an AuditSink.
+type AuditSinkFunc func(record AuditRecord)
+
+func (f AuditSinkFunc) Audit(record AuditRecord) { f(record) }
+
+// WithAudit passes every SET sent by the dialogs to sink, whether the sign
+// accepted it or not. Retried SETs are recorded once, with the final
+// result; the SETs of WithDryRun and of read-only clients are not sent and
+// not recorded. The old value of an object is known if the dialog read it
+// before writing it, as most dialogs do with the status objects of a table.
+func WithAudit(sink AuditSink) Option {
+	return func(o *dialogOptions) { o.audit = sink }
+}
+
+// remember keeps the values read or written by the session while auditing,
+// for the old values of the next SETs.
+func (s *session) remember(variables []gosnmp.SnmpPDU) {
+	if s.options.audit == nil {
+		return
+	}
+	if s.known == nil {
+		s.known = map[string]interface{}{}
+	}
+	for _, variable := range variables {
+		switch variable.Type {
+		case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView, gosnmp.Null:
+			continue
+		}
+		s.known[strings.TrimPrefix(variable.Name, ".")] = variable.Value
+	}
+}
+
+// audit passes the SET of request r, sent at start, to the audit sink of
+// the session.
+func (s *session) audit(r request, err error, start time.Time) {
+	if s.options.audit == nil {
+		return
+	}
+	record := AuditRecord{
+		Time:   start,
+		Target: s.target(),
+		Dialog: s.dialog,
+		Step:   r.step,
+		Err:    err,
+	}
+	owners := []string{parentOID(parentOID(d.DmsMessageOwner.Identifier(0, 0)))}
+	for _, pdu := range r.pdus {
+		name := strings.TrimPrefix(pdu.Name, ".")
+		record.Values = append(record.Values, AuditValue{OID: name, Old: s.known[name], New: pdu.Value})
+		if value, ok := pdu.Value.(string); ok && underAny(name, owners) {
+			record.Owner = value
+		}
+	}
+	if record.Owner == "" {
+		if s.owner == "" {
+			s.owner, _ = sourceAddress(s.dms, s.options)
+		}
+		record.Owner = s.owner
+	}
+	if err == nil {
+		s.remember(r.pdus)
+	}
+	s.options.audit.Audit(record)
+}
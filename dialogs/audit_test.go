@@ -0,0 +1,64 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestWithAudit(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	var records []AuditRecord
+	audit := AuditSinkFunc(func(record AuditRecord) { records = append(records, record) })
+	client := NewClient(sign.GoSNMP(), WithAudit(audit), WithSourceAddress("10.0.0.9"))
+
+	if _, err := client.DefiningMessage(d.MemoryChangeable.Int(), 1, "ROAD WORK", "10.0.0.5", 50, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	steps := []string{"set dmsMessageStatus to modifyReq", "set message table entry", "set dmsMessageStatus to validateReq"}
+	if len(records) != len(steps) {
+		t.Fatalf("DefiningMessage() audited %+v, want %d SETs", records, len(steps))
+	}
+	for i, record := range records {
+		if record.Step != steps[i] || record.Dialog != "DefiningMessage" || record.Target != (&session{dms: client.dms}).target() || record.Err != nil || record.Time.IsZero() {
+			t.Errorf("record %d = %+v, want %q", i, record, steps[i])
+		}
+	}
+	// dmsMessageStatus is read after modifyReq, not before.
+	if modify := records[0].Values[0]; modify.Old != nil || modify.New != d.ModifyReq.Int() {
+		t.Errorf("modifyReq value = %+v, want an unknown old value", modify)
+	}
+	validate := records[2].Values[0]
+	if !oidEqual(validate.OID, d.DmsMessageStatus.Identifier(d.MemoryChangeable.Int(), 1)) || validate.Old != d.Modifying.Int() || validate.New != d.ValidateReq.Int() {
+		t.Errorf("validateReq value = %+v, want modifying to validateReq", validate)
+	}
+	if records[1].Owner != "10.0.0.5" {
+		t.Errorf("message table entry owner = %q, want dmsMessageOwner", records[1].Owner)
+	}
+	if records[0].Owner != "10.0.0.9" {
+		t.Errorf("modifyReq owner = %q, want the source address", records[0].Owner)
+	}
+
+	// A refused SET is recorded with its error.
+	records = nil
+	if _, err := client.ActivatingMessage(100, 255, d.MemoryChangeable.Int(), 2); err == nil {
+		t.Fatal("ActivatingMessage() of a message not defined succeeded")
+	}
+	if len(records) != 1 || records[0].Step != "set dmsActivateMessage" || records[0].Err == nil || records[0].Values[0].Old != nil {
+		t.Errorf("ActivatingMessage() audited %+v, want the refused SET", records)
+	}
+
+	// Read-only clients send no SET.
+	records = nil
+	readOnly := NewClient(sign.GoSNMP(), WithAudit(audit), WithReadOnly())
+	if _, err := readOnly.SetControlMode(d.ControlCentral.Int()); !errors.Is(err, ErrReadOnlyClient) || len(records) != 0 {
+		t.Errorf("read-only SetControlMode() = %v, audited %+v", err, records)
+	}
+}
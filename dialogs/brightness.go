@@ -0,0 +1,239 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// Brightness holds the illumination objects (dmsIllum) of a sign.
+type Brightness struct {
+	Control           int    // dmsIllumControl, e.g. d.IllumPhotocell
+	ControlName       string // e.g. "photocell" or "manualDirect"
+	ManLevel          int    // dmsIllumManLevel
+	BrightLevel       int    // dmsIllumBrightLevelStatus, 0 (off) to NumBrightLevels
+	NumBrightLevels   int    // dmsIllumNumBrightLevels
+	PhotocellLevel    int    // dmsIllumPhotocellLevelStatus
+	MaxPhotocellLevel int    // dmsIllumMaxPhotocellLevel
+	LightOutput       int    // dmsIllumLightOutputStatus, 0 (darkest) to 65535
+}
+
+// Manual reports whether the brightness level is set through
+// dmsIllumManLevel.
+func (b Brightness) Manual() bool { return manualIllumControl(b.Control) }
+
+func manualIllumControl(mode int) bool {
+	return mode == d.IllumManual.Int() || mode == d.IllumManualDirect.Int() || mode == d.IllumManualIndexed.Int()
+}
+
+// GetBrightness reads the brightness control mode, the current brightness
+// level, the photocell level and the light output of the sign.
+func GetBrightness(dms *gosnmp.GoSNMP, options ...Option) (Brightness, error) {
+	s := newSession(dms, "GetBrightness", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return Brightness{}, err
+	}
+	return getBrightness(s)
+}
+
+func getBrightness(s *session) (brightness Brightness, err error) {
+	values := []struct {
+		object d.Reader
+		value  *int
+	}{
+		{d.DmsIllumControl, &brightness.Control},
+		{d.DmsIllumManLevel, &brightness.ManLevel},
+		{d.DmsIllumBrightLevelStatus, &brightness.BrightLevel},
+		{d.DmsIllumNumBrightLevels, &brightness.NumBrightLevels},
+		{d.DmsIllumPhotocellLevelStatus, &brightness.PhotocellLevel},
+		{d.DmsIllumMaxPhotocellLevel, &brightness.MaxPhotocellLevel},
+		{d.DmsIllumLightOutputStatus, &brightness.LightOutput},
+	}
+	oids := make([]string, 0, len(values))
+	for _, v := range values {
+		oids = append(oids, v.object.Identifier(0))
+	}
+	getResults, err := s.get("get brightness", oids...)
+	if err != nil {
+		return brightness, err
+	}
+	for _, variable := range getResults.Variables {
+		for _, v := range values {
+			if oidEqual(variable.Name, v.object.Identifier(0)) {
+				*v.value, _ = variable.Value.(int)
+			}
+		}
+	}
+	name, err := d.Format(d.DmsIllumControl, brightness.Control)
+	if err != nil {
+		return brightness, s.wrap(err, "format dmsIllumControl", d.DmsIllumControl.Identifier(0))
+	}
+	brightness.ControlName = name.(string)
+	return brightness, nil
+}
+
+// SetBrightness sets dmsIllumControl to mode, e.g. d.IllumPhotocell or
+// d.IllumManualDirect, and for the manual modes dmsIllumManLevel to level,
+// which must not exceed dmsIllumNumBrightLevels. level is ignored for the
+// other modes. The brightness objects are read back to verify the sign
+// accepted the settings, and returned.
+func SetBrightness(dms *gosnmp.GoSNMP, mode, level int, options ...Option) (brightness Brightness, err error) {
+	s := newSession(dms, "SetBrightness", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return brightness, err
+	}
+
+	manual := manualIllumControl(mode)
+	if manual {
+		numLevels, err := getInt(s, "dmsIllumNumBrightLevels", d.DmsIllumNumBrightLevels.Identifier(0))
+		if err != nil {
+			return brightness, err
+		}
+		if level < 0 || level > numLevels {
+			return brightness, s.wrap(errors.Errorf("brightness level %d out of range 0-%d", level, numLevels), "check brightness level")
+		}
+	}
+
+	// dmsIllumControl is set first: switching to a manual mode loads the
+	// current level into dmsIllumManLevel.
+	if _, err = s.set("set dmsIllumControl", gosnmp.SnmpPDU{Name: d.DmsIllumControl.Identifier(0), Type: d.DmsIllumControl.Syntax(), Value: mode}); err != nil {
+		return brightness, err
+	}
+	if manual {
+		if _, err = s.set("set dmsIllumManLevel", gosnmp.SnmpPDU{Name: d.DmsIllumManLevel.Identifier(0), Type: d.DmsIllumManLevel.Syntax(), Value: level}); err != nil {
+			return brightness, err
+		}
+	}
+
+	if brightness, err = getBrightness(s); err != nil {
+		return brightness, err
+	}
+	if brightness.Control != mode {
+		return brightness, s.wrap(errors.Errorf("sign reports dmsIllumControl %d, want %d", brightness.Control, mode), "verify brightness", d.DmsIllumControl.Identifier(0))
+	}
+	if manual && brightness.ManLevel != level {
+		return brightness, s.wrap(errors.Errorf("sign reports dmsIllumManLevel %d, want %d", brightness.ManLevel, level), "verify brightness", d.DmsIllumManLevel.Identifier(0))
+	}
+	return brightness, nil
+}
+
+// BrightnessLevel is a row of the brightness table, dmsIllumBrightnessValues:
+// the light output is used while the photocell level stays between
+// PhotocellDown and PhotocellUp.
+type BrightnessLevel struct {
+	LightOutput   int // 0 (no light output) to 65535
+	PhotocellDown int // lowest photocell level of the light output
+	PhotocellUp   int // highest photocell level of the light output
+}
+
+// EncodeBrightnessTable encodes levels as a dmsIllumBrightnessValues
+// value: the number of rows followed by the OER encoding of each row.
+func EncodeBrightnessTable(levels []BrightnessLevel) ([]byte, error) {
+	if len(levels) > 255 {
+		return nil, errors.Errorf("%d brightness levels, at most 255 can be encoded", len(levels))
+	}
+	data := []byte{byte(len(levels))}
+	for i, level := range levels {
+		for _, value := range []int{level.LightOutput, level.PhotocellDown, level.PhotocellUp} {
+			if value < 0 || value > 65535 {
+				return nil, errors.Errorf("brightness level %d: value %d out of range 0-65535", i+1, value)
+			}
+			data = append(data, byte(value>>8), byte(value))
+		}
+	}
+	return data, nil
+}
+
+// DecodeBrightnessTable decodes a dmsIllumBrightnessValues value.
+func DecodeBrightnessTable(data []byte) ([]BrightnessLevel, error) {
+	if len(data) == 0 || len(data) != 1+6*int(data[0]) {
+		return nil, errors.Errorf("invalid brightness table of %d bytes", len(data))
+	}
+	levels := make([]BrightnessLevel, data[0])
+	for i := range levels {
+		row := data[1+6*i:]
+		levels[i] = BrightnessLevel{
+			LightOutput:   int(row[0])<<8 | int(row[1]),
+			PhotocellDown: int(row[2])<<8 | int(row[3]),
+			PhotocellUp:   int(row[4])<<8 | int(row[5]),
+		}
+	}
+	return levels, nil
+}
+
+// GetBrightnessTable reads the brightness table of the sign.
+func GetBrightnessTable(dms *gosnmp.GoSNMP, options ...Option) ([]BrightnessLevel, error) {
+	s := newSession(dms, "GetBrightnessTable", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	result, err := s.getSingle("get dmsIllumBrightnessValues", d.DmsIllumBrightnessValues.Identifier(0))
+	if err != nil {
+		return nil, err
+	}
+	data, _ := result.Value.([]byte)
+	levels, err := DecodeBrightnessTable(data)
+	if err != nil {
+		return nil, s.wrap(err, "decode dmsIllumBrightnessValues", result.Name)
+	}
+	return levels, nil
+}
+
+// SetBrightnessTable writes levels, ordered from the dimmest to the
+// brightest, into dmsIllumBrightnessValues. The table is checked against
+// dmsIllumNumBrightLevels and dmsIllumMaxPhotocellLevel first. If the sign
+// rejects it, the cause is read from dmsIllumBrightnessValuesError and
+// returned as a *BrightnessTableError.
+func SetBrightnessTable(dms *gosnmp.GoSNMP, levels []BrightnessLevel, options ...Option) error {
+	s := newSession(dms, "SetBrightnessTable", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return err
+	}
+
+	data, err := EncodeBrightnessTable(levels)
+	if err != nil {
+		return s.wrap(err, "encode dmsIllumBrightnessValues")
+	}
+	numLevels, err := getInt(s, "dmsIllumNumBrightLevels", d.DmsIllumNumBrightLevels.Identifier(0))
+	if err != nil {
+		return err
+	}
+	if len(levels) > numLevels {
+		return s.wrap(errors.Errorf("%d brightness levels exceed dmsIllumNumBrightLevels %d", len(levels), numLevels), "check brightness table")
+	}
+	maxPhotocell, err := getInt(s, "dmsIllumMaxPhotocellLevel", d.DmsIllumMaxPhotocellLevel.Identifier(0))
+	if err != nil {
+		return err
+	}
+	for i, level := range levels {
+		if level.PhotocellDown > maxPhotocell || level.PhotocellUp > maxPhotocell {
+			return s.wrap(errors.Errorf("brightness level %d: photocell levels %d-%d exceed dmsIllumMaxPhotocellLevel %d",
+				i+1, level.PhotocellDown, level.PhotocellUp, maxPhotocell), "check brightness table")
+		}
+	}
+
+	pdu := gosnmp.SnmpPDU{Name: d.DmsIllumBrightnessValues.Identifier(0), Type: d.DmsIllumBrightnessValues.Syntax(), Value: data}
+	_, err = s.set("set dmsIllumBrightnessValues", pdu)
+	var snmpError *SNMPError
+	if err == nil || !errors.As(err, &snmpError) {
+		return err
+	}
+
+	// The sign rejected the table; dmsIllumBrightnessValuesError.0 tells why.
+	result, getErr := s.getSingle("get dmsIllumBrightnessValuesError", d.DmsIllumBrightnessValuesError.Identifier(0))
+	if getErr != nil {
+		return getErr
+	}
+	tableError := &BrightnessTableError{Status: snmpError.Status}
+	tableError.Code, _ = result.Value.(int)
+	formatted, formatErr := d.Format(d.DmsIllumBrightnessValuesError, tableError.Code)
+	if formatErr != nil {
+		return s.wrap(formatErr, "format dmsIllumBrightnessValuesError", result.Name)
+	}
+	tableError.BrightnessValuesError = formatted.(string)
+	return s.wrap(tableError, "set dmsIllumBrightnessValues", pdu.Name)
+}
@@ -0,0 +1,95 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestSetBrightness(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	brightness, err := GetBrightness(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brightness.ControlName != "photocell" || brightness.Manual() || brightness.NumBrightLevels != simulator.IllumNumBrightLevels ||
+		brightness.PhotocellLevel != simulator.IllumMaxPhotocellLevel/2 || brightness.LightOutput == 0 {
+		t.Errorf("GetBrightness() = %+v", brightness)
+	}
+
+	brightness, err = SetBrightness(dms, d.IllumManualDirect.Int(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brightness.ControlName != "manualDirect" || brightness.ManLevel != 4 || brightness.BrightLevel != 4 || brightness.LightOutput != 65535*4/simulator.IllumNumBrightLevels {
+		t.Errorf("SetBrightness() = %+v, want manual level 4", brightness)
+	}
+
+	if _, err := SetBrightness(dms, d.IllumManualDirect.Int(), simulator.IllumNumBrightLevels+1); err == nil {
+		t.Error("SetBrightness() accepted a level above dmsIllumNumBrightLevels")
+	}
+	if _, err := SetBrightness(dms, d.IllumPhotocell.Int(), 0); err != nil {
+		t.Errorf("SetBrightness() back to photocell error = %v", err)
+	}
+}
+
+func TestSetBrightnessTable(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	levels := []BrightnessLevel{
+		{LightOutput: 1000, PhotocellDown: 0, PhotocellUp: 300},
+		{LightOutput: 20000, PhotocellDown: 250, PhotocellUp: 700},
+		{LightOutput: 65535, PhotocellDown: 650, PhotocellUp: simulator.IllumMaxPhotocellLevel},
+	}
+	if err := SetBrightnessTable(dms, levels); err != nil {
+		t.Fatal(err)
+	}
+	got, err := GetBrightnessTable(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, levels) {
+		t.Errorf("GetBrightnessTable() = %+v, want %+v", got, levels)
+	}
+
+	tests := []struct {
+		name    string
+		levels  []BrightnessLevel
+		wantErr error
+	}{
+		{name: "photocell gap", levels: []BrightnessLevel{levels[0], levels[2]}, wantErr: ErrBrightnessPhotocellGap},
+		{name: "negative slope", levels: []BrightnessLevel{levels[0], {LightOutput: 500, PhotocellDown: 300, PhotocellUp: simulator.IllumMaxPhotocellLevel}}, wantErr: ErrBrightnessNegativeSlope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetBrightnessTable(dms, tt.levels)
+			var tableError *BrightnessTableError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &tableError) || tableError.Status != gosnmp.GenErr {
+				t.Errorf("SetBrightnessTable() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	tooMany := make([]BrightnessLevel, simulator.IllumNumBrightLevels+1)
+	if err := SetBrightnessTable(dms, tooMany); err == nil || errors.As(err, new(*BrightnessTableError)) {
+		t.Errorf("SetBrightnessTable() of %d levels error = %v, want a local check", len(tooMany), err)
+	}
+	if _, err := DecodeBrightnessTable([]byte{2, 0, 0}); err == nil {
+		t.Error("DecodeBrightnessTable() accepted a truncated table")
+	}
+}
@@ -0,0 +1,87 @@
+package dialogs
+
+import (
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// configCache is the static configuration of the sign read by the dialogs
+// of a Client, guarded by the mu of the client. The capabilities are kept
+// in the capabilities of the client.
+type configCache struct {
+	setID int // globalSetIDParameter when the cache was filled, -1 if the sign has none
+	known bool
+	sign  *SignConfiguration
+	vms   *VMSConfiguration
+	fonts []FontEntry // nil until listed
+}
+
+// InvalidateConfiguration drops the configuration cached by the client: the
+// next GetSignConfiguration, GetVMSConfiguration, DiscoverCapabilities and
+// ListFonts read the sign again. The client does so on its own when
+// globalSetIDParameter changes or a dialog SETs dmsSWReset. To notice the
+// change, each of these calls still GETs globalSetIDParameter: the cache
+// saves the reads of the configuration itself, e.g. the walk of the
+// fontTable, not the round trip to the sign.
+func (c *Client) InvalidateConfiguration() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate()
+}
+
+// invalidate drops the cached configuration. It is called with mu held.
+func (c *Client) invalidate() {
+	c.cache = configCache{}
+	c.capabilitiesMu.Lock()
+	c.capabilities = nil
+	c.capabilitiesMu.Unlock()
+}
+
+// checkConfiguration GETs globalSetIDParameter and drops the cached
+// configuration if it changed since the cache was filled. Signs without
+// globalSetIDParameter keep the cache until InvalidateConfiguration. It is
+// called with mu held.
+func (c *Client) checkConfiguration(options []Option) error {
+	s := newSession(c.dms, "CheckConfiguration", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return err
+	}
+	setID, err := getInt(s, "globalSetIDParameter", d.GlobalSetIDParameter.Identifier(0))
+	if errors.Is(err, ErrNoSuchName) {
+		setID, err = -1, nil
+	}
+	if err != nil {
+		return err
+	}
+	if !c.cache.known || setID != c.cache.setID {
+		c.invalidate()
+		c.cache.setID, c.cache.known = setID, true
+	}
+	return nil
+}
+
+// configInterceptor drops the cached configuration after a SET of
+// dmsSWReset, which restarts the controller, and the cached fonts after a
+// SET of the fontTable.
+func (c *Client) configInterceptor() Interceptor {
+	reset := strings.TrimPrefix(d.DmsSWReset.Identifier(0), ".")
+	fonts := parentOID(parentOID(d.FontIndex.Identifier(0)))
+	return Interceptor{After: func(op Operation, result *gosnmp.SnmpPacket, err error) error {
+		if op.Kind != "set" {
+			return err
+		}
+		for _, pdu := range op.PDUs {
+			switch {
+			case oidEqual(pdu.Name, reset):
+				c.invalidate()
+			case underAny(pdu.Name, []string{fonts}):
+				c.cache.fonts = nil
+			}
+		}
+		return err
+	}}
+}
@@ -0,0 +1,99 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_configurationCache(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	var requests int
+	count := Interceptor{Before: func(op *Operation) error {
+		requests++
+		return nil
+	}}
+	client := NewClient(sign.GoSNMP(), WithInterceptor(count))
+	read := func() VMSConfiguration {
+		t.Helper()
+		requests = 0
+		config, err := client.GetVMSConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config
+	}
+
+	first := read()
+	if requests < 2 {
+		t.Fatalf("first GetVMSConfiguration() sent %d requests, want the configuration read", requests)
+	}
+	if cached := read(); !reflect.DeepEqual(cached, first) || requests != 1 {
+		t.Errorf("cached GetVMSConfiguration() = %+v after %d requests, want %+v after the globalSetIDParameter GET", cached, requests, first)
+	}
+
+	// A change of the database of the sign.
+	sign.Store(d.GlobalSetIDParameter.Identifier(0), gosnmp.Integer, 7)
+	if read(); requests < 2 {
+		t.Errorf("GetVMSConfiguration() after a globalSetIDParameter change sent %d requests, want the configuration read", requests)
+	}
+	// Invalidating runs no dialog: the connection is not used.
+	used := client.conn.used
+	client.InvalidateConfiguration()
+	if !client.conn.used.Equal(used) {
+		t.Error("InvalidateConfiguration() released the connection of the client")
+	}
+	if read(); requests < 2 {
+		t.Errorf("GetVMSConfiguration() after InvalidateConfiguration sent %d requests, want the configuration read", requests)
+	}
+
+	// A SET of dmsSWReset restarts the controller.
+	client.mu.Lock()
+	client.configInterceptor().After(Operation{Kind: "set", PDUs: []gosnmp.SnmpPDU{{Name: d.DmsSWReset.Identifier(0)}}}, nil, nil)
+	client.mu.Unlock()
+	if read(); requests < 2 {
+		t.Errorf("GetVMSConfiguration() after a dmsSWReset SET sent %d requests, want the configuration read", requests)
+	}
+
+	// Without globalSetIDParameter, the cache is kept until invalidated.
+	sign.Delete(d.GlobalSetIDParameter.Identifier(0))
+	read()
+	if read(); requests != 1 {
+		t.Errorf("GetVMSConfiguration() of a sign without globalSetIDParameter sent %d requests, want 1", requests)
+	}
+}
+
+func TestClient_ListFonts(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	client := NewClient(sign.GoSNMP())
+	fonts, err := client.ListFonts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FontEntry{{Index: 1, Number: 1, Name: "simulator 7", Height: 7, CharSpacing: 1, LineSpacing: 2, VersionID: simulator.Font1VersionID, Status: d.FontPermanent.Int()}}
+	if !reflect.DeepEqual(fonts, want) {
+		t.Fatalf("ListFonts() = %+v, want %+v", fonts, want)
+	}
+
+	// A font downloaded through the client is listed next time.
+	font := Font{Number: 2, Name: "copy", Height: 7, CharSpacing: 1, LineSpacing: 2, Characters: simulator.Font1Characters}
+	if _, err := client.ConfiguringFont(2, font); err != nil {
+		t.Fatal(err)
+	}
+	if fonts, err = client.ListFonts(); err != nil || len(fonts) != 2 || fonts[1].Name != "copy" {
+		t.Errorf("ListFonts() after ConfiguringFont = %+v, %v, want the new font", fonts, err)
+	}
+}
@@ -0,0 +1,133 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// Capabilities lists the optional features and capacities of a sign.
+type Capabilities struct {
+	NTCIPVersion         int  // 1 for NTCIP 1203 v1, 2 for v2 or later
+	Beacon               bool // dmsMessageBeacon is supported
+	PixelService         bool // dmsMessagePixelService is supported
+	Fonts                int  // numFonts
+	Graphics             int  // dmsGraphicMaxEntries, 0 without graphics support
+	ColorScheme          int  // dmsColorScheme, 0 for a v1 sign
+	SupportedMultiTags   int  // dmsSupportedMultiTags bitmap, 0 for a v1 sign
+	MaxChangeableMsg     int  // dmsMaxChangeableMsg
+	MaxVolatileMsg       int  // dmsMaxVolatileMsg
+	MaxMultiStringLength int  // dmsMaxMultiStringLength, 0 for a v1 sign
+}
+
+// SupportsMultiTag reports whether bit of dmsSupportedMultiTags is set, e.g.
+// bit 5 for [fo]. Signs that do not report their tags are assumed to
+// support them all.
+func (c Capabilities) SupportsMultiTag(bit int) bool {
+	return c.SupportedMultiTags == 0 || c.SupportedMultiTags&(1<<bit) != 0
+}
+
+// DiscoverCapabilities probes which optional objects the sign supports and
+// reads its capacities. Optional objects the sign answers noSuchName for are
+// reported as unsupported.
+func DiscoverCapabilities(dms *gosnmp.GoSNMP, options ...Option) (c Capabilities, err error) {
+	s := newSession(dms, "DiscoverCapabilities", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return c, err
+	}
+
+	for _, object := range []struct {
+		reader d.Reader
+		value  *int
+	}{
+		{d.NumFonts, &c.Fonts},
+		{d.DmsMaxChangeableMsg, &c.MaxChangeableMsg},
+		{d.DmsMaxVolatileMsg, &c.MaxVolatileMsg},
+	} {
+		if *object.value, err = getInt(s, object.reader.ObjectType(), object.reader.Identifier(0)); err != nil {
+			return c, err
+		}
+	}
+
+	// Every sign has the blank messages, so their row tells whether the
+	// optional message table columns exist.
+	blank := d.MemoryBlank.Int()
+	if c.Beacon, err = supported(s, "dmsMessageBeacon", d.DmsMessageBeacon.Identifier(blank, 1)); err != nil {
+		return c, err
+	}
+	if c.PixelService, err = supported(s, "dmsMessagePixelService", d.DmsMessagePixelService.Identifier(blank, 1)); err != nil {
+		return c, err
+	}
+
+	for _, object := range []struct {
+		reader d.Reader
+		value  *int
+	}{
+		{d.DmsGraphicMaxEntries, &c.Graphics},
+		{d.DmsColorScheme, &c.ColorScheme},
+		{d.DmsSupportedMultiTags, &c.SupportedMultiTags},
+		{d.DmsMaxMultiStringLength, &c.MaxMultiStringLength},
+	} {
+		*object.value, err = getInt(s, object.reader.ObjectType(), object.reader.Identifier(0))
+		if err != nil && !errors.Is(err, ErrNoSuchName) {
+			return c, err
+		}
+	}
+
+	// fontStatus and dmsColorScheme were added in NTCIP 1203 v2.
+	c.NTCIPVersion = 1
+	v2, err := supported(s, "fontStatus", d.FontStatus.Identifier(1))
+	if err != nil {
+		return c, err
+	}
+	if v2 || c.ColorScheme != 0 {
+		c.NTCIPVersion = 2
+	}
+	return c, nil
+}
+
+// supported reports whether the sign has the object oid.
+func supported(s *session, objectType, oid string) (bool, error) {
+	_, err := s.getSingle("get "+objectType, oid)
+	if errors.Is(err, ErrNoSuchName) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DiscoverCapabilities runs the DiscoverCapabilities dialog against the
+// client's sign and caches the result until globalSetIDParameter changes,
+// see InvalidateConfiguration: later dialogs run through the client skip
+// the optional objects the sign does not support.
+func (c *Client) DiscoverCapabilities(options ...Option) (Capabilities, error) {
+	if err := c.lock(); err != nil {
+		return Capabilities{}, err
+	}
+	defer c.unlock()
+	options = c.dialogOptions(options)
+	if err := c.checkConfiguration(options); err != nil {
+		return Capabilities{}, err
+	}
+	if capabilities, ok := c.Capabilities(); ok {
+		return capabilities, nil
+	}
+	capabilities, err := DiscoverCapabilities(c.dms, options...)
+	if err != nil {
+		return capabilities, err
+	}
+	c.capabilitiesMu.Lock()
+	c.capabilities = &capabilities
+	c.capabilitiesMu.Unlock()
+	return capabilities, nil
+}
+
+// Capabilities returns the capabilities cached by DiscoverCapabilities.
+func (c *Client) Capabilities() (Capabilities, bool) {
+	c.capabilitiesMu.Lock()
+	defer c.capabilitiesMu.Unlock()
+	if c.capabilities == nil {
+		return Capabilities{}, false
+	}
+	return *c.capabilities, true
+}
@@ -0,0 +1,56 @@
+package dialogs
+
+import (
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_DiscoverCapabilities(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	c := NewClient(sign.GoSNMP())
+
+	if _, ok := c.Capabilities(); ok {
+		t.Fatalf("Capabilities() reported capabilities before discovery")
+	}
+	// A sign without beacons.
+	sign.Delete(d.DmsMessageBeacon.Identifier(d.MemoryBlank.Int(), 1))
+
+	capabilities, err := c.DiscoverCapabilities()
+	if err != nil {
+		t.Fatalf("DiscoverCapabilities() error = %v", err)
+	}
+	want := Capabilities{
+		NTCIPVersion:         2,
+		PixelService:         true,
+		Fonts:                simulator.NumFonts,
+		Graphics:             simulator.MaxGraphics,
+		ColorScheme:          2,
+		SupportedMultiTags:   simulator.SupportedMultiTags,
+		MaxChangeableMsg:     simulator.MaxChangeableMsg,
+		MaxVolatileMsg:       simulator.MaxVolatileMsg,
+		MaxMultiStringLength: 1024,
+	}
+	if capabilities != want {
+		t.Errorf("DiscoverCapabilities() = %+v, want %+v", capabilities, want)
+	}
+	if cached, ok := c.Capabilities(); !ok || cached != want {
+		t.Errorf("Capabilities() = %+v, %v, want the discovered capabilities", cached, ok)
+	}
+	if !capabilities.SupportsMultiTag(5) || capabilities.SupportsMultiTag(20) {
+		t.Errorf("SupportsMultiTag() does not follow dmsSupportedMultiTags")
+	}
+
+	// The beacon is no longer written.
+	if _, err := c.DefiningMessage(d.MemoryChangeable.Int(), 1, "[jp3]TEST", "10.0.0.5", 50, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if beacon, _ := sign.Load(d.DmsMessageBeacon.Identifier(d.MemoryChangeable.Int(), 1)); beacon != 0 {
+		t.Errorf("DefiningMessage() set dmsMessageBeacon to %v on a sign without beacons", beacon)
+	}
+}
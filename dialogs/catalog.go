@@ -0,0 +1,178 @@
+package dialogs
+
+import (
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Catalog maps message keys to operator-facing text in one language. Text may
+// contain placeholders in braces (e.g. "{position}") that are filled in by
+// Localize. Keys missing from a catalog fall back to the English text.
+//
+// The keys are:
+//
+//	activation.<dmsActivateMsgError>    e.g. activation.priority
+//	validation.<dmsValidateMessageError> e.g. validation.beacons
+//	validation.timeout
+//	multi.<dmsMultiSyntaxError>         e.g. multi.fontNotDefined
+//	multi.position                      {error}, {position}
+//	multi.description                   {description}
+//	status.<shortErrorStatus bit>       e.g. status.pixelError
+//	dialog.communication                {target}, {step}
+type Catalog map[string]string
+
+// English is the default catalog.
+var English = Catalog{
+	"activation.other":                 "The sign rejected the message for a reason it did not specify.",
+	"activation.none":                  "The sign rejected the message without reporting an error.",
+	"activation.priority":              "A message with a higher priority is currently displayed.",
+	"activation.messageStatus":         "The message is not valid on the sign.",
+	"activation.messageMemoryType":     "The sign does not support this message memory type.",
+	"activation.messageNumber":         "The message number is not defined on the sign.",
+	"activation.messageCRC":            "The message stored on the sign differs from the requested one.",
+	"activation.syntaxMULTI":           "The message contains a formatting error.",
+	"activation.localMode":             "The sign is under local control.",
+	"activation.centralMode":           "The sign is under central control.",
+	"activation.centralOverrideMode":   "The sign is under central override control.",
+	"validation.other":                 "The sign could not validate the message.",
+	"validation.none":                  "The sign could not validate the message.",
+	"validation.beacons":               "The sign does not support the requested beacon setting.",
+	"validation.pixelService":          "The sign does not support the requested pixel service setting.",
+	"validation.syntaxMULTI":           "The message contains a formatting error.",
+	"validation.timeout":               "The sign did not finish validating the message in time.",
+	"multi.other":                      "Unspecified formatting error",
+	"multi.unsupportedTag":             "A tag is not supported by the sign",
+	"multi.unsupportedTagValue":        "A tag value is not supported by the sign",
+	"multi.textTooBig":                 "The text does not fit on the sign",
+	"multi.fontNotDefined":             "The font is not defined on the sign",
+	"multi.characterNotDefined":        "A character is not defined in the selected font",
+	"multi.fieldDeviceNotExist":        "A field device does not exist",
+	"multi.fieldDeviceError":           "A field device reports an error",
+	"multi.flashRegionError":           "The flashing region cannot be flashed",
+	"multi.tagConflict":                "Tags conflict with each other",
+	"multi.tooManyPages":               "The message has too many pages",
+	"multi.fontVersionID":              "The font version does not match the sign",
+	"multi.graphicID":                  "The graphic version does not match the sign",
+	"multi.graphicNotDefined":          "The graphic is not defined on the sign",
+	"multi.position":                   "{error} at character {position}.",
+	"multi.description":                "Sign reports: {description}",
+	"status.communicationsError":       "Communication failure",
+	"status.powerError":                "Power failure",
+	"status.attachedDeviceError":       "Attached device failure",
+	"status.lampError":                 "Lamp failure",
+	"status.pixelError":                "Pixel failure",
+	"status.photocellError":            "Light sensor failure",
+	"status.messageError":              "Message error",
+	"status.controllerError":           "Controller failure",
+	"status.temperatureWarning":        "Temperature warning",
+	"status.climateControlSystemError": "Climate control failure",
+	"status.criticalTemperatureError":  "Critical temperature, display off",
+	"status.drumSignRotorError":        "Drum rotor failure",
+	"status.doorOpenError":             "Door open",
+	"status.humidityWarning":           "Humidity warning",
+	"dialog.communication":             "The sign at {target} could not be reached ({step}).",
+}
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = map[string]Catalog{"en": English}
+)
+
+// RegisterCatalog installs the translations of a language, identified by a
+// tag such as "fr" or "zh-HK". Registering a language again replaces it.
+func RegisterCatalog(language string, catalog Catalog) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	catalogs[strings.ToLower(language)] = catalog
+}
+
+// Text returns the text of key in language. A region specific language
+// ("fr-CA") falls back to its base language ("fr") and then to English. The
+// key itself is returned when no catalog knows it.
+func Text(language, key string) string {
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+	language = strings.ToLower(language)
+	for _, tag := range []string{language, strings.SplitN(language, "-", 2)[0], "en"} {
+		if text, ok := catalogs[tag][key]; ok {
+			return text
+		}
+	}
+	return key
+}
+
+func fill(text string, values ...string) string {
+	return strings.NewReplacer(values...).Replace(text)
+}
+
+// Localize returns an operator-facing description of an error returned by a
+// dialog in the given language. The programmatic error values are not
+// affected; errors that are not dialog errors are returned as err.Error().
+func Localize(err error, language string) string {
+	if err == nil {
+		return ""
+	}
+
+	var activationError *ActivationError
+	if errors.As(err, &activationError) {
+		return joinSentences(
+			Text(language, "activation."+activationError.ActivateMsgError),
+			localizeMulti(language, activationError.MultiSyntaxError, activationError.MultiSyntaxErrorPosition, activationError.MultiOtherErrorDescription),
+		)
+	}
+
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		key := "validation." + validationError.ValidateMessageError
+		if errors.Is(validationError, ErrValidationTimeout) || validationError.ValidateMessageError == "" {
+			key = "validation.timeout"
+		}
+		return joinSentences(
+			Text(language, key),
+			localizeMulti(language, validationError.MultiSyntaxError, validationError.MultiSyntaxErrorPosition, validationError.MultiOtherErrorDescription),
+		)
+	}
+
+	var dialogError *DialogError
+	if errors.As(err, &dialogError) {
+		return fill(Text(language, "dialog.communication"), "{target}", dialogError.Target, "{step}", dialogError.Step)
+	}
+	return err.Error()
+}
+
+// LocalizeStatus translates the names of the bits set in shortErrorStatus.
+func LocalizeStatus(status d.ShortErrorStatusBits, language string) []string {
+	names := status.Names()
+	localized := make([]string, 0, len(names))
+	for _, name := range names {
+		localized = append(localized, Text(language, "status."+name))
+	}
+	return localized
+}
+
+func localizeMulti(language, syntaxError string, position int, description string) string {
+	if syntaxError == "" {
+		return ""
+	}
+	text := fill(Text(language, "multi.position"),
+		"{error}", Text(language, "multi."+syntaxError),
+		"{position}", strconv.Itoa(position),
+	)
+	if description != "" {
+		text = joinSentences(text, fill(Text(language, "multi.description"), "{description}", description))
+	}
+	return text
+}
+
+func joinSentences(sentences ...string) string {
+	var parts []string
+	for _, sentence := range sentences {
+		if sentence != "" {
+			parts = append(parts, sentence)
+		}
+	}
+	return strings.Join(parts, " ")
+}
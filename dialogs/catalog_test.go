@@ -0,0 +1,70 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+func TestLocalize(t *testing.T) {
+	RegisterCatalog("fr", Catalog{
+		"activation.priority": "Un message de priorité supérieure est affiché.",
+		"multi.position":      "{error} au caractère {position}.",
+	})
+
+	s := &session{dms: &gosnmp.GoSNMP{Target: "10.0.11.41", Port: 161}, dialog: "ActivatingMessage"}
+	tests := []struct {
+		name     string
+		err      error
+		language string
+		want     string
+	}{
+		{
+			name:     "english",
+			err:      s.wrap(&ActivationError{ActivateMsgError: "priority"}, "set dmsActivateMessage"),
+			language: "en",
+			want:     "A message with a higher priority is currently displayed.",
+		},
+		{
+			name:     "regional language falls back to base language",
+			err:      &ActivationError{ActivateMsgError: "priority"},
+			language: "fr-CA",
+			want:     "Un message de priorité supérieure est affiché.",
+		},
+		{
+			name:     "missing key falls back to english",
+			err:      &ValidationError{MessageStatus: 5, ValidateMessageError: "syntaxMULTI", MultiSyntaxError: "fontNotDefined", MultiSyntaxErrorPosition: 3},
+			language: "fr",
+			want:     "The message contains a formatting error. The font is not defined on the sign au caractère 3.",
+		},
+		{
+			name:     "communication",
+			err:      s.wrap(errors.New("request timeout"), "connect"),
+			language: "en",
+			want:     "The sign at 10.0.11.41:161 could not be reached (connect).",
+		},
+		{
+			name:     "foreign error",
+			err:      errors.New("boom"),
+			language: "en",
+			want:     "boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Localize(tt.err, tt.language); got != tt.want {
+				t.Errorf("Localize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizeStatus(t *testing.T) {
+	got := LocalizeStatus(d.ShortErrorPower|d.ShortErrorDoorOpen, "en")
+	if want := []string{"Power failure", "Door open"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LocalizeStatus() = %v, want %v", got, want)
+	}
+}
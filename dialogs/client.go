@@ -0,0 +1,867 @@
+package dialogs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/multi"
+	"github.com/jacobleehei/godms/stmp"
+)
+
+// Client binds the SNMP session of a single sign to a set of default dialog
+// options and keeps the state that outlives a single dialog, such as the
+// activation history.
+type Client struct {
+	dms     *gosnmp.GoSNMP
+	options []Option
+	history *activationHistory
+
+	// mu serializes the dialogs, which share the gosnmp session.
+	mu     sync.Mutex
+	closed int32 // set by Shutdown, read atomically
+
+	deadManMu sync.Mutex
+	deadMan   *time.Timer
+
+	capabilitiesMu sync.Mutex
+	capabilities   *Capabilities
+
+	// cache is the static configuration read by the dialogs, guarded by mu.
+	cache configCache
+
+	// communities are the communities found by WithCommunityFallback,
+	// guarded by mu like the dialogs that use them.
+	communities communities
+
+	// version is the version found by WithVersionFallback, -1 until then,
+	// guarded by mu.
+	version int
+
+	// spans are the spans of the dialogs in flight, started with
+	// WithTracer, guarded by mu and ended by unlock.
+	spans []Span
+
+	// limiter applies the WithRateLimit option of NewClient to all the
+	// dialogs of the client.
+	limiter *rateLimiter
+
+	// conn is the SNMP connection shared by the dialogs, opened by the first
+	// one and closed after idleTimeout, guarded by mu.
+	conn        connection
+	idleTimeout time.Duration
+
+	// link is the state of the link to the sign, updated by the dialogs
+	// through observeLink; linkEvents are the changes not yet passed to the
+	// linkHandlers.
+	linkMu            sync.Mutex
+	link              LinkStatus
+	linkEvents        []LinkEvent
+	linkHandlers      map[int]func(LinkEvent)
+	nextLinkHandler   int
+	reconnectInterval time.Duration
+	probing           bool // a probe of the sign is scheduled
+}
+
+// NewClient returns a Client for the sign reachable through dms. The given
+// options are applied to every dialog run through the client, before the
+// options passed to the dialog call itself.
+func NewClient(dms *gosnmp.GoSNMP, options ...Option) *Client {
+	o := newDialogOptions(options...)
+	return &Client{
+		dms:               dms,
+		options:           options,
+		history:           newActivationHistory(o.historySize),
+		version:           -1,
+		limiter:           newRateLimiter(o.rateLimit),
+		idleTimeout:       o.idleTimeout,
+		reconnectInterval: o.reconnectInterval,
+	}
+}
+
+// ReadOnly reports whether the client never sends a SET, because it was
+// created with WithReadOnly or the package is built with the godms_readonly
+// tag.
+func (c *Client) ReadOnly() bool {
+	return buildReadOnly || newDialogOptions(c.options...).readOnly
+}
+
+// SNMP returns the underlying gosnmp session of the client.
+func (c *Client) SNMP() *gosnmp.GoSNMP { return c.dms }
+
+// Version returns the SNMP version currently used for the sign, i.e. the
+// version that last worked when WithVersionFallback is in effect.
+func (c *Client) Version() gosnmp.SnmpVersion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version >= 0 {
+		return gosnmp.SnmpVersion(c.version)
+	}
+	dms := *c.dms
+	newDialogOptions(c.options...).apply(&dms)
+	return dms.Version
+}
+
+// lock acquires mu for a dialog, unless the client has been shut down.
+func (c *Client) lock() error {
+	if atomic.LoadInt32(&c.closed) != 0 {
+		return ErrClientClosed
+	}
+	c.mu.Lock()
+	if atomic.LoadInt32(&c.closed) != 0 {
+		c.mu.Unlock()
+		return ErrClientClosed
+	}
+	return nil
+}
+
+// unlock ends the spans of the dialogs run since lock and releases mu.
+func (c *Client) unlock() {
+	for i := len(c.spans) - 1; i >= 0; i-- {
+		c.spans[i].End()
+	}
+	c.spans = nil
+	c.release()
+	c.mu.Unlock()
+	c.notifyLink()
+}
+
+func (c *Client) dialogOptions(options []Option) []Option {
+	options = append(append([]Option{}, c.options...), options...)
+	options = append(options, WithInterceptor(c.configInterceptor()), func(o *dialogOptions) {
+		o.communities, o.spans, o.limiter, o.conn = &c.communities, &c.spans, c.limiter, &c.conn
+		o.foundVersion = &c.version
+		o.link = c.observeLink
+	})
+	if capabilities, ok := c.Capabilities(); ok {
+		options = append(options, func(o *dialogOptions) { o.capabilities = &capabilities })
+	}
+	return options
+}
+
+// ActivatingMessage runs the ActivatingMessage dialog against the client's
+// sign and records the attempt in the activation history.
+func (c *Client) ActivatingMessage(
+	duration, priority, messageMemoryType, messageNumber int,
+	options ...Option,
+) (activatingMessageResult, error) {
+	options = c.dialogOptions(options)
+	o := newDialogOptions(options...)
+	if o.duplicateWindow > 0 {
+		options = append(options, func(o *dialogOptions) { o.duplicate = c.duplicateCheck(o.duplicateWindow) })
+	}
+	if err := c.lock(); err != nil {
+		return activatingMessageResult{}, err
+	}
+	result, err := ActivatingMessage(c.dms, duration, priority, messageMemoryType, messageNumber, options...)
+	owner, _ := sourceAddress(c.dms, o)
+	c.unlock()
+	if result.Duplicate || result.DryRun {
+		return result, err
+	}
+	record := ActivationRecord{
+		MessageMemoryType: messageMemoryType,
+		MessageNumber:     messageNumber,
+		MessageCRC:        result.MessageCRC,
+		Duration:          deadManDuration(duration, o.deadMan),
+		Priority:          priority,
+		Owner:             owner,
+		ShortErrorStatus:  result.ShortErrorStatus,
+		Err:               err,
+	}
+	c.history.add(record)
+	if err == nil {
+		c.disarmDeadMan()
+		if o.deadMan > 0 {
+			c.armDeadMan(record, o.deadMan)
+		}
+	}
+	return result, err
+}
+
+// BlankSign runs the BlankSign dialog against the client's sign and records
+// the attempt in the activation history.
+func (c *Client) BlankSign(priority int, options ...Option) (activatingMessageResult, error) {
+	options = c.dialogOptions(options)
+	if err := c.lock(); err != nil {
+		return activatingMessageResult{}, err
+	}
+	result, err := BlankSign(c.dms, priority, options...)
+	o := newDialogOptions(options...)
+	owner, _ := sourceAddress(c.dms, o)
+	c.unlock()
+	if result.DryRun {
+		return result, err
+	}
+	c.history.add(ActivationRecord{
+		MessageMemoryType: d.MemoryBlank.Int(),
+		MessageNumber:     o.blankMessageNumber(priority),
+		Duration:          65535,
+		Priority:          priority,
+		Owner:             owner,
+		ShortErrorStatus:  result.ShortErrorStatus,
+		Err:               err,
+	})
+	if err == nil {
+		c.disarmDeadMan()
+	}
+	return result, err
+}
+
+// DisplayText defines multiString as changeable message messageNumber, owned
+// by the client's source address, and activates it with the given priority
+// and duration (minutes, 65535 for infinite).
+func (c *Client) DisplayText(
+	messageNumber int, multiString string, priority, duration int,
+	options ...Option,
+) (activatingMessageResult, error) {
+	if err := c.lock(); err != nil {
+		return activatingMessageResult{}, err
+	}
+	owner, err := sourceAddress(c.dms, newDialogOptions(c.dialogOptions(options)...))
+	c.mu.Unlock()
+	if err != nil {
+		return activatingMessageResult{}, err
+	}
+	_, err = c.DefiningMessage(d.MemoryChangeable.Int(), messageNumber, multiString, owner, priority, 0, 0, options...)
+	if err != nil {
+		return activatingMessageResult{}, err
+	}
+	return c.ActivatingMessage(duration, priority, d.MemoryChangeable.Int(), messageNumber, options...)
+}
+
+// DefiningMessage runs the DefiningMessage dialog against the client's sign.
+func (c *Client) DefiningMessage(
+	messageMemoryType, messageNumber int,
+	multiString, ownerAddress string, priority int,
+	beacon, pixelService int,
+	options ...Option,
+) (definingMessageResult, error) {
+	if err := c.lock(); err != nil {
+		return definingMessageResult{}, err
+	}
+	defer c.unlock()
+	return DefiningMessage(c.dms, messageMemoryType, messageNumber, multiString, ownerAddress, priority, beacon, pixelService, c.dialogOptions(options)...)
+}
+
+// RetrievingMessage runs the RetrievingMessage dialog against the client's sign.
+func (c *Client) RetrievingMessage(messageMemoryType, messageNumber int, options ...Option) (retrievingResult, error) {
+	if err := c.lock(); err != nil {
+		return retrievingResult{}, err
+	}
+	defer c.unlock()
+	return RetrievingMessage(c.dms, messageMemoryType, messageNumber, c.dialogOptions(options)...)
+}
+
+// RetrievingCurrentMessage runs the RetrievingCurrentMessage dialog against
+// the client's sign.
+func (c *Client) RetrievingCurrentMessage(options ...Option) (CurrentMessage, error) {
+	if err := c.lock(); err != nil {
+		return CurrentMessage{}, err
+	}
+	defer c.unlock()
+	return RetrievingCurrentMessage(c.dms, c.dialogOptions(options)...)
+}
+
+// ListMessages runs the ListMessages dialog against the client's sign.
+func (c *Client) ListMessages(messageMemoryType int, options ...Option) ([]MessageEntry, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return ListMessages(c.dms, messageMemoryType, c.dialogOptions(options)...)
+}
+
+// MemoryUsage runs the MemoryUsage dialog against the client's sign.
+func (c *Client) MemoryUsage(options ...Option) (MemoryReport, error) {
+	if err := c.lock(); err != nil {
+		return MemoryReport{}, err
+	}
+	defer c.unlock()
+	return MemoryUsage(c.dms, c.dialogOptions(options)...)
+}
+
+// SelfTest runs the SelfTest dialog against the client's sign.
+func (c *Client) SelfTest(options ...Option) (SelfTestReport, error) {
+	if err := c.lock(); err != nil {
+		return SelfTestReport{}, err
+	}
+	defer c.unlock()
+	return SelfTest(c.dms, c.dialogOptions(options)...)
+}
+
+// GetSignConfiguration runs the GetSignConfiguration dialog against the
+// client's sign and caches the result until globalSetIDParameter changes,
+// see InvalidateConfiguration.
+func (c *Client) GetSignConfiguration(options ...Option) (SignConfiguration, error) {
+	if err := c.lock(); err != nil {
+		return SignConfiguration{}, err
+	}
+	defer c.unlock()
+	options = c.dialogOptions(options)
+	if err := c.checkConfiguration(options); err != nil {
+		return SignConfiguration{}, err
+	}
+	if c.cache.sign != nil {
+		return *c.cache.sign, nil
+	}
+	config, err := GetSignConfiguration(c.dms, options...)
+	if err == nil {
+		c.cache.sign = &config
+	}
+	return config, err
+}
+
+// GetVMSConfiguration runs the GetVMSConfiguration dialog against the
+// client's sign and caches the result until globalSetIDParameter changes,
+// see InvalidateConfiguration.
+func (c *Client) GetVMSConfiguration(options ...Option) (VMSConfiguration, error) {
+	if err := c.lock(); err != nil {
+		return VMSConfiguration{}, err
+	}
+	defer c.unlock()
+	options = c.dialogOptions(options)
+	if err := c.checkConfiguration(options); err != nil {
+		return VMSConfiguration{}, err
+	}
+	if c.cache.vms != nil {
+		return *c.cache.vms, nil
+	}
+	config, err := GetVMSConfiguration(c.dms, options...)
+	if err == nil {
+		c.cache.vms = &config
+	}
+	return config, err
+}
+
+// ListFonts runs the ListFonts dialog against the client's sign and caches
+// the result until globalSetIDParameter changes or a dialog of the client
+// SETs the fontTable, see InvalidateConfiguration.
+func (c *Client) ListFonts(options ...Option) ([]FontEntry, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	options = c.dialogOptions(options)
+	if err := c.checkConfiguration(options); err != nil {
+		return nil, err
+	}
+	if c.cache.fonts != nil {
+		return append([]FontEntry{}, c.cache.fonts...), nil
+	}
+	fonts, err := ListFonts(c.dms, options...)
+	if err == nil {
+		c.cache.fonts = append([]FontEntry{}, fonts...)
+	}
+	return fonts, err
+}
+
+// GetFontVersionIDs runs the GetFontVersionIDs dialog against the client's
+// sign.
+func (c *Client) GetFontVersionIDs(fontIndexes []int, options ...Option) (map[int]int, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetFontVersionIDs(c.dms, fontIndexes, c.dialogOptions(options)...)
+}
+
+// ConfiguringFont runs the ConfiguringFont dialog against the client's sign.
+func (c *Client) ConfiguringFont(fontIndex int, font Font, options ...Option) (configuringFontResult, error) {
+	if err := c.lock(); err != nil {
+		return configuringFontResult{}, err
+	}
+	defer c.unlock()
+	return ConfiguringFont(c.dms, fontIndex, font, c.dialogOptions(options)...)
+}
+
+// RetrievingFont runs the RetrievingFont dialog against the client's sign.
+func (c *Client) RetrievingFont(fontIndex int, options ...Option) (retrievingFontResult, error) {
+	if err := c.lock(); err != nil {
+		return retrievingFontResult{}, err
+	}
+	defer c.unlock()
+	return RetrievingFont(c.dms, fontIndex, c.dialogOptions(options)...)
+}
+
+// ConfiguringGraphic runs the ConfiguringGraphic dialog against the client's
+// sign.
+func (c *Client) ConfiguringGraphic(graphicIndex int, graphic Graphic, options ...Option) (configuringGraphicResult, error) {
+	if err := c.lock(); err != nil {
+		return configuringGraphicResult{}, err
+	}
+	defer c.unlock()
+	return ConfiguringGraphic(c.dms, graphicIndex, graphic, c.dialogOptions(options)...)
+}
+
+// ListGraphics runs the ListGraphics dialog against the client's sign.
+func (c *Client) ListGraphics(options ...Option) ([]GraphicEntry, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return ListGraphics(c.dms, c.dialogOptions(options)...)
+}
+
+// VerifyingGraphic runs the VerifyingGraphic dialog against the client's
+// sign.
+func (c *Client) VerifyingGraphic(graphicIndex int, graphic Graphic, options ...Option) (verifyingGraphicResult, error) {
+	if err := c.lock(); err != nil {
+		return verifyingGraphicResult{}, err
+	}
+	defer c.unlock()
+	return VerifyingGraphic(c.dms, graphicIndex, graphic, c.dialogOptions(options)...)
+}
+
+// GetBrightness runs the GetBrightness dialog against the client's sign.
+func (c *Client) GetBrightness(options ...Option) (Brightness, error) {
+	if err := c.lock(); err != nil {
+		return Brightness{}, err
+	}
+	defer c.unlock()
+	return GetBrightness(c.dms, c.dialogOptions(options)...)
+}
+
+// SetBrightness runs the SetBrightness dialog against the client's sign.
+func (c *Client) SetBrightness(mode, level int, options ...Option) (Brightness, error) {
+	if err := c.lock(); err != nil {
+		return Brightness{}, err
+	}
+	defer c.unlock()
+	return SetBrightness(c.dms, mode, level, c.dialogOptions(options)...)
+}
+
+// GetBrightnessTable runs the GetBrightnessTable dialog against the client's
+// sign.
+func (c *Client) GetBrightnessTable(options ...Option) ([]BrightnessLevel, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetBrightnessTable(c.dms, c.dialogOptions(options)...)
+}
+
+// SetBrightnessTable runs the SetBrightnessTable dialog against the client's
+// sign.
+func (c *Client) SetBrightnessTable(levels []BrightnessLevel, options ...Option) error {
+	if err := c.lock(); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return SetBrightnessTable(c.dms, levels, c.dialogOptions(options)...)
+}
+
+// GetPhotocellStatus runs the GetPhotocellStatus dialog against the client's
+// sign.
+func (c *Client) GetPhotocellStatus(options ...Option) (PhotocellStatus, error) {
+	if err := c.lock(); err != nil {
+		return PhotocellStatus{}, err
+	}
+	defer c.unlock()
+	return GetPhotocellStatus(c.dms, c.dialogOptions(options)...)
+}
+
+// PixelTest runs the PixelTest dialog against the client's sign.
+func (c *Client) PixelTest(options ...Option) (pixelTestResult, error) {
+	if err := c.lock(); err != nil {
+		return pixelTestResult{}, err
+	}
+	defer c.unlock()
+	return PixelTest(c.dms, c.dialogOptions(options)...)
+}
+
+// LampTest runs the LampTest dialog against the client's sign.
+func (c *Client) LampTest(options ...Option) (lampTestResult, error) {
+	if err := c.lock(); err != nil {
+		return lampTestResult{}, err
+	}
+	defer c.unlock()
+	return LampTest(c.dms, c.dialogOptions(options)...)
+}
+
+// GetClimateStatus runs the GetClimateStatus dialog against the client's
+// sign.
+func (c *Client) GetClimateStatus(options ...Option) (ClimateStatus, error) {
+	if err := c.lock(); err != nil {
+		return ClimateStatus{}, err
+	}
+	defer c.unlock()
+	return GetClimateStatus(c.dms, c.dialogOptions(options)...)
+}
+
+// FanTest runs the FanTest dialog against the client's sign.
+func (c *Client) FanTest(options ...Option) (fanTestResult, error) {
+	if err := c.lock(); err != nil {
+		return fanTestResult{}, err
+	}
+	defer c.unlock()
+	return FanTest(c.dms, c.dialogOptions(options)...)
+}
+
+// GetPowerStatus runs the GetPowerStatus dialog against the client's sign.
+func (c *Client) GetPowerStatus(options ...Option) (PowerStatus, error) {
+	if err := c.lock(); err != nil {
+		return PowerStatus{}, err
+	}
+	defer c.unlock()
+	return GetPowerStatus(c.dms, c.dialogOptions(options)...)
+}
+
+// GetSignHealth runs the GetSignHealth dialog against the client's sign.
+func (c *Client) GetSignHealth(options ...Option) (SignHealthReport, error) {
+	if err := c.lock(); err != nil {
+		return SignHealthReport{}, err
+	}
+	defer c.unlock()
+	return GetSignHealth(c.dms, c.dialogOptions(options)...)
+}
+
+// ResetController runs the ResetController dialog against the client's sign.
+func (c *Client) ResetController(options ...Option) (resetControllerResult, error) {
+	if err := c.lock(); err != nil {
+		return resetControllerResult{}, err
+	}
+	defer c.unlock()
+	return ResetController(c.dms, c.dialogOptions(options)...)
+}
+
+// GetControlMode runs the GetControlMode dialog against the client's sign.
+func (c *Client) GetControlMode(options ...Option) (ControlMode, error) {
+	if err := c.lock(); err != nil {
+		return ControlMode{}, err
+	}
+	defer c.unlock()
+	return GetControlMode(c.dms, c.dialogOptions(options)...)
+}
+
+// SetControlMode runs the SetControlMode dialog against the client's sign.
+func (c *Client) SetControlMode(mode int, options ...Option) (ControlMode, error) {
+	if err := c.lock(); err != nil {
+		return ControlMode{}, err
+	}
+	defer c.unlock()
+	return SetControlMode(c.dms, mode, c.dialogOptions(options)...)
+}
+
+// ClearMessageMemory runs the ClearMessageMemory dialog against the client's
+// sign.
+func (c *Client) ClearMessageMemory(messageMemoryType int, options ...Option) (clearMessageMemoryResult, error) {
+	if err := c.lock(); err != nil {
+		return clearMessageMemoryResult{}, err
+	}
+	defer c.unlock()
+	return ClearMessageMemory(c.dms, messageMemoryType, c.dialogOptions(options)...)
+}
+
+// GetMessageCapacity runs the GetMessageCapacity dialog against the client's
+// sign.
+func (c *Client) GetMessageCapacity(messageMemoryType int, options ...Option) (MessageCapacity, error) {
+	if err := c.lock(); err != nil {
+		return MessageCapacity{}, err
+	}
+	defer c.unlock()
+	return GetMessageCapacity(c.dms, messageMemoryType, c.dialogOptions(options)...)
+}
+
+// AllocateMessageSlot runs the AllocateMessageSlot dialog against the
+// client's sign.
+func (c *Client) AllocateMessageSlot(messageMemoryType int, options ...Option) (int, error) {
+	if err := c.lock(); err != nil {
+		return 0, err
+	}
+	defer c.unlock()
+	return AllocateMessageSlot(c.dms, messageMemoryType, c.dialogOptions(options)...)
+}
+
+// BackupLibrary runs the BackupLibrary dialog against the client's sign.
+func (c *Client) BackupLibrary(options ...Option) (Library, error) {
+	if err := c.lock(); err != nil {
+		return Library{}, err
+	}
+	defer c.unlock()
+	return BackupLibrary(c.dms, c.dialogOptions(options)...)
+}
+
+// SyncLibrary runs the SyncLibrary dialog against the client's sign.
+func (c *Client) SyncLibrary(library Library, options ...Option) (syncLibraryResult, error) {
+	if err := c.lock(); err != nil {
+		return syncLibraryResult{}, err
+	}
+	defer c.unlock()
+	return SyncLibrary(c.dms, library, c.dialogOptions(options)...)
+}
+
+// DefineMessage runs the DefineMessage dialog against the client's sign.
+func (c *Client) DefineMessage(message d.Message, options ...Option) (definingMessageResult, error) {
+	return c.DefiningMessage(message.MemoryType.Int(), message.Number,
+		message.MULTI, message.Owner, message.Priority,
+		message.Beacon, message.PixelService, options...)
+}
+
+// ActivateMessage runs the ActivateMessage dialog against the client's sign
+// and records the attempt in the activation history.
+func (c *Client) ActivateMessage(message d.Message, duration, priority int, options ...Option) (activatingMessageResult, error) {
+	return c.ActivatingMessage(duration, priority, message.MemoryType.Int(), message.Number, options...)
+}
+
+// GetMessageConfig runs the GetMessageConfig dialog against the client's
+// sign.
+func (c *Client) GetMessageConfig(options ...Option) (MessageConfig, error) {
+	if err := c.lock(); err != nil {
+		return MessageConfig{}, err
+	}
+	defer c.unlock()
+	return GetMessageConfig(c.dms, c.dialogOptions(options)...)
+}
+
+// SetMessageConfig runs the SetMessageConfig dialog against the client's
+// sign.
+func (c *Client) SetMessageConfig(config MessageConfig, options ...Option) (MessageConfig, error) {
+	if err := c.lock(); err != nil {
+		return MessageConfig{}, err
+	}
+	defer c.unlock()
+	return SetMessageConfig(c.dms, config, c.dialogOptions(options)...)
+}
+
+// GetDefaults runs the GetDefaults dialog against the client's sign.
+func (c *Client) GetDefaults(options ...Option) (Defaults, error) {
+	if err := c.lock(); err != nil {
+		return Defaults{}, err
+	}
+	defer c.unlock()
+	return GetDefaults(c.dms, c.dialogOptions(options)...)
+}
+
+// SetDefaults runs the SetDefaults dialog against the client's sign.
+func (c *Client) SetDefaults(defaults Defaults, options ...Option) (Defaults, error) {
+	if err := c.lock(); err != nil {
+		return Defaults{}, err
+	}
+	defer c.unlock()
+	return SetDefaults(c.dms, defaults, c.dialogOptions(options)...)
+}
+
+// GetColorScheme runs the GetColorScheme dialog against the client's sign.
+func (c *Client) GetColorScheme(options ...Option) (multi.ColorScheme, error) {
+	if err := c.lock(); err != nil {
+		return 0, err
+	}
+	defer c.unlock()
+	return GetColorScheme(c.dms, c.dialogOptions(options)...)
+}
+
+// DefineDynamicObject runs the DefineDynamicObject dialog against the
+// client's sign.
+func (c *Client) DefineDynamicObject(object stmp.Object, options ...Option) error {
+	if err := c.lock(); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return DefineDynamicObject(c.dms, object, c.dialogOptions(options)...)
+}
+
+// GetDynamicObject runs the GetDynamicObject dialog against the client's
+// sign.
+func (c *Client) GetDynamicObject(number int, options ...Option) ([]string, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetDynamicObject(c.dms, number, c.dialogOptions(options)...)
+}
+
+// PollDynamicObject runs the PollDynamicObject dialog against the client's
+// sign.
+func (c *Client) PollDynamicObject(object stmp.Object, options ...Option) ([]interface{}, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return PollDynamicObject(c.dms, object, c.dialogOptions(options)...)
+}
+
+// SetNotificationTarget runs the SetNotificationTarget dialog against the
+// client's sign.
+func (c *Client) SetNotificationTarget(target NotificationTarget, options ...Option) (NotificationTarget, error) {
+	if err := c.lock(); err != nil {
+		return NotificationTarget{}, err
+	}
+	defer c.unlock()
+	return SetNotificationTarget(c.dms, target, c.dialogOptions(options)...)
+}
+
+// GetNotificationTarget runs the GetNotificationTarget dialog against the
+// client's sign.
+func (c *Client) GetNotificationTarget(name string, options ...Option) (NotificationTarget, error) {
+	if err := c.lock(); err != nil {
+		return NotificationTarget{}, err
+	}
+	defer c.unlock()
+	return GetNotificationTarget(c.dms, name, c.dialogOptions(options)...)
+}
+
+// DeleteNotificationTarget runs the DeleteNotificationTarget dialog against
+// the client's sign.
+func (c *Client) DeleteNotificationTarget(name string, options ...Option) error {
+	if err := c.lock(); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return DeleteNotificationTarget(c.dms, name, c.dialogOptions(options)...)
+}
+
+// GetEventLog runs the GetEventLog dialog against the client's sign.
+func (c *Client) GetEventLog(options ...Option) ([]LogEvent, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetEventLog(c.dms, c.dialogOptions(options)...)
+}
+
+// GetEventClasses runs the GetEventClasses dialog against the client's sign.
+func (c *Client) GetEventClasses(options ...Option) ([]EventClass, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetEventClasses(c.dms, c.dialogOptions(options)...)
+}
+
+// SetEventClass runs the SetEventClass dialog against the client's sign.
+func (c *Client) SetEventClass(class EventClass, options ...Option) (EventClass, error) {
+	if err := c.lock(); err != nil {
+		return EventClass{}, err
+	}
+	defer c.unlock()
+	return SetEventClass(c.dms, class, c.dialogOptions(options)...)
+}
+
+// ClearEventClass runs the ClearEventClass dialog against the client's sign.
+func (c *Client) ClearEventClass(class int, before time.Time, options ...Option) error {
+	if err := c.lock(); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return ClearEventClass(c.dms, class, before, c.dialogOptions(options)...)
+}
+
+// GetEventConfigs runs the GetEventConfigs dialog against the client's sign.
+func (c *Client) GetEventConfigs(options ...Option) ([]EventConfig, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetEventConfigs(c.dms, c.dialogOptions(options)...)
+}
+
+// SetEventConfig runs the SetEventConfig dialog against the client's sign.
+func (c *Client) SetEventConfig(config EventConfig, options ...Option) (EventConfig, error) {
+	if err := c.lock(); err != nil {
+		return EventConfig{}, err
+	}
+	defer c.unlock()
+	return SetEventConfig(c.dms, config, c.dialogOptions(options)...)
+}
+
+// SyncTime runs the SyncTime dialog against the client's sign.
+func (c *Client) SyncTime(options ...Option) (TimeSync, error) {
+	if err := c.lock(); err != nil {
+		return TimeSync{}, err
+	}
+	defer c.unlock()
+	return SyncTime(c.dms, c.dialogOptions(options)...)
+}
+
+// GetTimeZone runs the GetTimeZone dialog against the client's sign.
+func (c *Client) GetTimeZone(options ...Option) (TimeZone, error) {
+	if err := c.lock(); err != nil {
+		return TimeZone{}, err
+	}
+	defer c.unlock()
+	return GetTimeZone(c.dms, c.dialogOptions(options)...)
+}
+
+// SetTimeZone runs the SetTimeZone dialog against the client's sign.
+func (c *Client) SetTimeZone(zone TimeZone, options ...Option) (TimeZone, error) {
+	if err := c.lock(); err != nil {
+		return TimeZone{}, err
+	}
+	defer c.unlock()
+	return SetTimeZone(c.dms, zone, c.dialogOptions(options)...)
+}
+
+// GetInventory runs the GetInventory dialog against the client's sign.
+func (c *Client) GetInventory(options ...Option) (Inventory, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetInventory(c.dms, c.dialogOptions(options)...)
+}
+
+// GetScheduleSize runs the GetScheduleSize dialog against the client's sign.
+func (c *Client) GetScheduleSize(options ...Option) (ScheduleSize, error) {
+	if err := c.lock(); err != nil {
+		return ScheduleSize{}, err
+	}
+	defer c.unlock()
+	return GetScheduleSize(c.dms, c.dialogOptions(options)...)
+}
+
+// GetSchedule runs the GetSchedule dialog against the client's sign.
+func (c *Client) GetSchedule(options ...Option) (Schedule, error) {
+	if err := c.lock(); err != nil {
+		return Schedule{}, err
+	}
+	defer c.unlock()
+	return GetSchedule(c.dms, c.dialogOptions(options)...)
+}
+
+// SetSchedule runs the SetSchedule dialog against the client's sign.
+func (c *Client) SetSchedule(schedule Schedule, options ...Option) (Schedule, error) {
+	if err := c.lock(); err != nil {
+		return Schedule{}, err
+	}
+	defer c.unlock()
+	return SetSchedule(c.dms, schedule, c.dialogOptions(options)...)
+}
+
+// GetActions runs the GetActions dialog against the client's sign.
+func (c *Client) GetActions(options ...Option) ([]ScheduleAction, error) {
+	if err := c.lock(); err != nil {
+		return nil, err
+	}
+	defer c.unlock()
+	return GetActions(c.dms, c.dialogOptions(options)...)
+}
+
+// GetAction runs the GetAction dialog against the client's sign.
+func (c *Client) GetAction(index int, options ...Option) (ScheduleAction, error) {
+	if err := c.lock(); err != nil {
+		return ScheduleAction{}, err
+	}
+	defer c.unlock()
+	return GetAction(c.dms, index, c.dialogOptions(options)...)
+}
+
+// SetAction runs the SetAction dialog against the client's sign.
+func (c *Client) SetAction(action ScheduleAction, options ...Option) (ScheduleAction, error) {
+	if err := c.lock(); err != nil {
+		return ScheduleAction{}, err
+	}
+	defer c.unlock()
+	return SetAction(c.dms, action, c.dialogOptions(options)...)
+}
+
+// DeleteAction runs the DeleteAction dialog against the client's sign.
+func (c *Client) DeleteAction(index int, options ...Option) error {
+	if err := c.lock(); err != nil {
+		return err
+	}
+	defer c.unlock()
+	return DeleteAction(c.dms, index, c.dialogOptions(options)...)
+}
@@ -0,0 +1,130 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// ClimateStatus holds the temperatures and humidity of a sign, the failed
+// fans and the climate flags of shortErrorStatus.
+type ClimateStatus struct {
+	CtrlCabinetMin int // tempMinCtrlCabinet in degrees Celsius
+	CtrlCabinetMax int // tempMaxCtrlCabinet in degrees Celsius
+	AmbientMin     int // tempMinAmbient in degrees Celsius
+	AmbientMax     int // tempMaxAmbient in degrees Celsius
+	SignHousingMin int // tempMinSignHousing in degrees Celsius
+	SignHousingMax int // tempMaxSignHousing in degrees Celsius
+	Humidity       int // tempHumiditySignHousing in percent, -1 if not supported
+
+	FailedFans []int // fans set in fanFailures, from 1
+
+	TemperatureWarning  bool // the sign is outside its normal temperature range
+	CriticalTemperature bool // the sign blanked or will blank due to temperature
+	HumidityWarning     bool
+	ClimateControlError bool // a fan, heater or air conditioner failed
+}
+
+// GetClimateStatus reads the temperatures, the humidity, fanFailures as
+// updated by the last fan test, and the climate flags of shortErrorStatus.
+func GetClimateStatus(dms *gosnmp.GoSNMP, options ...Option) (ClimateStatus, error) {
+	s := newSession(dms, "GetClimateStatus", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return ClimateStatus{}, err
+	}
+	return getClimateStatus(s)
+}
+
+func getClimateStatus(s *session) (status ClimateStatus, err error) {
+	values := []struct {
+		object d.Reader
+		value  *int
+	}{
+		{d.TempMinCtrlCabinet, &status.CtrlCabinetMin},
+		{d.TempMaxCtrlCabinet, &status.CtrlCabinetMax},
+		{d.TempMinAmbient, &status.AmbientMin},
+		{d.TempMaxAmbient, &status.AmbientMax},
+		{d.TempMinSignHousing, &status.SignHousingMin},
+		{d.TempMaxSignHousing, &status.SignHousingMax},
+	}
+	oids := make([]string, 0, len(values)+2)
+	for _, v := range values {
+		oids = append(oids, v.object.Identifier(0))
+	}
+	oids = append(oids, d.FanFailures.Identifier(0), d.ShortErrorStatus.Identifier(0))
+	getResults, err := s.get("get climate status", oids...)
+	if err != nil {
+		return status, err
+	}
+	for _, variable := range getResults.Variables {
+		for _, v := range values {
+			if oidEqual(variable.Name, v.object.Identifier(0)) {
+				*v.value, _ = variable.Value.(int)
+			}
+		}
+		switch {
+		case oidEqual(variable.Name, d.FanFailures.Identifier(0)):
+			bitmap, _ := variable.Value.([]byte)
+			status.FailedFans = bitmapNumbers(bitmap)
+		case oidEqual(variable.Name, d.ShortErrorStatus.Identifier(0)):
+			bits, _ := variable.Value.(int)
+			shortError := d.ShortErrorStatusBits(bits)
+			status.TemperatureWarning = shortError.TemperatureWarning()
+			status.CriticalTemperature = shortError.CriticalTemperature()
+			status.HumidityWarning = shortError.HumidityWarning()
+			status.ClimateControlError = shortError.ClimateControlError()
+		}
+	}
+
+	// tempHumiditySignHousing is not supported by every sign.
+	status.Humidity = -1
+	humidity, err := s.getSingle("get tempHumiditySignHousing", d.TempHumiditySignHousing.Identifier(0))
+	if err != nil && !errors.Is(err, ErrNoSuchName) {
+		return status, err
+	}
+	if err == nil {
+		status.Humidity, _ = humidity.Value.(int)
+	}
+	return status, nil
+}
+
+type fanTestResult struct {
+	FailedFans []int // fans set in fanFailures, from 1
+	Timings    []StepTiming
+}
+
+// FanTest runs the fan test of the sign: fanTestActivation is SET to 'test'
+// and polled until the sign sets it back to 'noTest', bounded by
+// WithTestTimeout, then fanFailures is read.
+func FanTest(dms *gosnmp.GoSNMP, options ...Option) (result fanTestResult, err error) {
+	s := newSession(dms, "FanTest", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	if err = runTest(s, d.FanTestActivation); err != nil {
+		return result, err
+	}
+	failures, err := s.getSingle("get fanFailures", d.FanFailures.Identifier(0))
+	if err != nil {
+		return result, err
+	}
+	bitmap, _ := failures.Value.([]byte)
+	result.FailedFans = bitmapNumbers(bitmap)
+	return result, nil
+}
+
+// bitmapNumbers returns the numbers of the bits set in a failure bitmap, in
+// which bit 0 of the first octet is number 1.
+func bitmapNumbers(bitmap []byte) []int {
+	var numbers []int
+	for i := 0; i < len(bitmap)*8; i++ {
+		if bitmap[i/8]&(1<<(i%8)) != 0 {
+			numbers = append(numbers, i+1)
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,55 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetClimateStatus(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	sign.FailFan(3)
+	sign.Store(d.TempMaxSignHousing.Identifier(0), gosnmp.Integer, 71)
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, 1<<9|1<<10)
+
+	fans, err := FanTest(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fans.FailedFans, []int{3}) {
+		t.Errorf("FanTest() = %v, want fan 3", fans.FailedFans)
+	}
+
+	status, err := GetClimateStatus(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ClimateStatus{
+		CtrlCabinetMin:      24,
+		CtrlCabinetMax:      27,
+		AmbientMin:          18,
+		AmbientMax:          19,
+		SignHousingMin:      25,
+		SignHousingMax:      71,
+		Humidity:            40,
+		FailedFans:          []int{3},
+		TemperatureWarning:  true,
+		ClimateControlError: true,
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("GetClimateStatus() = %+v, want %+v", status, want)
+	}
+
+	sign.Delete(d.TempHumiditySignHousing.Identifier(0))
+	if status, err := GetClimateStatus(dms); err != nil || status.Humidity != -1 {
+		t.Errorf("GetClimateStatus() without humidity = %+v, %v", status, err)
+	}
+}
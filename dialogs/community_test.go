@@ -0,0 +1,53 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestCommunities(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.SetCommunities("ntcip-read", "ntcip-write")
+
+	dms := sign.GoSNMP()
+	dms.Timeout = 100 * time.Millisecond
+	if _, err := GetControlMode(dms); err == nil {
+		t.Fatalf("GetControlMode() with an unknown community expected an error")
+	}
+
+	client := NewClient(dms, WithCommunities("ntcip-read", "ntcip-write"))
+	if _, err := client.SetControlMode(d.ControlCentral.Int()); err != nil {
+		t.Errorf("SetControlMode() with read and write communities error = %v", err)
+	}
+
+	client = NewClient(dms, WithCommunityFallback("ntcip-read", "ntcip-write"))
+	requests := 0
+	dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	if _, err := client.SetControlMode(d.ControlCentral.Int()); err != nil {
+		t.Fatalf("SetControlMode() with fallback communities error = %v", err)
+	}
+	// public fails for the SET, then ntcip-read and ntcip-write are tried;
+	// public fails for the GET, then ntcip-read works.
+	if requests != 5 {
+		t.Errorf("SetControlMode() sent %d requests, want 5", requests)
+	}
+
+	requests = 0
+	if _, err := client.SetControlMode(d.ControlCentral.Int()); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("SetControlMode() sent %d requests with the communities found, want 2", requests)
+	}
+	if dms.Community != "public" {
+		t.Errorf("gosnmp session community = %q, want public kept", dms.Community)
+	}
+}
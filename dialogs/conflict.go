@@ -0,0 +1,73 @@
+package dialogs
+
+import (
+	"fmt"
+
+	d "github.com/jacobleehei/godms"
+)
+
+// ConflictError is returned by the activation dialogs run with
+// WithConflictCheck when the sign displays a message that the activation
+// would replace against the wishes of its owner: one with a run-time priority
+// above the activation priority, or one activated by another management
+// station, e.g. a police message.
+type ConflictError struct {
+	Current  CurrentMessage // the message displayed
+	Owner    string         // dmsMsgRequesterID.0 of the displayed message
+	Priority int            // activation priority of the refused activation
+}
+
+func (e *ConflictError) Error() string {
+	if e.Current.RunTimePriority > e.Priority {
+		return fmt.Sprintf("message of %s displayed at run-time priority %d, above activation priority %d",
+			e.Owner, e.Current.RunTimePriority, e.Priority)
+	}
+	return fmt.Sprintf("message of %s displayed", e.Owner)
+}
+
+// Is reports whether target is a *ConflictError, so that errors.Is(err,
+// ErrConflict) matches any conflict.
+func (e *ConflictError) Is(target error) bool {
+	_, ok := target.(*ConflictError)
+	return ok
+}
+
+// ErrConflict matches any *ConflictError with errors.Is.
+var ErrConflict = &ConflictError{}
+
+// conflict returns the conflict between current and an activation with the
+// given priority by owner, nil if there is none. A blank message is only a
+// conflict by its priority, as there is nothing of another owner to replace.
+func conflict(current CurrentMessage, priority int, owner string) *ConflictError {
+	requester := current.RequesterID
+	if requester == "0.0.0.0" {
+		requester = ""
+	}
+	otherOwner := requester != "" && requester != owner && current.MessageMemoryType != d.MemoryBlank.Int()
+	if current.RunTimePriority <= priority && !otherOwner {
+		return nil
+	}
+	return &ConflictError{Current: current, Owner: requester, Priority: priority}
+}
+
+// checkConflict reads the message displayed and passes a conflict with an
+// activation at priority to the WithConflictCheck callback, returning the
+// conflict itself when there is no callback.
+func checkConflict(s *session, priority int) error {
+	current, err := retrieveCurrentMessage(s)
+	if err != nil {
+		return err
+	}
+	requester, err := s.sourceAddress()
+	if err != nil {
+		return s.wrap(err, "check conflict")
+	}
+	c := conflict(current, priority, requester)
+	if c == nil {
+		return nil
+	}
+	if s.options.onConflict != nil {
+		return s.options.onConflict(c)
+	}
+	return s.wrap(c, "check conflict", d.DmsMsgRequesterID.Identifier(0))
+}
@@ -0,0 +1,51 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestConflictCheck(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for _, number := range []int{1, 2} {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), number, "[jp3]TEST", "10.0.0.5", 100, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A police message activated from another management station.
+	if _, err := ActivatingMessage(dms, 65535, 100, d.MemoryChangeable.Int(), 1, WithSourceAddress("10.0.0.9")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ActivatingMessage(dms, 65535, 200, d.MemoryChangeable.Int(), 2, WithSourceAddress("10.0.0.5"), WithConflictCheck(nil))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) || conflict.Owner != "10.0.0.9" {
+		t.Fatalf("ActivatingMessage() error = %v, want a conflict with 10.0.0.9", err)
+	}
+	if current, err := RetrievingCurrentMessage(dms); err != nil || current.MessageNumber != 1 {
+		t.Errorf("RetrievingCurrentMessage() = %+v, %v after a conflict, want message 1 still displayed", current, err)
+	}
+
+	var warned *ConflictError
+	warn := func(c *ConflictError) error { warned = c; return nil }
+	if _, err := ActivatingMessage(dms, 65535, 200, d.MemoryChangeable.Int(), 2, WithSourceAddress("10.0.0.5"), WithConflictCheck(warn)); err != nil {
+		t.Fatalf("ActivatingMessage() with a warning callback error = %v", err)
+	}
+	if warned == nil || warned.Owner != "10.0.0.9" {
+		t.Errorf("WithConflictCheck() callback got %v, want a conflict with 10.0.0.9", warned)
+	}
+
+	// Our own message at a lower priority is no conflict.
+	if _, err := ActivatingMessage(dms, 65535, 200, d.MemoryChangeable.Int(), 1, WithSourceAddress("10.0.0.5"), WithConflictCheck(nil)); err != nil {
+		t.Errorf("ActivatingMessage() over our own message error = %v", err)
+	}
+}
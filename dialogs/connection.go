@@ -0,0 +1,88 @@
+package dialogs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// connection is the SNMP connection a Client keeps open between its
+// dialogs, guarded by the mu of the client.
+type connection struct {
+	key   string // the parameters the connection was opened with, "" if closed
+	owned bool   // the connection was opened by the client, not given by WithPMPP
+	used  time.Time
+	idle  *time.Timer
+}
+
+// WithIdleTimeout makes a Client close its SNMP connection once no dialog
+// has used it for timeout, 1 minute by default; the next dialog opens it
+// again. 0 keeps the connection open until Close or Shutdown.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(o *dialogOptions) { o.idleTimeout = timeout }
+}
+
+// connectionKey returns the parameters of the connection of s: a dialog with
+// other ones needs a connection of its own.
+func (s *session) connectionKey() string {
+	return fmt.Sprintf("%s %s %d %s %p %t", s.dms.Transport, s.dms.Target, s.dms.Port, s.dms.LocalAddr, s.options.pmpp, s.options.control != nil)
+}
+
+// reuse reports whether the connection of the client can carry the dialog
+// of s. Otherwise the connection is closed, unless it is not the client's
+// own, and true is returned once connected is called.
+func (c *connection) reuse(s *session) bool {
+	if c.key != "" && c.key == s.connectionKey() && s.dms.Conn != nil {
+		return true
+	}
+	if c.key != "" && c.owned && s.dms.Conn != nil {
+		s.dms.Conn.Close()
+	}
+	c.key = ""
+	return false
+}
+
+// connected records the connection opened for s.
+func (c *connection) connected(s *session) {
+	c.key = s.connectionKey()
+	c.owned = s.options.pmpp == nil
+}
+
+// release starts the idle timeout of the connection at the end of a dialog.
+// It is called with the mu of the client held.
+func (c *Client) release() {
+	if c.conn.key == "" {
+		return
+	}
+	if c.linkDown() {
+		c.disconnect()
+		return
+	}
+	c.conn.used = time.Now()
+	timeout := c.idleTimeout
+	if timeout <= 0 {
+		return
+	}
+	if c.conn.idle != nil {
+		c.conn.idle.Stop()
+	}
+	c.conn.idle = time.AfterFunc(timeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.conn.key != "" && time.Since(c.conn.used) >= timeout {
+			c.disconnect()
+		}
+	})
+}
+
+// disconnect closes the idle connection of the client, unless it was given
+// by WithPMPP. It is called with mu held.
+func (c *Client) disconnect() {
+	c.closeConn()
+}
+
+// Close shuts the client down like Shutdown, waiting for the dialog in
+// flight to finish before closing the SNMP connection.
+func (c *Client) Close() error {
+	return c.Shutdown(context.Background())
+}
@@ -0,0 +1,57 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/simulator"
+	"github.com/pkg/errors"
+)
+
+func TestClient_connection(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	dms := sign.GoSNMP()
+	client := NewClient(dms, WithIdleTimeout(50*time.Millisecond))
+	if dms.Conn != nil {
+		t.Fatal("NewClient connected, want a lazy connection")
+	}
+	if _, err := client.GetAction(1); err != nil {
+		t.Fatal(err)
+	}
+	conn := dms.Conn
+	if _, err := client.GetInventory(); err != nil {
+		t.Fatal(err)
+	}
+	if dms.Conn != conn {
+		t.Error("the second dialog connected again, want the connection reused")
+	}
+	if _, err := client.GetAction(1, WithTransport("udp", dms.Port)); err != nil || dms.Conn != conn {
+		t.Errorf("GetAction() with the same transport: %v, connection reused %t", err, dms.Conn == conn)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	client.mu.Lock()
+	idle := client.conn.key == ""
+	client.mu.Unlock()
+	if !idle {
+		t.Error("connection still open after the idle timeout")
+	}
+	if _, err := client.GetAction(1); err != nil {
+		t.Fatal(err)
+	}
+	if dms.Conn == conn {
+		t.Error("dialog after the idle timeout reused the closed connection")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetAction(1); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("GetAction() after Close error = %v, want ErrClientClosed", err)
+	}
+}
@@ -2,7 +2,7 @@ package dialogs
 
 import (
 	"log"
-	"strings"
+	"net"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,12 +18,16 @@ defined in the following subsections.
 **********************************************************************************************/
 
 type activatingMessageResult struct {
-	ShortErrorStatus              []string
+	MessageCRC                    int
+	Duplicate                     bool // coalesced with an identical activation, nothing was sent
+	DryRun                        bool // run with WithDryRun, nothing was sent
+	ShortErrorStatus              d.ShortErrorStatusBits
 	DmsActivateMsgError           string
-	DmsActivateErrorMsgCode       int
+	DmsActivateErrorMsgCode       []byte
 	DmsMultiSyntaxError           string
 	DmsMultiSyntaxErrorPosition   int
 	DmsMultiOtherErrorDescription string
+	Timings                       []StepTiming // time spent in each step of the dialog
 }
 
 func ActivatingMessage(
@@ -39,10 +43,18 @@ func ActivatingMessage(
 	//    - message source address
 	// 	also feel free to See Clause 4.4.6.4 from https://www.ntcip.org/file/2018/11/NTCIP1203v03f.pdf
 	duration, priority, messageMemoryType, messageNumber int,
+	options ...Option,
 ) (activeResult activatingMessageResult, err error) {
-	if err = dms.Connect(); err != nil {
+	s := newSession(dms, "ActivatingMessage", options)
+	defer s.close()
+	defer func() { activeResult.Timings = s.timings }()
+	if err = checkPriority("activation priority", priority); err != nil {
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
+	}
+	if err = s.connect(); err != nil {
 		return
 	}
+	duration = deadManDuration(duration, s.options.deadMan)
 
 	// The management station shall SET dmsActivateMessage.0 to the desired value. This will cause the
 	// controller to perform a consistency check on the message. (See Section 4.3.5 for a description of this
@@ -54,155 +66,166 @@ func ActivatingMessage(
 	var beaconOnTargetMessageNumber int
 	var pixelServiceOnTargetMessageNumber int
 
-	multistringAndBeaconResults, err := dms.Get([]string{
-		d.DmsMessageMultiString.Identifier(messageMemoryType, messageNumber),
-		d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber),
-	})
+	multiStringResult, err := s.getSingle("get dmsMessageMultiString",
+		d.DmsMessageMultiString.Identifier(messageMemoryType, messageNumber))
 	if err != nil {
-		return activeResult, errors.Wrap(err, "get dms failed")
+		return activeResult, err
+	}
+	if value, ok := multiStringResult.Value.([]byte); ok {
+		multiStringOnTargetMessageNumber = string(value)
 	}
 
-	for _, variable := range multistringAndBeaconResults.Variables {
-		switch variable.Name {
-		case d.DmsMessageMultiString.Identifier(messageMemoryType, messageNumber):
-			if variable.Value == nil {
-				log.Printf("Get DmsMessage MultiString value nil at message number %v", messageNumber)
-				continue
-			}
-			multiStringOnTargetMessageNumber = string(variable.Value.([]uint8))
-		case d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber):
-			if variable.Value == nil {
-				log.Printf("Get DmsMessage Beacon value nil at message number %v", messageNumber)
-				continue
-			}
-			beaconOnTargetMessageNumber = variable.Value.(int)
-		default:
-			return activeResult, errors.New("no available results")
+	// dmsMessageBeacon and dmsMessagePixelService are optional. A noSuchName error means the sign
+	// calculates the CRC with these values defaulted to zero (0).
+	// Signs whose capabilities are known not to include them are not asked, except with
+	// WithStrictStandard.
+	c := s.options.capabilities
+	if c == nil || c.Beacon || s.options.strict {
+		beaconResult, err := s.getSingle("get dmsMessageBeacon",
+			d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber))
+		if err != nil && !errors.Is(err, ErrNoSuchName) {
+			return activeResult, err
 		}
+		beaconOnTargetMessageNumber, _ = beaconResult.Value.(int)
 	}
 
-	// separate pixel service for nil value safety
-	pixelServiceResults, err := dms.Get([]string{
-		d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber),
-	})
-	if err != nil {
-		return activeResult, errors.Wrap(err, "get dms failed")
-	}
-	for _, variable := range pixelServiceResults.Variables {
-		switch variable.Name {
-		case d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber):
-			if variable.Value == nil {
-				log.Printf("Get DmsMessage Pixel Service value nil at message number %v", messageNumber)
-				continue
-			}
-			pixelServiceOnTargetMessageNumber = variable.Value.(int)
+	if c == nil || c.PixelService || s.options.strict {
+		pixelServiceResult, err := s.getSingle("get dmsMessagePixelService",
+			d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber))
+		if err != nil && !errors.Is(err, ErrNoSuchName) {
+			return activeResult, err
 		}
+		pixelServiceOnTargetMessageNumber, _ = pixelServiceResult.Value.(int)
 	}
 
+	activeResult.MessageCRC = calcChecksum(multiStringOnTargetMessageNumber, beaconOnTargetMessageNumber, pixelServiceOnTargetMessageNumber)
+	if s.options.duplicate != nil && !s.options.strict && s.options.duplicate(messageMemoryType, messageNumber, activeResult.MessageCRC, priority) {
+		activeResult.Duplicate = true
+		return activeResult, nil
+	}
+	if s.options.conflictCheck {
+		if err := checkConflict(s, priority); err != nil {
+			return activeResult, err
+		}
+	}
+	requester, err := s.sourceAddress()
+	if err != nil {
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
+	}
 	activeMessageCode, err := EncodeActivateMessageCode(
 		multiStringOnTargetMessageNumber, beaconOnTargetMessageNumber, pixelServiceOnTargetMessageNumber,
 		messageMemoryType, duration, priority, messageNumber,
-		"127.0.0.1",
+		requester,
 	)
 	if err != nil {
-		return activeResult, errors.Wrap(err, "encode activate message failed")
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
 	}
-	activeMessagePDU, err := d.DmsActivateMessage.WriteIdentifier(activeMessageCode)
-	if err != nil {
-		return activeResult, errors.Wrap(err, "write activate message object identifier failed")
+	err = setActivateMessage(s, activeMessageCode, &activeResult)
+	return
+}
+
+// BlankSign blanks the sign at the given activation priority (1-255) by
+// activating a blank message, as described in Clause 4.4.6.4 of NTCIP 1203.
+// By default the blank message of the same number is used, whose run-time
+// priority is priority; see WithBlankMode for a soft blank. Blank messages
+// have an empty MULTI string and a dmsMessageCRC of 0x0000, so no message
+// table entry is read beforehand.
+func BlankSign(dms *gosnmp.GoSNMP, priority int, options ...Option) (activeResult activatingMessageResult, err error) {
+	s := newSession(dms, "BlankSign", options)
+	defer s.close()
+	if err := checkPriority("blank message activation priority", priority); err != nil {
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
+	}
+	messageNumber := s.options.blankMessageNumber(priority)
+	if err := checkPriority("blank message run-time priority", messageNumber); err != nil {
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
 	}
 
-	setResult, err := dms.Set([]gosnmp.SnmpPDU{activeMessagePDU})
+	// The message number of a blank message is its run-time priority; the activation priority is taken
+	// from the MessageActivationCode. The message is displayed until replaced (duration 65535).
+	return activateEntry(s, 65535, priority, d.MemoryBlank.Int(), messageNumber, 0)
+}
+
+// activateEntry connects and activates a message whose CRC is already known,
+// without reading its message table entry.
+func activateEntry(s *session, duration, priority, messageMemoryType, messageNumber, messageCRC int) (activeResult activatingMessageResult, err error) {
+	defer func() { activeResult.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return
+	}
+	activeResult.MessageCRC = messageCRC
+	requester, err := s.sourceAddress()
+	if err != nil {
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
+	}
+	activeMessageCode, err := encodeActivateMessageCode(
+		duration, priority, messageMemoryType, messageNumber, messageCRC,
+		requester,
+	)
 	if err != nil {
-		return activeResult, errors.Wrap(err, "dms set failed")
+		return activeResult, s.wrap(err, "encode dmsActivateMessage")
 	}
+	err = setActivateMessage(s, activeMessageCode, &activeResult)
+	return
+}
 
-	if setResult.Error == gosnmp.NoError {
-		// If the response indicates 'noError', the message has been activated and the management station
-		// shall GET shortErrorStatus.0 to ensure that there are no errors preventing the display of the message
-		// (e.g. a 'criticalTemperature' alarm). The management station may then exit the process.
-		var getResult gosnmp.SnmpPDU
-		getResult, err = d.GetSingleOID(dms, d.ShortErrorStatus.Identifier(0))
-		if err != nil {
-			return activeResult, errors.Wrap(err, "dms get shortErrorStatus failed")
+// setActivateMessage SETs dmsActivateMessage.0 and completes the activation
+// dialog: shortErrorStatus.0 on success, the activation error objects on
+// failure.
+func setActivateMessage(s *session, activeMessageCode []byte, activeResult *activatingMessageResult) error {
+	if s.options.controlModeCheck {
+		if err := checkControlMode(s); err != nil {
+			return err
 		}
+	}
 
-		var formatResult interface{}
-		formatResult, err = d.Format(d.ShortErrorStatus, getResult.Value)
-		if err != nil {
-			return activeResult, errors.Wrap(err, "format short error stratus failed")
-		}
+	activeMessagePDU, err := d.DmsActivateMessage.WriteIdentifier(activeMessageCode)
+	if err != nil {
+		return s.wrap(err, "encode dmsActivateMessage")
+	}
+	if s.options.dryRun {
+		s.logDryRun("set dmsActivateMessage", activeMessagePDU)
+		activeResult.DryRun = true
+		return nil
+	}
 
-		activeResult.ShortErrorStatus = formatResult.([]string)
-		return
+	_, err = s.set("set dmsActivateMessage", activeMessagePDU)
+	var snmpError *SNMPError
+	if err != nil && !errors.As(err, &snmpError) {
+		return err
+	}
 
-	} else {
-		// If the response from Step 2 indicates an error, the message was not activated. The management
-		// station shall GET dmsActivateMsgError.0 and dmsActivateErrorMsgCode.0 to determine the type of
-		// error.
-		var result *gosnmp.SnmpPacket
-		result, err = dms.Get([]string{
-			d.DmsActivateMsgError.Identifier(0),
-			d.DmsActivateErrorMsgCode.Identifier(0),
-		})
+	if err == nil {
+		// If the response indicates 'noError', the message has been activated and the management station
+		// shall GET shortErrorStatus.0 to ensure that there are no errors preventing the display of the message
+		// (e.g. a 'criticalTemperature' alarm). The management station may then exit the process.
+		getResult, err := s.getSingle("get shortErrorStatus", d.ShortErrorStatus.Identifier(0))
 		if err != nil {
-			return activeResult, errors.Wrap(err, "get dmsActivateMsgError failed")
-		}
-		for _, variable := range result.Variables {
-			if variable.Name == d.DmsActivateMsgError.Identifier(0) {
-				result, err := d.Format(d.DmsActivateMsgError, variable.Value)
-				if err != nil {
-					return activeResult, errors.Wrap(err, "format dmsActivateMsgError failed")
-				}
-				activeResult.DmsActivateMsgError = result.(string)
-			}
-
-			if variable.Name == d.DmsActivateErrorMsgCode.Identifier(0) {
-				activeResult.DmsActivateErrorMsgCode = variable.Value.(int)
-			}
-		}
-
-		if activeResult.DmsActivateMsgError != "syntaxMULTI" {
-			return activeResult, errors.Wrap(errors.New(setResult.Error.String()), "error")
+			return err
 		}
 
-		// e) If dmsActivateMsgError equals 'syntaxMULTI' then the management station shall GET the following
-		// data to determine the error details:
-		// 1) dmsMultiSyntaxError.0
-		// 2) dmsMultiSyntaxErrorPosition.0
-		result, err = dms.Get([]string{
-			d.DmsMultiSyntaxError.Identifier(0),
-			d.DmsMultiSyntaxErrorPosition.Identifier(0),
-		})
+		formatResult, err := d.Format(d.ShortErrorStatus, getResult.Value)
 		if err != nil {
-			return activeResult, errors.Wrap(err, "get dmsMultiSyntaxError failed")
+			return s.wrap(err, "format shortErrorStatus", getResult.Name)
 		}
-		for _, variable := range result.Variables {
-			if strings.Contains(variable.Name, d.DmsMultiSyntaxError.Identifier(0)) {
-				result, err := d.Format(d.DmsMultiSyntaxError, variable.Value)
-				if err != nil {
-					return activeResult, errors.Wrap(err, "format dmsMultiSyntaxError failed")
-				}
-				activeResult.DmsMultiSyntaxError = result.(string)
-			}
 
-			if strings.Contains(variable.Name, d.DmsMultiSyntaxErrorPosition.Identifier(0)) {
-				activeResult.DmsMultiSyntaxErrorPosition = variable.Value.(int)
-			}
-		}
-		// f) If dmsActivateMessageError equals “syntaxMULTI(8)” and dmsMultiSyntaxError equals “other(1)”
-		// then the management station shall GET dmsMultiOtherErrorDescription.0 to determine the vendor
-		// specific error.
-		if activeResult.DmsActivateMsgError == "syntaxMULTI" && activeResult.DmsMultiSyntaxError == "other" {
-			result, err := d.GetSingleOID(dms, d.DmsMultiOtherErrorDescription.Identifier(0))
-			if err != nil {
-				return activeResult, errors.Wrap(err, "get dmsMultiOtherErrorDescription failed")
-			}
-			activeResult.DmsMultiOtherErrorDescription = string(result.Value.([]uint8))
-		}
+		activeResult.ShortErrorStatus = formatResult.(d.ShortErrorStatusBits)
+		return nil
+	}
 
-		return
+	// If the response from Step 2 indicates an error, the message was not activated. The management
+	// station shall GET dmsActivateMsgError.0 and dmsActivateErrorMsgCode.0 to determine the type of
+	// error.
+	activationError, err := readActivationError(s, snmpError.Status)
+	if err != nil {
+		return err
 	}
+	activeResult.DmsActivateMsgError = activationError.ActivateMsgError
+	activeResult.DmsActivateErrorMsgCode = activationError.ActivateErrorMsgCode
+	activeResult.DmsMultiSyntaxError = activationError.MultiSyntaxError
+	activeResult.DmsMultiSyntaxErrorPosition = activationError.MultiSyntaxErrorPosition
+	activeResult.DmsMultiOtherErrorDescription = activationError.MultiOtherErrorDescription
+	return s.wrap(activationError, "set dmsActivateMessage", activeMessagePDU.Name)
 }
 
 // Preconditions1:
@@ -214,11 +237,16 @@ func ActivatingMessage(
 // Preconditions2:
 // The management station shall ensure that there is sufficient
 // storage space remaining for the message to be downloaded.
+// GetMessageCapacity reads the storage space; with WithCapacityCheck
+// DefiningMessage checks it itself.
 type definingMessageResult struct {
+	Unchanged                     bool // the sign already held the message, nothing was SET
+	DryRun                        bool // run with WithDryRun, nothing was SET
 	DmsValidateMessageError       int
 	DmsMultiSyntaxError           string
 	DmsMultiSyntaxErrorPosition   int
-	DmsMultiOtherErrorDescription int
+	DmsMultiOtherErrorDescription string
+	Timings                       []StepTiming // time spent in each step of the dialog
 }
 
 func DefiningMessage(
@@ -226,32 +254,81 @@ func DefiningMessage(
 	messageMemoryType, messageNumber int,
 	multiString, ownerAddress string, priority int,
 	beacon, pixelService int,
+	options ...Option,
 ) (defineResult definingMessageResult, err error) {
-	if err := dms.Connect(); err != nil {
+	s := newSession(dms, "DefiningMessage", options)
+	defer s.close()
+	defer func() { defineResult.Timings = s.timings }()
+	if err := s.connect(); err != nil {
 		return defineResult, err
 	}
+	return defineMessage(s, messageMemoryType, messageNumber, multiString, ownerAddress, priority, beacon, pixelService)
+}
+
+// defineMessage runs the steps of DefiningMessage on a connected session.
+func defineMessage(
+	s *session,
+	messageMemoryType, messageNumber int,
+	multiString, ownerAddress string, priority int,
+	beacon, pixelService int,
+) (defineResult definingMessageResult, err error) {
+	if err := checkPriority("run-time priority", priority); err != nil {
+		return defineResult, s.wrap(err, "set message table entry")
+	}
+	// With WithSkipUnchanged, a valid row with the CRC of the message, the
+	// same owner and run-time priority already holds it; periodic library
+	// syncs then cost a single GET. WithStrictStandard always runs the dialog.
+	if s.options.skipUnchanged && !s.options.strict {
+		entry, err := readMessageEntry(s, messageMemoryType, messageNumber)
+		if err != nil {
+			return defineResult, err
+		}
+		if entry.Status == d.Valid.Int() && entry.MessageCRC == calcChecksum(multiString, beacon, pixelService) &&
+			entry.MultiString == multiString && entry.Owner == ownerAddress && entry.RunTimePriority == priority {
+			defineResult.Unchanged = true
+			return defineResult, nil
+		}
+	}
+	if s.options.capacityCheck {
+		if err := checkMessageCapacity(s, messageMemoryType, messageNumber, multiString); err != nil {
+			return defineResult, err
+		}
+	}
 
-	// The management station shall SET dmsMessageStatus.x.y to 'modifyReq'.
 	dmsMessageStatusName := d.DmsMessageStatus.Identifier(messageMemoryType, messageNumber)
-	_, err = dms.Set([]gosnmp.SnmpPDU{{
+	pdus := messageEntryPDUs(messageMemoryType, messageNumber, multiString, ownerAddress, priority, beacon, pixelService)
+	if s.options.dryRun {
+		s.logDryRun("set dmsMessageStatus to modifyReq", gosnmp.SnmpPDU{Value: d.ModifyReq.Int(), Name: dmsMessageStatusName, Type: gosnmp.Integer})
+		s.logDryRun("set message table entry", pdus...)
+		s.logDryRun("set dmsMessageStatus to validateReq", gosnmp.SnmpPDU{Value: d.ValidateReq.Int(), Name: dmsMessageStatusName, Type: gosnmp.Integer})
+		defineResult.DryRun = true
+		return defineResult, nil
+	}
+
+	// The management station shall SET dmsMessageStatus.x.y to 'modifyReq'.
+	_, err = s.set("set dmsMessageStatus to modifyReq", gosnmp.SnmpPDU{
 		Value: d.ModifyReq.Int(),
 		Name:  dmsMessageStatusName,
 		Type:  gosnmp.Integer,
-	}})
+	})
 	if err != nil {
-		return defineResult, errors.Wrap(err, "set message status failed")
+		return defineResult, err
 	}
 
 	// The management station shall GET dmsMessageStatus.x.y.
-	result, err := d.GetSingleOID(dms, dmsMessageStatusName)
+	result, err := s.getSingle("get dmsMessageStatus", dmsMessageStatusName)
 	if err != nil {
-		return defineResult, errors.Wrap(err, "get message status failed")
+		return defineResult, err
 	}
 
 	if result.Value == nil || result.Value.(int) != d.Modifying.Int() {
 		// If the value is not 'modifying', exit the process. In this case, the management station may SET
 		// dmsMessageStatus.x.y to 'notUsedReq' and attempt to restart this process from the beginning. (See
 		// Section 4.3.4 for a complete description of the Message Table State Machine.)
+		// The pragmatic mode carries on, as some signs report the state late.
+		if s.options.strict {
+			return defineResult, s.wrap(errors.Errorf("dmsMessageStatus is %v, want modifying", result.Value), "get dmsMessageStatus", dmsMessageStatusName)
+		}
 		log.Printf("message status parameter returns wrong value: %v. expect: %d", result.Value, d.Modifying.Int())
 	}
 
@@ -259,147 +336,171 @@ func DefiningMessage(
 	// 1) dmsMessageMultiString.x.y
 	// 2) dmsMessageOwner.x.y
 	// 3) dmsMessageRunTimePriority.x.y
-	_, err = dms.Set(
-		[]gosnmp.SnmpPDU{{
-			Value: multiString,
-			Name:  d.DmsMessageMultiString.Identifier(messageMemoryType, messageNumber),
-			Type:  d.DmsMessageMultiString.Syntax(),
-		},
-			{
-				Value: ownerAddress,
-				Name:  d.DmsMessageOwner.Identifier(messageMemoryType, messageNumber),
-				Type:  d.DmsMessageOwner.Syntax(),
-			},
-			{
-				Value: priority,
-				Name:  d.DmsMessageRunTimePriority.Identifier(messageMemoryType, messageNumber),
-				Type:  d.DmsMessageRunTimePriority.Syntax(),
-			},
-		})
-	if err != nil {
-		return defineResult, errors.Wrap(err, "set multiString failed")
-	}
-
 	// (Required step only if Requirement 3.6.6.5 Beacon Activation Flag is selected as Yes in PRL) The
 	// management station shall SET dmsMessageBeacon.x.y to the desired value.
-	// Note: The response to this request may be a noSuchName error, indicating that the DMS does not
-	// support this optional feature. This error will not affect the sequence of this dialog, but the
-	// management station should be aware that the CRC will be calculated with this value defaulted to zero
-	// (0).
-	_, err = dms.Set([]gosnmp.SnmpPDU{{
-		Value: beacon,
-		Name:  d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber),
-		Type:  d.DmsMessageBeacon.Syntax(),
-	}})
-	if err != nil {
-		return defineResult, errors.Wrap(err, "set beacon failed")
-	}
-
 	// (Required step only if 2.3.2.2.1 Fiber or 2.3.2.2.3 Flip/Shutter is selected as Yes in PRL) The
 	// management station shall SET dmsMessagePixelService.x.y to the desired value.
-	// Note: The response to this request may be a noSuchName error, indicating that the DMS does not
-	// support this optional feature. This error will not affect the sequence of this dialog, but the
+	// All five objects are SET in a single request to save round trips on slow links, except with
+	// WithStrictStandard.
+	// Note: The response to this request may be a noSuchName error for dmsMessageBeacon or
+	// dmsMessagePixelService, indicating that the DMS does not support this optional feature. The request
+	// is then repeated without that object. This error will not affect the sequence of this dialog, but the
 	// management station should be aware that the CRC will be calculated with this value defaulted to zero
 	// (0).
-	_, err = dms.Set([]gosnmp.SnmpPDU{{
-		Value: pixelService,
-		Name:  d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber),
-		Type:  d.DmsMessagePixelService.Syntax(),
-	}})
+	if s.options.strict {
+		err = setStrictly(s, pdus)
+	} else {
+		if c := s.options.capabilities; c != nil && !c.PixelService {
+			pdus = pdus[:4]
+		}
+		if c := s.options.capabilities; c != nil && !c.Beacon {
+			pdus = append(pdus[:3:3], pdus[4:]...)
+		}
+		err = setOptional(s, "set message table entry", pdus, 3)
+	}
 	if err != nil {
-		return defineResult, errors.Wrap(err, "set pixel service failed")
+		return defineResult, err
 	}
 
 	// The management station shall SET dmsMessageStatus.x.y to 'validateReq'. This will cause the
 	// controller to initiate a consistency check on the message. (See Section 4.3.5 for a description of this
 	// consistency check.)
-	_, err = dms.Set([]gosnmp.SnmpPDU{{
+	_, err = s.set("set dmsMessageStatus to validateReq", gosnmp.SnmpPDU{
 		Value: d.ValidateReq.Int(),
 		Name:  dmsMessageStatusName,
 		Type:  gosnmp.Integer,
-	}})
+	})
 	if err != nil {
-		return defineResult, errors.Wrap(err, "set message status failed")
+		return defineResult, err
 	}
 
 	// The management station shall repeatedly GET dmsMessageStatus.x.y until the value is not
 	// 'validating' or a time-out has been reached.
-	timeout := 3
-	for result.Value.(int) != d.Valid.Int() {
-		if timeout == 0 {
-			goto GET_VALIDATE_MESSAGE_ERROR
-		}
-		result, err = d.GetSingleOID(dms, dmsMessageStatusName)
+	validationStart := time.Now()
+	deadline := validationStart.Add(s.options.validationTimeout)
+	for {
+		result, err = s.getSingle("get dmsMessageStatus", dmsMessageStatusName)
 		if err != nil {
-			return defineResult, errors.Wrap(err, "get message status failed")
+			return defineResult, err
+		}
+		status, _ := result.Value.(int)
+		if status != d.Validating.Int() && status != d.ValidateReq.Int() {
+			break
 		}
-		time.Sleep(1 * time.Second)
-		timeout--
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(s.options.pollInterval)
 	}
+	s.timed("wait for validation", validationStart)
+
 	// If the value is 'valid', exit the process. Otherwise, the management station shall GET
 	// dmsValidateMessageError.0 to determine the reason the message was not validated.
-	return
-GET_VALIDATE_MESSAGE_ERROR:
-	dmsValidateMessageErrorResult, err := d.GetSingleOID(dms, d.DmsValidateMessageError.Identifier(0))
+	status, _ := result.Value.(int)
+	if status == d.Valid.Int() {
+		return
+	}
+	validationError, err := readValidationError(s, status)
 	if err != nil {
-		return defineResult, errors.Wrap(err, "get dmsValidateMessageError failed")
-	}
-
-	// If the value is 'syntaxMULTI', the management station shall GET the following data to determine the
-	// error details:
-	// 1) dmsMultiSyntaxError.0
-	// 2) dmsMultiSyntaxErrorPosition.0
-	defineResult.DmsValidateMessageError = result.Value.(int)
-	if dmsValidateMessageErrorResult.Value == d.SyntaxMULTI.Int() {
-		result, err := dms.Get([]string{
-			d.DmsMultiSyntaxError.Identifier(0),
-			d.DmsMultiSyntaxErrorPosition.Identifier(0),
-		})
-		if err != nil {
-			return defineResult, errors.Wrap(err, "get DmsMultiSyntaxError or DmsMultiSyntaxErrorPosition failed")
-		}
+		return defineResult, err
+	}
+	defineResult.DmsValidateMessageError = validationError.ValidateMessageErrorCode
+	defineResult.DmsMultiSyntaxError = validationError.MultiSyntaxError
+	defineResult.DmsMultiSyntaxErrorPosition = validationError.MultiSyntaxErrorPosition
+	defineResult.DmsMultiOtherErrorDescription = validationError.MultiOtherErrorDescription
+	// Note: If, at the end of this process, the value of dmsMessageStatus.x.y is 'valid', the message can
+	// be activated.
+	return defineResult, s.wrap(validationError, "validate message", dmsMessageStatusName)
+}
 
-		result, err = dms.Get([]string{
-			d.DmsMultiSyntaxError.Identifier(0),
-			d.DmsMultiSyntaxErrorPosition.Identifier(0),
-		})
-		if err != nil {
-			return defineResult, errors.Wrap(err, "get dmsMultiSyntaxError failed")
-		}
-		for _, variable := range result.Variables {
-			if strings.Contains(variable.Name, d.DmsMultiSyntaxError.Identifier(0)) {
-				result, err := d.Format(d.DmsMultiSyntaxError, variable.Value)
-				if err != nil {
-					return defineResult, errors.Wrap(err, "format dmsMultiSyntaxError failed")
-				}
-				defineResult.DmsMultiSyntaxError = result.(string)
-			}
+// messageEntryPDUs returns the objects of the message table entry SET by
+// DefiningMessage.
+func messageEntryPDUs(
+	messageMemoryType, messageNumber int,
+	multiString, ownerAddress string, priority int,
+	beacon, pixelService int,
+) []gosnmp.SnmpPDU {
+	return []gosnmp.SnmpPDU{
+		{
+			Value: multiString,
+			Name:  d.DmsMessageMultiString.Identifier(messageMemoryType, messageNumber),
+			Type:  d.DmsMessageMultiString.Syntax(),
+		},
+		{
+			Value: ownerAddress,
+			Name:  d.DmsMessageOwner.Identifier(messageMemoryType, messageNumber),
+			Type:  d.DmsMessageOwner.Syntax(),
+		},
+		{
+			Value: priority,
+			Name:  d.DmsMessageRunTimePriority.Identifier(messageMemoryType, messageNumber),
+			Type:  d.DmsMessageRunTimePriority.Syntax(),
+		},
+		{
+			Value: beacon,
+			Name:  d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber),
+			Type:  d.DmsMessageBeacon.Syntax(),
+		},
+		{
+			Value: pixelService,
+			Name:  d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber),
+			Type:  d.DmsMessagePixelService.Syntax(),
+		},
+	}
+}
 
-			if strings.Contains(variable.Name, d.DmsMultiSyntaxErrorPosition.Identifier(0)) {
-				defineResult.DmsMultiSyntaxErrorPosition = variable.Value.(int)
-			}
+// setStrictly SETs the message table entry pdus in the requests Clause
+// 4.4.3.1 lists: the required objects together, then dmsMessageBeacon and
+// dmsMessagePixelService on their own, tolerating noSuchName for those.
+func setStrictly(s *session, pdus []gosnmp.SnmpPDU) error {
+	if _, err := s.set("set message table entry", pdus[:3]...); err != nil {
+		return err
+	}
+	for _, pdu := range pdus[3:] {
+		step := "set dmsMessageBeacon"
+		if pdu.Name == pdus[4].Name {
+			step = "set dmsMessagePixelService"
+		}
+		if _, err := s.set(step, pdu); err != nil && !errors.Is(err, ErrNoSuchName) {
+			return err
 		}
-
 	}
+	return nil
+}
 
-	// If the value is 'other', the management station shall GET the following data to determine the error
-	// details:
-	// 1) dmsMultiOtherErrorDescription.0
-
-	// Where:
-	// x = message type
-	// y = message number
-	if dmsValidateMessageErrorResult.Value == d.Other.Int() {
-		dmsMultiOtherErrorDescriptionResult, err := d.GetSingleOID(dms, d.DmsMultiOtherErrorDescription.Identifier(0))
-		if err != nil {
-			return defineResult, errors.Wrap(err, "get DmsMultiOtherErrorDescription failed")
+// setOptional SETs pdus in a single request. The pdus from index optional on
+// are optional objects: when the sign answers that one of them is not
+// supported, it is dropped and the request repeated.
+func setOptional(s *session, step string, pdus []gosnmp.SnmpPDU, optional int) error {
+	for {
+		_, err := s.set(step, pdus...)
+		var snmpError *SNMPError
+		if err == nil || !errors.As(err, &snmpError) || !unsupported(snmpError.Status) {
+			return err
 		}
+		dropped := false
+		for i := optional; i < len(pdus); i++ {
+			if oidEqual(pdus[i].Name, snmpError.OID) {
+				pdus = append(pdus[:i:i], pdus[i+1:]...)
+				dropped = true
+				break
+			}
+		}
+		if !dropped {
+			return err
+		}
+	}
+}
 
-		defineResult.DmsMultiOtherErrorDescription = dmsMultiOtherErrorDescriptionResult.Value.(int)
+// unsupported reports whether status is how a sign refuses the SET of an
+// object it does not implement: noSuchName in SNMPv1, and noCreation,
+// notWritable or noAccess in SNMPv2c and v3.
+func unsupported(status gosnmp.SNMPError) bool {
+	switch status {
+	case gosnmp.NoSuchName, gosnmp.NoCreation, gosnmp.NotWritable, gosnmp.NoAccess:
+		return true
 	}
-	// Note: If, at the end of this process, the value of dmsMessageStatus.x.y is 'valid', the message can
-	// be activated.
-	return
+	return false
 }
 
 type retrievingResult struct {
@@ -409,6 +510,7 @@ type retrievingResult struct {
 	DmsMessageStatus          int // the return shall be 4(Valid)
 	DmsMessageBeacon          int
 	DmsMessagePixelService    int
+	Timings                   []StepTiming // time spent in each step of the dialog
 }
 
 // The standardized dialog for a management station to upload a message from the DMS
@@ -417,8 +519,12 @@ type retrievingResult struct {
 func RetrievingMessage(
 	dms *gosnmp.GoSNMP,
 	messageMemoryType, messageNumber int,
+	options ...Option,
 ) (result retrievingResult, err error) {
-	if err = dms.Connect(); err != nil {
+	s := newSession(dms, "RetrievingMessage", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
 		return result, err
 	}
 	// The management station shall GET the following data:
@@ -433,9 +539,9 @@ func RetrievingMessage(
 		d.DmsMessageStatus.Identifier(messageMemoryType, messageNumber),
 	}
 
-	getResults, err := dms.Get(oids)
+	getResults, err := s.get("get message table entry", oids...)
 	if err != nil {
-		return result, errors.Wrapf(err, "get dmsMessageMultiString failed")
+		return result, err
 	}
 	for _, variable := range getResults.Variables {
 		switch variable.Name {
@@ -455,10 +561,7 @@ func RetrievingMessage(
 	// support this optional feature. This error will not affect the sequence of this dialog, but the
 	// management station should be aware that the CRC will be calculated with this value defaulted to zero
 	// (0).
-	getResult, _ := d.GetSingleOID(dms, d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber))
-	if err != nil {
-		return result, errors.Wrap(err, "get dmsMessageBeacon failed")
-	}
+	getResult, _ := s.getSingle("get dmsMessageBeacon", d.DmsMessageBeacon.Identifier(messageMemoryType, messageNumber))
 	if _, ok := getResult.Value.(int); ok {
 		result.DmsMessageBeacon = getResult.Value.(int)
 	}
@@ -467,12 +570,124 @@ func RetrievingMessage(
 	// support this optional feature. This error will not affect the sequence of this dialog, but the
 	// management station should be aware that the CRC will be calculated with this value defaulted to zero
 	// (0).
-	getResult, _ = d.GetSingleOID(dms, d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber))
-	if err != nil {
-		return result, errors.Wrap(err, "get dmsMessagePixelService failed")
-	}
+	getResult, _ = s.getSingle("get dmsMessagePixelService", d.DmsMessagePixelService.Identifier(messageMemoryType, messageNumber))
 	if _, ok := getResult.Value.(int); ok {
 		result.DmsMessagePixelService = getResult.Value.(int)
 	}
 	return
 }
+
+// CurrentMessage describes the message a sign is displaying and who put it
+// there.
+type CurrentMessage struct {
+	// MessageMemoryType, MessageNumber and MessageCRC identify the message
+	// table row that was copied into the currentBuffer (dmsMsgTableSource.0).
+	MessageMemoryType int
+	MessageNumber     int
+	MessageCRC        int
+	MultiString       string // dmsMessageMultiString of the currentBuffer
+	RunTimePriority   int    // dmsMessageRunTimePriority of the currentBuffer
+	RequesterID       string // source address of the activation, dmsMsgRequesterID.0
+	SourceMode        int    // dmsMsgSourceMode.0
+	SourceModeName    string // e.g. "central" or "timebasedScheduler"
+	ControlMode       int    // dmsControlMode.0
+	ControlModeName   string // e.g. "central" or "local"
+	TimeRemaining     int    // minutes, 65535 is infinite (dmsMessageTimeRemaining.0)
+}
+
+// Central reports whether the message was activated by a central system
+// rather than by the sign itself, e.g. by its scheduler or after a
+// communication loss.
+func (c CurrentMessage) Central() bool {
+	return c.SourceMode == d.SourceCentral.Int()
+}
+
+// Overridable reports whether a central activation with the given activation
+// priority would replace the current message: the sign must be in central
+// control and the priority must be at least the run-time priority of the
+// current message. This applies to scheduled messages as well.
+func (c CurrentMessage) Overridable(priority int) bool {
+	central := c.ControlMode == d.ControlCentral.Int() || c.ControlMode == d.ControlCentralOverride.Int()
+	return central && priority >= c.RunTimePriority
+}
+
+// RetrievingCurrentMessage reads what the sign is currently displaying: the
+// source and run-time priority of the currentBuffer, the requester and source
+// mode of the activation, the control mode of the sign and the time
+// remaining.
+func RetrievingCurrentMessage(dms *gosnmp.GoSNMP, options ...Option) (current CurrentMessage, err error) {
+	s := newSession(dms, "RetrievingCurrentMessage", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return current, err
+	}
+	return retrieveCurrentMessage(s)
+}
+
+// retrieveCurrentMessage runs the steps of RetrievingCurrentMessage on a
+// connected session.
+func retrieveCurrentMessage(s *session) (current CurrentMessage, err error) {
+	var oids = []string{
+		d.DmsMsgTableSource.Identifier(0),
+		d.DmsMsgRequesterID.Identifier(0),
+		d.DmsMsgSourceMode.Identifier(0),
+		d.DmsMessageTimeRemaining.Identifier(0),
+		d.DmsMessageMultiString.Identifier(d.MemoryCurrentBuffer.Int(), 1),
+		d.DmsMessageRunTimePriority.Identifier(d.MemoryCurrentBuffer.Int(), 1),
+		d.DmsControlMode.Identifier(0),
+	}
+	getResults, err := s.get("get current message", oids...)
+	if err != nil {
+		return current, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, d.DmsMsgTableSource.Identifier(0)):
+			value, _ := variable.Value.([]byte)
+			current.MessageMemoryType, current.MessageNumber, current.MessageCRC, err = decodeMessageIDCode(value)
+			if err != nil {
+				return current, s.wrap(err, "decode dmsMsgTableSource", variable.Name)
+			}
+		case oidEqual(variable.Name, d.DmsMsgRequesterID.Identifier(0)):
+			current.RequesterID = formatRequesterID(variable.Value)
+		case oidEqual(variable.Name, d.DmsMsgSourceMode.Identifier(0)):
+			current.SourceMode, _ = variable.Value.(int)
+			formatted, err := d.Format(d.DmsMsgSourceMode, variable.Value)
+			if err != nil {
+				return current, s.wrap(err, "format dmsMsgSourceMode", variable.Name)
+			}
+			current.SourceModeName = formatted.(string)
+		case oidEqual(variable.Name, d.DmsMessageTimeRemaining.Identifier(0)):
+			current.TimeRemaining, _ = variable.Value.(int)
+		case oidEqual(variable.Name, d.DmsMessageMultiString.Identifier(d.MemoryCurrentBuffer.Int(), 1)):
+			value, _ := variable.Value.([]byte)
+			current.MultiString = string(value)
+		case oidEqual(variable.Name, d.DmsMessageRunTimePriority.Identifier(d.MemoryCurrentBuffer.Int(), 1)):
+			current.RunTimePriority, _ = variable.Value.(int)
+		case oidEqual(variable.Name, d.DmsControlMode.Identifier(0)):
+			current.ControlMode, _ = variable.Value.(int)
+			formatted, err := d.Format(d.DmsControlMode, variable.Value)
+			if err != nil {
+				return current, s.wrap(err, "format dmsControlMode", variable.Name)
+			}
+			current.ControlModeName = formatted.(string)
+		}
+	}
+	return current, nil
+}
+
+// formatRequesterID renders dmsMsgRequesterID.0, which signs report either as
+// a 4 byte IP address or as text.
+func formatRequesterID(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		if len(v) == net.IPv4len {
+			return net.IP(v).String()
+		}
+		return string(v)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
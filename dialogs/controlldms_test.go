@@ -1,12 +1,15 @@
 package dialogs
 
 import (
+	"errors"
 	"log"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
 )
 
 var test_dms = &gosnmp.GoSNMP{
@@ -187,3 +190,222 @@ func TestGetSnmp(t *testing.T) {
 	})
 
 }
+
+func TestDefiningMessage_requests(t *testing.T) {
+	tests := []struct {
+		name         string
+		unsupported  []string
+		version      gosnmp.SnmpVersion
+		options      []Option
+		wantRequests int
+	}{
+		{name: "all objects supported", wantRequests: 5},
+		{name: "no beacon", unsupported: []string{"beacon"}, wantRequests: 6},
+		{name: "no beacon and pixel service", unsupported: []string{"beacon", "pixelService"}, wantRequests: 7},
+		{name: "v2c no beacon", unsupported: []string{"beacon"}, version: gosnmp.Version2c, wantRequests: 6},
+		{name: "v2c no beacon and pixel service", unsupported: []string{"beacon", "pixelService"}, version: gosnmp.Version2c, wantRequests: 7},
+		{name: "strict", options: []Option{WithStrictStandard()}, wantRequests: 7},
+		{name: "strict without beacon", unsupported: []string{"beacon"}, options: []Option{WithStrictStandard()}, wantRequests: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sign, err := simulator.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sign.Close()
+			for _, object := range tt.unsupported {
+				switch object {
+				case "beacon":
+					sign.Delete(d.DmsMessageBeacon.Identifier(3, 1))
+				case "pixelService":
+					sign.Delete(d.DmsMessagePixelService.Identifier(3, 1))
+				}
+			}
+
+			dms := sign.GoSNMP()
+			if tt.version != gosnmp.Version1 {
+				dms.Version = tt.version
+			}
+			requests := 0
+			dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+			if _, err := DefiningMessage(dms, 3, 1, "[jp3]TEST", "10.0.0.5", 50, 1, 1, tt.options...); err != nil {
+				t.Fatalf("DefiningMessage() error = %v", err)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("DefiningMessage() sent %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCurrentMessage_Overridable(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  CurrentMessage
+		priority int
+		want     bool
+	}{
+		{name: "higher priority", current: CurrentMessage{ControlMode: 4, RunTimePriority: 50}, priority: 100, want: true},
+		{name: "same priority", current: CurrentMessage{ControlMode: 4, RunTimePriority: 50}, priority: 50, want: true},
+		{name: "lower priority", current: CurrentMessage{ControlMode: 4, RunTimePriority: 50}, priority: 10, want: false},
+		{name: "local control", current: CurrentMessage{ControlMode: 2, RunTimePriority: 1}, priority: 255, want: false},
+		{name: "central override", current: CurrentMessage{ControlMode: 5, RunTimePriority: 1}, priority: 1, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.current.Overridable(tt.priority); got != tt.want {
+				t.Errorf("Overridable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetrievingCurrentMessage_scheduled(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	if _, err := BlankSign(dms, 200); err != nil {
+		t.Fatal(err)
+	}
+	sign.Store(d.DmsMsgSourceMode.Identifier(0), gosnmp.Integer, d.SourceTimebasedScheduler.Int())
+
+	current, err := RetrievingCurrentMessage(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Central() || current.SourceModeName != "timebasedScheduler" || current.RunTimePriority != 200 {
+		t.Errorf("RetrievingCurrentMessage() = %+v, want a scheduled message at priority 200", current)
+	}
+	if _, err := BlankSign(dms, 100); !errors.Is(err, ErrActivatePriority) {
+		t.Errorf("BlankSign() error = %v, want ErrActivatePriority", err)
+	}
+}
+
+func TestBlankSign_soft(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	if _, err := BlankSign(dms, 200, WithBlankMode(SoftBlank, 1)); err != nil {
+		t.Fatal(err)
+	}
+	current, err := RetrievingCurrentMessage(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.MessageMemoryType != d.MemoryBlank.Int() || current.MessageNumber != 1 || current.RunTimePriority != 1 {
+		t.Errorf("RetrievingCurrentMessage() = %+v, want blank message 1", current)
+	}
+	// A message of low priority can replace the soft blank.
+	if _, err := BlankSign(dms, 2); err != nil {
+		t.Errorf("BlankSign() after a soft blank error = %v", err)
+	}
+
+	if _, err := BlankSign(dms, 200, WithBlankMode(SoftBlank, 0)); err == nil {
+		t.Error("BlankSign() accepted run-time priority 0")
+	}
+}
+
+func TestDefiningMessage_timings(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	defined, err := DefiningMessage(dms, 3, 1, "TEST", "10.0.0.5", 50, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []string
+	for _, timing := range defined.Timings {
+		steps = append(steps, timing.Step)
+	}
+	want := []string{
+		"connect",
+		"set dmsMessageStatus to modifyReq",
+		"get dmsMessageStatus",
+		"set message table entry",
+		"set dmsMessageStatus to validateReq",
+		"get dmsMessageStatus",
+		"wait for validation",
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("DefiningMessage() timings = %v, want %v", steps, want)
+	}
+
+	activated, err := ActivatingMessage(dms, 65535, 50, 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(activated.Timings); n == 0 || activated.Timings[n-1].Step != "get shortErrorStatus" {
+		t.Errorf("ActivatingMessage() timings = %v, want get shortErrorStatus last", activated.Timings)
+	}
+}
+
+func TestDefiningMessage_skipUnchanged(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	if _, err := DefiningMessage(dms, 3, 1, "[jp3]TEST", "10.0.0.5", 50, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	result, err := DefiningMessage(dms, 3, 1, "[jp3]TEST", "10.0.0.5", 50, 0, 0, WithSkipUnchanged())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Unchanged || requests != 1 {
+		t.Errorf("DefiningMessage() of an unchanged message = %+v after %d requests, want unchanged after 1", result, requests)
+	}
+
+	// A different run-time priority is not covered by the CRC but still redefines the message.
+	result, err = DefiningMessage(dms, 3, 1, "[jp3]TEST", "10.0.0.5", 60, 0, 0, WithSkipUnchanged())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Unchanged {
+		t.Error("DefiningMessage() with a new priority reported the message unchanged")
+	}
+	if priority, _ := sign.Load(d.DmsMessageRunTimePriority.Identifier(3, 1)); priority != 60 {
+		t.Errorf("dmsMessageRunTimePriority = %v, want 60", priority)
+	}
+}
+
+func TestPriorityValidation(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	requests := 0
+	dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	if _, err := ActivatingMessage(dms, 65535, 0, 3, 1); !errors.Is(err, ErrPriority) {
+		t.Errorf("ActivatingMessage() with priority 0 error = %v, want ErrPriority", err)
+	}
+	if _, err := DefiningMessage(dms, 3, 1, "[jp3]TEST", "10.0.0.5", 256, 0, 0); !errors.Is(err, ErrPriority) {
+		t.Errorf("DefiningMessage() with run-time priority 256 error = %v, want ErrPriority", err)
+	}
+	if _, err := BlankSign(dms, 300); !errors.Is(err, ErrPriority) {
+		t.Errorf("BlankSign() with priority 300 error = %v, want ErrPriority", err)
+	}
+	if requests != 0 {
+		t.Errorf("%d requests sent for out-of-range priorities, want 0", requests)
+	}
+}
@@ -0,0 +1,49 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestControlMode(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	mode, err := GetControlMode(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode != (ControlMode{Mode: d.ControlCentral.Int(), Name: "central"}) || !mode.Central() {
+		t.Errorf("GetControlMode() = %+v, want central", mode)
+	}
+
+	mode, err = SetControlMode(dms, d.ControlCentralOverride.Int())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode.Name != "centralOverride" || !mode.Central() {
+		t.Errorf("SetControlMode() = %+v, want centralOverride", mode)
+	}
+	if _, err := SetControlMode(dms, 9); err == nil {
+		t.Error("SetControlMode() of an unknown mode succeeded")
+	}
+
+	// The switch at the sign is turned to local.
+	sign.Store(d.DmsControlMode.Identifier(0), gosnmp.Integer, d.ControlLocal.Int())
+	before, _ := sign.Load(d.DmsActivateMessage.Identifier(0))
+	if _, err := BlankSign(dms, 100, WithControlModeCheck()); !errors.Is(err, ErrNotCentralControl) {
+		t.Errorf("BlankSign() in local mode error = %v, want ErrNotCentralControl", err)
+	}
+	if after, _ := sign.Load(d.DmsActivateMessage.Identifier(0)); !reflect.DeepEqual(after, before) {
+		t.Errorf("dmsActivateMessage = %v, want no activation", after)
+	}
+}
@@ -0,0 +1,49 @@
+package dialogs
+
+import (
+	"time"
+)
+
+// deadManDuration caps an activation duration in minutes so that the sign
+// ends the message no later than after, rounded up to a full minute.
+func deadManDuration(duration int, after time.Duration) int {
+	if after <= 0 {
+		return duration
+	}
+	minutes := int((after + time.Minute - 1) / time.Minute)
+	if duration == 65535 || duration > minutes {
+		return minutes
+	}
+	return duration
+}
+
+// armDeadMan blanks the sign once after has passed, unless the message of
+// record is no longer displayed by then.
+func (c *Client) armDeadMan(record ActivationRecord, after time.Duration) {
+	c.deadManMu.Lock()
+	defer c.deadManMu.Unlock()
+	if c.deadMan != nil {
+		c.deadMan.Stop()
+	}
+	c.deadMan = time.AfterFunc(after, func() {
+		current, err := c.RetrievingCurrentMessage()
+		if err != nil ||
+			current.MessageMemoryType != record.MessageMemoryType ||
+			current.MessageNumber != record.MessageNumber ||
+			current.MessageCRC != record.MessageCRC {
+			return
+		}
+		c.BlankSign(record.Priority)
+	})
+}
+
+// disarmDeadMan cancels a pending dead-man blank, as a new activation
+// replaces the message it guards.
+func (c *Client) disarmDeadMan() {
+	c.deadManMu.Lock()
+	defer c.deadManMu.Unlock()
+	if c.deadMan != nil {
+		c.deadMan.Stop()
+		c.deadMan = nil
+	}
+}
@@ -0,0 +1,56 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func Test_deadManDuration(t *testing.T) {
+	tests := []struct {
+		duration int
+		after    time.Duration
+		want     int
+	}{
+		{duration: 65535, after: 0, want: 65535},
+		{duration: 65535, after: 10 * time.Minute, want: 10},
+		{duration: 5, after: 10 * time.Minute, want: 5},
+		{duration: 65535, after: 90 * time.Second, want: 2},
+	}
+	for _, tt := range tests {
+		if got := deadManDuration(tt.duration, tt.after); got != tt.want {
+			t.Errorf("deadManDuration(%d, %v) = %d, want %d", tt.duration, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestClient_deadMan(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	client := NewClient(sign.GoSNMP())
+	if _, err := client.DisplayText(1, "[jp3]TEST MESSAGE", 100, 65535, WithDeadMan(20*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	current, err := client.RetrievingCurrentMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.MessageMemoryType != d.MemoryChangeable.Int() || current.TimeRemaining != 1 {
+		t.Fatalf("RetrievingCurrentMessage() = %+v, want the test message for 1 minute", current)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if current, err = client.RetrievingCurrentMessage(); err == nil && current.MessageMemoryType == d.MemoryBlank.Int() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("sign was not blanked, displays %+v", current)
+}
@@ -0,0 +1,231 @@
+package dialogs
+
+import (
+	"bytes"
+	"image/color"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/multi"
+	"github.com/pkg/errors"
+)
+
+// Defaults holds the default MULTI tag values of a sign, the multiCfg
+// objects a MULTI string falls back to when it has no tag for them.
+type Defaults struct {
+	Font              int // defaultFont, a fontNumber
+	JustificationLine int // defaultJustificationLine, e.g. multi.JustifyCenter
+	JustificationPage int // defaultJustificationPage, e.g. multi.JustifyTop
+	PageOnTime        int // defaultPageOnTime in tenths of a second
+	PageOffTime       int // defaultPageOffTime in tenths of a second
+	FlashOn           int // defaultFlashOn in tenths of a second
+	FlashOff          int // defaultFlashOff in tenths of a second
+	CharacterSet      int // defaultCharacterSet, 2 for eightBit
+
+	// ColorScheme is dmsColorScheme, which is read-only; 0 if the sign
+	// does not support it.
+	ColorScheme multi.ColorScheme
+	// ForegroundRGB and BackgroundRGB are defaultForegroundRGB and
+	// defaultBackgroundRGB, in the encoding of the color scheme: three
+	// octets for color24bit, one otherwise. nil if the sign does not
+	// support them.
+	ForegroundRGB []byte
+	BackgroundRGB []byte
+}
+
+// Equal reports whether c and other hold the same values.
+func (c Defaults) Equal(other Defaults) bool {
+	otherFields := other.fields()
+	for i, field := range c.fields() {
+		if *field != *otherFields[i] {
+			return false
+		}
+	}
+	return c.ColorScheme == other.ColorScheme &&
+		bytes.Equal(c.ForegroundRGB, other.ForegroundRGB) && bytes.Equal(c.BackgroundRGB, other.BackgroundRGB)
+}
+
+// MultiSign returns sign with the default font, justifications and, on a
+// color24bit sign, colors, for rendering MULTI previews with package multi.
+func (c Defaults) MultiSign(sign multi.Sign) multi.Sign {
+	sign.DefaultFont = c.Font
+	sign.DefaultLineJustify = c.JustificationLine
+	sign.DefaultPageJustify = c.JustificationPage
+	if len(c.ForegroundRGB) == 3 {
+		sign.DefaultForegroundColor = color.RGBA{c.ForegroundRGB[0], c.ForegroundRGB[1], c.ForegroundRGB[2], 255}
+	}
+	if len(c.BackgroundRGB) == 3 {
+		sign.DefaultBackgroundColor = color.RGBA{c.BackgroundRGB[0], c.BackgroundRGB[1], c.BackgroundRGB[2], 255}
+	}
+	return sign
+}
+
+// ForegroundColor returns ForegroundRGB as a color for the MULTI color tags,
+// and false if the sign did not report it.
+func (c Defaults) ForegroundColor() (multi.Color, bool) {
+	return decodeColor(c.ForegroundRGB)
+}
+
+// BackgroundColor returns BackgroundRGB as a color for the MULTI color tags,
+// and false if the sign did not report it.
+func (c Defaults) BackgroundColor() (multi.Color, bool) {
+	return decodeColor(c.BackgroundRGB)
+}
+
+// decodeColor decodes defaultForegroundRGB or defaultBackgroundRGB: three
+// octets for color24bit, one otherwise.
+func decodeColor(value []byte) (multi.Color, bool) {
+	switch len(value) {
+	case 1:
+		return multi.SingleColor(int(value[0])), true
+	case 3:
+		return multi.RGBColor(int(value[0]), int(value[1]), int(value[2])), true
+	}
+	return multi.Color{}, false
+}
+
+// GetColorScheme reads dmsColorScheme. A sign that answers noSuchName
+// implements NTCIP 1203 v1, which only knows the classic colors, and is
+// reported as multi.ColorClassic.
+func GetColorScheme(dms *gosnmp.GoSNMP, options ...Option) (multi.ColorScheme, error) {
+	s := newSession(dms, "GetColorScheme", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return 0, err
+	}
+	getResult, err := s.getSingle("get dmsColorScheme", d.DmsColorScheme.Identifier(0))
+	if errors.Is(err, ErrNoSuchName) {
+		return multi.ColorClassic, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	value, _ := getResult.Value.(int)
+	return multi.ColorScheme(value), nil
+}
+
+// requiredDefaults are the multiCfg objects of NTCIP 1203 v1 in Defaults.
+var requiredDefaults = []d.Reader{
+	d.DefaultFont,
+	d.DefaultJustificationLine,
+	d.DefaultJustificationPage,
+	d.DefaultPageOnTime,
+	d.DefaultPageOffTime,
+	d.DefaultFlashOn,
+	d.DefaultFlashOff,
+	d.DefaultCharacterSet,
+}
+
+// fields returns the fields of c that hold the requiredDefaults, in the
+// same order.
+func (c *Defaults) fields() []*int {
+	return []*int{
+		&c.Font,
+		&c.JustificationLine,
+		&c.JustificationPage,
+		&c.PageOnTime,
+		&c.PageOffTime,
+		&c.FlashOn,
+		&c.FlashOff,
+		&c.CharacterSet,
+	}
+}
+
+// GetDefaults reads the default MULTI tag values of the sign. The color
+// scheme and the default colors were added in NTCIP 1203 v2 and are left
+// empty on a sign that answers noSuchName for them.
+func GetDefaults(dms *gosnmp.GoSNMP, options ...Option) (Defaults, error) {
+	s := newSession(dms, "GetDefaults", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return Defaults{}, err
+	}
+	return getDefaults(s)
+}
+
+func getDefaults(s *session) (defaults Defaults, err error) {
+	oids := make([]string, 0, len(requiredDefaults))
+	for _, object := range requiredDefaults {
+		oids = append(oids, object.Identifier(0))
+	}
+	getResults, err := s.get("get defaults", oids...)
+	if err != nil {
+		return defaults, err
+	}
+	fields := defaults.fields()
+	for _, variable := range getResults.Variables {
+		for i, object := range requiredDefaults {
+			if oidEqual(variable.Name, object.Identifier(0)) {
+				*fields[i], _ = variable.Value.(int)
+			}
+		}
+	}
+
+	scheme, err := s.getSingle("get dmsColorScheme", d.DmsColorScheme.Identifier(0))
+	if err != nil && !errors.Is(err, ErrNoSuchName) {
+		return defaults, err
+	}
+	value, _ := scheme.Value.(int)
+	defaults.ColorScheme = multi.ColorScheme(value)
+	for _, c := range []struct {
+		object d.Reader
+		value  *[]byte
+	}{
+		{d.DefaultForegroundRGB, &defaults.ForegroundRGB},
+		{d.DefaultBackgroundRGB, &defaults.BackgroundRGB},
+	} {
+		getResult, err := s.getSingle("get "+c.object.ObjectType(), c.object.Identifier(0))
+		if err != nil && !errors.Is(err, ErrNoSuchName) {
+			return defaults, err
+		}
+		if value, ok := getResult.Value.([]byte); ok {
+			*c.value = append([]byte{}, value...)
+		}
+	}
+	return defaults, nil
+}
+
+// SetDefaults sets the default MULTI tag values of the sign in a single
+// request and reads them back. ColorScheme is read-only and ignored; nil
+// colors are not set. A sign that does not support the default colors drops
+// them from the request. Get the current values with GetDefaults and change
+// the ones to set.
+func SetDefaults(dms *gosnmp.GoSNMP, defaults Defaults, options ...Option) (Defaults, error) {
+	s := newSession(dms, "SetDefaults", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return Defaults{}, err
+	}
+
+	pdus := make([]gosnmp.SnmpPDU, 0, len(requiredDefaults)+2)
+	for i, field := range defaults.fields() {
+		object := requiredDefaults[i]
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: object.Identifier(0), Type: object.Syntax(), Value: *field})
+	}
+	if defaults.ForegroundRGB != nil {
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: d.DefaultForegroundRGB.Identifier(0), Type: d.DefaultForegroundRGB.Syntax(), Value: defaults.ForegroundRGB})
+	}
+	if defaults.BackgroundRGB != nil {
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: d.DefaultBackgroundRGB.Identifier(0), Type: d.DefaultBackgroundRGB.Syntax(), Value: defaults.BackgroundRGB})
+	}
+	if err := setOptional(s, "set defaults", pdus, len(requiredDefaults)); err != nil {
+		return Defaults{}, err
+	}
+
+	current, err := getDefaults(s)
+	if err != nil {
+		return current, err
+	}
+	want := defaults
+	want.ColorScheme = current.ColorScheme
+	if want.ForegroundRGB == nil || current.ForegroundRGB == nil {
+		want.ForegroundRGB = current.ForegroundRGB
+	}
+	if want.BackgroundRGB == nil || current.BackgroundRGB == nil {
+		want.BackgroundRGB = current.BackgroundRGB
+	}
+	if !current.Equal(want) {
+		return current, s.wrap(errors.Errorf("sign reports %+v, want %+v", current, want), "verify defaults")
+	}
+	return current, nil
+}
@@ -0,0 +1,73 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/multi"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestDefaults(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	defaults, err := GetDefaults(dms)
+	if err != nil {
+		t.Fatalf("GetDefaults() error = %v", err)
+	}
+	want := Defaults{
+		Font: 1, JustificationLine: multi.JustifyCenter, JustificationPage: multi.JustifyTop,
+		PageOnTime: 30, FlashOn: 5, FlashOff: 5, CharacterSet: 2, ColorScheme: 2,
+		ForegroundRGB: []byte{255}, BackgroundRGB: []byte{0},
+	}
+	if !defaults.Equal(want) {
+		t.Errorf("GetDefaults() = %+v, want %+v", defaults, want)
+	}
+
+	if fg, ok := defaults.ForegroundColor(); !ok || fg != multi.SingleColor(255) {
+		t.Errorf("Defaults.ForegroundColor() = %v, %v, want 255", fg, ok)
+	}
+	if scheme, err := GetColorScheme(dms); err != nil || scheme != multi.Monochrome8Bit {
+		t.Errorf("GetColorScheme() = %v, %v, want monochrome8bit", scheme, err)
+	}
+
+	defaults.Font = 2
+	defaults.PageOnTime = 25
+	defaults.ForegroundRGB = []byte{200}
+	got, err := SetDefaults(dms, defaults)
+	if err != nil {
+		t.Fatalf("SetDefaults() error = %v", err)
+	}
+	if !got.Equal(defaults) {
+		t.Errorf("SetDefaults() = %+v, want %+v", got, defaults)
+	}
+	if rendered := got.MultiSign(multi.Sign{}); rendered.DefaultFont != 2 || rendered.DefaultLineJustify != multi.JustifyCenter {
+		t.Errorf("Defaults.MultiSign() = %+v", rendered)
+	}
+
+	// An NTCIP 1203 v1 sign without the color objects.
+	sign.Delete(d.DmsColorScheme.Identifier(0))
+	sign.Delete(d.DefaultForegroundRGB.Identifier(0))
+	sign.Delete(d.DefaultBackgroundRGB.Identifier(0))
+	got, err = SetDefaults(dms, defaults)
+	if err != nil {
+		t.Fatalf("SetDefaults() on a v1 sign error = %v", err)
+	}
+	if got.ColorScheme != 0 || got.ForegroundRGB != nil {
+		t.Errorf("SetDefaults() on a v1 sign = %+v, want no colors", got)
+	}
+	if scheme, err := GetColorScheme(dms); err != nil || scheme != multi.ColorClassic {
+		t.Errorf("GetColorScheme() on a v1 sign = %v, %v, want colorClassic", scheme, err)
+	}
+
+	sign.Delete(d.DefaultFont.Identifier(0))
+	if _, err := GetDefaults(dms); !errors.Is(err, ErrNoSuchName) {
+		t.Errorf("GetDefaults() without defaultFont error = %v, want ErrNoSuchName", err)
+	}
+}
@@ -0,0 +1,49 @@
+package dialogs
+
+import (
+	"log"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// WithDryRun makes DefiningMessage, ActivatingMessage and BlankSign, and the
+// dialogs built on them, run their GET precondition checks, e.g. those of
+// WithConflictCheck or WithCapacityCheck, and log the SETs they would send
+// instead of sending them, for the change-management review of a TMC. The
+// SETs are passed to the Logger of WithLogger whatever its level, with
+// DryRun set, or printed with the standard log package without one. Other
+// write dialogs fail with ErrDryRun before their first SET. Pass it to
+// NewClient to put the whole client in dry-run mode; its activation history
+// and dead man timer then ignore the activations.
+//
+// An activation reads the message to activate from the sign, so the dry
+// run of a message defined in the same dry run carries the CRC of the
+// message the sign holds.
+func WithDryRun() Option {
+	return func(o *dialogOptions) { o.dryRun = true }
+}
+
+// DryRun reports whether the client was created with WithDryRun.
+func (c *Client) DryRun() bool {
+	return newDialogOptions(c.options...).dryRun
+}
+
+// logDryRun logs the SET of pdus that step of the dialog would send.
+func (s *session) logDryRun(step string, pdus ...gosnmp.SnmpPDU) {
+	entry := LogEntry{
+		Time:      time.Now(),
+		Dialog:    s.dialog,
+		Step:      step,
+		Operation: "set",
+		Target:    s.target(),
+		OIDs:      pduNames(pdus),
+		Values:    pdus,
+		DryRun:    true,
+	}
+	if s.options.logger == nil {
+		log.Print(entry)
+		return
+	}
+	s.options.logger.Log(entry)
+}
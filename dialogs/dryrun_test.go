@@ -0,0 +1,93 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_WithDryRun(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	logger := &recordingLogger{}
+	sets := 0
+	count := Interceptor{Before: func(op *Operation) error {
+		if op.Kind == "set" {
+			sets++
+		}
+		return nil
+	}}
+	client := NewClient(sign.GoSNMP(), WithDryRun(), WithLogger(logger, LogErrors), WithInterceptor(count))
+	if !client.DryRun() {
+		t.Error("DryRun() = false")
+	}
+
+	defined, err := client.DefiningMessage(d.MemoryChangeable.Int(), 1, "ROAD WORK", "10.0.0.5", 50, 0, 0, WithCapacityCheck())
+	if err != nil || !defined.DryRun {
+		t.Fatalf("DefiningMessage() = %+v, %v, want a dry run", defined, err)
+	}
+	steps := []string{"set dmsMessageStatus to modifyReq", "set message table entry", "set dmsMessageStatus to validateReq"}
+	if len(logger.entries) != len(steps) {
+		t.Fatalf("DefiningMessage() logged %d entries, want %d", len(logger.entries), len(steps))
+	}
+	for i, entry := range logger.entries {
+		if !entry.DryRun || entry.Step != steps[i] || len(entry.Values) == 0 {
+			t.Errorf("entry %d = %+v, want the dry run of %q", i, entry, steps[i])
+		}
+	}
+	if multi := logger.entries[1].Values[0].Value; multi != "ROAD WORK" {
+		t.Errorf("logged dmsMessageMultiString = %v, want ROAD WORK", multi)
+	}
+
+	logger.entries = nil
+	blanked, err := client.BlankSign(100)
+	if err != nil || !blanked.DryRun {
+		t.Fatalf("BlankSign() = %+v, %v, want a dry run", blanked, err)
+	}
+	if len(logger.entries) != 1 || logger.entries[0].Step != "set dmsActivateMessage" {
+		t.Errorf("BlankSign() logged %+v, want the SET of dmsActivateMessage", logger.entries)
+	}
+	if history := client.ActivationHistory(); len(history) != 0 {
+		t.Errorf("ActivationHistory() = %+v, want no dry run", history)
+	}
+
+	// Restoring a snapshot neither records the activation nor arms the
+	// dead-man timer.
+	logger.entries = nil
+	snapshot := &Snapshot{
+		Message:  CurrentMessage{MessageMemoryType: d.MemoryChangeable.Int(), MessageNumber: 1, TimeRemaining: 65535},
+		Priority: 100,
+		Taken:    time.Now(),
+	}
+	restored, err := client.Restore(snapshot, WithDeadMan(time.Hour))
+	if err != nil || !restored.DryRun {
+		t.Fatalf("Restore() = %+v, %v, want a dry run", restored, err)
+	}
+	if len(logger.entries) != 1 || logger.entries[0].Step != "set dmsActivateMessage" {
+		t.Errorf("Restore() logged %+v, want the SET of dmsActivateMessage", logger.entries)
+	}
+	if history := client.ActivationHistory(); len(history) != 0 {
+		t.Errorf("ActivationHistory() after Restore = %+v, want no dry run", history)
+	}
+	if client.deadMan != nil {
+		t.Error("Restore() armed the dead-man timer in a dry run")
+	}
+
+	// Dialogs without a dry-run mode do not write either.
+	if _, err := client.SetControlMode(d.ControlCentral.Int()); !errors.Is(err, ErrDryRun) {
+		t.Errorf("SetControlMode() error = %v, want ErrDryRun", err)
+	}
+	if sets != 0 {
+		t.Errorf("dry-run client sent %d SETs", sets)
+	}
+	if status, _ := sign.Load(d.DmsMessageStatus.Identifier(d.MemoryChangeable.Int(), 1)); status != d.NotUsed.Int() {
+		t.Errorf("dmsMessageStatus = %v, want notUsed", status)
+	}
+}
@@ -0,0 +1,364 @@
+package dialogs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// SNMPError is returned when the sign answers a request with an SNMP error
+// status, e.g. noSuchName for an object it does not support or genErr for a
+// rejected SET.
+type SNMPError struct {
+	Status gosnmp.SNMPError
+	OID    string // object the error index points at, if known
+}
+
+func (e *SNMPError) Error() string {
+	if e.OID == "" {
+		return fmt.Sprintf("snmp error %s", e.Status)
+	}
+	return fmt.Sprintf("snmp error %s on %s", e.Status, e.OID)
+}
+
+// Is reports whether target is an *SNMPError with the same status and, if
+// target names an OID, the same OID.
+func (e *SNMPError) Is(target error) bool {
+	t, ok := target.(*SNMPError)
+	if !ok {
+		return false
+	}
+	return t.Status == e.Status && (t.OID == "" || oidEqual(t.OID, e.OID))
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrNoSuchName = &SNMPError{Status: gosnmp.NoSuchName}
+	ErrBadValue   = &SNMPError{Status: gosnmp.BadValue}
+	ErrReadOnly   = &SNMPError{Status: gosnmp.ReadOnly}
+	ErrGenErr     = &SNMPError{Status: gosnmp.GenErr}
+
+	ErrActivateOther               = &ActivationError{Code: 1}
+	ErrActivatePriority            = &ActivationError{Code: 3}
+	ErrActivateMessageStatus       = &ActivationError{Code: 4}
+	ErrActivateMessageMemoryType   = &ActivationError{Code: 5}
+	ErrActivateMessageNumber       = &ActivationError{Code: 6}
+	ErrActivateMessageCRC          = &ActivationError{Code: 7}
+	ErrActivateSyntaxMULTI         = &ActivationError{Code: 8}
+	ErrActivateLocalMode           = &ActivationError{Code: 9}
+	ErrActivateCentralMode         = &ActivationError{Code: 10}
+	ErrActivateCentralOverrideMode = &ActivationError{Code: 11}
+
+	ErrValidateOther        = &ValidationError{ValidateMessageErrorCode: d.Other.Int()}
+	ErrValidateBeacons      = &ValidationError{ValidateMessageErrorCode: d.Beacons.Int()}
+	ErrValidatePixelService = &ValidationError{ValidateMessageErrorCode: d.PixelService.Int()}
+	ErrValidateSyntaxMULTI  = &ValidationError{ValidateMessageErrorCode: d.SyntaxMULTI.Int()}
+	ErrValidationTimeout    = &ValidationError{MessageStatus: d.Validating.Int()}
+
+	ErrBrightnessOther         = &BrightnessTableError{Code: d.BrightnessErrorOther.Int()}
+	ErrBrightnessPhotocellGap  = &BrightnessTableError{Code: d.BrightnessErrorPhotocellGap.Int()}
+	ErrBrightnessNegativeSlope = &BrightnessTableError{Code: d.BrightnessErrorNegativeSlope.Int()}
+	ErrBrightnessTooManyLevels = &BrightnessTableError{Code: d.BrightnessErrorTooManyLevels.Int()}
+	ErrBrightnessInvalidData   = &BrightnessTableError{Code: d.BrightnessErrorInvalidData.Int()}
+)
+
+// ErrFontVersionID is returned when the fontVersionID calculated by the sign
+// does not match the font that was downloaded.
+var ErrFontVersionID = errors.New("fontVersionID does not match the font")
+
+// ErrGraphicID is returned when the dmsGraphicID calculated by the sign does
+// not match the graphic that was downloaded.
+var ErrGraphicID = errors.New("dmsGraphicID does not match the graphic")
+
+// ErrPriority is returned, without a request being sent, for an activation
+// or run-time priority outside d.PriorityLowest to d.PriorityHighest.
+var ErrPriority = errors.Errorf("priority out of range %d-%d", d.PriorityLowest, d.PriorityHighest)
+
+// checkPriority returns ErrPriority, naming the priority, if priority is out
+// of range.
+func checkPriority(name string, priority int) error {
+	if d.ValidPriority(priority) {
+		return nil
+	}
+	return errors.Wrapf(ErrPriority, "%s %d", name, priority)
+}
+
+// ErrReadOnlyClient is returned instead of sending a SET when the dialog
+// runs with WithReadOnly or the package is built with the godms_readonly
+// tag.
+var ErrReadOnlyClient = errors.New("read-only client, SET not sent")
+
+// ErrDryRun is returned instead of sending a SET when the dialog runs with
+// WithDryRun and has no dry-run mode of its own.
+var ErrDryRun = errors.New("dry run, SET not sent")
+
+// ErrClientClosed is returned by the dialogs of a Client after Shutdown.
+var ErrClientClosed = errors.New("dialogs: client shut down")
+
+// ErrSourceAddress is returned by the dialogs that encode the address of the
+// management station, e.g. into a message activation code, when it is not
+// set with WithSourceAddress and the local address used to reach the sign is
+// not an IPv4 address, e.g. over IPv6 or PMPP.
+var ErrSourceAddress = errors.New("no IPv4 source address, set WithSourceAddress")
+
+// ErrSnapshotExpired is returned by Client.Restore when the duration of the
+// message of the snapshot has run out: the sign would no longer display it.
+var ErrSnapshotExpired = errors.New("snapshot message expired")
+
+// ErrMessageMemoryFull is returned by DefiningMessage run with
+// WithCapacityCheck when the message does not fit in the message memory.
+var ErrMessageMemoryFull = errors.New("message memory full")
+
+// ActivationError is returned by ActivatingMessage when the sign rejected the
+// activation request. It carries the details retrieved by the standardized
+// error dialog.
+type ActivationError struct {
+	// Status is the error status of the response to the SET of
+	// dmsActivateMessage.0, usually genErr.
+	Status gosnmp.SNMPError
+	// Code is the value of dmsActivateMsgError.0 and ActivateMsgError its
+	// name, e.g. "priority", "messageCRC" or "syntaxMULTI".
+	Code             int
+	ActivateMsgError string
+	// ActivateErrorMsgCode is the MessageActivationCode that caused the
+	// error, read from dmsActivateErrorMsgCode.0.
+	ActivateErrorMsgCode []byte
+	// The MULTI fields are only filled in when ActivateMsgError is
+	// "syntaxMULTI".
+	MultiSyntaxErrorCode       int
+	MultiSyntaxError           string
+	MultiSyntaxErrorPosition   int
+	MultiOtherErrorDescription string
+}
+
+func (e *ActivationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "message activation failed (%s): %s", e.Status, e.ActivateMsgError)
+	if e.MultiSyntaxError != "" {
+		fmt.Fprintf(&b, ", MULTI syntax error %s at position %d", e.MultiSyntaxError, e.MultiSyntaxErrorPosition)
+	}
+	if e.MultiOtherErrorDescription != "" {
+		fmt.Fprintf(&b, ": %s", e.MultiOtherErrorDescription)
+	}
+	return b.String()
+}
+
+// Is reports whether target is an *ActivationError with the same
+// dmsActivateMsgError code, e.g. errors.Is(err, ErrActivatePriority).
+func (e *ActivationError) Is(target error) bool {
+	t, ok := target.(*ActivationError)
+	return ok && t.Code == e.Code
+}
+
+// readActivationError performs the error branch of the activation dialog
+// after the SET of dmsActivateMessage.0 answered with status.
+func readActivationError(s *session, status gosnmp.SNMPError) (*ActivationError, error) {
+	activationError := &ActivationError{Status: status}
+
+	result, err := s.get("get dmsActivateMsgError and dmsActivateErrorMsgCode",
+		d.DmsActivateMsgError.Identifier(0),
+		d.DmsActivateErrorMsgCode.Identifier(0),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, variable := range result.Variables {
+		switch {
+		case oidEqual(variable.Name, d.DmsActivateMsgError.Identifier(0)):
+			activationError.Code, _ = variable.Value.(int)
+			formatted, err := d.Format(d.DmsActivateMsgError, variable.Value)
+			if err != nil {
+				return nil, s.wrap(err, "format dmsActivateMsgError", variable.Name)
+			}
+			activationError.ActivateMsgError = formatted.(string)
+		case oidEqual(variable.Name, d.DmsActivateErrorMsgCode.Identifier(0)):
+			if code, ok := variable.Value.([]byte); ok {
+				activationError.ActivateErrorMsgCode = code
+			}
+		}
+	}
+
+	if activationError.ActivateMsgError != "syntaxMULTI" {
+		return activationError, nil
+	}
+
+	// If dmsActivateMsgError equals 'syntaxMULTI' then the management station shall GET the following
+	// data to determine the error details:
+	// 1) dmsMultiSyntaxError.0
+	// 2) dmsMultiSyntaxErrorPosition.0
+	// If dmsMultiSyntaxError equals 'other(1)' then the management station shall GET
+	// dmsMultiOtherErrorDescription.0 to determine the vendor specific error.
+	activationError.MultiSyntaxErrorCode,
+		activationError.MultiSyntaxError,
+		activationError.MultiSyntaxErrorPosition,
+		activationError.MultiOtherErrorDescription,
+		err = readMultiSyntaxError(s)
+	if err != nil {
+		return nil, err
+	}
+	return activationError, nil
+}
+
+// ValidationError is returned by DefiningMessage when the message did not
+// reach the 'valid' state. It carries the details retrieved from
+// dmsValidateMessageError.0 and the MULTI syntax error objects.
+type ValidationError struct {
+	// MessageStatus is the last dmsMessageStatus.x.y read, e.g. error(5), or
+	// validating(3) when the validation timed out.
+	MessageStatus int
+	// ValidateMessageError is the decoded value of dmsValidateMessageError.0:
+	// "other", "none", "beacons", "pixelService" or "syntaxMULTI".
+	ValidateMessageError     string
+	ValidateMessageErrorCode int
+	// The MULTI fields are only filled in when ValidateMessageError is
+	// "syntaxMULTI".
+	MultiSyntaxErrorCode       int
+	MultiSyntaxError           string
+	MultiSyntaxErrorPosition   int
+	MultiOtherErrorDescription string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if e.MessageStatus == d.Validating.Int() {
+		b.WriteString("message validation timed out")
+	} else {
+		fmt.Fprintf(&b, "message validation failed (status %d)", e.MessageStatus)
+	}
+	if e.ValidateMessageError != "" {
+		fmt.Fprintf(&b, ": %s", e.ValidateMessageError)
+	}
+	if e.MultiSyntaxError != "" {
+		fmt.Fprintf(&b, ", MULTI syntax error %s at position %d", e.MultiSyntaxError, e.MultiSyntaxErrorPosition)
+	}
+	if e.MultiOtherErrorDescription != "" {
+		fmt.Fprintf(&b, ": %s", e.MultiOtherErrorDescription)
+	}
+	return b.String()
+}
+
+// Is reports whether target is a *ValidationError with the same
+// dmsValidateMessageError code, e.g. errors.Is(err, ErrValidateSyntaxMULTI),
+// or whether both are validation time-outs (ErrValidationTimeout).
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	if !ok {
+		return false
+	}
+	if t.MessageStatus == d.Validating.Int() {
+		return e.MessageStatus == d.Validating.Int()
+	}
+	return t.ValidateMessageErrorCode == e.ValidateMessageErrorCode
+}
+
+// readValidationError performs the error branch of the message definition
+// dialog after dmsMessageStatus.x.y ended up in status instead of 'valid'.
+func readValidationError(s *session, status int) (*ValidationError, error) {
+	validationError := &ValidationError{MessageStatus: status}
+
+	result, err := s.getSingle("get dmsValidateMessageError", d.DmsValidateMessageError.Identifier(0))
+	if err != nil {
+		return nil, err
+	}
+	code, ok := result.Value.(int)
+	if !ok {
+		return validationError, nil
+	}
+	formatted, err := d.Format(d.DmsValidateMessageError, code)
+	if err != nil {
+		return nil, s.wrap(err, "format dmsValidateMessageError", result.Name)
+	}
+	validationError.ValidateMessageErrorCode = code
+	validationError.ValidateMessageError = formatted.(string)
+
+	// If the value is 'syntaxMULTI', the management station shall GET the following data to determine the
+	// error details:
+	// 1) dmsMultiSyntaxError.0
+	// 2) dmsMultiSyntaxErrorPosition.0
+	// If the value is 'other', the management station shall GET the following data to determine the error
+	// details:
+	// 1) dmsMultiOtherErrorDescription.0
+	if code != d.SyntaxMULTI.Int() {
+		return validationError, nil
+	}
+	validationError.MultiSyntaxErrorCode,
+		validationError.MultiSyntaxError,
+		validationError.MultiSyntaxErrorPosition,
+		validationError.MultiOtherErrorDescription,
+		err = readMultiSyntaxError(s)
+	if err != nil {
+		return nil, err
+	}
+	return validationError, nil
+}
+
+// readMultiSyntaxError GETs dmsMultiSyntaxError.0 and
+// dmsMultiSyntaxErrorPosition.0 and, for the syntax error 'other',
+// dmsMultiOtherErrorDescription.0.
+func readMultiSyntaxError(s *session) (code int, syntaxError string, position int, description string, err error) {
+	result, err := s.get("get dmsMultiSyntaxError and dmsMultiSyntaxErrorPosition",
+		d.DmsMultiSyntaxError.Identifier(0),
+		d.DmsMultiSyntaxErrorPosition.Identifier(0),
+	)
+	if err != nil {
+		return 0, "", 0, "", err
+	}
+	for _, variable := range result.Variables {
+		switch {
+		case oidEqual(variable.Name, d.DmsMultiSyntaxError.Identifier(0)):
+			code, _ = variable.Value.(int)
+			formatted, err := d.Format(d.DmsMultiSyntaxError, variable.Value)
+			if err != nil {
+				return 0, "", 0, "", s.wrap(err, "format dmsMultiSyntaxError", variable.Name)
+			}
+			syntaxError = formatted.(string)
+		case oidEqual(variable.Name, d.DmsMultiSyntaxErrorPosition.Identifier(0)):
+			position, _ = variable.Value.(int)
+		}
+	}
+
+	if syntaxError == "other" {
+		result, err := s.getSingle("get dmsMultiOtherErrorDescription", d.DmsMultiOtherErrorDescription.Identifier(0))
+		if err != nil {
+			return 0, "", 0, "", err
+		}
+		if value, ok := result.Value.([]byte); ok {
+			description = string(value)
+		}
+	}
+	return code, syntaxError, position, description, nil
+}
+
+// oidEqual compares two OIDs ignoring the leading dot gosnmp adds to the
+// names of returned variables.
+func oidEqual(a, b string) bool {
+	return strings.TrimPrefix(a, ".") == strings.TrimPrefix(b, ".")
+}
+
+// BrightnessTableError is returned by SetBrightnessTable when the sign
+// rejected the brightness table. It carries the cause read from
+// dmsIllumBrightnessValuesError.0.
+type BrightnessTableError struct {
+	// Status is the error status of the response to the SET of
+	// dmsIllumBrightnessValues.0, usually genErr.
+	Status gosnmp.SNMPError
+	// Code is the value of dmsIllumBrightnessValuesError.0 and
+	// BrightnessValuesError its name, e.g. "photocellGap" or "tooManyLevels".
+	Code                  int
+	BrightnessValuesError string
+}
+
+func (e *BrightnessTableError) Error() string {
+	return fmt.Sprintf("brightness table rejected (%s): %s", e.Status, e.BrightnessValuesError)
+}
+
+// Is reports whether target is a *BrightnessTableError with the same
+// dmsIllumBrightnessValuesError code, e.g.
+// errors.Is(err, ErrBrightnessPhotocellGap).
+func (e *BrightnessTableError) Is(target error) bool {
+	t, ok := target.(*BrightnessTableError)
+	return ok && t.Code == e.Code
+}
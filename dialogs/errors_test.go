@@ -0,0 +1,157 @@
+package dialogs
+
+import (
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+)
+
+func TestActivationError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ActivationError
+		want string
+	}{
+		{
+			name: "priority",
+			err:  &ActivationError{Status: gosnmp.GenErr, ActivateMsgError: "priority"},
+			want: "message activation failed (GenErr): priority",
+		},
+		{
+			name: "syntaxMULTI other",
+			err: &ActivationError{
+				Status:                     gosnmp.GenErr,
+				ActivateMsgError:           "syntaxMULTI",
+				MultiSyntaxError:           "other",
+				MultiSyntaxErrorPosition:   12,
+				MultiOtherErrorDescription: "bad tag",
+			},
+			want: "message activation failed (GenErr): syntaxMULTI, MULTI syntax error other at position 12: bad tag",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ValidationError
+		want string
+	}{
+		{
+			name: "timed out",
+			err:  &ValidationError{MessageStatus: 3},
+			want: "message validation timed out",
+		},
+		{
+			name: "syntaxMULTI",
+			err: &ValidationError{
+				MessageStatus:            5,
+				ValidateMessageError:     "syntaxMULTI",
+				MultiSyntaxError:         "fontNotDefined",
+				MultiSyntaxErrorPosition: 4,
+			},
+			want: "message validation failed (status 5): syntaxMULTI, MULTI syntax error fontNotDefined at position 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorsIs(t *testing.T) {
+	s := &session{dialog: "ActivatingMessage"}
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{
+			name:   "snmp status",
+			err:    s.wrap(&SNMPError{Status: gosnmp.NoSuchName, OID: "1.3.6.1.4.1.1206.4.2.3.6.19.0"}, "get"),
+			target: ErrNoSuchName,
+			want:   true,
+		},
+		{
+			name:   "snmp status and oid",
+			err:    &SNMPError{Status: gosnmp.NoSuchName, OID: ".1.3.6.1.4.1.1206.4.2.3.6.19.0"},
+			target: &SNMPError{Status: gosnmp.NoSuchName, OID: "1.3.6.1.4.1.1206.4.2.3.6.19.0"},
+			want:   true,
+		},
+		{
+			name:   "snmp other oid",
+			err:    &SNMPError{Status: gosnmp.NoSuchName, OID: "1.3.6.1.4.1.1206.4.2.3.6.19.0"},
+			target: &SNMPError{Status: gosnmp.NoSuchName, OID: "1.3.6.1.4.1.1206.4.2.3.6.20.0"},
+			want:   false,
+		},
+		{
+			name:   "snmp other status",
+			err:    &SNMPError{Status: gosnmp.GenErr},
+			target: ErrNoSuchName,
+			want:   false,
+		},
+		{
+			name:   "activation code",
+			err:    s.wrap(&ActivationError{Status: gosnmp.GenErr, Code: 3, ActivateMsgError: "priority"}, "set"),
+			target: ErrActivatePriority,
+			want:   true,
+		},
+		{
+			name:   "activation other code",
+			err:    &ActivationError{Code: 7},
+			target: ErrActivatePriority,
+			want:   false,
+		},
+		{
+			name:   "validation code",
+			err:    &ValidationError{MessageStatus: 5, ValidateMessageErrorCode: 5},
+			target: ErrValidateSyntaxMULTI,
+			want:   true,
+		},
+		{
+			name:   "validation timeout",
+			err:    s.wrap(&ValidationError{MessageStatus: 3}, "validate"),
+			target: ErrValidationTimeout,
+			want:   true,
+		},
+		{
+			name:   "validation failed is not a timeout",
+			err:    &ValidationError{MessageStatus: 5, ValidateMessageErrorCode: 5},
+			target: ErrValidationTimeout,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var snmpError *SNMPError
+	if !errors.As(s.wrap(&SNMPError{Status: gosnmp.BadValue, OID: "1.2"}, "set"), &snmpError) || snmpError.OID != "1.2" {
+		t.Errorf("errors.As() did not reach the *SNMPError")
+	}
+}
+
+func Test_oidEqual(t *testing.T) {
+	if !oidEqual(".1.3.6.1.4.1.1206.4.2.3.6.17.0", "1.3.6.1.4.1.1206.4.2.3.6.17.0") {
+		t.Errorf("oidEqual() should ignore the leading dot")
+	}
+	if oidEqual(".1.3.6.1.4.1.1206.4.2.3.6.17.0", "1.3.6.1.4.1.1206.4.2.3.6.1.0") {
+		t.Errorf("oidEqual() matched different OIDs")
+	}
+}
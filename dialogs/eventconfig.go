@@ -0,0 +1,261 @@
+package dialogs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// EventClass is an event class of the event log, a row of the
+// eventClassTable. The log of a class rolls over: once it holds Limit
+// events, a new event replaces the oldest one. NTCIP 1201 has no mode that
+// stops logging when the log is full.
+type EventClass struct {
+	Number       int       // eventClassNumber
+	Limit        int       // eventClassLimit, 0 logs nothing
+	ClearTime    time.Time // eventClassClearTime, zero if never cleared
+	Description  string    // eventClassDescription
+	NumRowsInLog int       // eventClassNumRowsInLog, read-only
+	NumEvents    int       // eventClassNumEvents, read-only
+}
+
+// eventClassColumns are the columns of the eventClassTable in EventClass.
+var eventClassColumns = []d.Reader{
+	d.EventClassLimit,
+	d.EventClassClearTime,
+	d.EventClassDescription,
+	d.EventClassNumRowsInLog,
+	d.EventClassNumEvents,
+}
+
+// GetEventClasses reads the event classes of the sign, from 1 to
+// maxEventClasses.
+func GetEventClasses(dms *gosnmp.GoSNMP, options ...Option) ([]EventClass, error) {
+	s := newSession(dms, "GetEventClasses", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	maxClasses, err := getInt(s, "maxEventClasses", d.MaxEventClasses.Identifier(0))
+	if err != nil {
+		return nil, err
+	}
+	classes := make([]EventClass, 0, maxClasses)
+	for number := 1; number <= maxClasses; number++ {
+		class, err := getEventClass(s, number)
+		if err != nil {
+			return classes, err
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+func getEventClass(s *session, number int) (EventClass, error) {
+	class := EventClass{Number: number}
+	oids := make([]string, len(eventClassColumns))
+	for i, column := range eventClassColumns {
+		oids[i] = column.Identifier(number)
+	}
+	getResults, err := s.get(fmt.Sprintf("get eventClassEntry %d", number), oids...)
+	if err != nil {
+		return class, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			class.Limit, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[1]):
+			if clearTime := gosnmp.ToBigInt(variable.Value).Int64(); clearTime > 0 {
+				class.ClearTime = time.Unix(clearTime, 0).UTC()
+			}
+		case oidEqual(variable.Name, oids[2]):
+			description, _ := variable.Value.([]byte)
+			class.Description = string(description)
+		case oidEqual(variable.Name, oids[3]):
+			class.NumRowsInLog, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[4]):
+			class.NumEvents, _ = variable.Value.(int)
+		}
+	}
+	return class, nil
+}
+
+// SetEventClass sets the limit and the description of an event class and
+// reads the class back. ClearTime and the counts are ignored, see
+// ClearEventClass. Lowering the limit removes the oldest events of the class
+// beyond it.
+func SetEventClass(dms *gosnmp.GoSNMP, class EventClass, options ...Option) (EventClass, error) {
+	s := newSession(dms, "SetEventClass", options)
+	defer s.close()
+	if class.Number < 1 || class.Limit < 0 {
+		return EventClass{}, s.wrap(errors.Errorf("event class %d with limit %d", class.Number, class.Limit), "validate event class")
+	}
+	if err := s.connect(); err != nil {
+		return EventClass{}, err
+	}
+	if _, err := s.set("set eventClassEntry",
+		gosnmp.SnmpPDU{Name: d.EventClassLimit.Identifier(class.Number), Type: d.EventClassLimit.Syntax(), Value: class.Limit},
+		gosnmp.SnmpPDU{Name: d.EventClassDescription.Identifier(class.Number), Type: d.EventClassDescription.Syntax(), Value: class.Description},
+	); err != nil {
+		return EventClass{}, err
+	}
+	current, err := getEventClass(s, class.Number)
+	if err != nil {
+		return current, err
+	}
+	if current.Limit != class.Limit || current.Description != class.Description {
+		return current, s.wrap(errors.Errorf("sign reports limit %d and description %q, want %d and %q",
+			current.Limit, current.Description, class.Limit, class.Description), "verify eventClassEntry")
+	}
+	return current, nil
+}
+
+// ClearEventClass removes the events of class that occurred at or before
+// before from the event log by setting eventClassClearTime. Use time.Now()
+// to clear the whole class.
+func ClearEventClass(dms *gosnmp.GoSNMP, class int, before time.Time, options ...Option) error {
+	s := newSession(dms, "ClearEventClass", options)
+	defer s.close()
+	if before.Unix() < 0 {
+		return s.wrap(errors.Errorf("clear time %v before 1970", before), "validate clear time")
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	// gosnmp refuses a SET whose first varbind is a Counter, so the request
+	// starts with the current eventClassLimit.
+	limit, err := getInt(s, "eventClassLimit", d.EventClassLimit.Identifier(class))
+	if err != nil {
+		return err
+	}
+	_, err = s.set("set eventClassClearTime",
+		gosnmp.SnmpPDU{Name: d.EventClassLimit.Identifier(class), Type: d.EventClassLimit.Syntax(), Value: limit},
+		gosnmp.SnmpPDU{Name: d.EventClassClearTime.Identifier(class), Type: d.EventClassClearTime.Syntax(), Value: uint(before.Unix())},
+	)
+	return err
+}
+
+// EventConfig is an event configuration, a row of the eventLogConfigTable:
+// the condition on an object that logs an event in a class.
+type EventConfig struct {
+	ID            int    // eventConfigID
+	Class         int    // eventConfigClass
+	Mode          int    // eventConfigMode, e.g. d.EventModeOnChange
+	CompareValue  int    // eventConfigCompareValue
+	CompareValue2 int    // eventConfigCompareValue2
+	CompareOID    string // eventConfigCompareOID, the object watched
+	LogOID        string // eventConfigLogOID, the object logged
+	Action        int    // eventConfigAction, d.EventActionLog or d.EventActionDisabled
+}
+
+// eventConfigColumns are the writable columns of the eventLogConfigTable in
+// EventConfig, in the order they are set.
+var eventConfigColumns = []d.Reader{
+	d.EventConfigClass,
+	d.EventConfigMode,
+	d.EventConfigCompareValue,
+	d.EventConfigCompareValue2,
+	d.EventConfigCompareOID,
+	d.EventConfigLogOID,
+	d.EventConfigAction,
+}
+
+// fields returns the values of c for the eventConfigColumns.
+func (c EventConfig) fields() []interface{} {
+	return []interface{}{c.Class, c.Mode, c.CompareValue, c.CompareValue2, c.CompareOID, c.LogOID, c.Action}
+}
+
+// GetEventConfigs reads the event configurations of the sign, from 1 to
+// maxEventLogConfigs.
+func GetEventConfigs(dms *gosnmp.GoSNMP, options ...Option) ([]EventConfig, error) {
+	s := newSession(dms, "GetEventConfigs", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	maxConfigs, err := getInt(s, "maxEventLogConfigs", d.MaxEventLogConfigs.Identifier(0))
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]EventConfig, 0, maxConfigs)
+	for id := 1; id <= maxConfigs; id++ {
+		config, err := getEventConfig(s, id)
+		if err != nil {
+			return configs, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func getEventConfig(s *session, id int) (EventConfig, error) {
+	config := EventConfig{ID: id}
+	oids := make([]string, len(eventConfigColumns))
+	for i, column := range eventConfigColumns {
+		oids[i] = column.Identifier(id)
+	}
+	getResults, err := s.get(fmt.Sprintf("get eventLogConfigEntry %d", id), oids...)
+	if err != nil {
+		return config, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			config.Class, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[1]):
+			config.Mode, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[2]):
+			config.CompareValue, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[3]):
+			config.CompareValue2, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[4]):
+			oid, _ := variable.Value.(string)
+			config.CompareOID = strings.TrimPrefix(oid, ".")
+		case oidEqual(variable.Name, oids[5]):
+			oid, _ := variable.Value.(string)
+			config.LogOID = strings.TrimPrefix(oid, ".")
+		case oidEqual(variable.Name, oids[6]):
+			config.Action, _ = variable.Value.(int)
+		}
+	}
+	return config, nil
+}
+
+// SetEventConfig sets an event configuration in a single request and reads
+// it back. Empty object identifiers are set to the null 0.0.
+func SetEventConfig(dms *gosnmp.GoSNMP, config EventConfig, options ...Option) (EventConfig, error) {
+	s := newSession(dms, "SetEventConfig", options)
+	defer s.close()
+	if config.ID < 1 || config.Class < 1 {
+		return EventConfig{}, s.wrap(errors.Errorf("event configuration %d with class %d", config.ID, config.Class), "validate event configuration")
+	}
+	if err := s.connect(); err != nil {
+		return EventConfig{}, err
+	}
+	for _, oid := range []*string{&config.CompareOID, &config.LogOID} {
+		if *oid = strings.TrimPrefix(*oid, "."); *oid == "" {
+			*oid = nullOID
+		}
+	}
+	pdus := make([]gosnmp.SnmpPDU, len(eventConfigColumns))
+	for i, value := range config.fields() {
+		column := eventConfigColumns[i]
+		pdus[i] = gosnmp.SnmpPDU{Name: column.Identifier(config.ID), Type: column.Syntax(), Value: value}
+	}
+	if _, err := s.set("set eventLogConfigEntry", pdus...); err != nil {
+		return EventConfig{}, err
+	}
+	current, err := getEventConfig(s, config.ID)
+	if err != nil {
+		return current, err
+	}
+	if current != config {
+		return current, s.wrap(errors.Errorf("sign reports %+v, want %+v", current, config), "verify eventLogConfigEntry")
+	}
+	return current, nil
+}
@@ -0,0 +1,94 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestEventClass(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		sign.LogEvent(3, 1, []byte{byte(i)}, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	classes, err := client.GetEventClasses()
+	if err != nil {
+		t.Fatalf("GetEventClasses() error = %v", err)
+	}
+	if len(classes) != simulator.MaxEventClasses || classes[2].NumRowsInLog != 5 || classes[2].Description != "messages" {
+		t.Fatalf("GetEventClasses() = %+v", classes)
+	}
+
+	class, err := client.SetEventClass(EventClass{Number: 3, Limit: 3, Description: "message changes"})
+	if err != nil {
+		t.Fatalf("SetEventClass() error = %v", err)
+	}
+	if class.Limit != 3 || class.Description != "message changes" || class.NumRowsInLog != 3 || class.NumEvents != 5 {
+		t.Errorf("SetEventClass() = %+v, want 3 of 5 events kept", class)
+	}
+
+	if err := client.ClearEventClass(3, start.Add(3*time.Minute)); err != nil {
+		t.Fatalf("ClearEventClass() error = %v", err)
+	}
+	events, err := client.GetEventLog()
+	if err != nil || len(events) != 1 || events[0].Value[0] != 4 {
+		t.Errorf("GetEventLog() after ClearEventClass() = %+v, %v, want the last event", events, err)
+	}
+	classes, err = client.GetEventClasses()
+	if err != nil || !classes[2].ClearTime.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("GetEventClasses() after ClearEventClass() = %+v, %v", classes, err)
+	}
+
+	if _, err := client.SetEventClass(EventClass{Number: 3, Limit: -1}); err == nil {
+		t.Errorf("SetEventClass() with a negative limit expected an error")
+	}
+}
+
+func TestEventConfig(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	configs, err := client.GetEventConfigs()
+	if err != nil {
+		t.Fatalf("GetEventConfigs() error = %v", err)
+	}
+	if len(configs) != simulator.MaxEventLogConfigs || configs[0].Action != d.EventActionDisabled.Int() || configs[0].CompareOID != nullOID {
+		t.Fatalf("GetEventConfigs() = %+v", configs)
+	}
+
+	config := EventConfig{
+		ID:         2,
+		Class:      4,
+		Mode:       d.EventModeOnChange.Int(),
+		CompareOID: d.ShortErrorStatus.Identifier(0),
+		LogOID:     d.ShortErrorStatus.Identifier(0),
+		Action:     d.EventActionLog.Int(),
+	}
+	got, err := client.SetEventConfig(config)
+	if err != nil {
+		t.Fatalf("SetEventConfig() error = %v", err)
+	}
+	if got != config {
+		t.Errorf("SetEventConfig() = %+v, want %+v", got, config)
+	}
+
+	config.Action = d.EventActionDisabled.Int()
+	config.LogOID = ""
+	if got, err := client.SetEventConfig(config); err != nil || got.LogOID != nullOID {
+		t.Errorf("SetEventConfig() without a log object = %+v, %v, want %s", got, err, nullOID)
+	}
+}
@@ -0,0 +1,109 @@
+package dialogs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// LogEvent is an event of the event log of the sign, a row of the
+// eventLogTable.
+type LogEvent struct {
+	Class  int       // eventLogClass
+	Number int       // eventLogNumber within the class, wraps after 255
+	ID     int       // eventLogID, the eventConfigID that logged the event
+	Time   time.Time // eventLogTime
+	Value  []byte    // eventLogValue, the value of the logged object
+}
+
+// GetEventLog reads the event log of the sign and returns its events in
+// chronological order; events of the same second are ordered by class and
+// number. The events of each class are found by walking
+// eventLogID, as their numbers wrap and may have been cleared.
+func GetEventLog(dms *gosnmp.GoSNMP, options ...Option) ([]LogEvent, error) {
+	s := newSession(dms, "GetEventLog", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return getEventLog(s)
+}
+
+func getEventLog(s *session) ([]LogEvent, error) {
+	maxClasses, err := getInt(s, "maxEventClasses", d.MaxEventClasses.Identifier(0))
+	if err != nil {
+		return nil, err
+	}
+	var classes []string
+	for class := 1; class <= maxClasses; class++ {
+		classes = append(classes, d.EventClassNumRowsInLog.Identifier(class))
+	}
+	rows, err := s.get("get eventClassNumRowsInLog", classes...)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []LogEvent
+	for i, variable := range rows.Variables {
+		if n, _ := variable.Value.(int); n == 0 {
+			continue
+		}
+		class := i + 1
+		ids, err := s.walk(fmt.Sprintf("walk eventLogID of class %d", class), d.EventLogID.Identifier(class))
+		if err != nil {
+			return nil, err
+		}
+		first := len(events)
+		prefix := d.EventLogID.Identifier(class) + "."
+		for _, id := range ids {
+			number, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(id.Name, "."), prefix))
+			if err != nil {
+				continue
+			}
+			value, _ := id.Value.(int)
+			events = append(events, LogEvent{Class: class, Number: number, ID: value})
+		}
+		if err := getEventDetails(s, events[first:]); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
+
+// getEventDetails reads the time and the value of events of one class, as
+// many per request as dms.MaxOids allows.
+func getEventDetails(s *session, events []LogEvent) error {
+	perRequest := len(events)
+	if s.dms.MaxOids >= 2 && s.dms.MaxOids/2 < perRequest {
+		perRequest = s.dms.MaxOids / 2
+	}
+	for first := 0; first < len(events); first += perRequest {
+		last := first + perRequest
+		if last > len(events) {
+			last = len(events)
+		}
+		var oids []string
+		for _, event := range events[first:last] {
+			oids = append(oids,
+				fmt.Sprintf("%s.%d", d.EventLogTime.Identifier(event.Class), event.Number),
+				fmt.Sprintf("%s.%d", d.EventLogValue.Identifier(event.Class), event.Number))
+		}
+		getResults, err := s.get(fmt.Sprintf("get events %d-%d of class %d", first+1, last, events[first].Class), oids...)
+		if err != nil {
+			return err
+		}
+		for i := 0; i+1 < len(getResults.Variables) && first+i/2 < last; i += 2 {
+			event := &events[first+i/2]
+			event.Time = time.Unix(gosnmp.ToBigInt(getResults.Variables[i].Value).Int64(), 0).UTC()
+			value, _ := getResults.Variables[i+1].Value.([]byte)
+			event.Value = append([]byte{}, value...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetEventLog(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	dms.MaxOids = 4
+
+	if events, err := GetEventLog(dms); err != nil || len(events) != 0 {
+		t.Fatalf("GetEventLog() of an empty log = %v, %v", events, err)
+	}
+
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	sign.LogEvent(2, 7, []byte{1}, start.Add(2*time.Minute))
+	sign.LogEvent(4, 9, []byte{0, 0x20}, start)
+	sign.LogEvent(2, 7, []byte{2}, start.Add(3*time.Minute))
+	sign.LogEvent(4, 9, []byte{0, 0x00}, start.Add(time.Minute))
+	sign.LogEvent(4, 9, []byte{0, 0x20}, start.Add(4*time.Minute))
+
+	events, err := GetEventLog(dms)
+	if err != nil {
+		t.Fatalf("GetEventLog() error = %v", err)
+	}
+	want := []LogEvent{
+		{Class: 4, Number: 1, ID: 9, Time: start, Value: []byte{0, 0x20}},
+		{Class: 4, Number: 2, ID: 9, Time: start.Add(time.Minute), Value: []byte{0, 0x00}},
+		{Class: 2, Number: 1, ID: 7, Time: start.Add(2 * time.Minute), Value: []byte{1}},
+		{Class: 2, Number: 2, ID: 7, Time: start.Add(3 * time.Minute), Value: []byte{2}},
+		{Class: 4, Number: 3, ID: 9, Time: start.Add(4 * time.Minute), Value: []byte{0, 0x20}},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("GetEventLog() = %+v, want %+v", events, want)
+	}
+
+	// Once the class limit is reached, the oldest events are replaced and
+	// the numbers keep increasing.
+	sign.Store(d.EventClassLimit.Identifier(4), gosnmp.Integer, 2)
+	sign.LogEvent(4, 9, []byte{0, 0x00}, start.Add(5*time.Minute))
+	events, err = GetEventLog(dms)
+	if err != nil {
+		t.Fatalf("GetEventLog() error = %v", err)
+	}
+	var numbers []int
+	for _, event := range events {
+		if event.Class == 4 {
+			numbers = append(numbers, event.Number)
+		}
+	}
+	if !reflect.DeepEqual(numbers, []int{3, 4}) {
+		t.Errorf("GetEventLog() class 4 numbers = %v, want [3 4]", numbers)
+	}
+
+	dms = sign.GoSNMP()
+	dms.Version = gosnmp.Version2c
+	if events, err := GetEventLog(dms); err != nil || len(events) != 4 {
+		t.Errorf("GetEventLog() over SNMPv2c = %d events, %v, want 4", len(events), err)
+	}
+}
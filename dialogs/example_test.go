@@ -0,0 +1,91 @@
+package dialogs_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pkg/errors"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/dialogs"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func ExampleActivatingMessage() {
+	sign, err := simulator.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	_, err = dialogs.DefiningMessage(dms, d.MemoryChangeable.Int(), 1, "[jp3]ROAD WORK[nl]AHEAD", "10.0.0.5", 100, 0, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	result, err := dialogs.ActivatingMessage(dms, 65535, 100, d.MemoryChangeable.Int(), 1,
+		dialogs.WithSourceAddress("10.0.0.5"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("CRC %04X, errors %v\n", result.MessageCRC, result.ShortErrorStatus)
+	// Output: CRC 46BE, errors noError
+}
+
+func ExampleDefiningMessage() {
+	sign, err := simulator.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sign.Close()
+
+	_, err = dialogs.DefiningMessage(sign.GoSNMP(), d.MemoryChangeable.Int(), 2, "[jp3ROAD WORK", "10.0.0.5", 100, 0, 0)
+	var validationError *dialogs.ValidationError
+	if errors.As(err, &validationError) {
+		fmt.Println(validationError.ValidateMessageError, validationError.MultiSyntaxErrorPosition)
+	}
+	fmt.Println(errors.Is(err, dialogs.ErrValidateSyntaxMULTI))
+	// Output:
+	// syntaxMULTI 1
+	// true
+}
+
+func ExampleClient_DisplayText() {
+	sign, err := simulator.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sign.Close()
+
+	client := dialogs.NewClient(sign.GoSNMP(), dialogs.WithSourceAddress("10.0.0.5"))
+	if _, err := client.DisplayText(1, "[jp3]LEFT LANE CLOSED", 100, 60); err != nil {
+		log.Fatal(err)
+	}
+	current, err := client.RetrievingCurrentMessage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(current.MultiString)
+	fmt.Println(current.RequesterID, current.SourceModeName, current.TimeRemaining)
+	// Output:
+	// [jp3]LEFT LANE CLOSED
+	// 10.0.0.5 central 60
+}
+
+func ExampleBlankSign() {
+	sign, err := simulator.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sign.Close()
+
+	if _, err := dialogs.BlankSign(sign.GoSNMP(), 100); err != nil {
+		log.Fatal(err)
+	}
+	current, err := dialogs.RetrievingCurrentMessage(sign.GoSNMP())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(current.MessageMemoryType, current.MessageNumber, current.MessageCRC)
+	// Output: 7 100 0
+}
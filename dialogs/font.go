@@ -0,0 +1,180 @@
+package dialogs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+	"github.com/pkg/errors"
+)
+
+// Font is a font of the fontTable with the characters of its
+// characterTable.
+type Font struct {
+	Number      int    // fontNumber, as referenced by the [fo] tag
+	Name        string // fontName
+	Height      int    // fontHeight in pixels
+	CharSpacing int    // fontCharSpacing in pixels
+	LineSpacing int    // fontLineSpacing in pixels
+	Characters  []crc.Character
+}
+
+// VersionID returns the fontVersionID a sign calculates for the font.
+func (f Font) VersionID() int {
+	return int(crc.FontVersionID(f.Number, f.Height, f.CharSpacing, f.LineSpacing, f.Characters))
+}
+
+type configuringFontResult struct {
+	FontIndex     int // the fontTable row the font was stored in
+	FontVersionID int
+	Timings       []StepTiming // time spent in each step of the dialog
+}
+
+// ConfiguringFont downloads font into row fontIndex of the fontTable, as
+// described in Clause 4.2.3.2 of NTCIP 1203 v03, or into the first unused
+// row if fontIndex is 0. The row is put into the 'modifying' state, the
+// font and its characters are written, and the row is made 'readyForUse'.
+// The fontVersionID calculated by the sign is compared with the font's; a
+// mismatch is reported as ErrFontVersionID.
+func ConfiguringFont(dms *gosnmp.GoSNMP, fontIndex int, font Font, options ...Option) (result configuringFontResult, err error) {
+	s := newSession(dms, "ConfiguringFont", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	if fontIndex == 0 {
+		if fontIndex, err = unusedRow(s, d.NumFonts, d.FontStatus); err != nil {
+			return result, err
+		}
+	}
+	result.FontIndex = fontIndex
+	statusName := d.FontStatus.Identifier(fontIndex)
+
+	// The management station shall GET fontStatus.x; a permanent font or the
+	// font of the displayed message cannot be modified.
+	status, err := getInt(s, "fontStatus", statusName)
+	if err != nil {
+		return result, err
+	}
+	if status == d.FontPermanent.Int() || status == d.FontInUse.Int() {
+		return result, s.wrap(errors.Errorf("font %d cannot be modified in fontStatus %d", fontIndex, status), "get fontStatus", statusName)
+	}
+
+	// The management station shall SET fontStatus.x to 'modifyReq' and GET
+	// fontStatus.x until it is 'modifying'.
+	if status != d.FontModifying.Int() {
+		if status, err = setRowStatus(s, d.FontStatus, fontIndex, d.FontModifyReq, d.FontModifying); err != nil {
+			return result, err
+		}
+	}
+
+	// The management station shall SET fontNumber.x, fontName.x, fontHeight.x,
+	// fontCharSpacing.x and fontLineSpacing.x, then characterWidth.x.y and
+	// characterBitmap.x.y of every character. Setting fontHeight.x clears the
+	// characters of the row.
+	_, err = s.set("set font", []gosnmp.SnmpPDU{
+		{Name: d.FontNumber.Identifier(fontIndex), Type: d.FontNumber.Syntax(), Value: font.Number},
+		{Name: d.FontName.Identifier(fontIndex), Type: d.FontName.Syntax(), Value: font.Name},
+		{Name: d.FontHeight.Identifier(fontIndex), Type: d.FontHeight.Syntax(), Value: font.Height},
+		{Name: d.FontCharSpacing.Identifier(fontIndex), Type: d.FontCharSpacing.Syntax(), Value: font.CharSpacing},
+		{Name: d.FontLineSpacing.Identifier(fontIndex), Type: d.FontLineSpacing.Syntax(), Value: font.LineSpacing},
+	}...)
+	if err != nil {
+		return result, err
+	}
+	total := 0
+	for _, character := range font.Characters {
+		if character.Width != 0 {
+			total++
+		}
+	}
+	done := 0
+	for _, character := range font.Characters {
+		if character.Width == 0 {
+			continue
+		}
+		step := fmt.Sprintf("set character %d", character.Number)
+		_, err = s.set(step,
+			gosnmp.SnmpPDU{Name: characterName(d.CharacterWidth, fontIndex, character.Number), Type: d.CharacterWidth.Syntax(), Value: character.Width},
+			gosnmp.SnmpPDU{Name: characterName(d.CharacterBitmap, fontIndex, character.Number), Type: d.CharacterBitmap.Syntax(), Value: character.Bitmap},
+		)
+		if err != nil {
+			return result, err
+		}
+		done++
+		s.progress(step, done, total)
+	}
+
+	// The management station shall SET fontStatus.x to 'readyForUseReq' and
+	// GET fontStatus.x until it is no longer 'calculatingID'.
+	if _, err = setRowStatus(s, d.FontStatus, fontIndex, d.FontReadyForUseReq, d.FontReadyForUse); err != nil {
+		return result, err
+	}
+
+	// The management station shall GET fontVersionID.x to verify the font.
+	if result.FontVersionID, err = getInt(s, "fontVersionID", d.FontVersionID.Identifier(fontIndex)); err != nil {
+		return result, err
+	}
+	if want := font.VersionID(); result.FontVersionID != want {
+		err = errors.Wrapf(ErrFontVersionID, "sign calculated %04X, want %04X", result.FontVersionID, want)
+		return result, s.wrap(err, "verify fontVersionID", d.FontVersionID.Identifier(fontIndex))
+	}
+	return result, nil
+}
+
+// setRowStatus SETs the status column of a fontTable or dmsGraphicTable row
+// to request and polls it until it reaches want, leaves the transitional
+// states or the validation timeout passes. Both tables use the same state
+// machine.
+func setRowStatus(s *session, column d.Reader, index int, request, want interface{ Int() int }) (status int, err error) {
+	statusName := column.Identifier(index)
+	step := fmt.Sprintf("set %s to %d", column.ObjectType(), request.Int())
+	if _, err = s.set(step, gosnmp.SnmpPDU{Name: statusName, Type: gosnmp.Integer, Value: request.Int()}); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	defer s.timed("wait for "+column.ObjectType(), start)
+	deadline := start.Add(s.options.validationTimeout)
+	for {
+		if status, err = getInt(s, column.ObjectType(), statusName); err != nil {
+			return status, err
+		}
+		transitional := status == d.FontCalculatingID.Int() || status == request.Int()
+		if status == want.Int() || !transitional || !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(s.options.pollInterval)
+	}
+	if status != want.Int() {
+		return status, s.wrap(errors.Errorf("%s is %d, want %d", column.ObjectType(), status, want.Int()), step, statusName)
+	}
+	return status, nil
+}
+
+// unusedRow returns the first row in the 'notUsed' state of the fontTable
+// or dmsGraphicTable, whose number of rows is in rows.
+func unusedRow(s *session, rows, column d.Reader) (int, error) {
+	numRows, err := getInt(s, rows.ObjectType(), rows.Identifier(0))
+	if err != nil {
+		return 0, err
+	}
+	for index := 1; index <= numRows; index++ {
+		status, err := getInt(s, column.ObjectType(), column.Identifier(index))
+		if err != nil {
+			return 0, err
+		}
+		if status == d.FontNotUsed.Int() {
+			return index, nil
+		}
+	}
+	return 0, s.wrap(errors.Errorf("no unused row of %s", column.ObjectType()), "find unused row")
+}
+
+// characterName returns the OID of a characterTable column for a character.
+func characterName(column d.Reader, fontIndex, characterNumber int) string {
+	return fmt.Sprintf("%s.%d", column.Identifier(fontIndex), characterNumber)
+}
@@ -0,0 +1,74 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestConfiguringFont(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	font := Font{
+		Number:      5,
+		Name:        "narrow",
+		Height:      2,
+		CharSpacing: 1,
+		LineSpacing: 1,
+		Characters: []crc.Character{
+			{Number: 'I', Width: 1, Bitmap: []byte{0xC0}},
+			{Number: 'L', Width: 2, Bitmap: []byte{0xB0}},
+		},
+	}
+	result, err := ConfiguringFont(sign.GoSNMP(), 0, font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FontIndex != 2 || result.FontVersionID != font.VersionID() {
+		t.Errorf("ConfiguringFont() = %+v, want font index 2 and fontVersionID %04X", result, font.VersionID())
+	}
+	if status, _ := sign.Load(d.FontStatus.Identifier(2)); status != d.FontReadyForUse.Int() {
+		t.Errorf("fontStatus = %v, want readyForUse", status)
+	}
+	ids, err := GetFontVersionIDs(sign.GoSNMP(), []int{2})
+	if err != nil || ids[2] != font.VersionID() {
+		t.Errorf("GetFontVersionIDs() = %v, %v", ids, err)
+	}
+
+	// The row can be downloaded again, replacing the characters.
+	font.Characters = font.Characters[:1]
+	if result, err = ConfiguringFont(sign.GoSNMP(), 2, font); err != nil || result.FontVersionID != font.VersionID() {
+		t.Errorf("ConfiguringFont() again = %+v, %v", result, err)
+	}
+
+	if _, err = ConfiguringFont(sign.GoSNMP(), 1, font); err == nil {
+		t.Error("ConfiguringFont() modified the permanent font")
+	}
+}
+
+func TestConfiguringFontOversizedCharacter(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	font := Font{Number: 2, Height: 16, Characters: []crc.Character{
+		{Number: 'W', Width: 17, Bitmap: make([]byte, simulator.FontMaxCharacterSize+2)},
+	}}
+	_, err = ConfiguringFont(sign.GoSNMP(), 3, font)
+	var dialogErr *DialogError
+	if !errors.As(err, &dialogErr) {
+		t.Fatalf("ConfiguringFont() error = %v, want a DialogError", err)
+	}
+	if status, _ := sign.Load(d.FontStatus.Identifier(3)); status != d.FontModifying.Int() {
+		t.Errorf("fontStatus = %v, want modifying", status)
+	}
+}
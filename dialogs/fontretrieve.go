@@ -0,0 +1,106 @@
+package dialogs
+
+import (
+	"strconv"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+	"github.com/pkg/errors"
+)
+
+type retrievingFontResult struct {
+	Font          Font
+	FontVersionID int // as reported by the sign
+	FontStatus    int // 0 for NTCIP 1203 v1 signs, which have no fontStatus
+	Timings       []StepTiming
+}
+
+// RetrievingFont uploads the font in row fontIndex of the fontTable with all
+// its characters, as described in Clause 4.2.3.3 of NTCIP 1203 v03. The
+// fontVersionID is calculated from the uploaded font and compared with the
+// one reported by the sign; a mismatch is reported as ErrFontVersionID,
+// together with the uploaded font. Compare the Font's VersionID with the
+// central font library to verify the sign has the expected font.
+func RetrievingFont(dms *gosnmp.GoSNMP, fontIndex int, options ...Option) (result retrievingFontResult, err error) {
+	s := newSession(dms, "RetrievingFont", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	// fontStatus is not supported by NTCIP 1203 v1 signs.
+	status, err := s.getSingle("get fontStatus", d.FontStatus.Identifier(fontIndex))
+	if err != nil && !errors.Is(err, ErrNoSuchName) {
+		return result, err
+	}
+	result.FontStatus, _ = status.Value.(int)
+	if err == nil && result.FontStatus == d.FontNotUsed.Int() {
+		return result, s.wrap(errors.Errorf("font %d is not used", fontIndex), "get fontStatus", d.FontStatus.Identifier(fontIndex))
+	}
+
+	// The management station shall GET fontNumber.x, fontName.x, fontHeight.x,
+	// fontCharSpacing.x, fontLineSpacing.x and fontVersionID.x.
+	oids := []string{
+		d.FontNumber.Identifier(fontIndex),
+		d.FontName.Identifier(fontIndex),
+		d.FontHeight.Identifier(fontIndex),
+		d.FontCharSpacing.Identifier(fontIndex),
+		d.FontLineSpacing.Identifier(fontIndex),
+		d.FontVersionID.Identifier(fontIndex),
+	}
+	getResults, err := s.get("get font", oids...)
+	if err != nil {
+		return result, err
+	}
+	font := &result.Font
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			font.Number, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[1]):
+			name, _ := variable.Value.([]byte)
+			font.Name = string(name)
+		case oidEqual(variable.Name, oids[2]):
+			font.Height, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[3]):
+			font.CharSpacing, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[4]):
+			font.LineSpacing, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[5]):
+			result.FontVersionID, _ = variable.Value.(int)
+		}
+	}
+
+	// The management station shall GET characterWidth.x.y and
+	// characterBitmap.x.y of every character, y from 1 to maxFontCharacters:
+	// the rows of the font are walked. Characters with a width of zero are
+	// not defined.
+	roots := []string{d.CharacterWidth.Identifier(fontIndex), d.CharacterBitmap.Identifier(fontIndex)}
+	columns, err := s.walkColumns("walk characters", roots...)
+	if err != nil {
+		return result, err
+	}
+	bitmaps := map[string][]byte{}
+	for _, variable := range columns[1] {
+		bitmaps[rowIndex(roots[1], variable.Name)], _ = variable.Value.([]byte)
+	}
+	for _, variable := range columns[0] {
+		index := rowIndex(roots[0], variable.Name)
+		number, err := strconv.Atoi(index)
+		width, _ := variable.Value.(int)
+		if err != nil || width == 0 {
+			continue
+		}
+		font.Characters = append(font.Characters, crc.Character{Number: number, Width: width, Bitmap: bitmaps[index]})
+	}
+
+	// The fontVersionID of the uploaded font shall match the one calculated
+	// by the sign.
+	if want := font.VersionID(); result.FontVersionID != want {
+		err = errors.Wrapf(ErrFontVersionID, "sign reports %04X, uploaded font has %04X", result.FontVersionID, want)
+		return result, s.wrap(err, "verify fontVersionID", d.FontVersionID.Identifier(fontIndex))
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestRetrievingFont(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	result, err := RetrievingFont(sign.GoSNMP(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Font{Number: 1, Name: "simulator 7", Height: 7, CharSpacing: 1, LineSpacing: 2, Characters: simulator.Font1Characters}
+	if !reflect.DeepEqual(result.Font, want) {
+		t.Errorf("RetrievingFont() font = %+v, want %+v", result.Font, want)
+	}
+	if result.FontVersionID != simulator.Font1VersionID || result.FontStatus != d.FontPermanent.Int() {
+		t.Errorf("RetrievingFont() = %04X in fontStatus %d", result.FontVersionID, result.FontStatus)
+	}
+
+	// A character that changed on the sign without its fontVersionID.
+	sign.Store(characterName(d.CharacterWidth, 1, 'A'), gosnmp.Integer, 4)
+	if _, err := RetrievingFont(sign.GoSNMP(), 1); !errors.Is(err, ErrFontVersionID) {
+		t.Errorf("RetrievingFont() error = %v, want ErrFontVersionID", err)
+	}
+
+	if _, err := RetrievingFont(sign.GoSNMP(), 2); err == nil {
+		t.Error("RetrievingFont() of an unused font succeeded")
+	}
+}
@@ -0,0 +1,90 @@
+package dialogs
+
+import (
+	"strconv"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// FontEntry is a row of the fontTable, without its characters.
+type FontEntry struct {
+	Index       int // fontIndex, the row
+	Number      int // fontNumber, as referenced by the [fo] tag
+	Name        string
+	Height      int
+	CharSpacing int
+	LineSpacing int
+	VersionID   int // fontVersionID, as calculated by the sign
+	Status      int // fontStatus, 0 for NTCIP 1203 v1 signs
+}
+
+// ListFonts returns the rows of the fontTable, walking the table with
+// GETBULK requests, see WithBulkSize. Rows in the 'notUsed' state and, on
+// NTCIP 1203 v1 signs, rows with a fontHeight of zero are left out.
+func ListFonts(dms *gosnmp.GoSNMP, options ...Option) (entries []FontEntry, err error) {
+	s := newSession(dms, "ListFonts", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return nil, err
+	}
+
+	roots := []string{
+		parentOID(d.FontNumber.Identifier(0)),
+		parentOID(d.FontName.Identifier(0)),
+		parentOID(d.FontHeight.Identifier(0)),
+		parentOID(d.FontCharSpacing.Identifier(0)),
+		parentOID(d.FontLineSpacing.Identifier(0)),
+		parentOID(d.FontVersionID.Identifier(0)),
+		parentOID(d.FontStatus.Identifier(0)),
+	}
+	columns, err := s.walkColumns("walk font table", roots...)
+	if err != nil {
+		return nil, err
+	}
+	var all []FontEntry
+	rows := map[string]int{} // the index of the entry of each row
+	for _, variable := range columns[0] {
+		index := rowIndex(roots[0], variable.Name)
+		fontIndex, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		entry := FontEntry{Index: fontIndex}
+		entry.Number, _ = variable.Value.(int)
+		rows[index] = len(all)
+		all = append(all, entry)
+	}
+	for column, variables := range columns {
+		for _, variable := range variables {
+			i, ok := rows[rowIndex(roots[column], variable.Name)]
+			if !ok {
+				continue
+			}
+			entry := &all[i]
+			value, _ := variable.Value.(int)
+			switch column {
+			case 1:
+				name, _ := variable.Value.([]byte)
+				entry.Name = string(name)
+			case 2:
+				entry.Height = value
+			case 3:
+				entry.CharSpacing = value
+			case 4:
+				entry.LineSpacing = value
+			case 5:
+				entry.VersionID = value
+			case 6:
+				entry.Status = value
+			}
+		}
+	}
+	for _, entry := range all {
+		if entry.Status == d.FontNotUsed.Int() || entry.Status == 0 && entry.Height == 0 {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
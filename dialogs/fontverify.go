@@ -0,0 +1,97 @@
+package dialogs
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// GetFontVersionIDs returns the fontVersionID of the given fontTable rows.
+// Rows without a font, i.e. a fontStatus of notUsed or a row the sign does
+// not have, are left out of the result.
+func GetFontVersionIDs(dms *gosnmp.GoSNMP, fontIndexes []int, options ...Option) (map[int]int, error) {
+	s := newSession(dms, "GetFontVersionIDs", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+
+	versionIDs := map[int]int{}
+	for _, index := range fontIndexes {
+		status, err := s.getSingle("get fontStatus", d.FontStatus.Identifier(index))
+		switch {
+		case err == nil && status.Value == d.FontNotUsed.Int():
+			continue
+		case err != nil && !errors.Is(err, ErrNoSuchName):
+			// fontStatus is not supported by NTCIP 1203 v1 signs.
+			return versionIDs, err
+		}
+		versionID, err := s.getSingle("get fontVersionID", d.FontVersionID.Identifier(index))
+		if errors.Is(err, ErrNoSuchName) {
+			continue
+		}
+		if err != nil {
+			return versionIDs, err
+		}
+		versionIDs[index], _ = versionID.Value.(int)
+	}
+	return versionIDs, nil
+}
+
+// FontDrift is a font of a sign that does not match the expected
+// fontVersionID.
+type FontDrift struct {
+	Sign      string // key of the sign in the map passed to VerifyFonts
+	FontIndex int
+	Expected  int
+	Actual    int   // fontVersionID on the sign, 0 if Missing
+	Missing   bool  // the sign has no font in the row
+	Err       error // the fontVersionIDs could not be read; FontIndex is 0
+}
+
+// VerifyFonts reads the fontVersionID of the rows in expected (fontIndex to
+// fontVersionID) from every sign, in parallel, and lists the fonts that are
+// missing or differ, ordered by sign and font index. A sign that cannot be
+// read is listed once with Err set.
+func VerifyFonts(signs map[string]*Client, expected map[int]int, options ...Option) []FontDrift {
+	fontIndexes := make([]int, 0, len(expected))
+	for index := range expected {
+		fontIndexes = append(fontIndexes, index)
+	}
+	sort.Ints(fontIndexes)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var drifts []FontDrift
+	for name, client := range signs {
+		wg.Add(1)
+		go func(name string, client *Client) {
+			defer wg.Done()
+			versionIDs, err := client.GetFontVersionIDs(fontIndexes, options...)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				drifts = append(drifts, FontDrift{Sign: name, Err: err})
+				return
+			}
+			for _, index := range fontIndexes {
+				actual, ok := versionIDs[index]
+				if !ok || actual != expected[index] {
+					drifts = append(drifts, FontDrift{Sign: name, FontIndex: index, Expected: expected[index], Actual: actual, Missing: !ok})
+				}
+			}
+		}(name, client)
+	}
+	wg.Wait()
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Sign != drifts[j].Sign {
+			return drifts[i].Sign < drifts[j].Sign
+		}
+		return drifts[i].FontIndex < drifts[j].FontIndex
+	})
+	return drifts
+}
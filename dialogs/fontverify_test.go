@@ -0,0 +1,44 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestVerifyFonts(t *testing.T) {
+	signs := map[string]*Client{}
+	for _, name := range []string{"I-5 NB", "I-5 SB"} {
+		sign, err := simulator.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sign.Close()
+		signs[name] = NewClient(sign.GoSNMP())
+		if name == "I-5 SB" {
+			sign.Store(d.FontVersionID.Identifier(1), gosnmp.Integer, 0x1111)
+		}
+	}
+	broken, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken.Close()
+	signs["offline"] = NewClient(broken.GoSNMP())
+
+	drifts := VerifyFonts(signs, map[int]int{1: simulator.Font1VersionID, 2: 0x2222})
+	if len(drifts) != 4 || drifts[3].Sign != "offline" || drifts[3].Err == nil {
+		t.Fatalf("VerifyFonts() = %+v, want 3 drifts and the offline sign", drifts)
+	}
+	want := []FontDrift{
+		{Sign: "I-5 NB", FontIndex: 2, Expected: 0x2222, Missing: true},
+		{Sign: "I-5 SB", FontIndex: 1, Expected: simulator.Font1VersionID, Actual: 0x1111},
+		{Sign: "I-5 SB", FontIndex: 2, Expected: 0x2222, Missing: true},
+	}
+	if !reflect.DeepEqual(drifts[:3], want) {
+		t.Errorf("VerifyFonts() = %+v, want %+v", drifts[:3], want)
+	}
+}
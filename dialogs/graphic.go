@@ -0,0 +1,293 @@
+package dialogs
+
+import (
+	"fmt"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+	"github.com/pkg/errors"
+)
+
+// Graphic is a graphic of the dmsGraphicTable with its bitmap.
+type Graphic struct {
+	Number             int    // dmsGraphicNumber, as referenced by the [g] tag
+	Name               string // dmsGraphicName
+	Height             int    // dmsGraphicHeight in pixels
+	Width              int    // dmsGraphicWidth in pixels
+	Type               int    // dmsGraphicType, e.g. d.GraphicMonochrome1Bit
+	TransparentEnabled bool   // dmsGraphicTransparentEnabled
+	TransparentColor   []byte // dmsGraphicTransparentColor, black if nil
+	Bitmap             []byte // the whole bitmap, split into blocks by the dialogs
+}
+
+// ID returns the dmsGraphicID a sign calculates for the graphic.
+func (g Graphic) ID() int {
+	return int(crc.GraphicID(crc.Graphic{
+		Number:             g.Number,
+		Height:             g.Height,
+		Width:              g.Width,
+		Type:               g.Type,
+		TransparentEnabled: g.TransparentEnabled,
+		TransparentColor:   g.transparentColor(),
+		Bitmap:             g.Bitmap,
+	}))
+}
+
+// transparentColor returns the TransparentColor, which defaults to black.
+func (g Graphic) transparentColor() []byte {
+	if g.TransparentColor == nil {
+		if g.Type == d.GraphicColor24Bit.Int() {
+			return []byte{0, 0, 0}
+		}
+		return []byte{0}
+	}
+	return g.TransparentColor
+}
+
+// BitmapSize returns the size in bytes of the bitmap of a graphic of the
+// graphic's height, width and type, or -1 for an unknown type.
+func (g Graphic) BitmapSize() int {
+	pixels := g.Height * g.Width
+	switch g.Type {
+	case d.GraphicMonochrome1Bit.Int():
+		return (pixels + 7) / 8
+	case d.GraphicMonochrome8Bit.Int(), d.GraphicColorClassic.Int():
+		return pixels
+	case d.GraphicColor24Bit.Int():
+		return pixels * 3
+	default:
+		return -1
+	}
+}
+
+type configuringGraphicResult struct {
+	GraphicIndex int // the dmsGraphicTable row the graphic was stored in
+	GraphicID    int
+	NextBlock    int          // the first bitmap block not transferred, to resume with WithResumeBlock
+	Timings      []StepTiming // time spent in each step of the dialog
+}
+
+// ConfiguringGraphic downloads graphic into row graphicIndex of the
+// dmsGraphicTable, as described in Clause 4.2.5.1 of NTCIP 1203 v03, or into
+// the first unused row if graphicIndex is 0. The row is put into the
+// 'modifying' state, the graphic is written and its bitmap transferred in
+// dmsGraphicBlockSize blocks through the dmsGraphicBitmapTable, and the row
+// is made 'readyForUse'. The dmsGraphicID calculated by the sign is compared
+// with the graphic's; a mismatch is reported as ErrGraphicID.
+func ConfiguringGraphic(dms *gosnmp.GoSNMP, graphicIndex int, graphic Graphic, options ...Option) (result configuringGraphicResult, err error) {
+	s := newSession(dms, "ConfiguringGraphic", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if size := graphic.BitmapSize(); size < 0 || len(graphic.Bitmap) != size {
+		return result, s.wrap(errors.Errorf("bitmap of %d bytes, want %d for a %dx%d graphic of type %d",
+			len(graphic.Bitmap), size, graphic.Width, graphic.Height, graphic.Type), "check graphic")
+	}
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	if graphicIndex == 0 {
+		if graphicIndex, err = unusedRow(s, d.DmsGraphicMaxEntries, d.DmsGraphicStatus); err != nil {
+			return result, err
+		}
+	}
+	result.GraphicIndex = graphicIndex
+
+	// The management station shall GET dmsGraphicMaxSize.0, the size of the
+	// largest bitmap, and dmsGraphicBlockSize.0.
+	maxSize, err := getInt(s, "dmsGraphicMaxSize", d.DmsGraphicMaxSize.Identifier(0))
+	if err != nil {
+		return result, err
+	}
+	if len(graphic.Bitmap) > maxSize {
+		return result, s.wrap(errors.Errorf("bitmap of %d bytes exceeds dmsGraphicMaxSize %d", len(graphic.Bitmap), maxSize), "get dmsGraphicMaxSize")
+	}
+	blockSize, err := getInt(s, "dmsGraphicBlockSize", d.DmsGraphicBlockSize.Identifier(0))
+	if err != nil {
+		return result, err
+	}
+	if blockSize < 1 {
+		return result, s.wrap(errors.Errorf("invalid dmsGraphicBlockSize %d", blockSize), "get dmsGraphicBlockSize")
+	}
+
+	// The management station shall GET dmsGraphicStatus.x; a permanent graphic
+	// or a graphic of the displayed message cannot be modified.
+	statusName := d.DmsGraphicStatus.Identifier(graphicIndex)
+	status, err := getInt(s, "dmsGraphicStatus", statusName)
+	if err != nil {
+		return result, err
+	}
+	if status == d.GraphicPermanent.Int() || status == d.GraphicInUse.Int() {
+		return result, s.wrap(errors.Errorf("graphic %d cannot be modified in dmsGraphicStatus %d", graphicIndex, status), "get dmsGraphicStatus", statusName)
+	}
+
+	// The management station shall SET dmsGraphicStatus.x to 'modifyReq' and
+	// GET dmsGraphicStatus.x until it is 'modifying'. A resumed download
+	// continues where it stopped if the row is still 'modifying'.
+	firstBlock := 1
+	if status == d.GraphicModifying.Int() && s.options.resumeBlock > 1 {
+		firstBlock = s.options.resumeBlock
+	} else if status != d.GraphicModifying.Int() {
+		if _, err = setRowStatus(s, d.DmsGraphicStatus, graphicIndex, d.GraphicModifyReq, d.GraphicModifying); err != nil {
+			return result, err
+		}
+	}
+	result.NextBlock = firstBlock
+
+	// The management station shall SET dmsGraphicNumber.x, dmsGraphicName.x,
+	// dmsGraphicHeight.x, dmsGraphicWidth.x, dmsGraphicType.x,
+	// dmsGraphicTransparentEnabled.x and dmsGraphicTransparentColor.x.
+	// They were sent already if the download is resumed.
+	if firstBlock == 1 {
+		transparentEnabled := 0
+		if graphic.TransparentEnabled {
+			transparentEnabled = 1
+		}
+		_, err = s.set("set graphic", []gosnmp.SnmpPDU{
+			{Name: d.DmsGraphicNumber.Identifier(graphicIndex), Type: d.DmsGraphicNumber.Syntax(), Value: graphic.Number},
+			{Name: d.DmsGraphicName.Identifier(graphicIndex), Type: d.DmsGraphicName.Syntax(), Value: graphic.Name},
+			{Name: d.DmsGraphicHeight.Identifier(graphicIndex), Type: d.DmsGraphicHeight.Syntax(), Value: graphic.Height},
+			{Name: d.DmsGraphicWidth.Identifier(graphicIndex), Type: d.DmsGraphicWidth.Syntax(), Value: graphic.Width},
+			{Name: d.DmsGraphicType.Identifier(graphicIndex), Type: d.DmsGraphicType.Syntax(), Value: graphic.Type},
+			{Name: d.DmsGraphicTransparentEnabled.Identifier(graphicIndex), Type: d.DmsGraphicTransparentEnabled.Syntax(), Value: transparentEnabled},
+			{Name: d.DmsGraphicTransparentColor.Identifier(graphicIndex), Type: d.DmsGraphicTransparentColor.Syntax(), Value: graphic.transparentColor()},
+		}...)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// The management station shall SET dmsGraphicBlockBitmap.x.y for every
+	// block y of the bitmap.
+	result.NextBlock, err = sendBlocks(s, graphicIndex, graphic.Bitmap, blockSize, firstBlock)
+	if err != nil {
+		return result, err
+	}
+
+	// The management station shall SET dmsGraphicStatus.x to 'readyForUseReq'
+	// and GET dmsGraphicStatus.x until it is no longer 'calculatingID'.
+	if _, err = setRowStatus(s, d.DmsGraphicStatus, graphicIndex, d.GraphicReadyForUseReq, d.GraphicReadyForUse); err != nil {
+		return result, err
+	}
+
+	// The management station shall GET dmsGraphicID.x to verify the graphic.
+	if result.GraphicID, err = getInt(s, "dmsGraphicID", d.DmsGraphicID.Identifier(graphicIndex)); err != nil {
+		return result, err
+	}
+	if want := graphic.ID(); result.GraphicID != want {
+		err = errors.Wrapf(ErrGraphicID, "sign calculated %04X, want %04X", result.GraphicID, want)
+		return result, s.wrap(err, "verify dmsGraphicID", d.DmsGraphicID.Identifier(graphicIndex))
+	}
+	return result, nil
+}
+
+type verifyingGraphicResult struct {
+	GraphicID     int // as reported by the sign
+	GraphicStatus int
+	Timings       []StepTiming
+}
+
+// VerifyingGraphic checks that row graphicIndex of the dmsGraphicTable holds
+// graphic without uploading its bitmap: the dmsGraphicID reported by the sign
+// is compared with the one calculated from graphic. A graphic that is not
+// ready for use is reported as an error, a mismatch as ErrGraphicID. Use it to
+// confirm a download or to skip downloading a graphic the sign already has.
+func VerifyingGraphic(dms *gosnmp.GoSNMP, graphicIndex int, graphic Graphic, options ...Option) (result verifyingGraphicResult, err error) {
+	s := newSession(dms, "VerifyingGraphic", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	// The management station shall GET dmsGraphicStatus.x and dmsGraphicID.x.
+	statusName, idName := d.DmsGraphicStatus.Identifier(graphicIndex), d.DmsGraphicID.Identifier(graphicIndex)
+	getResults, err := s.get("get graphic", statusName, idName)
+	if err != nil {
+		return result, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, statusName):
+			result.GraphicStatus, _ = variable.Value.(int)
+		case oidEqual(variable.Name, idName):
+			result.GraphicID, _ = variable.Value.(int)
+		}
+	}
+	switch result.GraphicStatus {
+	case d.GraphicReadyForUse.Int(), d.GraphicInUse.Int(), d.GraphicPermanent.Int():
+	default:
+		return result, s.wrap(errors.Errorf("graphic %d is not ready for use, dmsGraphicStatus %d", graphicIndex, result.GraphicStatus), "get graphic", statusName)
+	}
+	if want := graphic.ID(); result.GraphicID != want {
+		err = errors.Wrapf(ErrGraphicID, "sign reported %04X, want %04X", result.GraphicID, want)
+		return result, s.wrap(err, "verify dmsGraphicID", idName)
+	}
+	return result, nil
+}
+
+// TransferGraphicBlocks sends bitmap to the dmsGraphicBitmapTable of the
+// graphic in row graphicIndex, which must be 'modifying', in blocks of
+// dmsGraphicBlockSize bytes, starting at block firstBlock (1 for the whole
+// bitmap). A block the sign does not answer is retried as set by
+// WithBlockRetries; WithBlockProgress reports the progress. It returns the
+// first block not transferred, so an interrupted transfer can be resumed
+// from there.
+func TransferGraphicBlocks(dms *gosnmp.GoSNMP, graphicIndex int, bitmap []byte, firstBlock int, options ...Option) (nextBlock int, err error) {
+	s := newSession(dms, "TransferGraphicBlocks", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return firstBlock, err
+	}
+	blockSize, err := getInt(s, "dmsGraphicBlockSize", d.DmsGraphicBlockSize.Identifier(0))
+	if err != nil {
+		return firstBlock, err
+	}
+	if blockSize < 1 {
+		return firstBlock, s.wrap(errors.Errorf("invalid dmsGraphicBlockSize %d", blockSize), "get dmsGraphicBlockSize")
+	}
+	return sendBlocks(s, graphicIndex, bitmap, blockSize, firstBlock)
+}
+
+// sendBlocks SETs dmsGraphicBlockBitmap for the blocks of bitmap from
+// firstBlock on and returns the first block not transferred.
+func sendBlocks(s *session, graphicIndex int, bitmap []byte, blockSize, firstBlock int) (int, error) {
+	if firstBlock < 1 {
+		firstBlock = 1
+	}
+	total := (len(bitmap) + blockSize - 1) / blockSize
+	for block := firstBlock; block <= total; block++ {
+		offset := (block - 1) * blockSize
+		end := offset + blockSize
+		if end > len(bitmap) {
+			end = len(bitmap)
+		}
+		pdu := gosnmp.SnmpPDU{
+			Name:  blockName(graphicIndex, block),
+			Type:  d.DmsGraphicBlockBitmap.Syntax(),
+			Value: bitmap[offset:end],
+		}
+		step := fmt.Sprintf("set dmsGraphicBlockBitmap %d of %d", block, total)
+		_, err := s.set(step, pdu)
+		var snmpError *SNMPError
+		for retry := 0; err != nil && !errors.As(err, &snmpError) && !errors.Is(err, ErrReadOnlyClient) && retry < s.options.blockRetries; retry++ {
+			s.retried(step)
+			_, err = s.set(step, pdu)
+		}
+		if err != nil {
+			return block, err
+		}
+		if s.options.blockProgress != nil {
+			s.options.blockProgress(block, total)
+		}
+		s.progress(step, block, total)
+	}
+	return total + 1, nil
+}
+
+// blockName returns the OID of dmsGraphicBlockBitmap for a block of a
+// graphic.
+func blockName(graphicIndex, blockNumber int) string {
+	return fmt.Sprintf("%s.%d", d.DmsGraphicBlockBitmap.Identifier(graphicIndex), blockNumber)
+}
@@ -0,0 +1,137 @@
+package dialogs
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestConfiguringGraphic(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	// A 64x40 amber-on-black 8 bit graphic takes three blocks.
+	graphic := Graphic{
+		Number: 7,
+		Name:   "detour arrow",
+		Height: 40,
+		Width:  64,
+		Type:   d.GraphicMonochrome8Bit.Int(),
+		Bitmap: bytes.Repeat([]byte{0, 255}, 40*64/2),
+	}
+	result, err := ConfiguringGraphic(sign.GoSNMP(), 0, graphic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GraphicIndex != 1 || result.GraphicID != graphic.ID() {
+		t.Errorf("ConfiguringGraphic() = %+v, want graphic index 1 and dmsGraphicID %04X", result, graphic.ID())
+	}
+	if status, _ := sign.Load(d.DmsGraphicStatus.Identifier(1)); status != d.GraphicReadyForUse.Int() {
+		t.Errorf("dmsGraphicStatus = %v, want readyForUse", status)
+	}
+	report, err := MemoryUsage(sign.GoSNMP())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Graphics.Used != 1 || report.FreeGraphicMemory != simulator.GraphicMemory-len(graphic.Bitmap) {
+		t.Errorf("MemoryUsage() graphics = %+v, free %d", report.Graphics, report.FreeGraphicMemory)
+	}
+
+	graphic.Bitmap = graphic.Bitmap[1:]
+	if _, err := ConfiguringGraphic(sign.GoSNMP(), 2, graphic); err == nil {
+		t.Error("ConfiguringGraphic() accepted a bitmap of the wrong size")
+	}
+	large := Graphic{Number: 8, Height: 100, Width: 100, Type: d.GraphicMonochrome8Bit.Int(), Bitmap: make([]byte, 100*100)}
+	if _, err := ConfiguringGraphic(sign.GoSNMP(), 2, large); err == nil {
+		t.Error("ConfiguringGraphic() accepted a bitmap larger than dmsGraphicMaxSize")
+	}
+}
+
+func TestConfiguringGraphic_resume(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	graphic := Graphic{
+		Number: 3,
+		Height: 32,
+		Width:  100,
+		Type:   d.GraphicColorClassic.Int(),
+		Bitmap: bytes.Repeat([]byte{9, 0, 0, 9}, 32*100/4),
+	}
+	// The first download stops after two of the four blocks.
+	var progress []int
+	stop := func(sent, total int) {
+		progress = append(progress, sent)
+		if sent == 2 {
+			sign.Delete(blockName(4, 3))
+		}
+	}
+	result, err := ConfiguringGraphic(sign.GoSNMP(), 4, graphic, WithBlockProgress(stop), WithBlockRetries(0))
+	if err == nil || result.NextBlock != 3 {
+		t.Fatalf("ConfiguringGraphic() = %+v, %v, want to stop at block 3", result, err)
+	}
+	sign.Store(blockName(4, 3), gosnmp.OctetString, []byte{})
+
+	requests := 0
+	dms := sign.GoSNMP()
+	dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	result, err = ConfiguringGraphic(dms, 4, graphic, WithResumeBlock(result.NextBlock), WithBlockProgress(func(sent, total int) {
+		progress = append(progress, sent)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GraphicID != graphic.ID() || result.NextBlock != 5 {
+		t.Errorf("resumed ConfiguringGraphic() = %+v", result)
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+	// Sizes, status, blocks 3 and 4, readyForUseReq, status and dmsGraphicID.
+	if requests != 8 {
+		t.Errorf("resumed ConfiguringGraphic() sent %d requests, want 8", requests)
+	}
+}
+
+func TestVerifyingGraphic(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	graphic := Graphic{Number: 3, Height: 8, Width: 8, Type: d.GraphicMonochrome1Bit.Int(), Bitmap: bytes.Repeat([]byte{0x81}, 8)}
+	if _, err := VerifyingGraphic(dms, 1, graphic); err == nil {
+		t.Error("VerifyingGraphic() of an unused row succeeded")
+	}
+	if _, err := ConfiguringGraphic(dms, 1, graphic); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	result, err := VerifyingGraphic(dms, 1, graphic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GraphicID != graphic.ID() || result.GraphicStatus != d.GraphicReadyForUse.Int() || requests != 1 {
+		t.Errorf("VerifyingGraphic() = %+v in %d requests, want dmsGraphicID %04X in 1", result, requests, graphic.ID())
+	}
+
+	graphic.Bitmap[0] = 0xff
+	if _, err := VerifyingGraphic(dms, 1, graphic); !errors.Is(err, ErrGraphicID) {
+		t.Errorf("VerifyingGraphic() of a changed graphic error = %v, want ErrGraphicID", err)
+	}
+}
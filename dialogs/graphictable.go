@@ -0,0 +1,85 @@
+package dialogs
+
+import (
+	"strconv"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// GraphicEntry is a row of the dmsGraphicTable, without its bitmap.
+type GraphicEntry struct {
+	Index  int // dmsGraphicIndex, the row
+	Number int // dmsGraphicNumber, as referenced by the [g] tag
+	Name   string
+	Height int
+	Width  int
+	Type   int // dmsGraphicType, e.g. d.GraphicMonochrome1Bit
+	ID     int // dmsGraphicID, as calculated by the sign
+	Status int // dmsGraphicStatus, e.g. d.GraphicReadyForUse
+}
+
+// ListGraphics returns the rows of the dmsGraphicTable, walking the table
+// with GETBULK requests, see WithBulkSize. Rows in the 'notUsed' state are
+// left out.
+func ListGraphics(dms *gosnmp.GoSNMP, options ...Option) (entries []GraphicEntry, err error) {
+	s := newSession(dms, "ListGraphics", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return nil, err
+	}
+
+	roots := []string{
+		parentOID(d.DmsGraphicStatus.Identifier(0)),
+		parentOID(d.DmsGraphicNumber.Identifier(0)),
+		parentOID(d.DmsGraphicName.Identifier(0)),
+		parentOID(d.DmsGraphicHeight.Identifier(0)),
+		parentOID(d.DmsGraphicWidth.Identifier(0)),
+		parentOID(d.DmsGraphicType.Identifier(0)),
+		parentOID(d.DmsGraphicID.Identifier(0)),
+	}
+	columns, err := s.walkColumns("walk graphic table", roots...)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]int{} // the index of the entry of each row
+	for _, variable := range columns[0] {
+		index := rowIndex(roots[0], variable.Name)
+		graphicIndex, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		entry := GraphicEntry{Index: graphicIndex}
+		entry.Status, _ = variable.Value.(int)
+		if entry.Status != d.GraphicNotUsed.Int() {
+			rows[index] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+	for column, variables := range columns {
+		for _, variable := range variables {
+			i, ok := rows[rowIndex(roots[column], variable.Name)]
+			if !ok {
+				continue
+			}
+			entry := &entries[i]
+			value, _ := variable.Value.(int)
+			switch column {
+			case 1:
+				entry.Number = value
+			case 2:
+				name, _ := variable.Value.([]byte)
+				entry.Name = string(name)
+			case 3:
+				entry.Height = value
+			case 4:
+				entry.Width = value
+			case 5:
+				entry.Type = value
+			case 6:
+				entry.ID = value
+			}
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,84 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// SignHealthReport aggregates shortErrorStatus with the detailed error
+// tables of the errors it reports. The tables of errors that are not
+// reported are not read and stay nil.
+type SignHealthReport struct {
+	ShortErrorStatus d.ShortErrorStatusBits
+
+	PixelFailures []PixelFailure   // pixelFailureTable rows of both detection types, on a pixel error
+	LampFailures  []LampFailure    // on a lamp error
+	Photocell     *PhotocellStatus // on a photocell error
+	Climate       *ClimateStatus   // on a climate control, temperature or humidity error
+	Power         *PowerStatus     // on a power error
+	Timings       []StepTiming
+}
+
+// Healthy reports whether shortErrorStatus reports no error.
+func (r SignHealthReport) Healthy() bool { return len(r.ShortErrorStatus.Names()) == 0 }
+
+// climateErrors are the bits of shortErrorStatus reported by ClimateStatus.
+const climateErrors = d.ShortErrorTemperatureWarning | d.ShortErrorClimateControl |
+	d.ShortErrorCriticalTemperature | d.ShortErrorHumidityWarning
+
+// GetSignHealth reads shortErrorStatus and, for each pixel, lamp, photocell,
+// climate or power error it reports, walks the corresponding detailed error
+// objects of dmsStatDmsError. No test is run: the failure tables hold the
+// results of the last tests and of the sign's own monitoring.
+func GetSignHealth(dms *gosnmp.GoSNMP, options ...Option) (report SignHealthReport, err error) {
+	s := newSession(dms, "GetSignHealth", options)
+	defer s.close()
+	defer func() { report.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return report, err
+	}
+
+	bits, err := getInt(s, "shortErrorStatus", d.ShortErrorStatus.Identifier(0))
+	if err != nil {
+		return report, err
+	}
+	report.ShortErrorStatus = d.ShortErrorStatusBits(bits)
+	status := report.ShortErrorStatus
+
+	if status.PixelError() {
+		for _, detection := range []int{d.PixelTestDetection.Int(), d.MessageDisplayDetection.Int()} {
+			failures, err := getPixelFailures(s, detection)
+			if err != nil {
+				return report, err
+			}
+			report.PixelFailures = append(report.PixelFailures, failures...)
+		}
+	}
+	if status.LampError() {
+		if report.LampFailures, err = getLampFailures(s); err != nil {
+			return report, err
+		}
+	}
+	if status.PhotocellError() {
+		photocell, err := getPhotocellStatus(s)
+		if err != nil {
+			return report, err
+		}
+		report.Photocell = &photocell
+	}
+	if status&climateErrors != 0 {
+		climate, err := getClimateStatus(s)
+		if err != nil {
+			return report, err
+		}
+		report.Climate = &climate
+	}
+	if status.PowerError() {
+		power, err := getPowerStatus(s)
+		if err != nil {
+			return report, err
+		}
+		report.Power = &power
+	}
+	return report, nil
+}
@@ -0,0 +1,62 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetSignHealth(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	report, err := GetSignHealth(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Healthy() || report.PixelFailures != nil || report.Power != nil || report.Climate != nil {
+		t.Errorf("GetSignHealth() of a healthy sign = %+v", report)
+	}
+
+	sign.FailPixel(3, 4, d.PixelStuckOff.Int())
+	sign.FailLamp(2, true)
+	if _, err := PixelTest(dms); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LampTest(dms); err != nil {
+		t.Fatal(err)
+	}
+	sign.Store(d.DmsPowerStatus.Identifier(2), gosnmp.Integer, d.PowerVoltageOutOfSpec.Int())
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, (d.ShortErrorPixel | d.ShortErrorLamp | d.ShortErrorPower).Int())
+
+	report, err = GetSignHealth(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Healthy() {
+		t.Error("Healthy() = true, want false")
+	}
+	if want := []PixelFailure{{DetectionType: 1, X: 3, Y: 4, Status: d.PixelStuckOff.Int()}}; !reflect.DeepEqual(report.PixelFailures, want) {
+		t.Errorf("PixelFailures = %+v, want %+v", report.PixelFailures, want)
+	}
+	if want := []LampFailure{{Number: 2, StuckOn: true}}; !reflect.DeepEqual(report.LampFailures, want) {
+		t.Errorf("LampFailures = %+v, want %+v", report.LampFailures, want)
+	}
+	if report.Power == nil || len(report.Power.FailedSupplies()) != 1 || !report.Power.PowerError {
+		t.Errorf("Power = %+v, want the backup battery out of spec", report.Power)
+	}
+	// Only the tables of the reported errors are read.
+	if report.Photocell != nil || report.Climate != nil {
+		t.Errorf("GetSignHealth() read unreported tables: %+v", report)
+	}
+	if len(report.Timings) == 0 {
+		t.Error("GetSignHealth() recorded no timings")
+	}
+}
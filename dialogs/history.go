@@ -0,0 +1,130 @@
+package dialogs
+
+import (
+	"sync"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+)
+
+// ActivationRecord is one entry of a client's activation history.
+type ActivationRecord struct {
+	Time              time.Time
+	MessageMemoryType int
+	MessageNumber     int
+	MessageCRC        int
+	Duration          int // minutes, 65535 is infinite
+	Priority          int
+	Owner             string // requester address encoded in the activation code
+	ShortErrorStatus  d.ShortErrorStatusBits
+	Err               error // nil when the sign accepted the activation
+}
+
+// expired reports whether the sign would have stopped displaying the message
+// by t because its duration ran out.
+func (r ActivationRecord) expired(t time.Time) bool {
+	if r.Duration == 65535 {
+		return false
+	}
+	return !t.Before(r.Time.Add(time.Duration(r.Duration) * time.Minute))
+}
+
+// activationHistory is a fixed size ring buffer of activation records. The
+// most recent record is kept apart, even with a size of zero, for
+// WithDuplicateWindow.
+type activationHistory struct {
+	mu      sync.Mutex
+	records []ActivationRecord
+	next    int
+	full    bool
+	last    *ActivationRecord
+}
+
+func newActivationHistory(size int) *activationHistory {
+	if size < 0 {
+		size = 0
+	}
+	return &activationHistory{records: make([]ActivationRecord, size)}
+}
+
+func (h *activationHistory) add(record ActivationRecord) {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = &record
+	if len(h.records) == 0 {
+		return
+	}
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the retained records, oldest first.
+func (h *activationHistory) list() []ActivationRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full {
+		return append([]ActivationRecord{}, h.records[:h.next]...)
+	}
+	return append(append([]ActivationRecord{}, h.records[h.next:]...), h.records[:h.next]...)
+}
+
+// latest returns the most recent record, retained or not.
+func (h *activationHistory) latest() (ActivationRecord, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.last == nil {
+		return ActivationRecord{}, false
+	}
+	return *h.last, true
+}
+
+// ActivationHistory returns the activations retained by the client, oldest
+// first. The number of retained records is set with WithActivationHistory.
+func (c *Client) ActivationHistory() []ActivationRecord {
+	return c.history.list()
+}
+
+// DisplayedAt answers "what was on this sign at t" from the activation
+// history: it returns the most recent successful activation made at or before
+// t whose duration had not run out yet. The answer only covers activations made
+// through this client.
+func (c *Client) DisplayedAt(t time.Time) (ActivationRecord, bool) {
+	records := c.history.list()
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if record.Err != nil || record.Time.After(t) {
+			continue
+		}
+		if record.expired(t) {
+			return ActivationRecord{}, false
+		}
+		return record, true
+	}
+	return ActivationRecord{}, false
+}
+
+// duplicateCheck returns a check for activations repeating the most recent
+// activation of the client within window while it is still displayed.
+func (c *Client) duplicateCheck(window time.Duration) func(messageMemoryType, messageNumber, messageCRC, priority int) bool {
+	return func(messageMemoryType, messageNumber, messageCRC, priority int) bool {
+		last, ok := c.history.latest()
+		if !ok {
+			return false
+		}
+		now := time.Now()
+		return last.Err == nil &&
+			last.MessageMemoryType == messageMemoryType &&
+			last.MessageNumber == messageNumber &&
+			last.MessageCRC == messageCRC &&
+			last.Priority == priority &&
+			now.Sub(last.Time) < window &&
+			!last.expired(now)
+	}
+}
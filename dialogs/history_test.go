@@ -0,0 +1,123 @@
+package dialogs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func Test_activationHistory(t *testing.T) {
+	h := newActivationHistory(3)
+	base := time.Date(2022, 5, 9, 14, 0, 0, 0, time.UTC)
+	for i := 1; i <= 4; i++ {
+		h.add(ActivationRecord{Time: base.Add(time.Duration(i) * time.Minute), MessageNumber: i})
+	}
+
+	got := h.list()
+	if len(got) != 3 {
+		t.Fatalf("list() returned %d records, want 3", len(got))
+	}
+	for i, want := range []int{2, 3, 4} {
+		if got[i].MessageNumber != want {
+			t.Errorf("list()[%d].MessageNumber = %d, want %d", i, got[i].MessageNumber, want)
+		}
+	}
+}
+
+func TestClient_DisplayedAt(t *testing.T) {
+	base := time.Date(2022, 5, 9, 14, 0, 0, 0, time.UTC)
+	c := NewClient(nil)
+	c.history.add(ActivationRecord{Time: base, MessageNumber: 1, Duration: 65535})
+	c.history.add(ActivationRecord{Time: base.Add(30 * time.Minute), MessageNumber: 2, Duration: 65535})
+	c.history.add(ActivationRecord{Time: base.Add(31 * time.Minute), MessageNumber: 3, Err: errors.New("genErr")})
+	c.history.add(ActivationRecord{Time: base.Add(40 * time.Minute), MessageNumber: 4, Duration: 5})
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		want   int
+		wantOk bool
+	}{
+		{name: "before any activation", at: base.Add(-time.Minute), wantOk: false},
+		{name: "first message", at: base.Add(10 * time.Minute), want: 1, wantOk: true},
+		{name: "failed activation is skipped", at: base.Add(32 * time.Minute), want: 2, wantOk: true},
+		{name: "within duration", at: base.Add(44 * time.Minute), want: 4, wantOk: true},
+		{name: "duration expired", at: base.Add(45 * time.Minute), wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.DisplayedAt(tt.at)
+			if ok != tt.wantOk {
+				t.Fatalf("DisplayedAt() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got.MessageNumber != tt.want {
+				t.Errorf("DisplayedAt() = message %d, want %d", got.MessageNumber, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_duplicateWindow(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	client := NewClient(sign.GoSNMP(), WithDuplicateWindow(time.Minute))
+	if _, err := client.DefiningMessage(3, 1, "[jp3]TEST", "10.0.0.5", 100, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		priority  int
+		duplicate bool
+	}{
+		{name: "first", priority: 100, duplicate: false},
+		{name: "repeated", priority: 100, duplicate: true},
+		{name: "other priority", priority: 150, duplicate: false},
+	}
+	for _, tt := range tests {
+		result, err := client.ActivatingMessage(65535, tt.priority, 3, 1)
+		if err != nil {
+			t.Fatalf("%s: ActivatingMessage() error = %v", tt.name, err)
+		}
+		if result.Duplicate != tt.duplicate {
+			t.Errorf("%s: ActivatingMessage() Duplicate = %v, want %v", tt.name, result.Duplicate, tt.duplicate)
+		}
+	}
+	if got := len(client.ActivationHistory()); got != 2 {
+		t.Errorf("ActivationHistory() has %d records, want 2", got)
+	}
+}
+
+func TestWithActivationHistory(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	// Without a history, activations are still coalesced.
+	client := NewClient(sign.GoSNMP(), WithActivationHistory(0), WithDuplicateWindow(time.Minute))
+	if _, err := client.DefiningMessage(3, 1, "[jp3]TEST", "10.0.0.5", 100, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i, duplicate := range []bool{false, true} {
+		result, err := client.ActivatingMessage(65535, 100, 3, 1)
+		if err != nil || result.Duplicate != duplicate {
+			t.Errorf("ActivatingMessage() %d = %+v, %v, want Duplicate %v", i, result, err, duplicate)
+		}
+	}
+	if history := client.ActivationHistory(); len(history) != 0 {
+		t.Errorf("ActivationHistory() = %+v, want none", history)
+	}
+
+	client = NewClient(sign.GoSNMP(), WithActivationHistory(-1))
+	if _, err := client.GetControlMode(); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("GetControlMode() with a negative history size error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package dialogs
+
+import (
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+)
+
+// ErrWriteNotAllowed is returned by the interceptor of AllowWrites for a SET
+// of an object outside its allow-list.
+var ErrWriteNotAllowed = errors.New("write of object not allowed")
+
+// Operation is an SNMP request of a dialog, as seen by an Interceptor.
+type Operation struct {
+	Kind   string // "get", "getnext", "getbulk" or "set"
+	Dialog string // e.g. "ActivatingMessage"
+	Step   string // e.g. "set dmsActivateMessage"
+	Target string // address of the sign, host:port
+
+	// OIDs are the objects of a GET or those a GETNEXT or GETBULK of a walk
+	// starts after. Before may replace them.
+	OIDs []string
+	// PDUs are the objects and values of a SET. Before may replace them;
+	// OIDs follows.
+	PDUs []gosnmp.SnmpPDU
+	// MaxRepetitions is the number of rows a GETBULK asks for.
+	MaxRepetitions int
+}
+
+func (op Operation) request() request {
+	r := request{operation: op.Kind, step: op.Step, oids: op.OIDs, pdus: op.PDUs, maxRepetitions: op.MaxRepetitions}
+	if op.Kind == "set" {
+		r.oids = pduNames(op.PDUs)
+	}
+	return r
+}
+
+// Interceptor is called around each SNMP request of the dialogs, e.g. to
+// audit the SETs, count the requests or refuse some of them. Either
+// function may be nil.
+type Interceptor struct {
+	// Before is called before the request is sent, and may change it. If it
+	// returns an error, the request is not sent and the dialog fails with
+	// the error.
+	Before func(op *Operation) error
+	// After is called with the response and the error of the request, and
+	// returns the error the dialog sees: err itself unless it overrides it.
+	After func(op Operation, result *gosnmp.SnmpPacket, err error) error
+}
+
+// WithInterceptor adds interceptors around the SNMP requests of the dialogs.
+// The Before functions are called in the order the interceptors are added,
+// the After functions in the reverse order, so that the first interceptor
+// wraps the others. Passed to NewClient, they apply to every dialog of the
+// client, before those passed to a dialog.
+func WithInterceptor(interceptors ...Interceptor) Option {
+	return func(o *dialogOptions) {
+		o.interceptors = append(append([]Interceptor{}, o.interceptors...), interceptors...)
+	}
+}
+
+// AllowWrites returns an interceptor refusing, with ErrWriteNotAllowed, the
+// SETs of objects outside the subtrees of prefixes, e.g. to let an
+// application activate messages but not change the configuration of signs.
+func AllowWrites(prefixes ...string) Interceptor {
+	return Interceptor{Before: func(op *Operation) error {
+		if op.Kind != "set" {
+			return nil
+		}
+		for _, pdu := range op.PDUs {
+			if !underAny(pdu.Name, prefixes) {
+				return errors.Wrap(ErrWriteNotAllowed, strings.TrimPrefix(pdu.Name, "."))
+			}
+		}
+		return nil
+	}}
+}
+
+// underAny reports whether oid is one of prefixes or in their subtrees.
+func underAny(oid string, prefixes []string) bool {
+	oid = strings.TrimPrefix(oid, ".")
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(strings.TrimPrefix(prefix, "."), ".")
+		if oid == prefix || strings.HasPrefix(oid, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// intercept returns the operation of r after the Before functions.
+func (s *session) intercept(r request) (Operation, error) {
+	op := Operation{
+		Kind:   r.operation,
+		Dialog: s.dialog,
+		Step:   r.step,
+		Target: s.target(),
+		OIDs:   r.oids,
+		PDUs:   r.pdus,
+
+		MaxRepetitions: r.maxRepetitions,
+	}
+	for _, interceptor := range s.options.interceptors {
+		if interceptor.Before == nil {
+			continue
+		}
+		if err := interceptor.Before(&op); err != nil {
+			return op, err
+		}
+	}
+	return op, nil
+}
+
+// interceptResult returns the error of op after the After functions.
+func (s *session) interceptResult(op Operation, result *gosnmp.SnmpPacket, err error) error {
+	for i := len(s.options.interceptors) - 1; i >= 0; i-- {
+		if after := s.options.interceptors[i].After; after != nil {
+			err = after(op, result, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package dialogs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+	"github.com/pkg/errors"
+)
+
+func TestWithInterceptor(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	var calls []string
+	trace := func(name string) Interceptor {
+		return Interceptor{
+			Before: func(op *Operation) error {
+				calls = append(calls, name+" before "+op.Kind+" "+op.Step)
+				return nil
+			},
+			After: func(op Operation, result *gosnmp.SnmpPacket, err error) error {
+				calls = append(calls, name+" after "+op.Kind)
+				return err
+			},
+		}
+	}
+	client := NewClient(sign.GoSNMP(), WithInterceptor(trace("outer")))
+	if _, err := client.GetAction(1, WithInterceptor(trace("inner"))); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"outer before get get dmsActionMsgCode 1",
+		"inner before get get dmsActionMsgCode 1",
+		"inner after get",
+		"outer after get",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+
+	// Before may change the request: piggyback shortErrorStatus on the GET.
+	var status int
+	piggyback := Interceptor{
+		Before: func(op *Operation) error {
+			op.OIDs = append(append([]string{}, op.OIDs...), d.ShortErrorStatus.Identifier(0))
+			return nil
+		},
+		After: func(op Operation, result *gosnmp.SnmpPacket, err error) error {
+			if err == nil && len(result.Variables) == 2 {
+				status, _ = result.Variables[1].Value.(int)
+			}
+			return err
+		},
+	}
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, int(d.ShortErrorPixel))
+	if _, err := GetAction(sign.GoSNMP(), 1, WithInterceptor(piggyback)); err != nil {
+		t.Fatal(err)
+	}
+	if status != int(d.ShortErrorPixel) {
+		t.Errorf("piggybacked shortErrorStatus = %d, want %d", status, d.ShortErrorPixel)
+	}
+
+	errDenied := errors.New("denied")
+	override := Interceptor{After: func(op Operation, result *gosnmp.SnmpPacket, err error) error { return errDenied }}
+	if _, err := GetAction(sign.GoSNMP(), 1, WithInterceptor(override)); !errors.Is(err, errDenied) {
+		t.Errorf("GetAction() error = %v, want the error of After", err)
+	}
+}
+
+func TestAllowWrites(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	// Allow writing the dmsActionTable but nothing else.
+	allow := WithInterceptor(AllowWrites(strings.TrimSuffix(d.DmsActionMsgCode.Identifier(0), ".0")))
+	action, err := NewScheduleAction(1, d.Message{MemoryType: d.MemoryBlank, Number: 1}, 65535, 255, "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetAction(sign.GoSNMP(), action, allow); err != nil {
+		t.Errorf("SetAction() error = %v, want the write allowed", err)
+	}
+	if _, err := BlankSign(sign.GoSNMP(), 255, allow); !errors.Is(err, ErrWriteNotAllowed) {
+		t.Errorf("BlankSign() error = %v, want ErrWriteNotAllowed", err)
+	}
+	current, err := RetrievingCurrentMessage(sign.GoSNMP(), allow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.MessageMemoryType == d.MemoryBlank.Int() && current.RunTimePriority == 255 {
+		t.Errorf("sign was blanked despite the allow-list")
+	}
+}
+
+func Test_underAny(t *testing.T) {
+	prefixes := []string{"1.3.6.1.4.1.1206.4.2.3.6"}
+	tests := []struct {
+		oid  string
+		want bool
+	}{
+		{oid: "1.3.6.1.4.1.1206.4.2.3.6", want: true},
+		{oid: ".1.3.6.1.4.1.1206.4.2.3.6.3.0", want: true},
+		{oid: "1.3.6.1.4.1.1206.4.2.3.60.1", want: false},
+		{oid: "1.3.6.1.4.1.1206.4.2.3.5.1", want: false},
+	}
+	for _, tt := range tests {
+		if got := underAny(tt.oid, prefixes); got != tt.want {
+			t.Errorf("underAny(%s) = %v, want %v", tt.oid, got, tt.want)
+		}
+	}
+}
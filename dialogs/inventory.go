@@ -0,0 +1,115 @@
+package dialogs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// Module is a hardware or software module of the sign, a row of the
+// globalModuleTable.
+type Module struct {
+	Number     int    // moduleNumber
+	DeviceNode string // moduleDeviceNode, e.g. the dms node for the sign controller
+	Make       string // moduleMake, the manufacturer
+	Model      string // moduleModel
+	Version    string // moduleVersion: the hardware revision or the firmware version
+	Type       int    // moduleType, e.g. d.ModuleSoftware.Int()
+}
+
+// Inventory is the list of modules of a sign, ordered by moduleNumber.
+type Inventory []Module
+
+// Hardware returns the hardware modules of the inventory.
+func (inventory Inventory) Hardware() Inventory {
+	return inventory.ofType(d.ModuleHardware.Int())
+}
+
+// Software returns the software modules of the inventory, whose versions
+// are the firmware versions of the sign.
+func (inventory Inventory) Software() Inventory {
+	return inventory.ofType(d.ModuleSoftware.Int())
+}
+
+func (inventory Inventory) ofType(moduleType int) Inventory {
+	var modules Inventory
+	for _, module := range inventory {
+		if module.Type == moduleType {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// moduleColumns are the columns of the globalModuleTable read for each
+// module, after moduleNumber.
+var moduleColumns = []d.Reader{
+	d.ModuleDeviceNode,
+	d.ModuleMake,
+	d.ModuleModel,
+	d.ModuleVersion,
+	d.ModuleType,
+}
+
+// GetInventory reads the modules of the sign. The rows of the
+// globalModuleTable are found by walking moduleNumber, as a sign may leave
+// gaps in the table.
+func GetInventory(dms *gosnmp.GoSNMP, options ...Option) (Inventory, error) {
+	s := newSession(dms, "GetInventory", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return getInventory(s)
+}
+
+func getInventory(s *session) (Inventory, error) {
+	root := strings.TrimSuffix(d.ModuleNumber.Identifier(0), ".0")
+	numbers, err := s.walk("walk moduleNumber", root)
+	if err != nil {
+		return nil, err
+	}
+	prefix := root + "."
+	var inventory Inventory
+	for _, variable := range numbers {
+		number, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(variable.Name, "."), prefix))
+		if err != nil {
+			continue
+		}
+		module := Module{Number: number}
+		oids := make([]string, len(moduleColumns))
+		for i, column := range moduleColumns {
+			oids[i] = column.Identifier(number)
+		}
+		getResults, err := s.get(fmt.Sprintf("get moduleTableEntry %d", number), oids...)
+		if err != nil {
+			return inventory, err
+		}
+		for _, variable := range getResults.Variables {
+			switch {
+			case oidEqual(variable.Name, oids[0]):
+				oid, _ := variable.Value.(string)
+				module.DeviceNode = strings.TrimPrefix(oid, ".")
+			case oidEqual(variable.Name, oids[1]):
+				module.Make = octetString(variable.Value)
+			case oidEqual(variable.Name, oids[2]):
+				module.Model = octetString(variable.Value)
+			case oidEqual(variable.Name, oids[3]):
+				module.Version = octetString(variable.Value)
+			case oidEqual(variable.Name, oids[4]):
+				module.Type, _ = variable.Value.(int)
+			}
+		}
+		inventory = append(inventory, module)
+	}
+	return inventory, nil
+}
+
+// octetString returns the text of an OCTET STRING value.
+func octetString(value interface{}) string {
+	text, _ := value.([]byte)
+	return string(text)
+}
@@ -0,0 +1,36 @@
+package dialogs
+
+import (
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetInventory(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	// A gap at the start of the table.
+	sign.Delete(d.ModuleNumber.Identifier(1))
+
+	inventory, err := client.GetInventory()
+	if err != nil {
+		t.Fatalf("GetInventory() error = %v", err)
+	}
+	if len(inventory) != 2 || inventory[0].Number != 2 || inventory[1].Number != 3 {
+		t.Fatalf("GetInventory() = %+v, want modules 2 and 3", inventory)
+	}
+	if module := inventory[0]; module.Make != "Simulated Signs" || module.Model != "SC-100 firmware" || module.Version != "4.2.1" ||
+		module.DeviceNode != "1.3.6.1.4.1.1206.4.2.3" || module.Type != d.ModuleSoftware.Int() {
+		t.Errorf("GetInventory() module 2 = %+v", module)
+	}
+	if hardware, software := inventory.Hardware(), inventory.Software(); len(hardware) != 1 || hardware[0].Number != 3 ||
+		len(software) != 1 || software[0].Version != "4.2.1" {
+		t.Errorf("Hardware() = %+v, Software() = %+v", hardware, software)
+	}
+}
@@ -0,0 +1,75 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// LampFailure is a failed lamp reported by the lamp test.
+type LampFailure struct {
+	Number   int  // lamp number, from 1
+	StuckOn  bool // set in lampFailureStuckOn
+	StuckOff bool // set in lampFailureStuckOff
+}
+
+type lampTestResult struct {
+	Failures []LampFailure // ordered by lamp number
+	Timings  []StepTiming
+}
+
+// LampTest runs the lamp test of a lamp technology sign: lampTestActivation
+// is SET to 'test' and polled until the sign sets it back to 'noTest',
+// bounded by WithTestTimeout, then lampFailureStuckOn and
+// lampFailureStuckOff are read and decoded into the failed lamps.
+func LampTest(dms *gosnmp.GoSNMP, options ...Option) (result lampTestResult, err error) {
+	s := newSession(dms, "LampTest", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	if err = runTest(s, d.LampTestActivation); err != nil {
+		return result, err
+	}
+
+	result.Failures, err = getLampFailures(s)
+	return result, err
+}
+
+// getLampFailures reads lampFailureStuckOn and lampFailureStuckOff.
+func getLampFailures(s *session) ([]LampFailure, error) {
+	stuckOnName, stuckOffName := d.LampFailureStuckOn.Identifier(0), d.LampFailureStuckOff.Identifier(0)
+	getResults, err := s.get("get lamp failures", stuckOnName, stuckOffName)
+	if err != nil {
+		return nil, err
+	}
+	var stuckOn, stuckOff []byte
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, stuckOnName):
+			stuckOn, _ = variable.Value.([]byte)
+		case oidEqual(variable.Name, stuckOffName):
+			stuckOff, _ = variable.Value.([]byte)
+		}
+	}
+	return lampFailures(stuckOn, stuckOff), nil
+}
+
+// lampFailures decodes the lamp bitmaps, in which bit 0 of the first octet
+// is lamp 1.
+func lampFailures(stuckOn, stuckOff []byte) []LampFailure {
+	bit := func(bitmap []byte, i int) bool { return i/8 < len(bitmap) && bitmap[i/8]&(1<<(i%8)) != 0 }
+	size := len(stuckOn)
+	if len(stuckOff) > size {
+		size = len(stuckOff)
+	}
+	var failures []LampFailure
+	for i := 0; i < size*8; i++ {
+		failure := LampFailure{Number: i + 1, StuckOn: bit(stuckOn, i), StuckOff: bit(stuckOff, i)}
+		if failure.StuckOn || failure.StuckOff {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
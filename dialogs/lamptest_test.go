@@ -0,0 +1,33 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestLampTest(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.FailLamp(1, true)
+	sign.FailLamp(12, false)
+
+	result, err := LampTest(sign.GoSNMP())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LampFailure{
+		{Number: 1, StuckOn: true},
+		{Number: 12, StuckOff: true},
+	}
+	if !reflect.DeepEqual(result.Failures, want) {
+		t.Errorf("LampTest() = %+v, want %+v", result.Failures, want)
+	}
+	if n := len(result.Timings); n == 0 || result.Timings[n-1].Step != "get lamp failures" {
+		t.Errorf("LampTest() timings = %v", result.Timings)
+	}
+}
@@ -0,0 +1,236 @@
+package dialogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// LibraryVersion is the version of the library schema written by
+// BackupLibrary.
+const LibraryVersion = 1
+
+// Library is the message library of a sign: its changeable and volatile
+// messages. It is archived as JSON, which YAML 1.2 tools read as well:
+//
+//	{
+//	  "version": 1,
+//	  "sign": "10.0.0.20:161",
+//	  "created": "2024-05-01T08:00:00Z",
+//	  "messages": [
+//	    {
+//	      "memoryType": 3,
+//	      "number": 1,
+//	      "multi": "[jp3]ROAD WORK[nl]AHEAD",
+//	      "owner": "10.0.0.5",
+//	      "runTimePriority": 50,
+//	      "beacon": 0,
+//	      "pixelService": 0,
+//	      "crc": 18110
+//	    }
+//	  ]
+//	}
+//
+// memoryType is the dmsMessageMemoryType, 3 (changeable) or 4 (volatile).
+// crc is the dmsMessageCRC reported by the sign; it is informative and
+// recalculated from multi, beacon and pixelService when the library is
+// read.
+type Library struct {
+	Version  int              `json:"version"`
+	Sign     string           `json:"sign,omitempty"` // address of the sign the library was read from
+	Created  time.Time        `json:"created"`
+	Messages []LibraryMessage `json:"messages"`
+}
+
+// LibraryMessage is a message of a Library.
+type LibraryMessage struct {
+	MemoryType      int    `json:"memoryType"`
+	Number          int    `json:"number"`
+	MultiString     string `json:"multi"`
+	Owner           string `json:"owner"`
+	RunTimePriority int    `json:"runTimePriority"`
+	Beacon          int    `json:"beacon"`
+	PixelService    int    `json:"pixelService"`
+	CRC             int    `json:"crc"`
+}
+
+// BackupLibrary reads the valid changeable and volatile messages of the sign
+// into a Library. Signs without dmsMessageBeacon or dmsMessagePixelService
+// report them as 0.
+func BackupLibrary(dms *gosnmp.GoSNMP, options ...Option) (library Library, err error) {
+	s := newSession(dms, "BackupLibrary", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return library, err
+	}
+
+	library = Library{Version: LibraryVersion, Sign: s.target(), Created: time.Now().UTC()}
+	for _, messageMemoryType := range []int{d.MemoryChangeable.Int(), d.MemoryVolatile.Int()} {
+		entries, err := listMessages(s, messageMemoryType)
+		if err != nil {
+			return library, err
+		}
+		for _, entry := range entries {
+			if entry.Status != d.Valid.Int() {
+				continue
+			}
+			message := LibraryMessage{
+				MemoryType:      entry.MessageMemoryType,
+				Number:          entry.MessageNumber,
+				MultiString:     entry.MultiString,
+				Owner:           entry.Owner,
+				RunTimePriority: entry.RunTimePriority,
+				CRC:             entry.MessageCRC,
+			}
+			if message.Beacon, err = getOptionalInt(s, d.DmsMessageBeacon, entry.MessageMemoryType, entry.MessageNumber); err != nil {
+				return library, err
+			}
+			if message.PixelService, err = getOptionalInt(s, d.DmsMessagePixelService, entry.MessageMemoryType, entry.MessageNumber); err != nil {
+				return library, err
+			}
+			library.Messages = append(library.Messages, message)
+		}
+	}
+	return library, nil
+}
+
+// getOptionalInt reads an optional dmsMessageTable column, 0 if the sign
+// does not support it.
+func getOptionalInt(s *session, column interface {
+	ObjectType() string
+	Identifier(messageMemoryType, messageNumber int) string
+}, messageMemoryType, messageNumber int) (int, error) {
+	value, err := getInt(s, column.ObjectType(), column.Identifier(messageMemoryType, messageNumber))
+	if errors.Is(err, ErrNoSuchName) {
+		return 0, nil
+	}
+	return value, err
+}
+
+// WriteJSON writes the library as indented JSON.
+func (l Library) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// ReadLibrary reads a library written by WriteJSON. The CRC of every message
+// is recalculated from its MULTI string, beacon and pixel service.
+func ReadLibrary(r io.Reader) (Library, error) {
+	var library Library
+	if err := json.NewDecoder(r).Decode(&library); err != nil {
+		return library, errors.Wrap(err, "decode message library")
+	}
+	if library.Version != LibraryVersion {
+		return library, errors.Errorf("message library version %d, want %d", library.Version, LibraryVersion)
+	}
+	for i, message := range library.Messages {
+		if message.MemoryType != d.MemoryChangeable.Int() && message.MemoryType != d.MemoryVolatile.Int() {
+			return library, errors.Errorf("message %d: memory type %d is neither changeable nor volatile", i+1, message.MemoryType)
+		}
+		library.Messages[i].CRC = calcChecksum(message.MultiString, message.Beacon, message.PixelService)
+	}
+	return library, nil
+}
+
+// SyncActionKind is what SyncLibrary does to a message of the sign.
+type SyncActionKind string
+
+const (
+	SyncDelete    SyncActionKind = "delete"    // the message is not in the library
+	SyncOverwrite SyncActionKind = "overwrite" // the message differs from the library
+	SyncDefine    SyncActionKind = "define"    // the row of the library message is unused
+)
+
+// SyncAction is a change SyncLibrary makes to the sign.
+type SyncAction struct {
+	Kind    SyncActionKind
+	Message LibraryMessage // the library message, or for SyncDelete the message on the sign
+}
+
+type syncLibraryResult struct {
+	Actions []SyncAction
+	Applied int  // number of Actions carried out, 0 on a dry run
+	DryRun  bool // the actions were not carried out, see WithDryRun
+	Timings []StepTiming
+}
+
+// SyncLibrary makes the changeable and volatile messages of the sign match
+// library. Messages are matched by memory type and number; a message is
+// overwritten when its dmsMessageCRC, owner or run-time priority differs
+// from the library, and messages of the sign missing from the library are
+// deleted. Identical messages are left alone. The deletions are made first
+// to free memory, then the messages are defined with DefiningMessage. With
+// WithDryRun the sign is only read and the actions that would be taken are
+// returned.
+func SyncLibrary(dms *gosnmp.GoSNMP, library Library, options ...Option) (result syncLibraryResult, err error) {
+	s := newSession(dms, "SyncLibrary", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	wanted := make(map[[2]int]LibraryMessage, len(library.Messages))
+	for _, message := range library.Messages {
+		wanted[[2]int{message.MemoryType, message.Number}] = message
+	}
+	var defines []SyncAction
+	for _, messageMemoryType := range []int{d.MemoryChangeable.Int(), d.MemoryVolatile.Int()} {
+		entries, err := listMessages(s, messageMemoryType)
+		if err != nil {
+			return result, err
+		}
+		current := make(map[int]MessageEntry, len(entries))
+		for _, entry := range entries {
+			current[entry.MessageNumber] = entry
+			message, ok := wanted[[2]int{messageMemoryType, entry.MessageNumber}]
+			switch {
+			case !ok:
+				result.Actions = append(result.Actions, SyncAction{Kind: SyncDelete, Message: LibraryMessage{
+					MemoryType:      messageMemoryType,
+					Number:          entry.MessageNumber,
+					MultiString:     entry.MultiString,
+					Owner:           entry.Owner,
+					RunTimePriority: entry.RunTimePriority,
+					CRC:             entry.MessageCRC,
+				}})
+			case entry.Status != d.Valid.Int() || entry.MessageCRC != message.CRC ||
+				entry.Owner != message.Owner || entry.RunTimePriority != message.RunTimePriority:
+				defines = append(defines, SyncAction{Kind: SyncOverwrite, Message: message})
+			}
+		}
+		for _, message := range library.Messages {
+			if _, ok := current[message.Number]; message.MemoryType == messageMemoryType && !ok {
+				defines = append(defines, SyncAction{Kind: SyncDefine, Message: message})
+			}
+		}
+	}
+	result.Actions = append(result.Actions, defines...)
+	if s.options.dryRun {
+		result.DryRun = true
+		return result, nil
+	}
+
+	for _, action := range result.Actions {
+		m := action.Message
+		s.progress(fmt.Sprintf("%s message %d.%d", action.Kind, m.MemoryType, m.Number), result.Applied, len(result.Actions))
+		if action.Kind == SyncDelete {
+			name := d.DmsMessageStatus.Identifier(m.MemoryType, m.Number)
+			_, err = s.set("set dmsMessageStatus to notUsedReq", gosnmp.SnmpPDU{Name: name, Type: gosnmp.Integer, Value: d.NotUsedReq.Int()})
+		} else {
+			_, err = defineMessage(s, m.MemoryType, m.Number, m.MultiString, m.Owner, m.RunTimePriority, m.Beacon, m.PixelService)
+		}
+		if err != nil {
+			return result, errors.Wrapf(err, "%s message %d.%d", action.Kind, m.MemoryType, m.Number)
+		}
+		result.Applied++
+	}
+	s.progress("sync library", result.Applied, len(result.Actions))
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package dialogs
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestBackupLibrary(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), 2, "[jp3]ROAD WORK[nl]AHEAD", "10.0.0.5", 50, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DefiningMessage(dms, d.MemoryVolatile.Int(), 1, "DETOUR", "10.0.0.6", 80, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	library, err := BackupLibrary(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LibraryMessage{
+		{MemoryType: 3, Number: 2, MultiString: "[jp3]ROAD WORK[nl]AHEAD", Owner: "10.0.0.5", RunTimePriority: 50, CRC: calcChecksum("[jp3]ROAD WORK[nl]AHEAD", 0, 0)},
+		{MemoryType: 4, Number: 1, MultiString: "DETOUR", Owner: "10.0.0.6", RunTimePriority: 80, CRC: calcChecksum("DETOUR", 0, 0)},
+	}
+	if library.Version != LibraryVersion || !reflect.DeepEqual(library.Messages, want) {
+		t.Errorf("BackupLibrary() = %+v, want messages %+v", library, want)
+	}
+
+	var b bytes.Buffer
+	if err := library.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadLibrary(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Messages, library.Messages) || !read.Created.Equal(library.Created) {
+		t.Errorf("ReadLibrary() = %+v, want %+v", read, library)
+	}
+
+	for _, data := range []string{`{"version": 2}`, `{"version": 1, "messages": [{"memoryType": 2}]}`, `[`} {
+		if _, err := ReadLibrary(strings.NewReader(data)); err == nil {
+			t.Errorf("ReadLibrary(%s) succeeded", data)
+		}
+	}
+}
+
+func TestSyncLibrary(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for messageNumber, multi := range map[int]string{1: "KEEP", 2: "OLD TEXT", 3: "DELETE ME"} {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), messageNumber, multi, "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	library := Library{Version: LibraryVersion, Messages: []LibraryMessage{
+		{MemoryType: 3, Number: 1, MultiString: "KEEP", Owner: "10.0.0.5", RunTimePriority: 50},
+		{MemoryType: 3, Number: 2, MultiString: "NEW TEXT", Owner: "10.0.0.5", RunTimePriority: 50},
+		{MemoryType: 4, Number: 5, MultiString: "DETOUR", Owner: "10.0.0.5", RunTimePriority: 80},
+	}}
+	for i, message := range library.Messages {
+		library.Messages[i].CRC = calcChecksum(message.MultiString, 0, 0)
+	}
+
+	plan, err := SyncLibrary(dms, library, WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, action := range plan.Actions {
+		kinds = append(kinds, fmt.Sprintf("%s %d.%d", action.Kind, action.Message.MemoryType, action.Message.Number))
+	}
+	if want := []string{"delete 3.3", "overwrite 3.2", "define 4.5"}; !reflect.DeepEqual(kinds, want) || plan.Applied != 0 || !plan.DryRun {
+		t.Errorf("SyncLibrary() dry run = %v, applied %d, want %v", kinds, plan.Applied, want)
+	}
+	if entries, _ := ListMessages(dms, d.MemoryChangeable.Int()); len(entries) != 3 {
+		t.Errorf("dry run changed the sign: %+v", entries)
+	}
+
+	result, err := SyncLibrary(dms, library)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Applied != 3 {
+		t.Errorf("SyncLibrary() applied %d actions, want 3", result.Applied)
+	}
+	backup, err := BackupLibrary(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(backup.Messages, library.Messages) {
+		t.Errorf("messages after SyncLibrary() = %+v, want %+v", backup.Messages, library.Messages)
+	}
+
+	// A second sync has nothing to do.
+	if result, err := SyncLibrary(dms, library); err != nil || len(result.Actions) != 0 {
+		t.Errorf("second SyncLibrary() = %+v, %v, want no actions", result.Actions, err)
+	}
+
+	// A message whose owner changed is written again.
+	library.Messages[0].Owner = "10.0.0.9"
+	result, err = SyncLibrary(dms, library)
+	if err != nil || len(result.Actions) != 1 || result.Actions[0].Kind != SyncOverwrite || result.Actions[0].Message.Number != 1 {
+		t.Errorf("SyncLibrary() after an owner change = %+v, %v, want message 1 overwritten", result.Actions, err)
+	}
+	if entries, err := ListMessages(dms, d.MemoryChangeable.Int()); err != nil || len(entries) == 0 || entries[0].Owner != "10.0.0.9" {
+		t.Errorf("messages after SyncLibrary() = %+v, %v, want owner 10.0.0.9", entries, err)
+	}
+}
@@ -0,0 +1,179 @@
+package dialogs
+
+import (
+	"fmt"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// LinkState is the state of the communication link between a Client and its
+// sign.
+type LinkState int
+
+const (
+	LinkUnknown LinkState = iota // no request has been sent yet
+	LinkUp                       // the last request was answered
+	LinkDown                     // the last request was not answered or could not be sent
+)
+
+func (s LinkState) String() string {
+	switch s {
+	case LinkUnknown:
+		return "unknown"
+	case LinkUp:
+		return "up"
+	case LinkDown:
+		return "down"
+	}
+	return fmt.Sprintf("LinkState(%d)", int(s))
+}
+
+// LinkStatus is the state of the link of a Client.
+type LinkStatus struct {
+	State     LinkState
+	Since     time.Time // when the link entered State
+	LastError error     // the last communication failure, nil if none yet
+	ErrorTime time.Time // when LastError happened
+}
+
+// LinkEvent is a change of the state of the link of a Client.
+type LinkEvent struct {
+	From, To LinkState
+	Time     time.Time
+	Err      error // the failure that took the link down, nil when it came up
+}
+
+// WithReconnectInterval makes a Client whose link went down probe the sign
+// every interval, reading dmsSignType over a new connection, until it
+// answers, so that the link comes up again without waiting for a dialog.
+// Without it, 0, the link state changes only when dialogs run. It is an
+// option of NewClient.
+func WithReconnectInterval(interval time.Duration) Option {
+	return func(o *dialogOptions) { o.reconnectInterval = interval }
+}
+
+// Link returns the state of the link of the client: up once the sign has
+// answered a request, down once a request got no answer, after the retries
+// of WithRetryPolicy, or the connection could not be opened. Error statuses
+// are answers, so they do not take the link down.
+func (c *Client) Link() LinkStatus {
+	c.linkMu.Lock()
+	defer c.linkMu.Unlock()
+	return c.link
+}
+
+// OnLinkChange calls handler with each change of the link state, after the
+// dialog that saw it has returned, and returns a function ending the
+// subscription. The handler may run dialogs of the client.
+func (c *Client) OnLinkChange(handler func(LinkEvent)) func() {
+	c.linkMu.Lock()
+	defer c.linkMu.Unlock()
+	if c.linkHandlers == nil {
+		c.linkHandlers = map[int]func(LinkEvent){}
+	}
+	id := c.nextLinkHandler
+	c.nextLinkHandler++
+	c.linkHandlers[id] = handler
+	return func() {
+		c.linkMu.Lock()
+		defer c.linkMu.Unlock()
+		delete(c.linkHandlers, id)
+	}
+}
+
+// observeLink records the outcome of a request: err is nil if the sign
+// answered, with or without an error status, and the communication failure
+// otherwise.
+func (c *Client) observeLink(err error) {
+	c.linkMu.Lock()
+	defer c.linkMu.Unlock()
+	now := time.Now()
+	state := LinkUp
+	if err != nil {
+		state = LinkDown
+		c.link.LastError, c.link.ErrorTime = err, now
+	}
+	if state == c.link.State {
+		return
+	}
+	c.linkEvents = append(c.linkEvents, LinkEvent{From: c.link.State, To: state, Time: now, Err: err})
+	c.link.State, c.link.Since = state, now
+}
+
+// linkDown reports whether the link is down, for the client to drop its
+// connection and open a new one for the next dialog.
+func (c *Client) linkDown() bool {
+	c.linkMu.Lock()
+	defer c.linkMu.Unlock()
+	return c.link.State == LinkDown
+}
+
+// notifyLink passes the link events of the dialog that has just returned to
+// the handlers, and starts probing the sign if the link went down. It is
+// called without mu held.
+func (c *Client) notifyLink() {
+	c.linkMu.Lock()
+	events := c.linkEvents
+	c.linkEvents = nil
+	handlers := make([]func(LinkEvent), 0, len(c.linkHandlers))
+	for _, handler := range c.linkHandlers {
+		handlers = append(handlers, handler)
+	}
+	c.linkMu.Unlock()
+
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(event)
+		}
+		if event.To == LinkDown {
+			c.scheduleProbe()
+		}
+	}
+}
+
+// scheduleProbe probes the sign after the reconnect interval, unless a probe
+// is already scheduled.
+func (c *Client) scheduleProbe() {
+	interval := c.reconnectInterval
+	if interval <= 0 {
+		return
+	}
+	c.linkMu.Lock()
+	defer c.linkMu.Unlock()
+	if c.probing {
+		return
+	}
+	c.probing = true
+	time.AfterFunc(interval, func() {
+		c.linkMu.Lock()
+		c.probing = false
+		c.linkMu.Unlock()
+		err := c.probeLink()
+		if errors.Is(err, ErrClientClosed) {
+			return
+		}
+		if c.linkDown() {
+			c.scheduleProbe()
+		}
+	})
+}
+
+// probeLink reads dmsSignType, the link being down, over a new connection.
+func (c *Client) probeLink() error {
+	if err := c.lock(); err != nil {
+		return err
+	}
+	defer c.unlock()
+	if !c.linkDown() {
+		return nil
+	}
+	s := newSession(c.dms, "ProbeLink", c.dialogOptions(nil))
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return err
+	}
+	_, err := s.getSingle("get dmsSignType", d.DmsSignType.Identifier(0))
+	return err
+}
@@ -0,0 +1,72 @@
+package dialogs
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_Link(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	dms.Timeout = 50 * time.Millisecond
+
+	client := NewClient(dms, WithReconnectInterval(20*time.Millisecond))
+	defer client.Close()
+	var mu sync.Mutex
+	var events []LinkEvent
+	client.OnLinkChange(func(event LinkEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	if state := client.Link().State; state != LinkUnknown {
+		t.Errorf("Link() before any dialog = %v, want unknown", state)
+	}
+
+	if _, err := client.GetAction(1); err != nil {
+		t.Fatal(err)
+	}
+	// An error status is an answer: the link stays up.
+	if _, err := client.GetAction(99); err == nil {
+		t.Fatal("GetAction(99) succeeded, want noSuchName")
+	}
+	if state := client.Link().State; state != LinkUp {
+		t.Errorf("Link() after answers = %v, want up", state)
+	}
+
+	sign.Drop(1)
+	if _, err := client.GetAction(1); !IsTransient(err) {
+		t.Fatalf("GetAction() error = %v, want a timeout", err)
+	}
+	status := client.Link()
+	if status.State != LinkDown || !IsTransient(status.LastError) {
+		t.Errorf("Link() after a timeout = %+v, want down", status)
+	}
+
+	// The probe brings the link up again without a dialog.
+	deadline := time.Now().Add(2 * time.Second)
+	for client.Link().State != LinkUp {
+		if time.Now().After(deadline) {
+			t.Fatal("link not up again after the sign answers")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []LinkState{LinkUp, LinkDown, LinkUp}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want changes to %v", events, want)
+	}
+	for i, event := range events {
+		if event.To != want[i] || (event.To == LinkDown) != (event.Err != nil) {
+			t.Errorf("event %d = %+v, want a change to %v", i, event, want[i])
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package dialogs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// LogLevel is the verbosity of WithLogger.
+type LogLevel int
+
+const (
+	LogErrors   LogLevel = iota + 1 // the requests that fail
+	LogRequests                     // every request, with its OIDs
+	LogValues                       // every request, with the values set and read
+)
+
+// LogEntry is an SNMP request of a dialog, passed to a Logger.
+type LogEntry struct {
+	Time      time.Time // when the request was sent
+	Dialog    string    // e.g. "ActivatingMessage"
+	Step      string    // e.g. "get shortErrorStatus"
+	Operation string    // "get", "getnext", "getbulk" or "set"
+	Target    string    // address of the sign, host:port
+	OIDs      []string  // the objects of the request
+	Duration  time.Duration
+	Err       error // nil if the request succeeded
+
+	// Values are the PDUs of a SET, or the variables of the response of a
+	// GET, GETNEXT or GETBULK, with LogValues only.
+	Values []gosnmp.SnmpPDU
+
+	// DryRun marks a SET that was not sent, see WithDryRun; its Values are
+	// always set.
+	DryRun bool
+}
+
+func (e LogEntry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s %s", e.Dialog, e.Step, e.Target)
+	if len(e.Values) > 0 {
+		values := make([]string, len(e.Values))
+		for i, pdu := range e.Values {
+			values[i] = strings.TrimPrefix(pdu.Name, ".") + "=" + formatLogValue(pdu.Value)
+		}
+		fmt.Fprintf(&b, " [%s]", strings.Join(values, " "))
+	} else {
+		fmt.Fprintf(&b, " %v", e.OIDs)
+	}
+	if e.DryRun {
+		b.WriteString(" not sent, dry run")
+	} else {
+		fmt.Fprintf(&b, " in %v", e.Duration.Round(time.Microsecond))
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	return b.String()
+}
+
+// formatLogValue renders a value, an OCTET STRING as text if it is
+// printable and in hexadecimal otherwise.
+func formatLogValue(value interface{}) string {
+	text, ok := value.([]byte)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	if !utf8.Valid(text) {
+		return fmt.Sprintf("%x", text)
+	}
+	for _, r := range string(text) {
+		if !unicode.IsPrint(r) {
+			return fmt.Sprintf("%x", text)
+		}
+	}
+	return fmt.Sprintf("%q", text)
+}
+
+// Logger records the SNMP requests of the dialogs.
+type Logger interface {
+	Log(entry LogEntry)
+}
+
+// LoggerFunc adapts a function to a Logger.
+type LoggerFunc func(entry LogEntry)
+
+func (f LoggerFunc) Log(entry LogEntry) { f(entry) }
+
+// PrintLogger returns a Logger printing each entry on a line of l.
+func PrintLogger(l *log.Logger) Logger {
+	return LoggerFunc(func(entry LogEntry) { l.Print(entry) })
+}
+
+// WithLogger passes the SNMP requests of the dialogs to logger, at the given
+// level. The dialogs log nothing by default.
+func WithLogger(logger Logger, level LogLevel) Option {
+	return func(o *dialogOptions) {
+		o.logger = logger
+		o.logLevel = level
+	}
+}
+
+// log passes request r, sent at start, to the logger of the session.
+func (s *session) log(r request, result *gosnmp.SnmpPacket, err error, start time.Time) {
+	logger, level := s.options.logger, s.options.logLevel
+	if logger == nil || level < LogRequests && err == nil {
+		return
+	}
+	entry := LogEntry{
+		Time:      start,
+		Dialog:    s.dialog,
+		Step:      r.step,
+		Operation: r.operation,
+		Target:    s.target(),
+		OIDs:      r.oids,
+		Duration:  time.Since(start),
+		Err:       err,
+	}
+	if level >= LogValues {
+		switch {
+		case r.operation == "set":
+			entry.Values = r.pdus
+		case result != nil:
+			entry.Values = result.Variables
+		}
+	}
+	logger.Log(entry)
+}
@@ -0,0 +1,82 @@
+package dialogs
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+type recordingLogger struct{ entries []LogEntry }
+
+func (l *recordingLogger) Log(entry LogEntry) { l.entries = append(l.entries, entry) }
+
+func TestWithLogger(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	logger := &recordingLogger{}
+	if _, err := GetAction(dms, 1, WithLogger(logger, LogErrors)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetAction(dms, 99, WithLogger(logger, LogErrors)); err == nil {
+		t.Fatal("GetAction(99) succeeded, want an error")
+	}
+	if len(logger.entries) != 1 || logger.entries[0].Err == nil {
+		t.Fatalf("LogErrors logged %v, want the failed GET only", logger.entries)
+	}
+
+	logger.entries = nil
+	action, err := NewScheduleAction(2, d.Message{MemoryType: d.MemoryBlank, Number: 1}, 65535, 255, "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetAction(dms, action, WithLogger(logger, LogRequests)); err != nil {
+		t.Fatal(err)
+	}
+	var set *LogEntry
+	for i := range logger.entries {
+		if logger.entries[i].Operation == "set" {
+			set = &logger.entries[i]
+		}
+		if logger.entries[i].Values != nil {
+			t.Errorf("LogRequests logged the values of %v", logger.entries[i])
+		}
+	}
+	if set == nil || set.Dialog != "SetAction" || len(set.OIDs) != 1 || set.OIDs[0] != d.DmsActionMsgCode.Identifier(2) {
+		t.Fatalf("LogRequests logged %v, want the SET of dmsActionMsgCode.2", logger.entries)
+	}
+
+	var buf bytes.Buffer
+	if _, err := RetrievingCurrentMessage(dms, WithLogger(PrintLogger(log.New(&buf, "", 0)), LogValues)); err != nil {
+		t.Fatal(err)
+	}
+	line := buf.String()
+	if !strings.HasPrefix(line, "RetrievingCurrentMessage: get current message "+dms.Target) ||
+		!strings.Contains(line, d.DmsControlMode.Identifier(0)+"=") {
+		t.Errorf("PrintLogger printed %q, want the GET with its values", line)
+	}
+}
+
+func Test_formatLogValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: 4, want: "4"},
+		{value: []byte("[jp3]TEST"), want: `"[jp3]TEST"`},
+		{value: []byte{0x00, 0x03, 0x12, 0x34}, want: "00031234"},
+	}
+	for _, tt := range tests {
+		if got := formatLogValue(tt.value); got != tt.want {
+			t.Errorf("formatLogValue(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package dialogs
+
+import (
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// Usage is the number of used slots of a sign resource out of its capacity.
+type Usage struct {
+	Used     int
+	Capacity int
+}
+
+// Free returns the number of unused slots.
+func (u Usage) Free() int { return u.Capacity - u.Used }
+
+// Percent returns the used share of the capacity, from 0 to 100. A resource
+// without capacity, e.g. graphics on a sign that does not support them, is
+// reported as 0.
+func (u Usage) Percent() float64 {
+	if u.Capacity <= 0 {
+		return 0
+	}
+	return float64(u.Used) * 100 / float64(u.Capacity)
+}
+
+// MemoryReport summarizes the message, font and graphic storage of a sign
+// for capacity planning.
+type MemoryReport struct {
+	ChangeableMessages   Usage // dmsNumChangeableMsg of dmsMaxChangeableMsg
+	FreeChangeableMemory int   // dmsFreeChangeableMemory, bytes
+	VolatileMessages     Usage // dmsNumVolatileMsg of dmsMaxVolatileMsg
+	FreeVolatileMemory   int   // dmsFreeVolatileMemory, bytes
+	Fonts                Usage // fontTable rows in use of numFonts
+	Graphics             Usage // dmsGraphicNumEntries of dmsGraphicMaxEntries
+	FreeGraphicMemory    int   // availableGraphicMemory, bytes
+	GraphicBlockSize     int   // dmsGraphicBlockSize, bytes
+}
+
+// MemoryUsage reads the memory objects of the sign into a MemoryReport.
+// Graphics are optional: a sign without the graphicDefinition objects
+// reports zero graphic usage. A font row counts as used unless its
+// fontStatus is notUsed; signs without fontStatus (NTCIP 1203 v1) count the
+// rows with a non-zero fontHeight instead.
+func MemoryUsage(dms *gosnmp.GoSNMP, options ...Option) (report MemoryReport, err error) {
+	s := newSession(dms, "MemoryUsage", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return report, err
+	}
+
+	for _, object := range []struct {
+		reader d.Reader
+		value  *int
+	}{
+		{d.DmsNumChangeableMsg, &report.ChangeableMessages.Used},
+		{d.DmsMaxChangeableMsg, &report.ChangeableMessages.Capacity},
+		{d.DmsFreeChangeableMemory, &report.FreeChangeableMemory},
+		{d.DmsNumVolatileMsg, &report.VolatileMessages.Used},
+		{d.DmsMaxVolatileMsg, &report.VolatileMessages.Capacity},
+		{d.DmsFreeVolatileMemory, &report.FreeVolatileMemory},
+		{d.NumFonts, &report.Fonts.Capacity},
+	} {
+		if *object.value, err = getInt(s, object.reader.ObjectType(), object.reader.Identifier(0)); err != nil {
+			return report, err
+		}
+	}
+
+	if report.Fonts.Used, err = fontsInUse(s, report.Fonts.Capacity); err != nil {
+		return report, err
+	}
+
+	for _, object := range []struct {
+		reader d.Reader
+		value  *int
+	}{
+		{d.DmsGraphicMaxEntries, &report.Graphics.Capacity},
+		{d.DmsGraphicNumEntries, &report.Graphics.Used},
+		{d.AvailableGraphicMemory, &report.FreeGraphicMemory},
+		{d.DmsGraphicBlockSize, &report.GraphicBlockSize},
+	} {
+		*object.value, err = getInt(s, object.reader.ObjectType(), object.reader.Identifier(0))
+		if errors.Is(err, ErrNoSuchName) {
+			report.Graphics, report.FreeGraphicMemory, report.GraphicBlockSize = Usage{}, 0, 0
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// MessageCapacity is the storage of the changeable or the volatile message
+// memory of a sign.
+type MessageCapacity struct {
+	Messages   Usage // dmsNumChangeableMsg of dmsMaxChangeableMsg, or the volatile equivalents
+	FreeMemory int   // dmsFreeChangeableMemory or dmsFreeVolatileMemory, bytes
+}
+
+// Fits reports whether a new message with multiString can be stored: a row
+// must be free and the free memory must hold the MULTI string.
+func (c MessageCapacity) Fits(multiString string) bool {
+	return c.Messages.Free() > 0 && len(multiString) <= c.FreeMemory
+}
+
+// GetMessageCapacity reads the number of messages, the maximum number of
+// messages and the free memory of the changeable or volatile message memory,
+// to ensure there is storage space before defining a message.
+func GetMessageCapacity(dms *gosnmp.GoSNMP, messageMemoryType int, options ...Option) (MessageCapacity, error) {
+	s := newSession(dms, "GetMessageCapacity", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return MessageCapacity{}, err
+	}
+	return getMessageCapacity(s, messageMemoryType)
+}
+
+func getMessageCapacity(s *session, messageMemoryType int) (capacity MessageCapacity, err error) {
+	var num, max, free d.Reader
+	switch messageMemoryType {
+	case d.MemoryChangeable.Int():
+		num, max, free = d.DmsNumChangeableMsg, d.DmsMaxChangeableMsg, d.DmsFreeChangeableMemory
+	case d.MemoryVolatile.Int():
+		num, max, free = d.DmsNumVolatileMsg, d.DmsMaxVolatileMsg, d.DmsFreeVolatileMemory
+	default:
+		return capacity, s.wrap(errors.Errorf("message memory type %d has no capacity objects", messageMemoryType), "get message capacity")
+	}
+	for _, object := range []struct {
+		reader d.Reader
+		value  *int
+	}{
+		{num, &capacity.Messages.Used},
+		{max, &capacity.Messages.Capacity},
+		{free, &capacity.FreeMemory},
+	} {
+		if *object.value, err = getInt(s, object.reader.ObjectType(), object.reader.Identifier(0)); err != nil {
+			return capacity, err
+		}
+	}
+	return capacity, nil
+}
+
+// checkMessageCapacity returns ErrMessageMemoryFull if multiString does not
+// fit into the row of a changeable or volatile message. A row that is
+// already in use is redefined: it does not need a free row and its current
+// MULTI string is released.
+func checkMessageCapacity(s *session, messageMemoryType, messageNumber int, multiString string) error {
+	capacity, err := getMessageCapacity(s, messageMemoryType)
+	if err != nil {
+		return err
+	}
+	entry, err := readMessageEntry(s, messageMemoryType, messageNumber)
+	if err != nil {
+		return err
+	}
+	if entry.Status == d.NotUsed.Int() {
+		if capacity.Messages.Free() <= 0 {
+			return s.wrap(errors.Wrapf(ErrMessageMemoryFull, "all %d messages in use", capacity.Messages.Capacity), "check message capacity")
+		}
+		entry.MultiString = ""
+	}
+	if available := capacity.FreeMemory + len(entry.MultiString); len(multiString) > available {
+		return s.wrap(errors.Wrapf(ErrMessageMemoryFull, "message of %d bytes, %d bytes free", len(multiString), available), "check message capacity")
+	}
+	return nil
+}
+
+// fontsInUse counts the rows of the fontTable that hold a font.
+func fontsInUse(s *session, numFonts int) (used int, err error) {
+	status := true
+	for index := 1; index <= numFonts; index++ {
+		if status {
+			value, err := getInt(s, "fontStatus", d.FontStatus.Identifier(index))
+			if err == nil {
+				if value != d.FontNotUsed.Int() {
+					used++
+				}
+				continue
+			}
+			if !errors.Is(err, ErrNoSuchName) {
+				return used, err
+			}
+			status = false
+		}
+		height, err := getInt(s, "fontHeight", d.FontHeight.Identifier(index))
+		if err != nil {
+			return used, err
+		}
+		if height != 0 {
+			used++
+		}
+	}
+	return used, nil
+}
+
+func getInt(s *session, objectType, oid string) (int, error) {
+	variable, err := s.getSingle("get "+objectType, oid)
+	if err != nil {
+		return 0, err
+	}
+	value, _ := variable.Value.(int)
+	return value, nil
+}
+
+type clearMessageMemoryResult struct {
+	Entries []MessageEntry // the message table read again after the clear, normally empty
+	Timings []StepTiming
+}
+
+// ClearMessageMemory clears the changeable or volatile message memory of the
+// sign through dmsMemoryMgmt, e.g. when its message library has become
+// corrupted. dmsMemoryMgmt.0 is SET to clearChangeableMessages or
+// clearVolatileMessages and polled until the sign reports 'normal' again,
+// bounded by WithTestTimeout. The clear is confirmed through
+// dmsNumChangeableMsg or dmsNumVolatileMsg, and the message table is read
+// again and returned so that a copy kept by the caller can be refreshed.
+func ClearMessageMemory(dms *gosnmp.GoSNMP, messageMemoryType int, options ...Option) (result clearMessageMemoryResult, err error) {
+	s := newSession(dms, "ClearMessageMemory", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+
+	var action int
+	var numMsg d.Reader
+	switch messageMemoryType {
+	case d.MemoryChangeable.Int():
+		action, numMsg = d.ClearChangeableMessages.Int(), d.DmsNumChangeableMsg
+	case d.MemoryVolatile.Int():
+		action, numMsg = d.ClearVolatileMessages.Int(), d.DmsNumVolatileMsg
+	default:
+		return result, s.wrap(errors.Errorf("message memory type %d cannot be cleared", messageMemoryType), "check message memory type")
+	}
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	name := d.DmsMemoryMgmt.Identifier(0)
+	if _, err = s.set("set dmsMemoryMgmt", gosnmp.SnmpPDU{Name: name, Type: d.DmsMemoryMgmt.Syntax(), Value: action}); err != nil {
+		return result, err
+	}
+	if err = waitMemoryMgmt(s); err != nil {
+		return result, err
+	}
+
+	num, err := getInt(s, numMsg.ObjectType(), numMsg.Identifier(0))
+	if err != nil {
+		return result, err
+	}
+	if num != 0 {
+		return result, s.wrap(errors.Errorf("%d messages left after the clear", num), "verify "+numMsg.ObjectType(), numMsg.Identifier(0))
+	}
+	result.Entries, err = listMessages(s, messageMemoryType)
+	return result, err
+}
+
+// waitMemoryMgmt GETs dmsMemoryMgmt.0 until the sign reports 'normal'.
+func waitMemoryMgmt(s *session) error {
+	name := d.DmsMemoryMgmt.Identifier(0)
+	start := time.Now()
+	defer s.timed("wait for dmsMemoryMgmt", start)
+	deadline := start.Add(s.options.testTimeout)
+	for {
+		state, err := getInt(s, "dmsMemoryMgmt", name)
+		if err != nil {
+			return err
+		}
+		if state == d.MemoryMgmtNormal.Int() {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return s.wrap(errors.Errorf("dmsMemoryMgmt still %d after %s", state, s.options.testTimeout), "wait for dmsMemoryMgmt", name)
+		}
+		time.Sleep(s.options.pollInterval)
+	}
+}
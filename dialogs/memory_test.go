@@ -0,0 +1,133 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestMemoryUsage(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for _, number := range []int{1, 2} {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), number, "[jp3]TEST", "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sign.Store(d.DmsGraphicNumEntries.Identifier(0), gosnmp.Integer, 2)
+
+	report, err := MemoryUsage(dms)
+	if err != nil {
+		t.Fatalf("MemoryUsage() error = %v", err)
+	}
+	want := MemoryReport{
+		ChangeableMessages:   Usage{Used: 2, Capacity: simulator.MaxChangeableMsg},
+		FreeChangeableMemory: simulator.MessageMemory - 2*len("[jp3]TEST"),
+		VolatileMessages:     Usage{Used: 0, Capacity: simulator.MaxVolatileMsg},
+		FreeVolatileMemory:   simulator.MessageMemory,
+		Fonts:                Usage{Used: 1, Capacity: simulator.NumFonts},
+		Graphics:             Usage{Used: 2, Capacity: simulator.MaxGraphics},
+		FreeGraphicMemory:    32768,
+		GraphicBlockSize:     1024,
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("MemoryUsage() = %+v, want %+v", report, want)
+	}
+	if got := report.ChangeableMessages.Percent(); got != 20 {
+		t.Errorf("Percent() = %v, want 20", got)
+	}
+
+	// A v1 sign without graphics and fontStatus.
+	for _, object := range []d.Reader{d.DmsGraphicMaxEntries, d.DmsGraphicNumEntries, d.DmsGraphicMaxSize, d.AvailableGraphicMemory, d.DmsGraphicBlockSize} {
+		sign.Delete(object.Identifier(0))
+	}
+	for index := 1; index <= simulator.NumFonts; index++ {
+		sign.Delete(d.FontStatus.Identifier(index))
+	}
+	sign.Store(d.FontHeight.Identifier(3), gosnmp.Integer, 16)
+	report, err = MemoryUsage(dms)
+	if err != nil {
+		t.Fatalf("MemoryUsage() error = %v", err)
+	}
+	if report.Fonts != (Usage{Used: 2, Capacity: simulator.NumFonts}) || report.Graphics != (Usage{}) || report.Graphics.Percent() != 0 {
+		t.Errorf("MemoryUsage() = %+v, want 2 fonts used and no graphics", report)
+	}
+}
+
+func TestClearMessageMemory(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for _, messageMemoryType := range []int{d.MemoryChangeable.Int(), d.MemoryVolatile.Int()} {
+		if _, err := DefiningMessage(dms, messageMemoryType, 1, "ROAD WORK", "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := ClearMessageMemory(dms, d.MemoryChangeable.Int())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 0 || len(result.Timings) == 0 {
+		t.Errorf("ClearMessageMemory() = %+v, want an empty table", result)
+	}
+	// The volatile messages are kept.
+	if entries, err := ListMessages(dms, d.MemoryVolatile.Int()); err != nil || len(entries) != 1 {
+		t.Errorf("ListMessages(volatile) = %+v, %v, want the volatile message", entries, err)
+	}
+
+	if _, err := ClearMessageMemory(dms, d.MemoryPermanent.Int()); err == nil {
+		t.Error("ClearMessageMemory() of the permanent memory succeeded")
+	}
+}
+
+func TestMessageCapacity(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), 1, "ROAD WORK", "10.0.0.5", 50, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	capacity, err := GetMessageCapacity(dms, d.MemoryChangeable.Int())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MessageCapacity{Messages: Usage{Used: 1, Capacity: simulator.MaxChangeableMsg}, FreeMemory: simulator.MessageMemory - len("ROAD WORK")}
+	if capacity != want || !capacity.Fits("AHEAD") {
+		t.Errorf("GetMessageCapacity() = %+v, want %+v", capacity, want)
+	}
+
+	// Every row is in use: a new message is refused, message 1 can be redefined.
+	sign.Store(d.DmsNumChangeableMsg.Identifier(0), gosnmp.Integer, simulator.MaxChangeableMsg)
+	if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), 2, "AHEAD", "10.0.0.5", 50, 0, 0, WithCapacityCheck()); !errors.Is(err, ErrMessageMemoryFull) {
+		t.Errorf("DefiningMessage() of a new message error = %v, want ErrMessageMemoryFull", err)
+	}
+	if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), 1, "AHEAD", "10.0.0.5", 50, 0, 0, WithCapacityCheck()); err != nil {
+		t.Errorf("DefiningMessage() of message 1 error = %v", err)
+	}
+
+	sign.Store(d.DmsFreeChangeableMemory.Identifier(0), gosnmp.Integer, 0)
+	if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), 1, "ROAD WORK AHEAD", "10.0.0.5", 50, 0, 0, WithCapacityCheck()); !errors.Is(err, ErrMessageMemoryFull) {
+		t.Errorf("DefiningMessage() of a longer message error = %v, want ErrMessageMemoryFull", err)
+	}
+	if _, err := GetMessageCapacity(dms, d.MemoryPermanent.Int()); err == nil {
+		t.Error("GetMessageCapacity() of the permanent memory succeeded")
+	}
+}
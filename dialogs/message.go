@@ -0,0 +1,33 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// Message returns the entry as a d.Message. dmsMessageBeacon and
+// dmsMessagePixelService are not part of a MessageEntry and are left 0.
+func (e MessageEntry) Message() d.Message {
+	return d.Message{
+		MemoryType: d.MessageMemoryType(e.MessageMemoryType),
+		Number:     e.MessageNumber,
+		MULTI:      e.MultiString,
+		Owner:      e.Owner,
+		Priority:   e.RunTimePriority,
+		CRC:        e.MessageCRC,
+	}
+}
+
+// DefineMessage runs DefiningMessage for message. Its CRC is not used: the
+// sign calculates dmsMessageCRC itself.
+func DefineMessage(dms *gosnmp.GoSNMP, message d.Message, options ...Option) (definingMessageResult, error) {
+	return DefiningMessage(dms, message.MemoryType.Int(), message.Number,
+		message.MULTI, message.Owner, message.Priority,
+		message.Beacon, message.PixelService, options...)
+}
+
+// ActivateMessage runs ActivatingMessage for message with the given duration
+// in minutes (65535 is infinite) and activation priority.
+func ActivateMessage(dms *gosnmp.GoSNMP, message d.Message, duration, priority int, options ...Option) (activatingMessageResult, error) {
+	return ActivatingMessage(dms, duration, priority, message.MemoryType.Int(), message.Number, options...)
+}
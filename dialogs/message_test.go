@@ -0,0 +1,48 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestDefineMessage(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	message := d.Message{
+		MemoryType: d.MemoryChangeable,
+		Number:     4,
+		MULTI:      "[jp3]TEST",
+		Owner:      "10.0.0.5",
+		Priority:   50,
+	}
+	if _, err := DefineMessage(dms, message); err != nil {
+		t.Fatalf("DefineMessage() error = %v", err)
+	}
+	if _, err := ActivateMessage(dms, message, 65535, 50); err != nil {
+		t.Fatalf("ActivateMessage() error = %v", err)
+	}
+
+	entries, err := ListMessages(dms, d.MemoryChangeable.Int())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("ListMessages() = %+v, want one entry", entries)
+	}
+	message.CRC = message.CalculateCRC()
+	if got := entries[0].Message(); !reflect.DeepEqual(got, message) {
+		t.Errorf("MessageEntry.Message() = %+v, want %+v", got, message)
+	}
+
+	if got := d.MessageMemoryType(9).String(); got != "MessageMemoryType(9)" {
+		t.Errorf("MessageMemoryType.String() = %q", got)
+	}
+}
@@ -0,0 +1,156 @@
+package dialogs
+
+import (
+	"encoding/binary"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// MessageID identifies a message table row by its message memory type,
+// message number and message CRC, as encoded in a MessageIDCode.
+type MessageID struct {
+	MessageMemoryType int
+	MessageNumber     int
+	MessageCRC        int
+}
+
+// BlankMessageID is the blank message 1, the default of the messages in
+// MessageConfig.
+var BlankMessageID = MessageID{MessageMemoryType: d.MemoryBlank.Int(), MessageNumber: 1}
+
+// MessageIDOf returns the MessageID of message, with its CRC calculated from
+// the MULTI string, beacon and pixel service.
+func MessageIDOf(message d.Message) MessageID {
+	return MessageID{
+		MessageMemoryType: message.MemoryType.Int(),
+		MessageNumber:     message.Number,
+		MessageCRC:        message.CalculateCRC(),
+	}
+}
+
+// encode returns the MessageIDCode of the message. It is the same as bytes 3
+// to 7 of the MessageActivationCode built by EncodeActivateMessageCode.
+func (m MessageID) encode() []byte {
+	code := make([]byte, 5)
+	code[0] = byte(m.MessageMemoryType)
+	binary.BigEndian.PutUint16(code[1:3], uint16(m.MessageNumber))
+	binary.BigEndian.PutUint16(code[3:5], uint16(m.MessageCRC))
+	return code
+}
+
+// MessageConfig is the set of messages the sign activates by itself, with an
+// activation priority of 255 and a source address of 127.0.0.1, and the
+// timers that trigger them.
+type MessageConfig struct {
+	ShortPowerRecoveryMessage MessageID // dmsShortPowerRecoveryMessage.0
+	LongPowerRecoveryMessage  MessageID // dmsLongPowerRecoveryMessage.0
+	ShortPowerLossTime        int       // seconds, 0 makes every power loss long (dmsShortPowerLossTime.0)
+	ResetMessage              MessageID // dmsResetMessage.0
+	CommunicationsLossMessage MessageID // dmsCommunicationsLossMessage.0
+	TimeCommLoss              int       // minutes, 0 ignores communications loss (dmsTimeCommLoss.0)
+	PowerLossMessage          MessageID // dmsPowerLossMessage.0
+	EndDurationMessage        MessageID // dmsEndDurationMessage.0
+}
+
+// messageObjects pairs the MessageIDCode objects of a MessageConfig
+// with its fields.
+func (c *MessageConfig) messageObjects() []struct {
+	object  d.Reader
+	message *MessageID
+} {
+	return []struct {
+		object  d.Reader
+		message *MessageID
+	}{
+		{d.DmsShortPowerRecoveryMessage, &c.ShortPowerRecoveryMessage},
+		{d.DmsLongPowerRecoveryMessage, &c.LongPowerRecoveryMessage},
+		{d.DmsResetMessage, &c.ResetMessage},
+		{d.DmsCommunicationsLossMessage, &c.CommunicationsLossMessage},
+		{d.DmsPowerLossMessage, &c.PowerLossMessage},
+		{d.DmsEndDurationMessage, &c.EndDurationMessage},
+	}
+}
+
+// GetMessageConfig reads the messages the sign activates after a power
+// recovery, a reset, a communications loss, during a power loss and at the
+// end of a message duration, and the dmsShortPowerLossTime and
+// dmsTimeCommLoss timers.
+func GetMessageConfig(dms *gosnmp.GoSNMP, options ...Option) (MessageConfig, error) {
+	s := newSession(dms, "GetMessageConfig", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return MessageConfig{}, err
+	}
+	return getMessageConfig(s)
+}
+
+func getMessageConfig(s *session) (config MessageConfig, err error) {
+	messages := config.messageObjects()
+	oids := []string{d.DmsShortPowerLossTime.Identifier(0), d.DmsTimeCommLoss.Identifier(0)}
+	for _, m := range messages {
+		oids = append(oids, m.object.Identifier(0))
+	}
+	getResults, err := s.get("get message config", oids...)
+	if err != nil {
+		return config, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, d.DmsShortPowerLossTime.Identifier(0)):
+			config.ShortPowerLossTime, _ = variable.Value.(int)
+		case oidEqual(variable.Name, d.DmsTimeCommLoss.Identifier(0)):
+			config.TimeCommLoss, _ = variable.Value.(int)
+		}
+		for _, m := range messages {
+			if !oidEqual(variable.Name, m.object.Identifier(0)) {
+				continue
+			}
+			value, _ := variable.Value.([]byte)
+			id := m.message
+			id.MessageMemoryType, id.MessageNumber, id.MessageCRC, err = decodeMessageIDCode(value)
+			if err != nil {
+				return config, s.wrap(err, "decode "+m.object.ObjectType(), variable.Name)
+			}
+		}
+	}
+	return config, nil
+}
+
+// SetMessageConfig sets all the objects of config in a single request and
+// reads them back. A sign rejects a message that is not valid in its message
+// table, usually with badValue; the blank messages are always valid.
+func SetMessageConfig(dms *gosnmp.GoSNMP, config MessageConfig, options ...Option) (MessageConfig, error) {
+	s := newSession(dms, "SetMessageConfig", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return MessageConfig{}, err
+	}
+	if config.TimeCommLoss < 0 || config.TimeCommLoss > 65535 {
+		return MessageConfig{}, s.wrap(errors.Errorf("dmsTimeCommLoss %d out of range 0-65535", config.TimeCommLoss), "check message config")
+	}
+	if config.ShortPowerLossTime < 0 || config.ShortPowerLossTime > 65535 {
+		return MessageConfig{}, s.wrap(errors.Errorf("dmsShortPowerLossTime %d out of range 0-65535", config.ShortPowerLossTime), "check message config")
+	}
+
+	pdus := []gosnmp.SnmpPDU{
+		{Name: d.DmsShortPowerLossTime.Identifier(0), Type: d.DmsShortPowerLossTime.Syntax(), Value: config.ShortPowerLossTime},
+		{Name: d.DmsTimeCommLoss.Identifier(0), Type: d.DmsTimeCommLoss.Syntax(), Value: config.TimeCommLoss},
+	}
+	for _, m := range config.messageObjects() {
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: m.object.Identifier(0), Type: m.object.Syntax(), Value: m.message.encode()})
+	}
+	if _, err := s.set("set message config", pdus...); err != nil {
+		return MessageConfig{}, err
+	}
+
+	current, err := getMessageConfig(s)
+	if err != nil {
+		return current, err
+	}
+	if current != config {
+		return current, s.wrap(errors.Errorf("sign reports %+v, want %+v", current, config), "verify message config")
+	}
+	return current, nil
+}
@@ -0,0 +1,48 @@
+package dialogs
+
+import (
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestMessageConfig(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	config, err := GetMessageConfig(dms)
+	if err != nil {
+		t.Fatalf("GetMessageConfig() error = %v", err)
+	}
+	if config.CommunicationsLossMessage != BlankMessageID || config.TimeCommLoss != 0 {
+		t.Errorf("GetMessageConfig() = %+v, want blank messages and no communications loss", config)
+	}
+
+	message := d.Message{MemoryType: d.MemoryChangeable, Number: 3, MULTI: "[jp3]SIGN OFFLINE", Owner: "10.0.0.5", Priority: 255}
+	if _, err := DefineMessage(dms, message); err != nil {
+		t.Fatal(err)
+	}
+	config.CommunicationsLossMessage = MessageIDOf(message)
+	config.TimeCommLoss = 30
+	config.ShortPowerLossTime = 10
+	got, err := SetMessageConfig(dms, config)
+	if err != nil {
+		t.Fatalf("SetMessageConfig() error = %v", err)
+	}
+	if got != config {
+		t.Errorf("SetMessageConfig() = %+v, want %+v", got, config)
+	}
+	if code := MessageIDOf(message).encode(); code[0] != 3 || code[2] != 3 {
+		t.Errorf("MessageID.encode() = % X", code)
+	}
+
+	config.TimeCommLoss = -1
+	if _, err := SetMessageConfig(dms, config); err == nil {
+		t.Errorf("SetMessageConfig() expected an error for dmsTimeCommLoss -1")
+	}
+}
@@ -0,0 +1,183 @@
+package dialogs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// MessageEntry is a row of the dmsMessageTable.
+type MessageEntry struct {
+	MessageMemoryType int
+	MessageNumber     int
+	MultiString       string
+	Owner             string
+	MessageCRC        int
+	RunTimePriority   int
+	Status            int // dmsMessageStatus, e.g. 4 (valid)
+}
+
+// ListMessages returns the rows of the dmsMessageTable defined for a message
+// memory type, walking the table with GETBULK requests, see WithBulkSize.
+// Rows beyond dmsNumPermanentMsg, dmsMaxChangeableMsg or dmsMaxVolatileMsg
+// and rows in the 'notUsed' state are left out.
+func ListMessages(dms *gosnmp.GoSNMP, messageMemoryType int, options ...Option) (entries []MessageEntry, err error) {
+	s := newSession(dms, "ListMessages", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return nil, err
+	}
+	return listMessages(s, messageMemoryType)
+}
+
+func listMessages(s *session, messageMemoryType int) (entries []MessageEntry, err error) {
+	numRows, err := messageTableRows(s, messageMemoryType)
+	if err != nil || numRows == 0 {
+		return nil, err
+	}
+	roots := []string{
+		parentOID(d.DmsMessageStatus.Identifier(messageMemoryType, 0)),
+		parentOID(d.DmsMessageMultiString.Identifier(messageMemoryType, 0)),
+		parentOID(d.DmsMessageOwner.Identifier(messageMemoryType, 0)),
+		d.DmsMessageCRC.Identifier(messageMemoryType),
+		parentOID(d.DmsMessageRunTimePriority.Identifier(messageMemoryType, 0)),
+	}
+	columns, err := s.walkColumns("walk message table", roots...)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]int{} // the index of the entry of each row
+	for _, variable := range columns[0] {
+		index := rowIndex(roots[0], variable.Name)
+		messageNumber, err := strconv.Atoi(index)
+		if err != nil || messageNumber > numRows {
+			continue
+		}
+		entry := MessageEntry{MessageMemoryType: messageMemoryType, MessageNumber: messageNumber}
+		entry.Status, _ = variable.Value.(int)
+		if entry.Status != d.NotUsed.Int() {
+			rows[index] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+	for column, variables := range columns {
+		for _, variable := range variables {
+			i, ok := rows[rowIndex(roots[column], variable.Name)]
+			if !ok {
+				continue
+			}
+			entry := &entries[i]
+			switch column {
+			case 1:
+				value, _ := variable.Value.([]byte)
+				entry.MultiString = string(value)
+			case 2:
+				value, _ := variable.Value.([]byte)
+				entry.Owner = string(value)
+			case 3:
+				entry.MessageCRC, _ = variable.Value.(int)
+			case 4:
+				entry.RunTimePriority, _ = variable.Value.(int)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// messageTableRows returns the number of rows of a message memory type.
+func messageTableRows(s *session, messageMemoryType int) (int, error) {
+	var object d.Reader
+	switch messageMemoryType {
+	case d.MemoryPermanent.Int():
+		object = d.DmsNumPermanentMsg
+	case d.MemoryChangeable.Int():
+		object = d.DmsMaxChangeableMsg
+	case d.MemoryVolatile.Int():
+		object = d.DmsMaxVolatileMsg
+	case d.MemoryCurrentBuffer.Int(), d.MemorySchedule.Int():
+		return 1, nil
+	case d.MemoryBlank.Int():
+		return 255, nil
+	default:
+		return 0, s.wrap(fmt.Errorf("unsupported message memory type %d", messageMemoryType), "get number of messages")
+	}
+	result, err := s.getSingle("get "+object.ObjectType(), object.Identifier(0))
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := result.Value.(int)
+	return rows, nil
+}
+
+func readMessageEntry(s *session, messageMemoryType, messageNumber int) (entry MessageEntry, err error) {
+	entry.MessageMemoryType = messageMemoryType
+	entry.MessageNumber = messageNumber
+
+	multiStringName := d.DmsMessageMultiString.Identifier(messageMemoryType, messageNumber)
+	ownerName := d.DmsMessageOwner.Identifier(messageMemoryType, messageNumber)
+	crcName := fmt.Sprintf("%s.%d", d.DmsMessageCRC.Identifier(messageMemoryType), messageNumber)
+	priorityName := d.DmsMessageRunTimePriority.Identifier(messageMemoryType, messageNumber)
+	statusName := d.DmsMessageStatus.Identifier(messageMemoryType, messageNumber)
+
+	getResults, err := s.get("get message table entry", multiStringName, ownerName, crcName, priorityName, statusName)
+	if err != nil {
+		return entry, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, multiStringName):
+			value, _ := variable.Value.([]byte)
+			entry.MultiString = string(value)
+		case oidEqual(variable.Name, ownerName):
+			value, _ := variable.Value.([]byte)
+			entry.Owner = string(value)
+		case oidEqual(variable.Name, crcName):
+			entry.MessageCRC, _ = variable.Value.(int)
+		case oidEqual(variable.Name, priorityName):
+			entry.RunTimePriority, _ = variable.Value.(int)
+		case oidEqual(variable.Name, statusName):
+			entry.Status, _ = variable.Value.(int)
+		}
+	}
+	return entry, nil
+}
+
+// AllocateMessageSlot returns the lowest numbered row of the changeable or
+// volatile message memory whose dmsMessageStatus is notUsed, so callers do
+// not have to hard-code message numbers and risk overwriting messages
+// defined by operators. The column is walked with GETBULK requests, see
+// WithBulkSize. The row is not reserved: define the message before another
+// management station takes it. ErrMessageMemoryFull is returned when every
+// row is in use.
+func AllocateMessageSlot(dms *gosnmp.GoSNMP, messageMemoryType int, options ...Option) (int, error) {
+	s := newSession(dms, "AllocateMessageSlot", options)
+	defer s.close()
+	if messageMemoryType != d.MemoryChangeable.Int() && messageMemoryType != d.MemoryVolatile.Int() {
+		return 0, s.wrap(fmt.Errorf("message memory type %d cannot be allocated", messageMemoryType), "check message memory type")
+	}
+	if err := s.connect(); err != nil {
+		return 0, err
+	}
+
+	rows, err := messageTableRows(s, messageMemoryType)
+	if err != nil {
+		return 0, err
+	}
+	root := parentOID(d.DmsMessageStatus.Identifier(messageMemoryType, 0))
+	columns, err := s.walkColumns("walk dmsMessageStatus", root)
+	if err != nil {
+		return 0, err
+	}
+	for _, variable := range columns[0] {
+		messageNumber, err := strconv.Atoi(rowIndex(root, variable.Name))
+		if err != nil || messageNumber > rows {
+			continue
+		}
+		if status, _ := variable.Value.(int); status == d.NotUsed.Int() {
+			return messageNumber, nil
+		}
+	}
+	return 0, s.wrap(ErrMessageMemoryFull, "find a notUsed message")
+}
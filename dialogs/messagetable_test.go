@@ -0,0 +1,88 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestListMessages(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for _, number := range []int{2, 5} {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), number, "[jp3]TEST", "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ListMessages(dms, d.MemoryChangeable.Int())
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	crc := calcChecksum("[jp3]TEST", 0, 0)
+	want := []MessageEntry{
+		{MessageMemoryType: 3, MessageNumber: 2, MultiString: "[jp3]TEST", Owner: "10.0.0.5", MessageCRC: crc, RunTimePriority: 50, Status: 4},
+		{MessageMemoryType: 3, MessageNumber: 5, MultiString: "[jp3]TEST", Owner: "10.0.0.5", MessageCRC: crc, RunTimePriority: 50, Status: 4},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("ListMessages() = %+v, want %+v", entries, want)
+	}
+
+	if _, err := ListMessages(dms, 1); err == nil {
+		t.Errorf("ListMessages() expected an error for memory type 'other'")
+	}
+}
+
+func TestAllocateMessageSlot(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for _, messageNumber := range []int{1, 2, 4} {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), messageNumber, "ROAD WORK", "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := AllocateMessageSlot(dms, d.MemoryChangeable.Int()); err != nil || got != 3 {
+		t.Errorf("AllocateMessageSlot() = %d, %v, want 3", got, err)
+	}
+	if got, err := AllocateMessageSlot(dms, d.MemoryVolatile.Int()); err != nil || got != 1 {
+		t.Errorf("AllocateMessageSlot(volatile) = %d, %v, want 1", got, err)
+	}
+
+	for messageNumber := 3; messageNumber <= simulator.MaxChangeableMsg; messageNumber++ {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), messageNumber, "AHEAD", "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := AllocateMessageSlot(dms, d.MemoryChangeable.Int()); !errors.Is(err, ErrMessageMemoryFull) {
+		t.Errorf("AllocateMessageSlot() of a full memory error = %v, want ErrMessageMemoryFull", err)
+	}
+
+	// A v2c session walks the column in pages instead of a GET per row.
+	dms.Version = gosnmp.Version2c
+	requests := 0
+	dms.OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	if _, err := AllocateMessageSlot(dms, d.MemoryChangeable.Int()); !errors.Is(err, ErrMessageMemoryFull) {
+		t.Errorf("AllocateMessageSlot() of a full memory over v2c error = %v, want ErrMessageMemoryFull", err)
+	}
+	if requests > 3 {
+		t.Errorf("AllocateMessageSlot() sent %d requests for %d rows, want at most 3", requests, simulator.MaxChangeableMsg)
+	}
+	dms.OnSent = nil
+	if _, err := AllocateMessageSlot(dms, d.MemoryPermanent.Int()); err == nil {
+		t.Error("AllocateMessageSlot() of the permanent memory succeeded")
+	}
+}
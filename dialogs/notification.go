@@ -0,0 +1,241 @@
+package dialogs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// NotificationTarget is a manager the sign sends its notifications to, e.g.
+// a trap.Listener. It is configured in the rows of the same name of the
+// snmpTargetAddrTable, snmpTargetParamsTable and snmpNotifyTable of RFC
+// 3413, which NTCIP 1103 v03 uses; the name is also the tag linking them.
+type NotificationTarget struct {
+	Name      string             // 1 to 32 characters
+	Address   string             // IPv4 address and UDP port, e.g. "10.0.0.5:162"
+	Version   gosnmp.SnmpVersion // gosnmp.Version1 or gosnmp.Version2c
+	Community string             // community of the notifications
+	Inform    bool               // send SNMPv2c informs instead of traps
+	Timeout   time.Duration      // wait for the response to an inform, to 10 ms
+	Retries   int                // informs sent again without a response
+}
+
+// validate checks the target and returns its snmpTargetAddrTAddress.
+func (t NotificationTarget) validate() ([]byte, error) {
+	if len(t.Name) < 1 || len(t.Name) > 32 {
+		return nil, errors.Errorf("notification target name %q: want 1 to 32 characters", t.Name)
+	}
+	if t.Version != gosnmp.Version1 && t.Version != gosnmp.Version2c {
+		return nil, errors.Errorf("notification target %s: version %v not supported", t.Name, t.Version)
+	}
+	if t.Inform && t.Version == gosnmp.Version1 {
+		return nil, errors.Errorf("notification target %s: SNMPv1 has no informs", t.Name)
+	}
+	host, port, err := net.SplitHostPort(t.Address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "notification target %s", t.Name)
+	}
+	ip := net.ParseIP(host).To4()
+	portNumber, err := strconv.ParseUint(port, 10, 16)
+	if ip == nil || err != nil {
+		return nil, errors.Errorf("notification target %s: address %q is not an IPv4 address and port", t.Name, t.Address)
+	}
+	address := make([]byte, 6)
+	copy(address, ip)
+	binary.BigEndian.PutUint16(address[4:], uint16(portNumber))
+	return address, nil
+}
+
+// rowName returns the instance of column in the row indexed by name, an
+// IMPLIED string with one sub-identifier per character.
+func rowName(column d.Reader, name string) string {
+	var b []byte
+	b = append(b, column.Identifier(int(name[0]))...)
+	for _, c := range []byte(name[1:]) {
+		b = append(b, '.')
+		b = strconv.AppendInt(b, int64(c), 10)
+	}
+	return string(b)
+}
+
+// notificationRows are the RowStatus columns of the rows of a target, in
+// the order they are created.
+var notificationRows = []d.Reader{
+	d.SnmpTargetParamsRowStatus,
+	d.SnmpTargetAddrRowStatus,
+	d.SnmpNotifyRowStatus,
+}
+
+// SetNotificationTarget configures the sign to send its notifications to
+// target, replacing the rows of the same name, and reads them back. The rows
+// are created with createAndGo in nonVolatile storage.
+func SetNotificationTarget(dms *gosnmp.GoSNMP, target NotificationTarget, options ...Option) (NotificationTarget, error) {
+	s := newSession(dms, "SetNotificationTarget", options)
+	defer s.close()
+	address, err := target.validate()
+	if err != nil {
+		return NotificationTarget{}, s.wrap(err, "validate notification target")
+	}
+	if err := s.connect(); err != nil {
+		return NotificationTarget{}, err
+	}
+	if err := deleteNotificationTarget(s, target.Name); err != nil {
+		return NotificationTarget{}, err
+	}
+
+	mpModel, securityModel := 1, 2
+	if target.Version == gosnmp.Version1 {
+		mpModel, securityModel = 0, 1
+	}
+	notifyType := d.NotifyTrap.Int()
+	if target.Inform {
+		notifyType = d.NotifyInform.Int()
+	}
+	pdu := func(column d.Reader, value interface{}) gosnmp.SnmpPDU {
+		return gosnmp.SnmpPDU{Name: rowName(column, target.Name), Type: column.Syntax(), Value: value}
+	}
+	createAndGo := d.RowCreateAndGo.Int()
+	for _, row := range []struct {
+		step string
+		pdus []gosnmp.SnmpPDU
+	}{
+		{"create snmpTargetParamsEntry", []gosnmp.SnmpPDU{
+			pdu(d.SnmpTargetParamsRowStatus, createAndGo),
+			pdu(d.SnmpTargetParamsMPModel, mpModel),
+			pdu(d.SnmpTargetParamsSecurityModel, securityModel),
+			pdu(d.SnmpTargetParamsSecurityName, target.Community),
+			pdu(d.SnmpTargetParamsSecurityLevel, 1),
+			pdu(d.SnmpTargetParamsStorageType, d.StorageNonVolatile),
+		}},
+		{"create snmpTargetAddrEntry", []gosnmp.SnmpPDU{
+			pdu(d.SnmpTargetAddrRowStatus, createAndGo),
+			pdu(d.SnmpTargetAddrTDomain, d.SnmpUDPDomain),
+			pdu(d.SnmpTargetAddrTAddress, address),
+			pdu(d.SnmpTargetAddrTimeout, int(target.Timeout/(10*time.Millisecond))),
+			pdu(d.SnmpTargetAddrRetryCount, target.Retries),
+			pdu(d.SnmpTargetAddrTagList, target.Name),
+			pdu(d.SnmpTargetAddrParams, target.Name),
+			pdu(d.SnmpTargetAddrStorageType, d.StorageNonVolatile),
+		}},
+		{"create snmpNotifyEntry", []gosnmp.SnmpPDU{
+			pdu(d.SnmpNotifyRowStatus, createAndGo),
+			pdu(d.SnmpNotifyTag, target.Name),
+			pdu(d.SnmpNotifyType, notifyType),
+			pdu(d.SnmpNotifyStorageType, d.StorageNonVolatile),
+		}},
+	} {
+		if _, err := s.set(row.step, row.pdus...); err != nil {
+			return NotificationTarget{}, err
+		}
+	}
+
+	current, err := getNotificationTarget(s, target.Name)
+	if err != nil {
+		return current, err
+	}
+	want := target
+	want.Timeout = want.Timeout.Truncate(10 * time.Millisecond)
+	if current != want {
+		return current, s.wrap(errors.Errorf("sign reports %+v, want %+v", current, want), "verify notification target")
+	}
+	return current, nil
+}
+
+// GetNotificationTarget reads the notification target name. ErrNoSuchName
+// is returned if the sign has no active rows of that name.
+func GetNotificationTarget(dms *gosnmp.GoSNMP, name string, options ...Option) (NotificationTarget, error) {
+	s := newSession(dms, "GetNotificationTarget", options)
+	defer s.close()
+	if name == "" {
+		return NotificationTarget{}, s.wrap(errors.New("empty notification target name"), "validate notification target")
+	}
+	if err := s.connect(); err != nil {
+		return NotificationTarget{}, err
+	}
+	return getNotificationTarget(s, name)
+}
+
+func getNotificationTarget(s *session, name string) (NotificationTarget, error) {
+	target := NotificationTarget{Name: name}
+	columns := []d.Reader{
+		d.SnmpTargetParamsRowStatus, d.SnmpTargetParamsMPModel, d.SnmpTargetParamsSecurityName,
+		d.SnmpTargetAddrRowStatus, d.SnmpTargetAddrTAddress, d.SnmpTargetAddrTimeout, d.SnmpTargetAddrRetryCount,
+		d.SnmpNotifyRowStatus, d.SnmpNotifyType,
+	}
+	oids := make([]string, len(columns))
+	for i, column := range columns {
+		oids[i] = rowName(column, name)
+	}
+	getResults, err := s.get("get notification target", oids...)
+	if err != nil {
+		return target, err
+	}
+	values := map[d.Reader]interface{}{}
+	for _, variable := range getResults.Variables {
+		for i, column := range columns {
+			if oidEqual(variable.Name, oids[i]) {
+				switch variable.Type {
+				case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+					return target, s.wrap(&SNMPError{Status: gosnmp.NoSuchName, OID: oids[i]}, "get notification target", oids[i])
+				}
+				values[column] = variable.Value
+			}
+		}
+	}
+	for _, row := range notificationRows {
+		if values[row] != d.RowActive.Int() {
+			return target, s.wrap(errors.Errorf("%s is %v, want active", row.ObjectType(), values[row]), "verify notification target", rowName(row, name))
+		}
+	}
+
+	target.Version = gosnmp.Version2c
+	if values[d.SnmpTargetParamsMPModel] == 0 {
+		target.Version = gosnmp.Version1
+	}
+	community, _ := values[d.SnmpTargetParamsSecurityName].([]byte)
+	target.Community = string(community)
+	if address, _ := values[d.SnmpTargetAddrTAddress].([]byte); len(address) == 6 {
+		target.Address = net.JoinHostPort(net.IP(address[:4]).String(), strconv.Itoa(int(binary.BigEndian.Uint16(address[4:]))))
+	} else {
+		target.Address = fmt.Sprintf("%x", address)
+	}
+	timeout, _ := values[d.SnmpTargetAddrTimeout].(int)
+	target.Timeout = time.Duration(timeout) * 10 * time.Millisecond
+	target.Retries, _ = values[d.SnmpTargetAddrRetryCount].(int)
+	target.Inform = values[d.SnmpNotifyType] == d.NotifyInform.Int()
+	return target, nil
+}
+
+// DeleteNotificationTarget stops the notifications to the target name by
+// destroying its rows. A sign without rows of that name is left unchanged.
+func DeleteNotificationTarget(dms *gosnmp.GoSNMP, name string, options ...Option) error {
+	s := newSession(dms, "DeleteNotificationTarget", options)
+	defer s.close()
+	if name == "" {
+		return s.wrap(errors.New("empty notification target name"), "validate notification target")
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	return deleteNotificationTarget(s, name)
+}
+
+// deleteNotificationTarget destroys the rows of the target name, in the
+// reverse order of their creation. A SNMPv1 agent answers noSuchName for a
+// row that does not exist.
+func deleteNotificationTarget(s *session, name string) error {
+	for i := len(notificationRows) - 1; i >= 0; i-- {
+		row := notificationRows[i]
+		_, err := s.set("destroy "+row.ObjectType(), gosnmp.SnmpPDU{Name: rowName(row, name), Type: row.Syntax(), Value: d.RowDestroy.Int()})
+		if err != nil && !errors.Is(err, ErrNoSuchName) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+	"github.com/jacobleehei/godms/trap"
+)
+
+func TestNotificationTarget(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	listener, err := trap.Listen("127.0.0.1:0", trap.WithCommunity("traps"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	client := NewClient(sign.GoSNMP())
+	if _, err := client.GetNotificationTarget("central"); !errors.Is(err, ErrNoSuchName) {
+		t.Errorf("GetNotificationTarget() of a missing target error = %v, want ErrNoSuchName", err)
+	}
+
+	target := NotificationTarget{
+		Name:      "central",
+		Address:   listener.Addr().String(),
+		Version:   gosnmp.Version2c,
+		Community: "traps",
+		Timeout:   1500 * time.Millisecond,
+		Retries:   3,
+	}
+	got, err := client.SetNotificationTarget(target)
+	if err != nil {
+		t.Fatalf("SetNotificationTarget() error = %v", err)
+	}
+	if got != target {
+		t.Errorf("SetNotificationTarget() = %+v, want %+v", got, target)
+	}
+
+	// The sign now reports its changes to the listener.
+	if err := sign.Notify(gosnmp.SnmpPDU{Name: d.ShortErrorStatus.Identifier(0), Type: gosnmp.Integer, Value: 0x20}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-listener.Events():
+		if event.Kind != trap.KindErrorStatus || event.ShortErrorStatus != 0x20 {
+			t.Errorf("event = %+v, want shortErrorStatus 0x20", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification received")
+	}
+
+	// Setting the target again replaces its rows.
+	target.Inform = true
+	if got, err := client.SetNotificationTarget(target); err != nil || !got.Inform {
+		t.Errorf("SetNotificationTarget() of informs = %+v, %v", got, err)
+	}
+	if err := sign.Notify(gosnmp.SnmpPDU{Name: d.DmsControlMode.Identifier(0), Type: gosnmp.Integer, Value: d.ControlCentral.Int()}); err != nil {
+		t.Errorf("Notify() of an inform error = %v", err)
+	}
+
+	if err := client.DeleteNotificationTarget("central"); err != nil {
+		t.Fatalf("DeleteNotificationTarget() error = %v", err)
+	}
+	if _, err := client.GetNotificationTarget("central"); !errors.Is(err, ErrNoSuchName) {
+		t.Errorf("GetNotificationTarget() after DeleteNotificationTarget error = %v, want ErrNoSuchName", err)
+	}
+
+	for _, invalid := range []NotificationTarget{
+		{Name: "", Address: "10.0.0.5:162", Version: gosnmp.Version2c},
+		{Name: "central", Address: "10.0.0.5", Version: gosnmp.Version2c},
+		{Name: "central", Address: "[::1]:162", Version: gosnmp.Version2c},
+		{Name: "central", Address: "10.0.0.5:162", Version: gosnmp.Version1, Inform: true},
+		{Name: "central", Address: "10.0.0.5:162", Version: gosnmp.Version3},
+	} {
+		if _, err := SetNotificationTarget(sign.GoSNMP(), invalid); err == nil {
+			t.Errorf("SetNotificationTarget(%+v) expected an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package dialogs
+
+import (
+	"syscall"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/jacobleehei/godms/pmpp"
+	"github.com/pkg/errors"
+)
+
+// Option tunes the behaviour of a dialog. Options are accepted by every
+// dialog in this package and are applied in the order given, so a later
+// option overrides an earlier one.
+type Option func(*dialogOptions)
+
+type dialogOptions struct {
+	validationTimeout time.Duration
+	pollInterval      time.Duration
+	testTimeout       time.Duration
+	resetTimeout      time.Duration
+	resetBackoff      time.Duration
+	sourceAddress     string
+	retries           int
+	version           int // gosnmp.SnmpVersion, -1 keeps the session's version
+	credentials       *Credentials
+	readCommunity     string
+	writeCommunity    string
+	communityFallback []string
+	versionFallback   bool
+	transport         string
+	port              uint16
+	localAddr         string
+	control           func(network, address string, c syscall.RawConn) error
+	pmpp              *pmpp.Conn
+	deadMan           time.Duration
+	duplicateWindow   time.Duration
+	historySize       int
+	blankMode         BlankMode
+	softBlankPriority int
+	strict            bool
+	blockRetries      int
+	blockProgress     func(sent, total int)
+	progress          func(Progress)
+	resumeBlock       int
+	readOnly          bool
+	dryRun            bool
+	controlModeCheck  bool
+	capacityCheck     bool
+	skipUnchanged     bool
+	conflictCheck     bool
+	onConflict        func(*ConflictError) error
+	driftThreshold    time.Duration
+	tracer            Tracer
+	parentSpan        Span
+	logger            Logger
+	logLevel          LogLevel
+	audit             AuditSink
+	interceptors      []Interceptor
+	retryPolicy       RetryPolicy
+	rateLimit         RateLimit
+	idleTimeout       time.Duration
+	reconnectInterval time.Duration
+	bulkSize          int
+
+	// duplicate reports whether an activation repeats a recent one; it is
+	// set by Client from the activation history.
+	duplicate func(messageMemoryType, messageNumber, messageCRC, priority int) bool
+
+	// communities keeps the communities found by WithCommunityFallback; it
+	// is set by Client so that they outlive a dialog.
+	communities *communities
+
+	// foundVersion keeps the version found by WithVersionFallback, -1 until
+	// then; it is set by Client so that it outlives a dialog and overrides
+	// WithVersion.
+	foundVersion *int
+
+	// capabilities are the capabilities discovered by Client, nil if
+	// unknown; dialogs skip the optional objects the sign lacks.
+	capabilities *Capabilities
+
+	// spans collects the dialog spans; it is set by Client, which ends them
+	// when the dialog returns.
+	spans *[]Span
+
+	// limiter is the rate limiter of Client, shared by its dialogs; nil
+	// without a client or a rate limit.
+	limiter *rateLimiter
+
+	// conn is the connection Client keeps open between its dialogs, nil
+	// without a client: each dialog connects again.
+	conn *connection
+
+	// link is told by the dialogs of Client whether the sign answered.
+	link func(err error)
+
+	// err is the error of an invalid option, returned by the dialogs when
+	// they connect.
+	err error
+}
+
+func newDialogOptions(options ...Option) dialogOptions {
+	o := dialogOptions{
+		validationTimeout: 3 * time.Second,
+		pollInterval:      1 * time.Second,
+		testTimeout:       2 * time.Minute,
+		resetTimeout:      2 * time.Minute,
+		resetBackoff:      30 * time.Second,
+		retries:           -1,
+		version:           -1,
+		historySize:       100,
+		blockRetries:      2,
+		idleTimeout:       time.Minute,
+		bulkSize:          10,
+	}
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// snmpSettings are the fields of a gosnmp session that the options set. A
+// session saves them before applying its options and restores them when
+// its dialog ends, so that the options of a dialog do not outlive it.
+type snmpSettings struct {
+	retries       int
+	version       gosnmp.SnmpVersion
+	securityModel gosnmp.SnmpV3SecurityModel
+	msgFlags      gosnmp.SnmpV3MsgFlags
+	contextName   string
+	transport     string
+	port          uint16
+	localAddr     string
+}
+
+func saveSettings(dms *gosnmp.GoSNMP) snmpSettings {
+	return snmpSettings{
+		retries:       dms.Retries,
+		version:       dms.Version,
+		securityModel: dms.SecurityModel,
+		msgFlags:      dms.MsgFlags,
+		contextName:   dms.ContextName,
+		transport:     dms.Transport,
+		port:          dms.Port,
+		localAddr:     dms.LocalAddr,
+	}
+}
+
+func (settings snmpSettings) restore(dms *gosnmp.GoSNMP) {
+	dms.Retries = settings.retries
+	dms.Version = settings.version
+	dms.SecurityModel = settings.securityModel
+	dms.MsgFlags = settings.msgFlags
+	dms.ContextName = settings.contextName
+	dms.Transport = settings.transport
+	dms.Port = settings.port
+	dms.LocalAddr = settings.localAddr
+}
+
+// apply copies the options that live on the SNMP session itself onto dms,
+// for the time of a dialog, see snmpSettings.
+func (o dialogOptions) apply(dms *gosnmp.GoSNMP) {
+	if o.retries >= 0 {
+		dms.Retries = o.retries
+	}
+	if o.version >= 0 {
+		dms.Version = gosnmp.SnmpVersion(o.version)
+	}
+	if o.foundVersion != nil && *o.foundVersion >= 0 {
+		dms.Version = gosnmp.SnmpVersion(*o.foundVersion)
+	}
+	if o.credentials != nil && dms.Version == gosnmp.Version3 {
+		o.credentials.apply(dms)
+	}
+	if o.transport != "" {
+		dms.Transport = o.transport
+	}
+	if o.port != 0 {
+		dms.Port = o.port
+	}
+	if o.localAddr != "" {
+		dms.LocalAddr = o.localAddr
+	}
+}
+
+// WithValidationTimeout bounds how long a dialog keeps polling the controller
+// while it performs a consistency check (e.g. dmsMessageStatus 'validating').
+// The default is 3 seconds.
+func WithValidationTimeout(timeout time.Duration) Option {
+	return func(o *dialogOptions) { o.validationTimeout = timeout }
+}
+
+// WithPollInterval sets the delay between two GETs while a dialog waits for
+// the controller to change state. The default is 1 second.
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *dialogOptions) { o.pollInterval = interval }
+}
+
+// WithTestTimeout bounds how long a diagnostic or maintenance dialog, e.g.
+// PixelTest or ClearMessageMemory, waits for the sign to complete the
+// operation. The default is 2 minutes.
+func WithTestTimeout(timeout time.Duration) Option {
+	return func(o *dialogOptions) { o.testTimeout = timeout }
+}
+
+// WithResetTimeout bounds how long ResetController waits for the controller
+// to answer again after the reset. The default is 2 minutes.
+func WithResetTimeout(timeout time.Duration) Option {
+	return func(o *dialogOptions) { o.resetTimeout = timeout }
+}
+
+// WithResetBackoff sets the longest delay between two GETs while
+// ResetController waits for the controller: the delay starts at the poll
+// interval and doubles after every unanswered GET, up to max. The default is
+// 30 seconds.
+func WithResetBackoff(max time.Duration) Option {
+	return func(o *dialogOptions) { o.resetBackoff = max }
+}
+
+// WithSourceAddress sets the management station address encoded into the
+// message activation code. It must be an IPv4 address, as the activation
+// code only has room for four bytes. By default the local address of the
+// connection to the sign is used; without a local IPv4 address, e.g. over
+// IPv6, the activations fail with ErrSourceAddress.
+func WithSourceAddress(address string) Option {
+	return func(o *dialogOptions) { o.sourceAddress = address }
+}
+
+// WithRetries overrides the number of SNMP retries of the session used by
+// the dialog. By default the value configured on the gosnmp session is kept;
+// the gosnmp session gets its own value back when the dialog ends, as with
+// WithVersion, WithSNMPv3, WithTransport and WithLocalAddr.
+func WithRetries(retries int) Option {
+	return func(o *dialogOptions) { o.retries = retries }
+}
+
+// WithVersion sets the SNMP version (gosnmp.Version1 or gosnmp.Version2c)
+// used to talk to the sign. By default the version configured on the gosnmp
+// session is kept.
+func WithVersion(version gosnmp.SnmpVersion) Option {
+	return func(o *dialogOptions) { o.version = int(version) }
+}
+
+// WithVersionFallback lets a dialog drop from SNMPv2c to SNMPv1 when a
+// request gets no answer, for controllers that only speak SNMPv1. The
+// version that worked is kept, so later dialogs on the same sign start with
+// it: a Client keeps it over WithVersion, the dialogs run without one keep it
+// on the gosnmp session until WithVersion sets another.
+func WithVersionFallback() Option {
+	return func(o *dialogOptions) { o.versionFallback = true }
+}
+
+// WithCommunities sets the community strings of GETs and of SETs, for signs
+// that use different communities to read and to write. An empty community
+// keeps the community of the gosnmp session. SNMPv3 ignores them.
+func WithCommunities(read, write string) Option {
+	return func(o *dialogOptions) { o.readCommunity, o.writeCommunity = read, write }
+}
+
+// WithCommunityFallback lets a dialog repeat a SNMPv1 or SNMPv2c request
+// that fails authentication with each of communities in turn. Signs usually
+// do not answer a request with an unknown community, so every community tried
+// costs the timeout of the session. The community that worked is used first
+// by the following requests of the same kind, GET or SET: for the rest of the
+// dialog or, with a Client, until the client is discarded.
+func WithCommunityFallback(communities ...string) Option {
+	return func(o *dialogOptions) { o.communityFallback = communities }
+}
+
+// WithTransport sets the transport ("udp" or "tcp") and the port of the
+// sign, for controllers behind a terminal server or a port-forwarding NAT
+// that do not listen on 161. A zero port keeps the port of the gosnmp
+// session.
+func WithTransport(transport string, port uint16) Option {
+	return func(o *dialogOptions) { o.transport, o.port = transport, port }
+}
+
+// WithPMPP makes the dialogs talk to the sign over conn, a serial link
+// framed with PMPP (NTCIP 2101), instead of UDP or TCP. The dialogs do not
+// close conn, which can be shared by the signs on a multi-drop line through a
+// Conn per address; Target and Port of the gosnmp session are then only used
+// in error messages. Combine it with WithSourceAddress, as there is no IP
+// address to encode into a message activation code.
+func WithPMPP(conn *pmpp.Conn) Option {
+	return func(o *dialogOptions) { o.pmpp = conn }
+}
+
+// WithLocalAddr binds the local end of the connection to address
+// ("host:port"), e.g. to pin the source port a NAT forwards replies to.
+func WithLocalAddr(address string) Option {
+	return func(o *dialogOptions) { o.localAddr = address }
+}
+
+// WithSocketControl installs a net.Dialer Control function that is called
+// on the socket before it connects, to set per-sign socket options such as
+// SO_REUSEADDR or a DSCP mark.
+func WithSocketControl(control func(network, address string, c syscall.RawConn) error) Option {
+	return func(o *dialogOptions) { o.control = control }
+}
+
+// WithDeadMan makes an activation end on its own after the given time, so a
+// forgotten test message does not linger on a live sign. The activation
+// duration is capped to that time, rounded up to a minute, so the sign ends
+// the message even if the management station goes away. A Client also blanks
+// the sign once the time is up, if the message is still displayed, in case
+// the sign's dmsEndDurationMessage is not blank.
+func WithDeadMan(after time.Duration) Option {
+	return func(o *dialogOptions) { o.deadMan = after }
+}
+
+// WithDuplicateWindow makes a Client coalesce an activation with the previous
+// one if it activates the same message (memory type, number and CRC) at the
+// same priority within window, and the previous one is still displayed. The
+// repeated activation is not sent and its result has Duplicate set. Dialogs
+// called directly ignore this option.
+func WithDuplicateWindow(window time.Duration) Option {
+	return func(o *dialogOptions) { o.duplicateWindow = window }
+}
+
+// WithActivationHistory sets how many activations a Client retains for
+// ActivationHistory and DisplayedAt. The default is 100; zero disables the
+// history, but not WithDuplicateWindow, which only needs the last
+// activation. A negative size makes the dialogs fail. Dialogs called
+// directly ignore this option.
+func WithActivationHistory(size int) Option {
+	return func(o *dialogOptions) {
+		if size < 0 {
+			o.err = errors.Errorf("activation history size %d is negative", size)
+			return
+		}
+		o.historySize = size
+	}
+}
+
+// WithStrictStandard makes the dialogs follow the steps of NTCIP 1203 Clause
+// 4.4 exactly, for agencies that must demonstrate conformance: every
+// conditional GET is made, even for objects the sign's capabilities lack;
+// DefiningMessage SETs the optional objects in requests of their own and
+// stops if the message does not enter the 'modifying' state; activations
+// are never coalesced by WithDuplicateWindow. By default the dialogs save
+// requests where the outcome is the same.
+func WithStrictStandard() Option {
+	return func(o *dialogOptions) { o.strict = true }
+}
+
+// BlankMode selects how BlankSign blanks a sign.
+type BlankMode int
+
+const (
+	// HardBlank activates the blank message whose run-time priority is the
+	// activation priority, so the sign stays blank until an activation of at
+	// least that priority. This is the default.
+	HardBlank BlankMode = iota
+	// SoftBlank activates a blank message with a low run-time priority, so
+	// the sign's scheduler or any central message can take over again.
+	SoftBlank
+)
+
+// WithBlankMode selects how BlankSign blanks the sign. With SoftBlank the
+// blank message has run-time priority runTimePriority (1-255), which should
+// be below the priorities of the messages allowed to replace it;
+// runTimePriority is ignored with HardBlank.
+func WithBlankMode(mode BlankMode, runTimePriority int) Option {
+	return func(o *dialogOptions) { o.blankMode, o.softBlankPriority = mode, runTimePriority }
+}
+
+// blankMessageNumber returns the number of the blank message BlankSign
+// activates at priority: the number of a blank message is its run-time
+// priority.
+func (o dialogOptions) blankMessageNumber(priority int) int {
+	if o.blankMode == SoftBlank {
+		return o.softBlankPriority
+	}
+	return priority
+}
+
+// WithBlockRetries sets how often a graphic bitmap block is sent again when
+// the sign does not answer. A block the sign rejects is not retried. The
+// default is 2.
+func WithBlockRetries(retries int) Option {
+	return func(o *dialogOptions) { o.blockRetries = retries }
+}
+
+// WithBlockProgress calls progress after each graphic bitmap block is
+// transferred, with the number of blocks sent so far, counting the blocks
+// skipped by a resume, and the total number of blocks.
+func WithBlockProgress(progress func(sent, total int)) Option {
+	return func(o *dialogOptions) { o.blockProgress = progress }
+}
+
+// WithResumeBlock makes ConfiguringGraphic resume an interrupted download:
+// if the graphic row is still 'modifying', the graphic objects and the
+// blocks before block are not sent again. Pass the NextBlock of the failed
+// download's result.
+func WithResumeBlock(block int) Option {
+	return func(o *dialogOptions) { o.resumeBlock = block }
+}
+
+// WithReadOnly makes every SET fail with ErrReadOnlyClient before it is sent,
+// so a monitoring deployment cannot change a sign by accident; dialogs that
+// only GET are not affected. Pass it to NewClient to make the whole client
+// read-only. Building with the godms_readonly tag has the same effect on
+// every dialog of the package.
+func WithReadOnly() Option {
+	return func(o *dialogOptions) { o.readOnly = true }
+}
+
+// WithControlModeCheck makes the activation dialogs, e.g. ActivatingMessage
+// and BlankSign, read dmsControlMode.0 first and return ErrNotCentralControl
+// without activating anything when the sign is not under central control.
+func WithControlModeCheck() Option {
+	return func(o *dialogOptions) { o.controlModeCheck = true }
+}
+
+// WithCapacityCheck makes DefiningMessage ensure there is storage space for
+// the message before changing the message table: a free row unless the
+// message is redefined, and free memory for its MULTI string. Otherwise it
+// returns ErrMessageMemoryFull.
+func WithCapacityCheck() Option {
+	return func(o *dialogOptions) { o.capacityCheck = true }
+}
+
+// WithConflictCheck makes ActivatingMessage read the message displayed
+// first and, without activating anything, return a *ConflictError naming its
+// owner when that message has a run-time priority above the activation
+// priority or was activated by another management station. onConflict, if not
+// nil, decides instead: the activation goes ahead if it returns nil, e.g.
+// after logging a warning, and fails with its error otherwise.
+func WithConflictCheck(onConflict func(*ConflictError) error) Option {
+	return func(o *dialogOptions) {
+		o.conflictCheck = true
+		o.onConflict = onConflict
+	}
+}
+
+// WithSkipUnchanged makes DefiningMessage read the message table row first
+// and skip the dialog when the row is valid and already holds the message:
+// the dmsMessageCRC calculated for the message, the MULTI string, the owner
+// and the run-time priority match. It is ignored with WithStrictStandard.
+func WithSkipUnchanged() Option {
+	return func(o *dialogOptions) { o.skipUnchanged = true }
+}
+
+// WithDriftThreshold makes SyncTime leave the clock of the sign alone unless
+// it is off by more than threshold, e.g. to avoid setting the clock of every
+// sign on each synchronization round. The default, 0, corrects any drift of a
+// second or more.
+func WithDriftThreshold(threshold time.Duration) Option {
+	return func(o *dialogOptions) { o.driftThreshold = threshold }
+}
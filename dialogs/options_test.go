@@ -0,0 +1,113 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func Test_newDialogOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    dialogOptions
+	}{
+		{
+			name: "defaults",
+			want: dialogOptions{
+				validationTimeout: 3 * time.Second,
+				pollInterval:      1 * time.Second,
+				testTimeout:       2 * time.Minute,
+				resetTimeout:      2 * time.Minute,
+				resetBackoff:      30 * time.Second,
+				retries:           -1,
+				version:           -1,
+				historySize:       100,
+				blockRetries:      2,
+				idleTimeout:       time.Minute,
+				bulkSize:          10,
+			},
+		},
+		{
+			name: "overrides",
+			options: []Option{
+				WithValidationTimeout(10 * time.Second),
+				WithPollInterval(500 * time.Millisecond),
+				WithTestTimeout(time.Minute),
+				WithResetTimeout(time.Minute),
+				WithResetBackoff(5 * time.Second),
+				WithSourceAddress("10.0.0.5"),
+				WithRetries(2),
+				WithVersion(gosnmp.Version1),
+				WithVersionFallback(),
+				WithTransport("tcp", 10161),
+				WithLocalAddr("0.0.0.0:40161"),
+				WithActivationHistory(10),
+				WithBlockRetries(5),
+				WithResumeBlock(3),
+				WithControlModeCheck(),
+				WithCapacityCheck(),
+				WithSkipUnchanged(),
+				WithConflictCheck(nil),
+				WithCommunities("ntcip-read", "ntcip-write"),
+				WithCommunityFallback("public", "private"),
+				WithDriftThreshold(2 * time.Second),
+				WithTracer(&recordingTracer{}),
+				WithParentSpan(&recordedSpan{name: "central"}),
+				WithLogger(&recordingLogger{}, LogValues),
+				WithInterceptor(Interceptor{}),
+				WithRetryPolicy(DefaultRetryPolicy()),
+				WithRateLimit(RateLimit{Rate: 2, Burst: 4}),
+				WithIdleTimeout(5 * time.Minute),
+				WithReconnectInterval(30 * time.Second),
+				WithBulkSize(25),
+				WithDryRun(),
+			},
+			want: dialogOptions{
+				validationTimeout: 10 * time.Second,
+				pollInterval:      500 * time.Millisecond,
+				testTimeout:       time.Minute,
+				resetTimeout:      time.Minute,
+				resetBackoff:      5 * time.Second,
+				sourceAddress:     "10.0.0.5",
+				retries:           2,
+				version:           int(gosnmp.Version1),
+				versionFallback:   true,
+				transport:         "tcp",
+				port:              10161,
+				localAddr:         "0.0.0.0:40161",
+				historySize:       10,
+				blockRetries:      5,
+				resumeBlock:       3,
+				controlModeCheck:  true,
+				capacityCheck:     true,
+				skipUnchanged:     true,
+				conflictCheck:     true,
+				readCommunity:     "ntcip-read",
+				writeCommunity:    "ntcip-write",
+				communityFallback: []string{"public", "private"},
+				driftThreshold:    2 * time.Second,
+				tracer:            &recordingTracer{},
+				parentSpan:        &recordedSpan{name: "central"},
+				logger:            &recordingLogger{},
+				logLevel:          LogValues,
+				interceptors:      []Interceptor{{}},
+				retryPolicy:       DefaultRetryPolicy(),
+				rateLimit:         RateLimit{Rate: 2, Burst: 4},
+				idleTimeout:       5 * time.Minute,
+				reconnectInterval: 30 * time.Second,
+				bulkSize:          25,
+				dryRun:            true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newDialogOptions(tt.options...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newDialogOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
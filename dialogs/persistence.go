@@ -0,0 +1,78 @@
+package dialogs
+
+import (
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// PersistedMessage is a changeable message checked by VerifyPersistence.
+type PersistedMessage struct {
+	MessageNumber int
+	Before        MessageEntry // the entry before the reset
+	After         MessageEntry // the entry after the reset
+}
+
+// Survived reports whether the message is still valid after the reset, with
+// the same MULTI string and CRC.
+func (m PersistedMessage) Survived() bool {
+	return m.After.Status == d.Valid.Int() &&
+		m.After.MessageCRC == m.Before.MessageCRC &&
+		m.After.MultiString == m.Before.MultiString
+}
+
+// VerifyPersistence checks that changeable messages survive a controller
+// reset, to catch controllers with unreliable non-volatile memory. It reads
+// the given changeable messages, calls reset and reads them again. reset is
+// e.g. a call to SoftwareReset, or a function that asks the operator to
+// power-cycle the controller; it must return once the sign answers again.
+// With a nil reset the messages are read twice without a reset.
+func VerifyPersistence(dms *gosnmp.GoSNMP, messageNumbers []int, reset func() error, options ...Option) ([]PersistedMessage, error) {
+	s := newSession(dms, "VerifyPersistence", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	messages := make([]PersistedMessage, len(messageNumbers))
+	for i, messageNumber := range messageNumbers {
+		entry, err := readMessageEntry(s, d.MemoryChangeable.Int(), messageNumber)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Status != d.Valid.Int() {
+			return nil, s.wrap(errors.Errorf("changeable message %d is not valid", messageNumber), "get message table entry")
+		}
+		messages[i] = PersistedMessage{MessageNumber: messageNumber, Before: entry}
+	}
+
+	if reset != nil {
+		if err := reset(); err != nil {
+			return nil, s.wrap(err, "reset")
+		}
+		// The reset may have dropped the connection.
+		if err := s.connect(); err != nil {
+			return nil, err
+		}
+	}
+	for i := range messages {
+		entry, err := readMessageEntry(s, d.MemoryChangeable.Int(), messages[i].MessageNumber)
+		if err != nil {
+			return messages, err
+		}
+		messages[i].After = entry
+	}
+	return messages, nil
+}
+
+// SoftwareReset resets the controller by setting dmsSWReset.0 to 1 and waits
+// up to timeout until the sign answers again with dmsSWReset.0 back at 0.
+func SoftwareReset(dms *gosnmp.GoSNMP, timeout time.Duration, options ...Option) error {
+	s := newSession(dms, "SoftwareReset", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return err
+	}
+	return resetController(s, timeout, s.options.pollInterval)
+}
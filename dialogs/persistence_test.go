@@ -0,0 +1,47 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestVerifyPersistence(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	for _, messageNumber := range []int{1, 2} {
+		if _, err := DefiningMessage(dms, d.MemoryChangeable.Int(), messageNumber, "ROAD WORK", "10.0.0.5", 50, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reset := func() error {
+		if err := SoftwareReset(dms, time.Second, WithPollInterval(10*time.Millisecond)); err != nil {
+			return err
+		}
+		// Flaky NVRAM: message 2 comes back corrupted.
+		sign.Store(d.DmsMessageStatus.Identifier(d.MemoryChangeable.Int(), 2), gosnmp.Integer, d.NotUsed.Int())
+		return nil
+	}
+	messages, err := VerifyPersistence(dms, []int{1, 2}, reset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || !messages[0].Survived() || messages[1].Survived() {
+		t.Errorf("VerifyPersistence() = %+v, want message 1 to survive and message 2 lost", messages)
+	}
+	if source, _ := sign.Load(d.DmsMsgSourceMode.Identifier(0)); source != d.SourceReset.Int() {
+		t.Errorf("dmsMsgSourceMode after SoftwareReset = %v, want reset", source)
+	}
+
+	if _, err := VerifyPersistence(dms, []int{3}, nil); err == nil {
+		t.Error("VerifyPersistence() of an undefined message succeeded")
+	}
+}
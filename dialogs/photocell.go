@@ -0,0 +1,121 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// LightSensor is a row of the dmsLightSensorStatusTable.
+type LightSensor struct {
+	Index       int    // dmsLightSensorIndex
+	Description string // dmsLightSensorDescription
+	Reading     int    // dmsLightSensorCurrentReading
+	Status      int    // dmsLightSensorStatus, e.g. d.LightSensorFail
+	StatusName  string // e.g. "noError" or "noResponse"
+}
+
+// Failed reports whether the sensor reports an error.
+func (l LightSensor) Failed() bool { return l.Status != d.LightSensorNoError.Int() }
+
+// PhotocellStatus holds the photocell level and light output of a sign and
+// the status of its individual light sensors.
+type PhotocellStatus struct {
+	PhotocellLevel    int           // dmsIllumPhotocellLevelStatus
+	MaxPhotocellLevel int           // dmsIllumMaxPhotocellLevel
+	LightOutput       int           // dmsIllumLightOutputStatus, 0 (darkest) to 65535
+	Sensors           []LightSensor // nil if the sign has no dmsLightSensorStatusTable
+}
+
+// FailedSensors returns the light sensors that report an error.
+func (p PhotocellStatus) FailedSensors() []LightSensor {
+	var failed []LightSensor
+	for _, sensor := range p.Sensors {
+		if sensor.Failed() {
+			failed = append(failed, sensor)
+		}
+	}
+	return failed
+}
+
+// GetPhotocellStatus reads the photocell level and light output of the sign
+// and walks the dmsLightSensorStatusTable, so that failed light sensors can
+// be detected. Signs without the table return no Sensors.
+func GetPhotocellStatus(dms *gosnmp.GoSNMP, options ...Option) (PhotocellStatus, error) {
+	s := newSession(dms, "GetPhotocellStatus", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return PhotocellStatus{}, err
+	}
+	return getPhotocellStatus(s)
+}
+
+func getPhotocellStatus(s *session) (status PhotocellStatus, err error) {
+	oids := []string{
+		d.DmsIllumPhotocellLevelStatus.Identifier(0),
+		d.DmsIllumMaxPhotocellLevel.Identifier(0),
+		d.DmsIllumLightOutputStatus.Identifier(0),
+	}
+	getResults, err := s.get("get photocell level", oids...)
+	if err != nil {
+		return status, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			status.PhotocellLevel, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[1]):
+			status.MaxPhotocellLevel, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[2]):
+			status.LightOutput, _ = variable.Value.(int)
+		}
+	}
+
+	// dmsLightSensorNumRows is not supported by signs without the table.
+	numRows, err := getInt(s, "dmsLightSensorNumRows", d.DmsLightSensorNumRows.Identifier(0))
+	if errors.Is(err, ErrNoSuchName) {
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	for index := 1; index <= numRows; index++ {
+		sensor, err := getLightSensor(s, index)
+		if err != nil {
+			return status, err
+		}
+		status.Sensors = append(status.Sensors, sensor)
+	}
+	return status, nil
+}
+
+// getLightSensor reads row index of the dmsLightSensorStatusTable.
+func getLightSensor(s *session, index int) (sensor LightSensor, err error) {
+	oids := []string{
+		d.DmsLightSensorDescription.Identifier(index),
+		d.DmsLightSensorCurrentReading.Identifier(index),
+		d.DmsLightSensorStatus.Identifier(index),
+	}
+	getResults, err := s.get("get light sensor", oids...)
+	if err != nil {
+		return sensor, err
+	}
+	sensor.Index = index
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			description, _ := variable.Value.([]byte)
+			sensor.Description = string(description)
+		case oidEqual(variable.Name, oids[1]):
+			sensor.Reading, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[2]):
+			sensor.Status, _ = variable.Value.(int)
+		}
+	}
+	name, err := d.Format(d.DmsLightSensorStatus, sensor.Status)
+	if err != nil {
+		return sensor, s.wrap(err, "format dmsLightSensorStatus", oids[2])
+	}
+	sensor.StatusName = name.(string)
+	return sensor, nil
+}
@@ -0,0 +1,46 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetPhotocellStatus(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.Store(d.DmsLightSensorStatus.Identifier(2), gosnmp.Integer, d.LightSensorNoResponse.Int())
+	sign.Store(d.DmsLightSensorCurrentReading.Identifier(2), gosnmp.Integer, 0)
+
+	status, err := GetPhotocellStatus(sign.GoSNMP())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PhotocellStatus{
+		PhotocellLevel:    simulator.IllumMaxPhotocellLevel / 2,
+		MaxPhotocellLevel: simulator.IllumMaxPhotocellLevel,
+		LightOutput:       65535 * (simulator.IllumNumBrightLevels / 2) / simulator.IllumNumBrightLevels,
+		Sensors: []LightSensor{
+			{Index: 1, Description: "front", Reading: simulator.IllumMaxPhotocellLevel / 2, Status: 2, StatusName: "noError"},
+			{Index: 2, Description: "rear", Reading: 0, Status: 3, StatusName: "noResponse"},
+		},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("GetPhotocellStatus() = %+v, want %+v", status, want)
+	}
+	if failed := status.FailedSensors(); len(failed) != 1 || failed[0].Description != "rear" {
+		t.Errorf("FailedSensors() = %+v, want the rear sensor", failed)
+	}
+
+	// Signs without the light sensor table still report the photocell level.
+	sign.Delete(d.DmsLightSensorNumRows.Identifier(0))
+	if status, err := GetPhotocellStatus(sign.GoSNMP()); err != nil || status.Sensors != nil || status.PhotocellLevel == 0 {
+		t.Errorf("GetPhotocellStatus() without sensors = %+v, %v", status, err)
+	}
+}
@@ -0,0 +1,140 @@
+package dialogs
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// PixelFailure is a row of the pixelFailureTable.
+type PixelFailure struct {
+	DetectionType int // pixelFailureDetectionType, e.g. d.PixelTestDetection
+	X             int // pixelFailureXLocation, from 1 at the left
+	Y             int // pixelFailureYLocation, from 1 at the top
+	Status        int // pixelFailureStatus bitmap, e.g. d.PixelStuckOff
+}
+
+// StuckOn reports whether the pixel is stuck on.
+func (f PixelFailure) StuckOn() bool { return f.Status&d.PixelStuckOn.Int() != 0 }
+
+// StuckOff reports whether the pixel is stuck off.
+func (f PixelFailure) StuckOff() bool { return f.Status&d.PixelStuckOff.Int() != 0 }
+
+type pixelTestResult struct {
+	Failures []PixelFailure
+	Timings  []StepTiming
+}
+
+// PixelTest runs the pixel test of the sign: pixelTestActivation is SET to
+// 'test' and polled until the sign sets it back to 'noTest', bounded by
+// WithTestTimeout, then the rows of the pixelFailureTable detected by the
+// test are read. Use PixelFailureMap to draw the failures.
+func PixelTest(dms *gosnmp.GoSNMP, options ...Option) (result pixelTestResult, err error) {
+	s := newSession(dms, "PixelTest", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	if err = runTest(s, d.PixelTestActivation); err != nil {
+		return result, err
+	}
+
+	result.Failures, err = getPixelFailures(s, d.PixelTestDetection.Int())
+	return result, err
+}
+
+// getPixelFailures walks the rows of the pixelFailureTable of a detection
+// type.
+func getPixelFailures(s *session, detection int) (failures []PixelFailure, err error) {
+	roots := []string{
+		d.PixelFailureXLocation.Identifier(detection),
+		d.PixelFailureYLocation.Identifier(detection),
+		d.PixelFailureStatus.Identifier(detection),
+	}
+	columns, err := s.walkColumns("walk pixel failures", roots...)
+	if err != nil {
+		return nil, err
+	}
+	rows := map[string]int{} // the index of the failure of each row
+	for column, variables := range columns {
+		for _, variable := range variables {
+			index := rowIndex(roots[column], variable.Name)
+			i, ok := rows[index]
+			if !ok {
+				i, rows[index] = len(failures), len(failures)
+				failures = append(failures, PixelFailure{DetectionType: detection})
+			}
+			value, _ := variable.Value.(int)
+			switch column {
+			case 0:
+				failures[i].X = value
+			case 1:
+				failures[i].Y = value
+			case 2:
+				failures[i].Status = value
+			}
+		}
+	}
+	return failures, nil
+}
+
+// runTest SETs the test activation object to 'test' and GETs it until the
+// sign sets it back to 'noTest'.
+func runTest(s *session, activation d.Reader) error {
+	name := activation.Identifier(0)
+	step := fmt.Sprintf("set %s to test", activation.ObjectType())
+	if _, err := s.set(step, gosnmp.SnmpPDU{Name: name, Type: activation.Syntax(), Value: d.Test.Int()}); err != nil {
+		return err
+	}
+	start := time.Now()
+	defer s.timed("wait for "+activation.ObjectType(), start)
+	deadline := start.Add(s.options.testTimeout)
+	for {
+		state, err := getInt(s, activation.ObjectType(), name)
+		if err != nil {
+			return err
+		}
+		if state == d.NoTest.Int() {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return s.wrap(errors.Errorf("%s still %d after %s", activation.ObjectType(), state, s.options.testTimeout), step, name)
+		}
+		time.Sleep(s.options.pollInterval)
+	}
+}
+
+// Colors of PixelFailureMap.
+var (
+	pixelStuckOnColor  = color.RGBA{255, 255, 255, 255}
+	pixelStuckOffColor = color.RGBA{255, 0, 0, 255}
+	pixelOtherColor    = color.RGBA{255, 191, 0, 255}
+)
+
+// PixelFailureMap draws the failures on a black image of the size of the
+// sign: pixels stuck on are white, pixels stuck off red and other failures
+// amber. Failures outside the sign are left out.
+func PixelFailureMap(width, height int, failures []PixelFailure) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	for _, failure := range failures {
+		c := pixelOtherColor
+		switch {
+		case failure.StuckOn():
+			c = pixelStuckOnColor
+		case failure.StuckOff():
+			c = pixelStuckOffColor
+		}
+		img.SetRGBA(failure.X-1, failure.Y-1, c)
+	}
+	return img
+}
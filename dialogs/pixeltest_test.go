@@ -0,0 +1,59 @@
+package dialogs
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestPixelTest(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	result, err := PixelTest(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Failures) != 0 {
+		t.Errorf("PixelTest() of a healthy sign = %+v", result.Failures)
+	}
+
+	sign.FailPixel(1, 1, d.PixelStuckOn.Int())
+	sign.FailPixel(10, 3, d.PixelStuckOff.Int()|d.PixelElectricalError.Int())
+	sign.FailPixel(20, 5, d.PixelPartialFailure.Int())
+	result, err = PixelTest(dms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PixelFailure{
+		{DetectionType: 1, X: 1, Y: 1, Status: 1},
+		{DetectionType: 1, X: 10, Y: 3, Status: 10},
+		{DetectionType: 1, X: 20, Y: 5, Status: 32},
+	}
+	if !reflect.DeepEqual(result.Failures, want) {
+		t.Errorf("PixelTest() = %+v, want %+v", result.Failures, want)
+	}
+
+	img := PixelFailureMap(simulator.SignWidthPixels, simulator.SignHeightPixels, result.Failures)
+	pixels := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{0, 0, pixelStuckOnColor},
+		{9, 2, pixelStuckOffColor},
+		{19, 4, pixelOtherColor},
+		{1, 1, color.RGBA{0, 0, 0, 255}},
+	}
+	for _, p := range pixels {
+		if got := img.RGBAAt(p.x, p.y); got != p.want {
+			t.Errorf("PixelFailureMap() at (%d, %d) = %v, want %v", p.x, p.y, got, p.want)
+		}
+	}
+}
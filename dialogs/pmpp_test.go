@@ -0,0 +1,77 @@
+package dialogs
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/pmpp"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestWithPMPP(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	// A serial line to a PMPP to UDP bridge in front of the simulator.
+	local, line := net.Pipe()
+	defer line.Close()
+	bridge, err := net.Dial("udp", net.JoinHostPort(dms.Target, strconv.Itoa(int(dms.Port))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Close()
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, err := line.Read(buf)
+			if err != nil {
+				return
+			}
+			address, message, err := pmpp.Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+			bridge.Write(message)
+			n, err = bridge.Read(buf)
+			if err != nil {
+				return
+			}
+			frame, _ := pmpp.Encode(address, buf[:n])
+			line.Write(frame)
+		}
+	}()
+
+	conn, err := pmpp.NewConn(local, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	mode, err := GetControlMode(dms, WithPMPP(conn))
+	if err != nil {
+		t.Fatalf("GetControlMode() over PMPP error = %v", err)
+	}
+	if mode.Mode != d.ControlCentral.Int() {
+		t.Errorf("GetControlMode() over PMPP = %+v, want central", mode)
+	}
+	if _, err := GetControlMode(dms, WithPMPP(conn)); err != nil {
+		t.Errorf("second GetControlMode() over the same PMPP connection error = %v", err)
+	}
+
+	// A client does not close the connection of the line on Close.
+	client := NewClient(dms, WithPMPP(conn))
+	if _, err := client.GetControlMode(); err != nil {
+		t.Fatalf("Client.GetControlMode() over PMPP error = %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetControlMode(dms, WithPMPP(conn)); err != nil {
+		t.Errorf("GetControlMode() over PMPP after Client.Close error = %v", err)
+	}
+}
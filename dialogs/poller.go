@@ -0,0 +1,307 @@
+package dialogs
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// HealthSnapshot is the state of a sign read by a Poller.
+type HealthSnapshot struct {
+	Time             time.Time // the time the poll ended
+	ShortErrorStatus d.ShortErrorStatusBits
+	Message          CurrentMessage
+	Climate          ClimateStatus
+}
+
+// HealthChange is the kind of a HealthEvent.
+type HealthChange int
+
+const (
+	// ChangeErrorStatus is a change of shortErrorStatus.
+	ChangeErrorStatus HealthChange = iota + 1
+	// ChangeMessage is a change of the displayed message: its memory type,
+	// number, CRC or source mode. The time remaining is not a change.
+	ChangeMessage
+	// ChangeClimate is a change of the temperatures, the humidity, the
+	// failed fans or the climate flags.
+	ChangeClimate
+	// ChangeCommunication is a poll failing after one that succeeded, or the
+	// first poll failing, with Err set, and a poll succeeding after one that
+	// failed, with Err nil.
+	ChangeCommunication
+)
+
+func (c HealthChange) String() string {
+	switch c {
+	case ChangeErrorStatus:
+		return "errorStatus"
+	case ChangeMessage:
+		return "message"
+	case ChangeClimate:
+		return "climate"
+	case ChangeCommunication:
+		return "communication"
+	}
+	return fmt.Sprintf("HealthChange(%d)", int(c))
+}
+
+// HealthEvent is a change seen by a Poller between two polls. Previous and
+// Current are the last snapshots read successfully, which are equal for a
+// ChangeCommunication.
+type HealthEvent struct {
+	Change   HealthChange
+	Previous HealthSnapshot
+	Current  HealthSnapshot
+	Err      error // the error of the poll, ChangeCommunication only
+}
+
+// Poller reads the health of the sign of a Client periodically, i.e.
+// shortErrorStatus, the current message and the climate status, and
+// publishes the changes to its subscribers. It is started by Client.Poll.
+type Poller struct {
+	client   *Client
+	interval time.Duration
+	options  []Option
+
+	mu          sync.Mutex
+	latest      HealthSnapshot
+	polled      bool // latest has been read once
+	err         error
+	subscribers map[int]*subscriber
+	next        int
+	stopped     bool
+
+	dropped int64
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// subscriber receives the events of a Poller on events or through handler.
+type subscriber struct {
+	events  chan HealthEvent
+	handler func(HealthEvent)
+	ended   bool // guarded by the mu of the Poller
+}
+
+// end ends the subscription. It is called with the mu of the Poller held.
+func (sub *subscriber) end() {
+	if !sub.ended && sub.events != nil {
+		close(sub.events)
+	}
+	sub.ended = true
+}
+
+// Poll starts a Poller reading the health of the sign every interval, the
+// first time right away. The polls are dialogs of the client, run with the
+// given options, so they wait for the dialog in flight. The poller stops
+// when Stop is called or when the client is shut down.
+func (c *Client) Poll(interval time.Duration, options ...Option) (*Poller, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf("poll interval %v is not positive", interval)
+	}
+	p := &Poller{
+		client:      c,
+		interval:    interval,
+		options:     options,
+		subscribers: map[int]*subscriber{},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+// Latest returns the last snapshot read successfully, with false if no poll
+// has succeeded yet, and the error of the last poll.
+func (p *Poller) Latest() (HealthSnapshot, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.latest, p.polled, p.err
+}
+
+// Subscribe returns a channel receiving the events, queueing up to buffer of
+// them, and a function ending the subscription. Events arriving while the
+// queue is full are dropped and counted by Dropped. The channel is closed
+// when the subscription ends or the poller stops.
+func (p *Poller) Subscribe(buffer int) (<-chan HealthEvent, func()) {
+	events := make(chan HealthEvent, buffer)
+	return events, p.subscribe(&subscriber{events: events})
+}
+
+// OnChange calls handler with each event, from the polling goroutine, and
+// returns a function ending the subscription. A slow handler delays the next
+// poll. The handler may call Stop, but not Wait.
+func (p *Poller) OnChange(handler func(HealthEvent)) func() {
+	return p.subscribe(&subscriber{handler: handler})
+}
+
+func (p *Poller) subscribe(sub *subscriber) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		sub.end()
+		return func() {}
+	}
+	id := p.next
+	p.next++
+	p.subscribers[id] = sub
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.subscribers, id)
+		sub.end()
+	}
+}
+
+// Dropped returns the number of events dropped because the channel of a
+// subscriber was full.
+func (p *Poller) Dropped() int { return int(atomic.LoadInt64(&p.dropped)) }
+
+// Done returns a channel closed once the poller has stopped.
+func (p *Poller) Done() <-chan struct{} { return p.done }
+
+// Stop stops polling: once the poll in flight has finished, the channels of
+// the subscribers are closed and Done is closed. Stop does not wait for it,
+// so an OnChange handler may call it; use Wait for that.
+func (p *Poller) Stop() {
+	p.once.Do(func() { close(p.stop) })
+}
+
+// Wait waits until the poller has stopped. It must not be called from an
+// OnChange handler, which runs on the polling goroutine and would wait for
+// itself.
+func (p *Poller) Wait() { <-p.done }
+
+func (p *Poller) run() {
+	defer p.close()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if errors.Is(p.poll(), ErrClientClosed) {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// close ends every subscription once the polling goroutine has returned.
+func (p *Poller) close() {
+	p.mu.Lock()
+	p.stopped = true
+	for id, sub := range p.subscribers {
+		delete(p.subscribers, id)
+		sub.end()
+	}
+	p.mu.Unlock()
+	close(p.done)
+}
+
+// poll reads a snapshot and publishes its changes.
+func (p *Poller) poll() error {
+	snapshot, err := p.client.pollHealth(p.options)
+
+	p.mu.Lock()
+	previous, polled, lastErr := p.latest, p.polled, p.err
+	var events []HealthEvent
+	switch {
+	case err != nil:
+		if lastErr == nil {
+			events = append(events, HealthEvent{Change: ChangeCommunication, Previous: previous, Current: previous, Err: err})
+		}
+	default:
+		if lastErr != nil {
+			events = append(events, HealthEvent{Change: ChangeCommunication, Previous: previous, Current: snapshot})
+		}
+		if polled {
+			events = append(events, healthChanges(previous, snapshot)...)
+		}
+		p.latest, p.polled = snapshot, true
+	}
+	p.err = err
+	subscribers := make([]*subscriber, 0, len(p.subscribers))
+	for _, sub := range p.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	p.mu.Unlock()
+
+	for _, event := range events {
+		for _, sub := range subscribers {
+			p.deliver(sub, event)
+		}
+	}
+	return err
+}
+
+func (p *Poller) deliver(sub *subscriber, event HealthEvent) {
+	p.mu.Lock()
+	if sub.ended {
+		p.mu.Unlock()
+		return
+	}
+	if sub.handler != nil {
+		p.mu.Unlock()
+		sub.handler(event)
+		return
+	}
+	defer p.mu.Unlock()
+	select {
+	case sub.events <- event:
+	default:
+		atomic.AddInt64(&p.dropped, 1)
+	}
+}
+
+// healthChanges returns the events between two snapshots.
+func healthChanges(previous, current HealthSnapshot) []HealthEvent {
+	var events []HealthEvent
+	if previous.ShortErrorStatus != current.ShortErrorStatus {
+		events = append(events, HealthEvent{Change: ChangeErrorStatus, Previous: previous, Current: current})
+	}
+	a, b := previous.Message, current.Message
+	if a.MessageMemoryType != b.MessageMemoryType || a.MessageNumber != b.MessageNumber ||
+		a.MessageCRC != b.MessageCRC || a.SourceMode != b.SourceMode {
+		events = append(events, HealthEvent{Change: ChangeMessage, Previous: previous, Current: current})
+	}
+	if !reflect.DeepEqual(previous.Climate, current.Climate) {
+		events = append(events, HealthEvent{Change: ChangeClimate, Previous: previous, Current: current})
+	}
+	return events
+}
+
+// pollHealth reads a snapshot in a single dialog.
+func (c *Client) pollHealth(options []Option) (HealthSnapshot, error) {
+	if err := c.lock(); err != nil {
+		return HealthSnapshot{}, err
+	}
+	defer c.unlock()
+	s := newSession(c.dms, "PollHealth", c.dialogOptions(options))
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return HealthSnapshot{}, err
+	}
+	var snapshot HealthSnapshot
+	bits, err := getInt(s, "shortErrorStatus", d.ShortErrorStatus.Identifier(0))
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.ShortErrorStatus = d.ShortErrorStatusBits(bits)
+	if snapshot.Message, err = retrieveCurrentMessage(s); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Climate, err = getClimateStatus(s); err != nil {
+		return snapshot, err
+	}
+	snapshot.Time = time.Now()
+	return snapshot, nil
+}
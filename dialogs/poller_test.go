@@ -0,0 +1,148 @@
+package dialogs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+	"github.com/pkg/errors"
+)
+
+// nextHealthEvent returns the next event of kind change, skipping the
+// others.
+func nextHealthEvent(t *testing.T, events <-chan HealthEvent, change HealthChange) HealthEvent {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed, want %v", change)
+			}
+			if event.Change == change {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("no %v event", change)
+		}
+	}
+}
+
+func TestClient_Poll(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	client := NewClient(sign.GoSNMP())
+	if _, err := client.Poll(0); err == nil {
+		t.Error("Poll(0) succeeded, want an error")
+	}
+	poller, err := client.Poll(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer poller.Stop()
+	events, unsubscribe := poller.Subscribe(16)
+	defer unsubscribe()
+	var handled []HealthChange
+	poller.OnChange(func(event HealthEvent) { handled = append(handled, event.Change) })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok, _ := poller.Latest(); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no snapshot")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, int(d.ShortErrorPixel))
+	event := nextHealthEvent(t, events, ChangeErrorStatus)
+	if event.Previous.ShortErrorStatus != 0 || event.Current.ShortErrorStatus != d.ShortErrorPixel {
+		t.Errorf("event = %v to %v, want 0 to pixel error", event.Previous.ShortErrorStatus, event.Current.ShortErrorStatus)
+	}
+
+	if _, err := client.DisplayText(1, "[jp3]POLLED", 100, 65535); err != nil {
+		t.Fatal(err)
+	}
+	event = nextHealthEvent(t, events, ChangeMessage)
+	if event.Current.Message.MessageMemoryType != d.MemoryChangeable.Int() || event.Current.Message.MultiString != "[jp3]POLLED" {
+		t.Errorf("event message = %+v, want the changeable message 1", event.Current.Message)
+	}
+	if snapshot, _, err := poller.Latest(); err != nil || snapshot.Message.MultiString != "[jp3]POLLED" {
+		t.Errorf("Latest() = %+v, %v, want the changeable message 1", snapshot.Message, err)
+	}
+
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	event = nextHealthEvent(t, events, ChangeCommunication)
+	if !errors.Is(event.Err, ErrClientClosed) {
+		t.Errorf("event error = %v, want ErrClientClosed", event.Err)
+	}
+	select {
+	case <-poller.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("poller did not stop after Shutdown")
+	}
+	if _, ok := <-events; ok {
+		t.Error("events not closed after the poller stopped")
+	}
+	want := []HealthChange{ChangeErrorStatus, ChangeMessage, ChangeCommunication}
+	if len(handled) != len(want) {
+		t.Fatalf("handler got %v, want %v", handled, want)
+	}
+	for i := range want {
+		if handled[i] != want[i] {
+			t.Errorf("handler got %v, want %v", handled, want)
+		}
+	}
+}
+
+func TestPoller_Stop(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	dms := sign.GoSNMP()
+	dms.Timeout = 50 * time.Millisecond
+	client := NewClient(dms)
+	poller, err := client.Poll(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A handler stopping the poller at the first event does not deadlock.
+	poller.OnChange(func(event HealthEvent) { poller.Stop() })
+	sign.Drop(1)
+	select {
+	case <-poller.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("poller did not stop from its handler")
+	}
+	poller.Stop()
+	poller.Wait()
+}
+
+func Test_healthChanges(t *testing.T) {
+	previous := HealthSnapshot{Message: CurrentMessage{MessageMemoryType: 3, MessageNumber: 1, MessageCRC: 0x1234, TimeRemaining: 10}}
+	current := previous
+	current.Message.TimeRemaining = 9
+	if events := healthChanges(previous, current); len(events) != 0 {
+		t.Errorf("healthChanges() with the time remaining changed = %v, want none", events)
+	}
+	current.Climate.AmbientMax = 40
+	current.ShortErrorStatus = d.ShortErrorPower
+	events := healthChanges(previous, current)
+	if len(events) != 2 || events[0].Change != ChangeErrorStatus || events[1].Change != ChangeClimate {
+		t.Errorf("healthChanges() = %v, want errorStatus and climate", events)
+	}
+}
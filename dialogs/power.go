@@ -0,0 +1,151 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// PowerSupply is a row of the dmsPowerStatusTable.
+type PowerSupply struct {
+	Index       int    // dmsPowerIndex
+	Description string // dmsPowerDescription
+	MfrStatus   string // dmsPowerMfrStatus
+	Status      int    // dmsPowerStatus, e.g. d.PowerFail
+	StatusName  string // e.g. "noError" or "voltageOutOfSpec"
+	Voltage     int    // dmsPowerVoltage in hundredths of a volt
+	Type        int    // dmsPowerType, e.g. d.PowerSourceBattery
+	TypeName    string // e.g. "acLine" or "battery"
+}
+
+// Failed reports whether the power supply reports an error.
+func (p PowerSupply) Failed() bool { return p.Status != d.PowerNoError.Int() }
+
+// PowerStatus holds the power source and voltages of a sign, the power error
+// flag of shortErrorStatus and the status of its individual power supplies.
+type PowerStatus struct {
+	Source     int    // powerSource, e.g. d.PowerSourceACLine
+	SourceName string // e.g. "acLine" or "battery"
+	SignVolts  int    // signVolts in hundredths of a volt
+	LineVolts  int    // lineVolts in hundredths of a volt
+	PowerError bool   // the power error bit of shortErrorStatus
+
+	Supplies []PowerSupply // nil if the sign has no dmsPowerStatusTable
+}
+
+// OnBattery reports whether the sign runs from its battery.
+func (p PowerStatus) OnBattery() bool { return p.Source == d.PowerSourceBattery.Int() }
+
+// FailedSupplies returns the power supplies that report an error.
+func (p PowerStatus) FailedSupplies() []PowerSupply {
+	var failed []PowerSupply
+	for _, supply := range p.Supplies {
+		if supply.Failed() {
+			failed = append(failed, supply)
+		}
+	}
+	return failed
+}
+
+// GetPowerStatus reads the power source and voltages of the sign and the
+// power error flag of shortErrorStatus, and walks the dmsPowerStatusTable, so
+// that signs running on battery or with failed power supplies can be
+// detected. Signs without the table return no Supplies.
+func GetPowerStatus(dms *gosnmp.GoSNMP, options ...Option) (PowerStatus, error) {
+	s := newSession(dms, "GetPowerStatus", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return PowerStatus{}, err
+	}
+	return getPowerStatus(s)
+}
+
+func getPowerStatus(s *session) (status PowerStatus, err error) {
+	oids := []string{
+		d.PowerSource.Identifier(0),
+		d.SignVolts.Identifier(0),
+		d.LineVolts.Identifier(0),
+		d.ShortErrorStatus.Identifier(0),
+	}
+	getResults, err := s.get("get power status", oids...)
+	if err != nil {
+		return status, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			status.Source, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[1]):
+			status.SignVolts, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[2]):
+			status.LineVolts, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[3]):
+			bits, _ := variable.Value.(int)
+			status.PowerError = d.ShortErrorStatusBits(bits).PowerError()
+		}
+	}
+	name, err := d.Format(d.PowerSource, status.Source)
+	if err != nil {
+		return status, s.wrap(err, "format powerSource", oids[0])
+	}
+	status.SourceName = name.(string)
+
+	// dmsPowerNumRows is not supported by signs without the table.
+	numRows, err := getInt(s, "dmsPowerNumRows", d.DmsPowerNumRows.Identifier(0))
+	if errors.Is(err, ErrNoSuchName) {
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	for index := 1; index <= numRows; index++ {
+		supply, err := getPowerSupply(s, index)
+		if err != nil {
+			return status, err
+		}
+		status.Supplies = append(status.Supplies, supply)
+	}
+	return status, nil
+}
+
+// getPowerSupply reads row index of the dmsPowerStatusTable.
+func getPowerSupply(s *session, index int) (supply PowerSupply, err error) {
+	oids := []string{
+		d.DmsPowerDescription.Identifier(index),
+		d.DmsPowerMfrStatus.Identifier(index),
+		d.DmsPowerStatus.Identifier(index),
+		d.DmsPowerVoltage.Identifier(index),
+		d.DmsPowerType.Identifier(index),
+	}
+	getResults, err := s.get("get power supply", oids...)
+	if err != nil {
+		return supply, err
+	}
+	supply.Index = index
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			description, _ := variable.Value.([]byte)
+			supply.Description = string(description)
+		case oidEqual(variable.Name, oids[1]):
+			mfrStatus, _ := variable.Value.([]byte)
+			supply.MfrStatus = string(mfrStatus)
+		case oidEqual(variable.Name, oids[2]):
+			supply.Status, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[3]):
+			supply.Voltage, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[4]):
+			supply.Type, _ = variable.Value.(int)
+		}
+	}
+	name, err := d.Format(d.DmsPowerStatus, supply.Status)
+	if err != nil {
+		return supply, s.wrap(err, "format dmsPowerStatus", oids[2])
+	}
+	supply.StatusName = name.(string)
+	if name, err = d.Format(d.DmsPowerType, supply.Type); err != nil {
+		return supply, s.wrap(err, "format dmsPowerType", oids[4])
+	}
+	supply.TypeName = name.(string)
+	return supply, nil
+}
@@ -0,0 +1,54 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetPowerStatus(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.Store(d.PowerSource.Identifier(0), gosnmp.Integer, d.PowerSourceBattery.Int())
+	sign.Store(d.LineVolts.Identifier(0), gosnmp.Integer, 0)
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, 1<<2)
+	sign.Store(d.DmsPowerStatus.Identifier(1), gosnmp.Integer, d.PowerFail.Int())
+	sign.Store(d.DmsPowerVoltage.Identifier(1), gosnmp.Integer, 0)
+
+	status, err := GetPowerStatus(sign.GoSNMP())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PowerStatus{
+		Source:     7,
+		SourceName: "battery",
+		SignVolts:  2400,
+		LineVolts:  0,
+		PowerError: true,
+		Supplies: []PowerSupply{
+			{Index: 1, Description: "main supply", MfrStatus: "OK", Status: 3, StatusName: "powerFail", Voltage: 0, Type: 4, TypeName: "acLine"},
+			{Index: 2, Description: "backup battery", MfrStatus: "OK", Status: 2, StatusName: "noError", Voltage: 2550, Type: 7, TypeName: "battery"},
+		},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("GetPowerStatus() = %+v, want %+v", status, want)
+	}
+	if !status.OnBattery() {
+		t.Error("OnBattery() = false, want true")
+	}
+	if failed := status.FailedSupplies(); len(failed) != 1 || failed[0].Description != "main supply" {
+		t.Errorf("FailedSupplies() = %+v, want the main supply", failed)
+	}
+
+	// Signs without the power status table still report the power source.
+	sign.Delete(d.DmsPowerNumRows.Identifier(0))
+	if status, err := GetPowerStatus(sign.GoSNMP()); err != nil || status.Supplies != nil || status.SourceName != "battery" {
+		t.Errorf("GetPowerStatus() without supplies = %+v, %v", status, err)
+	}
+}
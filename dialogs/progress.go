@@ -0,0 +1,41 @@
+package dialogs
+
+// Progress is the progress of a dialog of many steps, passed to the
+// function of WithProgress.
+type Progress struct {
+	Dialog  string // e.g. "ConfiguringFont"
+	Step    string // the step in progress, e.g. "set character 65"
+	Done    int    // items transferred so far, e.g. characters or messages
+	Total   int    // items to transfer, 0 if not known in advance
+	Retries int    // requests of the dialog repeated so far, see WithRetryPolicy
+}
+
+// WithProgress calls progress as the long dialogs advance, so that a user
+// interface can show a progress bar: after each character of
+// ConfiguringFont, each block of ConfiguringGraphic, each action of
+// SyncLibrary and each page of rows of a table walk, e.g. of RetrievingFont
+// or ListMessages, and whenever a request is repeated. It is called on the
+// goroutine of the dialog, which waits for it to return.
+func WithProgress(progress func(Progress)) Option {
+	return func(o *dialogOptions) { o.progress = progress }
+}
+
+// progress records that done of total items were transferred by step and
+// reports it to the function of WithProgress.
+func (s *session) progress(step string, done, total int) {
+	s.done, s.total = done, total
+	s.reportProgress(step)
+}
+
+// retried records that a request of step was repeated.
+func (s *session) retried(step string) {
+	s.retries++
+	s.reportProgress(step)
+}
+
+func (s *session) reportProgress(step string) {
+	if s.options.progress == nil {
+		return
+	}
+	s.options.progress(Progress{Dialog: s.dialog, Step: step, Done: s.done, Total: s.total, Retries: s.retries})
+}
@@ -0,0 +1,69 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/crc"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestWithProgress(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	dms.Timeout = 50 * time.Millisecond
+
+	var reports []Progress
+	progress := WithProgress(func(p Progress) { reports = append(reports, p) })
+	font := Font{
+		Number:      5,
+		Name:        "narrow",
+		Height:      2,
+		CharSpacing: 1,
+		LineSpacing: 1,
+		Characters: []crc.Character{
+			{Number: 'I', Width: 1, Bitmap: []byte{0xC0}},
+			{Number: ' '},
+			{Number: 'L', Width: 2, Bitmap: []byte{0xB0}},
+		},
+	}
+	sign.Drop(1)
+	if _, err := ConfiguringFont(dms, 0, font, progress, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond})); err != nil {
+		t.Fatal(err)
+	}
+	want := []Progress{
+		{Dialog: "ConfiguringFont", Retries: 1},
+		{Dialog: "ConfiguringFont", Step: "set character 73", Done: 1, Total: 2, Retries: 1},
+		{Dialog: "ConfiguringFont", Step: "set character 76", Done: 2, Total: 2, Retries: 1},
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("ConfiguringFont() reported %+v, want %+v", reports, want)
+	}
+	if reports[0].Retries != 1 || reports[0].Step == "" {
+		t.Errorf("first report = %+v, want the retry of the dropped request", reports[0])
+	}
+	for i := 1; i < len(want); i++ {
+		if reports[i] != want[i] {
+			t.Errorf("report %d = %+v, want %+v", i, reports[i], want[i])
+		}
+	}
+
+	// A table walk reports the rows read after each page.
+	reports = nil
+	dms.Timeout = time.Second
+	if _, err := ListGraphics(dms, progress); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) == 0 || reports[len(reports)-1].Done == 0 {
+		t.Fatalf("ListGraphics() reported %+v, want the rows read", reports)
+	}
+	for i, report := range reports {
+		if report.Step != "walk graphic table" || report.Total != 0 || i > 0 && report.Done < reports[i-1].Done {
+			t.Errorf("ListGraphics() report %d = %+v", i, report)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package dialogs
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit caps the SNMP requests sent to a sign, as many controllers lock
+// up when polled faster than a few requests per second. It is a token
+// bucket: Burst requests may be sent at once, then Rate per second.
+type RateLimit struct {
+	Rate  float64 // requests per second; 0 disables the limit
+	Burst int     // requests sent without waiting, at least 1
+}
+
+// RateLimits are the rate limits of classes of signs, e.g. keyed by the
+// moduleModel of their controller as reported by GetInventory.
+type RateLimits map[string]RateLimit
+
+// For returns the rate limit of class or, if there is none, the one of the
+// class "", the default.
+func (limits RateLimits) For(class string) RateLimit {
+	if limit, ok := limits[class]; ok {
+		return limit
+	}
+	return limits[""]
+}
+
+// WithRateLimit makes the dialogs wait so that the requests sent to the sign
+// respect limit; each attempt of WithRetryPolicy is a request, and so is
+// each page of rows of a table walk. Passed to NewClient, the limit applies
+// to all the dialogs of the client together, e.g. to a Poller and the
+// activations of an operator; otherwise to each dialog on its own.
+func WithRateLimit(limit RateLimit) Option {
+	return func(o *dialogOptions) { o.rateLimit = limit }
+}
+
+// rateLimiter is the token bucket of a RateLimit.
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a full bucket for limit, or nil if limit is
+// disabled.
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &rateLimiter{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+}
+
+// wait takes a token, waiting for it if the bucket is empty. Tokens are
+// reserved in order, so concurrent callers are served first come, first
+// served.
+func (l *rateLimiter) wait() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.limit.Rate
+	if burst := float64(l.limit.Burst); l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.limit.Rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
@@ -0,0 +1,50 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_WithRateLimit(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	// 2 requests at once, then one every 50 ms.
+	client := NewClient(sign.GoSNMP(), WithRateLimit(RateLimit{Rate: 20, Burst: 2}))
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAction(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("the burst took %v, want no wait", elapsed)
+	}
+	// The limit spans the dialogs of the client.
+	for i := 0; i < 4; i++ {
+		if _, err := client.GetAction(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("6 requests took %v, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimits_For(t *testing.T) {
+	limits := RateLimits{"": {Rate: 5, Burst: 1}, "SC-100": {Rate: 1, Burst: 1}}
+	if got := limits.For("SC-100"); got.Rate != 1 {
+		t.Errorf("For(SC-100) = %+v, want 1 request per second", got)
+	}
+	if got := limits.For("other"); got.Rate != 5 {
+		t.Errorf("For(other) = %+v, want the default", got)
+	}
+	if newRateLimiter(RateLimits{}.For("other")) != nil {
+		t.Error("no limit gives a limiter, want nil")
+	}
+}
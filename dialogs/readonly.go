@@ -0,0 +1,7 @@
+//go:build godms_readonly
+// +build godms_readonly
+
+package dialogs
+
+// buildReadOnly makes every dialog read-only, see WithReadOnly.
+const buildReadOnly = true
@@ -0,0 +1,42 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	client := NewClient(sign.GoSNMP(), WithReadOnly())
+	if !client.ReadOnly() {
+		t.Error("ReadOnly() = false")
+	}
+	requests := 0
+	client.SNMP().OnSent = func(*gosnmp.GoSNMP) { requests++ }
+	if _, err := client.DefiningMessage(d.MemoryChangeable.Int(), 1, "TEST", "10.0.0.5", 50, 0, 0); !errors.Is(err, ErrReadOnlyClient) {
+		t.Errorf("DefiningMessage() error = %v, want ErrReadOnlyClient", err)
+	}
+	if _, err := client.BlankSign(100); !errors.Is(err, ErrReadOnlyClient) {
+		t.Errorf("BlankSign() error = %v, want ErrReadOnlyClient", err)
+	}
+	if requests != 0 {
+		t.Errorf("read-only client sent %d requests", requests)
+	}
+
+	// Monitoring still works.
+	if _, err := client.RetrievingCurrentMessage(); err != nil {
+		t.Errorf("RetrievingCurrentMessage() error = %v", err)
+	}
+	if status, _ := sign.Load(d.DmsMessageStatus.Identifier(d.MemoryChangeable.Int(), 1)); status != d.NotUsed.Int() {
+		t.Errorf("dmsMessageStatus = %v, want notUsed", status)
+	}
+}
@@ -0,0 +1,83 @@
+package dialogs
+
+import (
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+type resetControllerResult struct {
+	Downtime         time.Duration // from the reset until the controller answered again
+	ShortErrorStatus d.ShortErrorStatusBits
+	Timings          []StepTiming
+}
+
+// ResetController performs the software reset dialog: dmsSWReset.0 is SET
+// to 1 and GET until the controller answers again with dmsSWReset.0 back at
+// 0, bounded by WithResetTimeout. The delay between the GETs starts at the
+// poll interval and backs off up to WithResetBackoff while the controller
+// does not answer. shortErrorStatus is then read to verify the controller
+// came back without errors; the errors it reports are returned in the result,
+// not as an error.
+func ResetController(dms *gosnmp.GoSNMP, options ...Option) (result resetControllerResult, err error) {
+	s := newSession(dms, "ResetController", options)
+	defer s.close()
+	defer func() { result.Timings = s.timings }()
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+	if err = resetController(s, s.options.resetTimeout, s.options.resetBackoff); err != nil {
+		return result, err
+	}
+	result.Downtime = time.Since(start)
+
+	// The reset may have dropped the connection.
+	if err = s.connect(); err != nil {
+		return result, err
+	}
+	bits, err := getInt(s, "shortErrorStatus", d.ShortErrorStatus.Identifier(0))
+	if err != nil {
+		return result, err
+	}
+	result.ShortErrorStatus = d.ShortErrorStatusBits(bits)
+	return result, nil
+}
+
+// resetController SETs dmsSWReset.0 to 1 and GETs it until it is back at 0.
+// The delay between the GETs starts at the poll interval and doubles after
+// every GET the controller does not answer, up to maxInterval.
+func resetController(s *session, timeout, maxInterval time.Duration) error {
+	_, err := s.set("set dmsSWReset", gosnmp.SnmpPDU{Name: d.DmsSWReset.Identifier(0), Type: d.DmsSWReset.Syntax(), Value: 1})
+	// A sign that resets at once may not answer the SET; only an error status fails the reset.
+	var snmpError *SNMPError
+	if errors.As(err, &snmpError) || errors.Is(err, ErrReadOnlyClient) {
+		return err
+	}
+
+	start := time.Now()
+	defer s.timed("wait for dmsSWReset", start)
+	deadline := start.Add(timeout)
+	interval := s.options.pollInterval
+	for {
+		value, err := getInt(s, "dmsSWReset", d.DmsSWReset.Identifier(0))
+		if err == nil && value == 0 {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			if err == nil {
+				err = s.wrap(errors.New("controller did not reset"), "get dmsSWReset", d.DmsSWReset.Identifier(0))
+			}
+			return err
+		}
+		time.Sleep(interval)
+		if err != nil && interval < maxInterval {
+			if interval *= 2; interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestResetController(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, d.ShortErrorDoorOpen.Int())
+
+	result, err := ResetController(sign.GoSNMP(), WithResetTimeout(time.Second), WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.ShortErrorStatus.DoorOpen() {
+		t.Errorf("ResetController() shortErrorStatus = %v, want doorOpenError", result.ShortErrorStatus)
+	}
+	if source, _ := sign.Load(d.DmsMsgSourceMode.Identifier(0)); source != d.SourceReset.Int() {
+		t.Errorf("dmsMsgSourceMode after ResetController = %v, want reset", source)
+	}
+	if len(result.Timings) == 0 {
+		t.Error("ResetController() recorded no timings")
+	}
+
+	if _, err := ResetController(sign.GoSNMP(), WithReadOnly()); err == nil {
+		t.Error("ResetController() of a read-only client succeeded")
+	}
+}
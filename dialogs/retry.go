@@ -0,0 +1,94 @@
+package dialogs
+
+import (
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy repeats the SNMP requests of the dialogs that fail for a
+// transient reason, e.g. over cellular links that drop packets, so that a
+// single lost datagram does not abort a dialog of many steps. Unlike
+// WithRetries, which makes gosnmp resend a request right away, the policy
+// waits longer between each attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent, including the
+	// first; 1 or less disables the policy.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt, multiplied by
+	// Multiplier, 2 if 0, before each later attempt, up to MaxBackoff if
+	// not 0.
+	Backoff    time.Duration
+	Multiplier float64
+	MaxBackoff time.Duration
+	// Jitter spreads each wait randomly by up to this fraction of it, from
+	// 0 to 1, so that the clients of many signs do not retry in step.
+	Jitter float64
+	// RetryOn reports whether a failed request is repeated, IsTransient if
+	// nil. A SET that timed out may have been applied by the sign; it is
+	// sent again with the same values.
+	RetryOn func(op Operation, err error) bool
+}
+
+// DefaultRetryPolicy returns a policy making 4 attempts, waiting 500
+// milliseconds, then 1 and 2 seconds, with a jitter of 20%.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 4, Backoff: 500 * time.Millisecond, Multiplier: 2, MaxBackoff: 8 * time.Second, Jitter: 0.2}
+}
+
+// WithRetryPolicy repeats the requests of the dialogs that fail for a
+// transient reason according to policy. Passed to NewClient, it applies to
+// every dialog of the client.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *dialogOptions) { o.retryPolicy = policy }
+}
+
+// IsTransient reports whether err is a failure worth repeating a request
+// for: the sign did not answer in time. Error statuses are answers of the
+// sign and are not transient.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var snmpErr *SNMPError
+	if errors.As(err, &snmpErr) {
+		return false
+	}
+	// gosnmp reports its timeouts with fmt.Errorf.
+	return strings.Contains(err.Error(), "request timeout")
+}
+
+// retry reports whether op is repeated after attempt failed with err.
+func (p RetryPolicy) retry(op Operation, attempt int, err error) bool {
+	if err == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.RetryOn != nil {
+		return p.RetryOn(op, err)
+	}
+	return IsTransient(err)
+}
+
+// backoff returns the wait after attempt failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	wait := float64(p.Backoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
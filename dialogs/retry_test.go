@@ -0,0 +1,63 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestWithRetryPolicy(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	dms.Timeout = 50 * time.Millisecond
+
+	sign.Drop(1)
+	_, err = GetAction(dms, 1)
+	if !IsTransient(err) {
+		t.Fatalf("GetAction() error = %v, want a transient error without a retry policy", err)
+	}
+
+	logger := &recordingLogger{}
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	sign.Drop(2)
+	if _, err := GetAction(dms, 1, WithRetryPolicy(policy), WithLogger(logger, LogRequests)); err != nil {
+		t.Fatalf("GetAction() error = %v, want success on the third attempt", err)
+	}
+	if len(logger.entries) != 3 || !IsTransient(logger.entries[0].Err) || logger.entries[2].Err != nil {
+		t.Errorf("logged %v, want two timeouts and a success", logger.entries)
+	}
+
+	sign.Drop(3)
+	if _, err := GetAction(dms, 1, WithRetryPolicy(policy)); !IsTransient(err) {
+		t.Errorf("GetAction() error = %v, want a transient error after 3 attempts", err)
+	}
+
+	// Error statuses are answers, not retried.
+	logger.entries = nil
+	if _, err := GetAction(dms, 99, WithRetryPolicy(policy), WithLogger(logger, LogRequests)); err == nil || IsTransient(err) {
+		t.Errorf("GetAction(99) error = %v, want noSuchName", err)
+	}
+	if len(logger.entries) != 1 {
+		t.Errorf("logged %d attempts for noSuchName, want 1", len(logger.entries))
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second} {
+		if got := policy.backoff(attempt + 1); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt+1, got, want)
+		}
+	}
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("backoff(1) with jitter = %v, want 50ms to 150ms", got)
+		}
+	}
+}
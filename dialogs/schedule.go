@@ -0,0 +1,395 @@
+package dialogs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// ScheduleEntry is a row of the timeBaseScheduleTable. On a day that
+// matches its months, its days of the week and its days of the month, the
+// sign runs its day plan. When several entries match a day, the sign picks
+// one of them; NTCIP 1201 leaves the choice to the vendor.
+type ScheduleEntry struct {
+	Number  int    // timeBaseScheduleNumber
+	Months  uint16 // timeBaseScheduleMonth, see MonthMask
+	Days    uint8  // timeBaseScheduleDay, see WeekdayMask
+	Dates   uint32 // timeBaseScheduleDate, see DateMask
+	DayPlan int    // timeBaseScheduleDayPlan, 0 disables the entry
+}
+
+// MonthMask returns the timeBaseScheduleMonth of months.
+func MonthMask(months ...time.Month) uint16 {
+	var mask uint16
+	for _, month := range months {
+		mask |= 1 << uint(month)
+	}
+	return mask
+}
+
+// WeekdayMask returns the timeBaseScheduleDay of days.
+func WeekdayMask(days ...time.Weekday) uint8 {
+	var mask uint8
+	for _, day := range days {
+		mask |= 1 << uint(day+1)
+	}
+	return mask
+}
+
+// DateMask returns the timeBaseScheduleDate of the days of the month dates,
+// from 1 to 31.
+func DateMask(dates ...int) uint32 {
+	var mask uint32
+	for _, date := range dates {
+		mask |= 1 << uint(date)
+	}
+	return mask
+}
+
+// Masks of every month, day of the week and day of the month.
+const (
+	AllMonths   uint16 = 0x1FFE
+	AllWeekdays uint8  = 0xFE
+	AllDates    uint32 = 0xFFFFFFFE
+)
+
+// DayPlanEvent is a row of the timeBaseDayPlanTable: at Hour:Minute local
+// time, the day plan runs Action.
+type DayPlanEvent struct {
+	DayPlan int    // dayPlanNumber
+	Event   int    // dayPlanEventNumber
+	Hour    int    // dayPlanHour, 0 to 23
+	Minute  int    // dayPlanMinute, 0 to 59
+	Action  string // dayPlanActionNumberOID, see ActionOID; "" for none
+}
+
+// ActionOID returns the dayPlanActionNumberOID of the row index of the
+// dmsActionTable, for DayPlanEvent.Action.
+func ActionOID(index int) string { return d.DmsActionMsgCode.Identifier(index) }
+
+// Schedule is the time base schedule of the sign: its entries, the events
+// of its day plans and the actions of the events.
+type Schedule struct {
+	Entries []ScheduleEntry
+	Events  []DayPlanEvent
+	Actions []ScheduleAction
+
+	// ActiveDayPlan is dayPlanStatus, the day plan running, 0 if none;
+	// read-only.
+	ActiveDayPlan int
+}
+
+// ScheduleSize is the size of the schedule tables of a sign.
+type ScheduleSize struct {
+	Entries   int // maxTimeBaseScheduleEntries
+	DayPlans  int // maxDayPlans
+	DayEvents int // maxDayPlanEvents, the events of each day plan
+	Actions   int // dmsActionTableEntries
+}
+
+// Validate checks that the rows of schedule fit the tables of a sign of the
+// given size, that the masks only have the bits of months, days of the week
+// and days of the month set and that the events run at a valid time. An
+// event that runs an action of the dmsActionTable must name a row of the
+// table.
+func (schedule Schedule) Validate(size ScheduleSize) error {
+	for _, entry := range schedule.Entries {
+		switch {
+		case entry.Number < 1 || entry.Number > size.Entries:
+			return errors.Errorf("schedule entry %d out of range 1-%d", entry.Number, size.Entries)
+		case entry.Months&^AllMonths != 0:
+			return errors.Errorf("schedule entry %d: invalid month mask %#x", entry.Number, entry.Months)
+		case entry.Days&^AllWeekdays != 0:
+			return errors.Errorf("schedule entry %d: invalid day mask %#x", entry.Number, entry.Days)
+		case entry.Dates&^AllDates != 0:
+			return errors.Errorf("schedule entry %d: invalid date mask %#x", entry.Number, entry.Dates)
+		case entry.DayPlan < 0 || entry.DayPlan > size.DayPlans:
+			return errors.Errorf("schedule entry %d: day plan %d out of range 0-%d", entry.Number, entry.DayPlan, size.DayPlans)
+		}
+	}
+	actionPrefix := strings.TrimSuffix(ActionOID(0), "0")
+	for _, event := range schedule.Events {
+		switch {
+		case event.DayPlan < 1 || event.DayPlan > size.DayPlans:
+			return errors.Errorf("day plan %d out of range 1-%d", event.DayPlan, size.DayPlans)
+		case event.Event < 1 || event.Event > size.DayEvents:
+			return errors.Errorf("day plan %d: event %d out of range 1-%d", event.DayPlan, event.Event, size.DayEvents)
+		case event.Hour < 0 || event.Hour > 23 || event.Minute < 0 || event.Minute > 59:
+			return errors.Errorf("day plan %d event %d: invalid time %d:%02d", event.DayPlan, event.Event, event.Hour, event.Minute)
+		}
+		if action := strings.TrimPrefix(event.Action, "."); strings.HasPrefix(action, actionPrefix) {
+			index := strings.TrimPrefix(action, actionPrefix)
+			if n, err := strconv.Atoi(index); err != nil || n < 1 || n > size.Actions {
+				return errors.Errorf("day plan %d event %d: action %s out of range 1-%d", event.DayPlan, event.Event, index, size.Actions)
+			}
+		}
+	}
+	for _, action := range schedule.Actions {
+		if err := action.Validate(size.Actions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetScheduleSize reads the size of the schedule tables of the sign.
+func GetScheduleSize(dms *gosnmp.GoSNMP, options ...Option) (ScheduleSize, error) {
+	s := newSession(dms, "GetScheduleSize", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return ScheduleSize{}, err
+	}
+	return getScheduleSize(s)
+}
+
+func getScheduleSize(s *session) (size ScheduleSize, err error) {
+	for _, c := range []struct {
+		object d.Reader
+		value  *int
+	}{
+		{d.MaxTimeBaseScheduleEntries, &size.Entries},
+		{d.MaxDayPlans, &size.DayPlans},
+		{d.MaxDayPlanEvents, &size.DayEvents},
+		{d.DmsActionTableEntries, &size.Actions},
+	} {
+		if *c.value, err = getInt(s, c.object.ObjectType(), c.object.Identifier(0)); err != nil {
+			return size, err
+		}
+	}
+	return size, nil
+}
+
+// GetSchedule reads the time base schedule of the sign. Only the rows in
+// use are returned: the entries with a day plan, the events with an action
+// and the actions of these events.
+func GetSchedule(dms *gosnmp.GoSNMP, options ...Option) (Schedule, error) {
+	s := newSession(dms, "GetSchedule", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return Schedule{}, err
+	}
+	size, err := getScheduleSize(s)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return getSchedule(s, size)
+}
+
+func getSchedule(s *session, size ScheduleSize) (schedule Schedule, err error) {
+	for number := 1; number <= size.Entries; number++ {
+		entry, err := getScheduleEntry(s, number)
+		if err != nil {
+			return schedule, err
+		}
+		if entry.DayPlan != 0 {
+			schedule.Entries = append(schedule.Entries, entry)
+		}
+	}
+
+	actions := map[int]bool{}
+	actionPrefix := strings.TrimSuffix(ActionOID(0), "0")
+	for plan := 1; plan <= size.DayPlans; plan++ {
+		for number := 1; number <= size.DayEvents; number++ {
+			event, err := getDayPlanEvent(s, plan, number)
+			if err != nil {
+				return schedule, err
+			}
+			if event.Action == "" {
+				continue
+			}
+			schedule.Events = append(schedule.Events, event)
+			if index, err := strconv.Atoi(strings.TrimPrefix(event.Action, actionPrefix)); err == nil && index <= size.Actions {
+				actions[index] = true
+			}
+		}
+	}
+
+	for index := 1; index <= size.Actions; index++ {
+		if !actions[index] {
+			continue
+		}
+		action, err := getAction(s, index)
+		if err != nil {
+			return schedule, err
+		}
+		schedule.Actions = append(schedule.Actions, action)
+	}
+
+	schedule.ActiveDayPlan, err = getInt(s, "dayPlanStatus", d.DayPlanStatus.Identifier(0))
+	return schedule, err
+}
+
+func getScheduleEntry(s *session, number int) (ScheduleEntry, error) {
+	entry := ScheduleEntry{Number: number}
+	oids := []string{
+		d.TimeBaseScheduleMonth.Identifier(number),
+		d.TimeBaseScheduleDay.Identifier(number),
+		d.TimeBaseScheduleDate.Identifier(number),
+		d.TimeBaseScheduleDayPlan.Identifier(number),
+	}
+	getResults, err := s.get(fmt.Sprintf("get timeBaseScheduleEntry %d", number), oids...)
+	if err != nil {
+		return entry, err
+	}
+	for _, variable := range getResults.Variables {
+		value, _ := variable.Value.(int)
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			entry.Months = uint16(value)
+		case oidEqual(variable.Name, oids[1]):
+			entry.Days = uint8(value)
+		case oidEqual(variable.Name, oids[2]):
+			entry.Dates = uint32(value)
+		case oidEqual(variable.Name, oids[3]):
+			entry.DayPlan = value
+		}
+	}
+	return entry, nil
+}
+
+// dayPlanOID returns the OID of column in the row of event of plan in the
+// timeBaseDayPlanTable.
+func dayPlanOID(column d.Reader, plan, event int) string {
+	return fmt.Sprintf("%s.%d", column.Identifier(plan), event)
+}
+
+func getDayPlanEvent(s *session, plan, number int) (DayPlanEvent, error) {
+	event := DayPlanEvent{DayPlan: plan, Event: number}
+	oids := []string{
+		dayPlanOID(d.DayPlanHour, plan, number),
+		dayPlanOID(d.DayPlanMinute, plan, number),
+		dayPlanOID(d.DayPlanActionNumberOID, plan, number),
+	}
+	getResults, err := s.get(fmt.Sprintf("get dayPlanEntry %d.%d", plan, number), oids...)
+	if err != nil {
+		return event, err
+	}
+	for _, variable := range getResults.Variables {
+		switch {
+		case oidEqual(variable.Name, oids[0]):
+			event.Hour, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[1]):
+			event.Minute, _ = variable.Value.(int)
+		case oidEqual(variable.Name, oids[2]):
+			oid, _ := variable.Value.(string)
+			if oid = strings.TrimPrefix(oid, "."); oid != nullOID {
+				event.Action = oid
+			}
+		}
+	}
+	return event, nil
+}
+
+// SetSchedule validates schedule against the size of the schedule tables of
+// the sign and sets its rows: the actions first, then the events of the day
+// plans and the entries last, so that the sign never runs an event before
+// its action is defined. The messages of the actions are checked as by
+// SetAction. Rows not in schedule are left as they are; set an entry with
+// DayPlan 0 or an event with no Action to disable it. The rows are read
+// back.
+func SetSchedule(dms *gosnmp.GoSNMP, schedule Schedule, options ...Option) (Schedule, error) {
+	s := newSession(dms, "SetSchedule", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return Schedule{}, err
+	}
+	size, err := getScheduleSize(s)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if err := schedule.Validate(size); err != nil {
+		return Schedule{}, s.wrap(err, "validate schedule")
+	}
+
+	current := Schedule{}
+	for _, action := range schedule.Actions {
+		if err := checkActionMessage(s, action); err != nil {
+			return current, err
+		}
+		got, err := writeAction(s, action)
+		if err != nil {
+			return current, err
+		}
+		current.Actions = append(current.Actions, got)
+	}
+
+	for _, event := range schedule.Events {
+		action := strings.TrimPrefix(event.Action, ".")
+		if action == "" {
+			action = nullOID
+		}
+		if _, err := s.set(fmt.Sprintf("set dayPlanEntry %d.%d", event.DayPlan, event.Event),
+			gosnmp.SnmpPDU{Name: dayPlanOID(d.DayPlanHour, event.DayPlan, event.Event), Type: d.DayPlanHour.Syntax(), Value: event.Hour},
+			gosnmp.SnmpPDU{Name: dayPlanOID(d.DayPlanMinute, event.DayPlan, event.Event), Type: d.DayPlanMinute.Syntax(), Value: event.Minute},
+			gosnmp.SnmpPDU{Name: dayPlanOID(d.DayPlanActionNumberOID, event.DayPlan, event.Event), Type: d.DayPlanActionNumberOID.Syntax(), Value: action},
+		); err != nil {
+			return current, err
+		}
+		got, err := getDayPlanEvent(s, event.DayPlan, event.Event)
+		if err != nil {
+			return current, err
+		}
+		current.Events = append(current.Events, got)
+	}
+
+	for _, entry := range schedule.Entries {
+		// timeBaseScheduleDate ranges up to 4294967295, but gosnmp encodes an
+		// Integer as 32 bits signed; bit 31, the 31st, is sent as the sign bit.
+		if _, err := s.set(fmt.Sprintf("set timeBaseScheduleEntry %d", entry.Number),
+			gosnmp.SnmpPDU{Name: d.TimeBaseScheduleMonth.Identifier(entry.Number), Type: d.TimeBaseScheduleMonth.Syntax(), Value: int(entry.Months)},
+			gosnmp.SnmpPDU{Name: d.TimeBaseScheduleDay.Identifier(entry.Number), Type: d.TimeBaseScheduleDay.Syntax(), Value: int(entry.Days)},
+			gosnmp.SnmpPDU{Name: d.TimeBaseScheduleDate.Identifier(entry.Number), Type: d.TimeBaseScheduleDate.Syntax(), Value: int(int32(entry.Dates))},
+			gosnmp.SnmpPDU{Name: d.TimeBaseScheduleDayPlan.Identifier(entry.Number), Type: d.TimeBaseScheduleDayPlan.Syntax(), Value: entry.DayPlan},
+		); err != nil {
+			return current, err
+		}
+		got, err := getScheduleEntry(s, entry.Number)
+		if err != nil {
+			return current, err
+		}
+		current.Entries = append(current.Entries, got)
+	}
+
+	if current.ActiveDayPlan, err = getInt(s, "dayPlanStatus", d.DayPlanStatus.Identifier(0)); err != nil {
+		return current, err
+	}
+	want := schedule
+	want.ActiveDayPlan = current.ActiveDayPlan
+	want.Events = append([]DayPlanEvent(nil), schedule.Events...)
+	for i := range want.Events {
+		want.Events[i].Action = strings.TrimPrefix(want.Events[i].Action, ".")
+	}
+	if !scheduleEqual(current, want) {
+		return current, s.wrap(errors.Errorf("sign reports %+v, want %+v", current, want), "verify schedule")
+	}
+	return current, nil
+}
+
+// scheduleEqual reports whether a and b hold the same rows in the same
+// order.
+func scheduleEqual(a, b Schedule) bool {
+	if len(a.Entries) != len(b.Entries) || len(a.Events) != len(b.Events) || len(a.Actions) != len(b.Actions) {
+		return false
+	}
+	for i := range a.Entries {
+		if a.Entries[i] != b.Entries[i] {
+			return false
+		}
+	}
+	for i := range a.Events {
+		if a.Events[i] != b.Events[i] {
+			return false
+		}
+	}
+	for i := range a.Actions {
+		if !a.Actions[i].Equal(b.Actions[i]) {
+			return false
+		}
+	}
+	return a.ActiveDayPlan == b.ActiveDayPlan
+}
@@ -0,0 +1,108 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestMasks(t *testing.T) {
+	if got := MonthMask(time.January, time.December); got != 0x1002 {
+		t.Errorf("MonthMask() = %#x, want 0x1002", got)
+	}
+	if got := WeekdayMask(time.Sunday, time.Saturday); got != 0x82 {
+		t.Errorf("WeekdayMask() = %#x, want 0x82", got)
+	}
+	if got := DateMask(1, 31); got != 0x80000002 {
+		t.Errorf("DateMask() = %#x, want 0x80000002", got)
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	size := ScheduleSize{Entries: 2, DayPlans: 2, DayEvents: 3, Actions: 4}
+	tests := []struct {
+		name     string
+		schedule Schedule
+		wantErr  bool
+	}{
+		{"valid", Schedule{
+			Entries: []ScheduleEntry{{Number: 1, Months: AllMonths, Days: WeekdayMask(time.Monday), Dates: AllDates, DayPlan: 2}},
+			Events:  []DayPlanEvent{{DayPlan: 2, Event: 3, Hour: 23, Minute: 59, Action: ActionOID(4)}},
+			Actions: []ScheduleAction{{Index: 4, Code: make([]byte, 12)}},
+		}, false},
+		{"entry out of range", Schedule{Entries: []ScheduleEntry{{Number: 3}}}, true},
+		{"month bit 0", Schedule{Entries: []ScheduleEntry{{Number: 1, Months: 1}}}, true},
+		{"day plan out of range", Schedule{Entries: []ScheduleEntry{{Number: 1, DayPlan: 3}}}, true},
+		{"event out of range", Schedule{Events: []DayPlanEvent{{DayPlan: 1, Event: 4}}}, true},
+		{"invalid time", Schedule{Events: []DayPlanEvent{{DayPlan: 1, Event: 1, Hour: 24}}}, true},
+		{"action out of range", Schedule{Events: []DayPlanEvent{{DayPlan: 1, Event: 1, Action: ActionOID(5)}}}, true},
+		{"other action", Schedule{Events: []DayPlanEvent{{DayPlan: 1, Event: 1, Action: "1.3.6.1.4.1.1206.4.2.99.1"}}}, false},
+		{"action row out of range", Schedule{Actions: []ScheduleAction{{Index: 0}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schedule.Validate(size); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetSchedule(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	size, err := client.GetScheduleSize()
+	if err != nil || size.Entries != simulator.MaxTimeBaseScheduleEntries || size.Actions != simulator.DmsActionTableEntries {
+		t.Fatalf("GetScheduleSize() = %+v, %v", size, err)
+	}
+
+	morning, err := NewScheduleAction(1, d.Message{MemoryType: d.MemoryBlank, Number: 2}, 90, 200, "10.0.0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	evening, err := NewScheduleAction(2, d.Message{MemoryType: d.MemoryBlank, Number: 1}, 65535, 255, "10.0.0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := Schedule{
+		Entries: []ScheduleEntry{{
+			Number:  1,
+			Months:  AllMonths,
+			Days:    WeekdayMask(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+			Dates:   AllDates,
+			DayPlan: 1,
+		}},
+		Events: []DayPlanEvent{
+			{DayPlan: 1, Event: 1, Hour: 7, Minute: 30, Action: ActionOID(1)},
+			{DayPlan: 1, Event: 2, Hour: 9, Action: ActionOID(2)},
+		},
+		Actions: []ScheduleAction{morning, evening},
+	}
+	got, err := client.SetSchedule(schedule)
+	if err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, schedule) {
+		t.Errorf("SetSchedule() = %+v, want %+v", got, schedule)
+	}
+
+	got, err = client.GetSchedule()
+	if err != nil {
+		t.Fatalf("GetSchedule() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, schedule) {
+		t.Errorf("GetSchedule() = %+v, want %+v", got, schedule)
+	}
+
+	if _, err := client.SetSchedule(Schedule{Entries: []ScheduleEntry{{Number: simulator.MaxTimeBaseScheduleEntries + 1}}}); err == nil {
+		t.Error("SetSchedule() beyond the table error = nil")
+	}
+}
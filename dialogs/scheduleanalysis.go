@@ -0,0 +1,173 @@
+package dialogs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	d "github.com/jacobleehei/godms"
+)
+
+// ScheduleIssueKind is the kind of a problem Schedule.Analyze finds.
+type ScheduleIssueKind int
+
+const (
+	// IssueEntryNeverMatches is an enabled entry with an empty month, day
+	// or date mask, which matches no day.
+	IssueEntryNeverMatches ScheduleIssueKind = iota + 1
+	// IssueOverlappingEntries is a pair of enabled entries that match a
+	// common day with different day plans; the sign runs only one of them.
+	IssueOverlappingEntries
+	// IssueEmptyDayPlan is an entry running a day plan without events.
+	IssueEmptyDayPlan
+	// IssueOverlappingEvents is a pair of events of a day plan at the same
+	// time.
+	IssueOverlappingEvents
+	// IssueDanglingAction is an event running a row of the dmsActionTable
+	// that is not in the schedule or activates no message.
+	IssueDanglingAction
+	// IssueUndefinedMessage is an action activating a message that is not
+	// valid in the message table, or whose CRC differs.
+	IssueUndefinedMessage
+)
+
+func (k ScheduleIssueKind) String() string {
+	switch k {
+	case IssueEntryNeverMatches:
+		return "entry never matches"
+	case IssueOverlappingEntries:
+		return "overlapping entries"
+	case IssueEmptyDayPlan:
+		return "empty day plan"
+	case IssueOverlappingEvents:
+		return "overlapping events"
+	case IssueDanglingAction:
+		return "dangling action"
+	case IssueUndefinedMessage:
+		return "undefined message"
+	}
+	return fmt.Sprintf("ScheduleIssueKind(%d)", int(k))
+}
+
+// ScheduleIssue is a problem of a schedule. The fields that do not apply to
+// its kind are 0.
+type ScheduleIssue struct {
+	Kind    ScheduleIssueKind
+	Entries []int // the timeBaseScheduleNumbers involved
+	DayPlan int
+	Events  []int // the dayPlanEventNumbers involved, of DayPlan
+	Action  int   // the dmsActionIndex involved
+	Detail  string
+}
+
+func (i ScheduleIssue) String() string { return i.Kind.String() + ": " + i.Detail }
+
+// Analyze reports the problems of schedule, e.g. as read by GetSchedule or
+// before SetSchedule deploys it: entries that never match or overlap, day
+// plans without events, events at the same time, events running an action
+// missing from schedule.Actions and actions activating a message missing
+// from messages. Pass the rows of ListMessages for the message memory types
+// the actions use; blank messages are always defined. Events running an
+// action of another table than the dmsActionTable are not checked. The
+// issues of the entries come first, then those of the day plans and those of
+// the actions.
+func (schedule Schedule) Analyze(messages []MessageEntry) []ScheduleIssue {
+	var issues []ScheduleIssue
+
+	var entries []ScheduleEntry
+	for _, entry := range schedule.Entries {
+		if entry.DayPlan == 0 {
+			continue
+		}
+		if entry.Months == 0 || entry.Days == 0 || entry.Dates == 0 {
+			issues = append(issues, ScheduleIssue{
+				Kind: IssueEntryNeverMatches, Entries: []int{entry.Number}, DayPlan: entry.DayPlan,
+				Detail: fmt.Sprintf("entry %d has an empty month, day or date mask", entry.Number),
+			})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	for i, a := range entries {
+		for _, b := range entries[i+1:] {
+			if a.DayPlan != b.DayPlan && a.Months&b.Months != 0 && a.Days&b.Days != 0 && a.Dates&b.Dates != 0 {
+				issues = append(issues, ScheduleIssue{
+					Kind: IssueOverlappingEntries, Entries: []int{a.Number, b.Number},
+					Detail: fmt.Sprintf("entries %d and %d match common days with day plans %d and %d", a.Number, b.Number, a.DayPlan, b.DayPlan),
+				})
+			}
+		}
+	}
+
+	events := map[int][]DayPlanEvent{}
+	for _, event := range schedule.Events {
+		if event.Action != "" {
+			events[event.DayPlan] = append(events[event.DayPlan], event)
+		}
+	}
+	for _, entry := range entries {
+		if len(events[entry.DayPlan]) == 0 {
+			issues = append(issues, ScheduleIssue{
+				Kind: IssueEmptyDayPlan, Entries: []int{entry.Number}, DayPlan: entry.DayPlan,
+				Detail: fmt.Sprintf("entry %d runs day plan %d, which has no events", entry.Number, entry.DayPlan),
+			})
+		}
+	}
+
+	actions := map[int]ScheduleAction{}
+	for _, action := range schedule.Actions {
+		actions[action.Index] = action
+	}
+	actionPrefix := strings.TrimSuffix(ActionOID(0), "0")
+	for _, event := range schedule.Events {
+		if event.Action == "" {
+			continue
+		}
+		for _, other := range events[event.DayPlan] {
+			if other.Event > event.Event && other.Hour == event.Hour && other.Minute == event.Minute {
+				issues = append(issues, ScheduleIssue{
+					Kind: IssueOverlappingEvents, DayPlan: event.DayPlan, Events: []int{event.Event, other.Event},
+					Detail: fmt.Sprintf("events %d and %d of day plan %d both run at %d:%02d", event.Event, other.Event, event.DayPlan, event.Hour, event.Minute),
+				})
+			}
+		}
+		oid := strings.TrimPrefix(event.Action, ".")
+		if !strings.HasPrefix(oid, actionPrefix) {
+			continue
+		}
+		index, _ := strconv.Atoi(strings.TrimPrefix(oid, actionPrefix))
+		if action, ok := actions[index]; !ok || action.Empty() {
+			issues = append(issues, ScheduleIssue{
+				Kind: IssueDanglingAction, DayPlan: event.DayPlan, Events: []int{event.Event}, Action: index,
+				Detail: fmt.Sprintf("event %d of day plan %d runs action %d, which activates no message", event.Event, event.DayPlan, index),
+			})
+		}
+	}
+
+	valid := map[MessageID]bool{}
+	for _, message := range messages {
+		if message.Status == d.Valid.Int() {
+			valid[MessageID{message.MessageMemoryType, message.MessageNumber, message.MessageCRC}] = true
+		}
+	}
+	for _, action := range schedule.Actions {
+		if action.Empty() {
+			continue
+		}
+		id, err := action.Message()
+		switch {
+		case err != nil:
+			issues = append(issues, ScheduleIssue{
+				Kind: IssueUndefinedMessage, Action: action.Index,
+				Detail: fmt.Sprintf("action %d: %v", action.Index, err),
+			})
+		case id.MessageMemoryType != d.MemoryBlank.Int() && !valid[id]:
+			issues = append(issues, ScheduleIssue{
+				Kind: IssueUndefinedMessage, Action: action.Index,
+				Detail: fmt.Sprintf("action %d activates message %d of memory type %d with CRC %#04x, which is not valid on the sign",
+					action.Index, id.MessageNumber, id.MessageMemoryType, id.MessageCRC),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,82 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+)
+
+func TestSchedule_Analyze(t *testing.T) {
+	message := d.Message{MemoryType: d.MemoryChangeable, Number: 1, MULTI: "[jp3]ROAD WORK"}
+	defined, _ := NewScheduleAction(1, message, 60, 100, "10.0.0.5")
+	blank, _ := NewScheduleAction(2, d.Message{MemoryType: d.MemoryBlank, Number: 1}, 65535, 255, "10.0.0.5")
+	changed := message
+	changed.MULTI = "[jp3]ROAD CLOSED"
+	stale, _ := NewScheduleAction(3, changed, 60, 100, "10.0.0.5")
+	messages := []MessageEntry{{
+		MessageMemoryType: message.MemoryType.Int(), MessageNumber: message.Number,
+		MultiString: message.MULTI, MessageCRC: message.CalculateCRC(), Status: d.Valid.Int(),
+	}}
+	weekdays := WeekdayMask(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     []ScheduleIssueKind
+	}{
+		{"clean", Schedule{
+			Entries: []ScheduleEntry{
+				{Number: 1, Months: AllMonths, Days: weekdays, Dates: AllDates, DayPlan: 1},
+				{Number: 2, Months: AllMonths, Days: WeekdayMask(time.Saturday, time.Sunday), Dates: AllDates, DayPlan: 2},
+				{Number: 3, DayPlan: 0},
+			},
+			Events: []DayPlanEvent{
+				{DayPlan: 1, Event: 1, Hour: 7, Action: ActionOID(1)},
+				{DayPlan: 1, Event: 2, Hour: 9, Action: ActionOID(2)},
+				{DayPlan: 2, Event: 1, Hour: 9, Action: ActionOID(2)},
+				{DayPlan: 2, Event: 2, Hour: 9},
+			},
+			Actions: []ScheduleAction{defined, blank},
+		}, nil},
+		{"overlapping entries", Schedule{
+			Entries: []ScheduleEntry{
+				{Number: 1, Months: AllMonths, Days: AllWeekdays, Dates: AllDates, DayPlan: 1},
+				{Number: 2, Months: MonthMask(time.December), Days: AllWeekdays, Dates: DateMask(25), DayPlan: 2},
+				{Number: 3, Months: MonthMask(time.December), Days: AllWeekdays, Dates: DateMask(25), DayPlan: 1},
+			},
+			Events:  []DayPlanEvent{{DayPlan: 1, Event: 1, Action: ActionOID(2)}, {DayPlan: 2, Event: 1, Action: ActionOID(2)}},
+			Actions: []ScheduleAction{blank},
+		}, []ScheduleIssueKind{IssueOverlappingEntries, IssueOverlappingEntries}},
+		{"never matches", Schedule{
+			Entries: []ScheduleEntry{{Number: 1, Months: AllMonths, Days: weekdays, DayPlan: 1}},
+		}, []ScheduleIssueKind{IssueEntryNeverMatches}},
+		{"empty day plan", Schedule{
+			Entries: []ScheduleEntry{{Number: 1, Months: AllMonths, Days: weekdays, Dates: AllDates, DayPlan: 3}},
+		}, []ScheduleIssueKind{IssueEmptyDayPlan}},
+		{"overlapping events and dangling action", Schedule{
+			Events: []DayPlanEvent{
+				{DayPlan: 1, Event: 1, Hour: 7, Minute: 30, Action: ActionOID(2)},
+				{DayPlan: 1, Event: 4, Hour: 7, Minute: 30, Action: ActionOID(5)},
+				{DayPlan: 1, Event: 5, Hour: 8, Action: "1.3.6.1.4.1.1206.4.2.99.1"},
+			},
+			Actions: []ScheduleAction{blank},
+		}, []ScheduleIssueKind{IssueOverlappingEvents, IssueDanglingAction}},
+		{"undefined messages", Schedule{
+			Actions: []ScheduleAction{stale, {Index: 4, Code: make([]byte, 12)}, {Index: 5, Code: []byte{1}}},
+		}, []ScheduleIssueKind{IssueUndefinedMessage, IssueUndefinedMessage}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []ScheduleIssueKind
+			issues := tt.schedule.Analyze(messages)
+			for _, issue := range issues {
+				got = append(got, issue.Kind)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %v, want %v", issues, tt.want)
+			}
+		})
+	}
+}
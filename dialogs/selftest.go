@@ -0,0 +1,101 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// SelfTestMultiString is the message defined by SelfTest.
+const SelfTestMultiString = "SELF TEST"
+
+// SelfTestStep is the outcome of one step of SelfTest.
+type SelfTestStep struct {
+	Name string
+	Err  error // nil if the step passed
+}
+
+// SelfTestReport lists the steps run by SelfTest, up to the first failure.
+type SelfTestReport struct {
+	Steps         []SelfTestStep
+	MessageNumber int // the scratch volatile message used, 0 if none was found
+}
+
+// Passed reports whether every step passed.
+func (r SelfTestReport) Passed() bool {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return false
+		}
+	}
+	return len(r.Steps) > 0
+}
+
+// SelfTest checks that the sign can be controlled centrally without changing
+// what it displays: it reads the sign configuration, defines and validates
+// SelfTestMultiString in an unused volatile message, and deletes that
+// message again. The error of the first failing step is returned as well.
+func SelfTest(dms *gosnmp.GoSNMP, options ...Option) (report SelfTestReport, err error) {
+	step := func(name string, err error) error {
+		report.Steps = append(report.Steps, SelfTestStep{Name: name, Err: err})
+		return err
+	}
+
+	s := newSession(dms, "SelfTest", options)
+	defer s.close()
+	var rows int
+	err = s.connect()
+	if err == nil {
+		rows, err = messageTableRows(s, d.MemoryVolatile.Int())
+	}
+	if err == nil {
+		_, err = s.getSingle("get dmsControlMode", d.DmsControlMode.Identifier(0))
+	}
+	if step("read configuration", err) != nil {
+		return report, err
+	}
+
+	// Use the last unused volatile message, which operators are least likely
+	// to be editing at the same time.
+	for messageNumber := rows; messageNumber >= 1 && report.MessageNumber == 0; messageNumber-- {
+		var entry MessageEntry
+		if entry, err = readMessageEntry(s, d.MemoryVolatile.Int(), messageNumber); err != nil {
+			break
+		}
+		if entry.Status == d.NotUsed.Int() {
+			report.MessageNumber = messageNumber
+		}
+	}
+	if err == nil && report.MessageNumber == 0 {
+		err = s.wrap(errors.New("no unused volatile message"), "find scratch message")
+	}
+	if step("find scratch message", err) != nil {
+		return report, err
+	}
+
+	owner, err := s.sourceAddress()
+	if err != nil {
+		err = s.wrap(err, "define and validate message")
+	} else {
+		_, err = DefiningMessage(dms, d.MemoryVolatile.Int(), report.MessageNumber, SelfTestMultiString, owner, 1, 0, 0, options...)
+	}
+	if step("define and validate message", err) != nil {
+		// Leave the slot as it was found.
+		s.set("set dmsMessageStatus to notUsedReq", notUsedReq(report.MessageNumber))
+		return report, err
+	}
+
+	_, err = s.set("set dmsMessageStatus to notUsedReq", notUsedReq(report.MessageNumber))
+	if step("delete message", err) != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func notUsedReq(messageNumber int) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{
+		Value: d.NotUsedReq.Int(),
+		Name:  d.DmsMessageStatus.Identifier(d.MemoryVolatile.Int(), messageNumber),
+		Type:  gosnmp.Integer,
+	}
+}
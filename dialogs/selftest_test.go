@@ -0,0 +1,42 @@
+package dialogs
+
+import (
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestSelfTest(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	if _, err := DefiningMessage(dms, d.MemoryVolatile.Int(), simulator.MaxVolatileMsg, "[jp3]IN USE", "10.0.0.5", 50, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := SelfTest(dms)
+	if err != nil || !report.Passed() {
+		t.Fatalf("SelfTest() = %+v, %v", report, err)
+	}
+	if len(report.Steps) != 4 || report.MessageNumber != simulator.MaxVolatileMsg-1 {
+		t.Errorf("SelfTest() = %+v, want 4 steps on message %d", report, simulator.MaxVolatileMsg-1)
+	}
+	entries, err := ListMessages(dms, d.MemoryVolatile.Int())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].MultiString != "[jp3]IN USE" {
+		t.Errorf("SelfTest() left the volatile messages %+v", entries)
+	}
+
+	sign.Delete(d.DmsControlMode.Identifier(0))
+	report, err = SelfTest(dms)
+	if err == nil || report.Passed() || len(report.Steps) != 1 {
+		t.Errorf("SelfTest() = %+v, %v, want the first step to fail", report, err)
+	}
+}
@@ -0,0 +1,432 @@
+package dialogs
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+)
+
+// DialogError wraps an error raised while running a dialog with the context
+// needed to locate it. Use errors.As to inspect it; the original error stays
+// reachable through Unwrap.
+type DialogError struct {
+	Dialog string   // name of the dialog, e.g. "ActivatingMessage"
+	Step   string   // step of the dialog that failed, e.g. "get shortErrorStatus"
+	OIDs   []string // objects involved in the failing step
+	Target string   // address of the sign, host:port
+	Err    error
+}
+
+func (e *DialogError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", e.Dialog, e.Step)
+	if len(e.OIDs) > 0 {
+		fmt.Fprintf(&b, " %v", e.OIDs)
+	}
+	if e.Target != "" {
+		fmt.Fprintf(&b, " on %s", e.Target)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *DialogError) Unwrap() error { return e.Err }
+
+// StepTiming is the time a dialog spent in one of its steps, e.g. a GET, a
+// SET or waiting for the controller's consistency check.
+type StepTiming struct {
+	Step     string
+	Duration time.Duration
+}
+
+// session carries the SNMP traffic of one dialog run and attaches the dialog
+// context to every error it returns.
+type session struct {
+	dms     *gosnmp.GoSNMP
+	dialog  string
+	options dialogOptions
+	timings []StepTiming // steps in the order they completed
+	span    Span         // the span of the dialog, see WithTracer
+	limiter *rateLimiter // nil without a rate limit
+
+	// done, total and retries are the progress of the dialog, see
+	// WithProgress.
+	done, total, retries int
+
+	// owner and known are the owner of the SETs and the values read or
+	// written so far, see WithAudit.
+	owner string
+	known map[string]interface{}
+
+	// saved are the settings of the gosnmp session before the options were
+	// applied, and version the version they set, see close.
+	saved   snmpSettings
+	version gosnmp.SnmpVersion
+}
+
+func newSession(dms *gosnmp.GoSNMP, dialog string, options []Option) *session {
+	o := newDialogOptions(options...)
+	saved := saveSettings(dms)
+	o.apply(dms)
+	if o.communities == nil {
+		o.communities = &communities{}
+	}
+	s := &session{dms: dms, dialog: dialog, options: o, limiter: o.limiter, saved: saved, version: dms.Version}
+	if s.limiter == nil {
+		s.limiter = newRateLimiter(o.rateLimit)
+	}
+	s.startDialogSpan()
+	return s
+}
+
+// close ends the dialog of s: the settings of the gosnmp session are
+// restored as they were before the options of the dialog were applied. A
+// version found by WithVersionFallback and the engine discovered for
+// WithSNMPv3 are kept for the dialogs that follow. The span of the dialog
+// ends, unless a Client ends it.
+func (s *session) close() {
+	version := s.dms.Version
+	s.saved.restore(s.dms)
+	if version != s.version {
+		s.dms.Version = version
+	}
+	if s.span != nil && s.options.spans == nil {
+		s.span.End()
+	}
+}
+
+// timed records the time spent in step since start.
+func (s *session) timed(step string, start time.Time) {
+	s.timings = append(s.timings, StepTiming{Step: step, Duration: time.Since(start)})
+}
+
+func (s *session) target() string {
+	if s.dms == nil {
+		return ""
+	}
+	return net.JoinHostPort(s.dms.Target, strconv.Itoa(int(s.dms.Port)))
+}
+
+// wrap returns err annotated with the dialog context, or nil if err is nil.
+func (s *session) wrap(err error, step string, oids ...string) error {
+	if err == nil {
+		return nil
+	}
+	return &DialogError{
+		Dialog: s.dialog,
+		Step:   step,
+		OIDs:   oids,
+		Target: s.target(),
+		Err:    err,
+	}
+}
+
+// sourceAddress returns the management station address to encode into a
+// message activation code.
+func (s *session) sourceAddress() (string, error) {
+	return sourceAddress(s.dms, s.options)
+}
+
+// sourceAddress returns the WithSourceAddress option or, by default, the
+// local IPv4 address used to reach the sign. If the sign is not connected
+// yet the address is looked up by routing a UDP socket to it, which sends
+// nothing. ErrSourceAddress is returned if there is no such address, rather
+// than an address that would misname the requester on the sign.
+func sourceAddress(dms *gosnmp.GoSNMP, o dialogOptions) (string, error) {
+	if o.sourceAddress != "" {
+		return o.sourceAddress, nil
+	}
+	var local net.Addr
+	if dms.Conn != nil {
+		local = dms.Conn.LocalAddr()
+	} else if conn, err := net.Dial("udp", net.JoinHostPort(dms.Target, strconv.Itoa(int(dms.Port)))); err == nil {
+		local = conn.LocalAddr()
+		conn.Close()
+	}
+	var ip net.IP
+	switch addr := local.(type) {
+	case *net.UDPAddr:
+		ip = addr.IP
+	case *net.TCPAddr:
+		ip = addr.IP
+	}
+	if ip4 := ip.To4(); ip4 != nil && !ip4.IsUnspecified() {
+		return ip4.String(), nil
+	}
+	return "", errors.Wrapf(ErrSourceAddress, "local address %v", local)
+}
+
+func (s *session) connect() error {
+	defer s.timed("connect", time.Now())
+	if s.options.err != nil {
+		return s.wrap(s.options.err, "connect")
+	}
+	if c := s.options.credentials; c != nil && s.dms.Version == gosnmp.Version3 {
+		if err := c.Validate(); err != nil {
+			return s.wrap(err, "connect")
+		}
+	}
+	if s.options.conn != nil && s.options.conn.reuse(s) {
+		return nil
+	}
+	if err := s.open(); err != nil {
+		if s.options.link != nil {
+			s.options.link(err)
+		}
+		return s.wrap(err, "connect")
+	}
+	if s.options.conn != nil {
+		s.options.conn.connected(s)
+	}
+	return nil
+}
+
+// open opens the connection of the gosnmp session.
+func (s *session) open() error {
+	if s.options.pmpp != nil {
+		return s.connectPMPP()
+	}
+	if err := s.dms.Connect(); err != nil || s.options.control == nil {
+		return err
+	}
+	return s.redial()
+}
+
+// connectPMPP lets gosnmp set up the session for UDP, then replaces the
+// connection with the PMPP connection, which gosnmp reads like a datagram
+// socket.
+func (s *session) connectPMPP() error {
+	s.dms.Transport = "udp"
+	if err := s.dms.Connect(); err != nil {
+		return err
+	}
+	s.dms.Conn.Close()
+	s.dms.Conn = s.options.pmpp
+	return nil
+}
+
+// redial replaces the connection opened by gosnmp with one dialed through
+// the WithSocketControl function, which gosnmp has no hook for.
+func (s *session) redial() error {
+	s.dms.Conn.Close()
+	transport := s.dms.Transport
+	dialer := net.Dialer{Timeout: s.dms.Timeout, Control: s.options.control}
+	if s.dms.LocalAddr != "" {
+		var err error
+		if strings.HasPrefix(transport, "tcp") {
+			dialer.LocalAddr, err = net.ResolveTCPAddr(transport, s.dms.LocalAddr)
+		} else {
+			dialer.LocalAddr, err = net.ResolveUDPAddr(transport, s.dms.LocalAddr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	conn, err := dialer.Dial(transport, s.target())
+	if err != nil {
+		return err
+	}
+	s.dms.Conn = conn
+	return nil
+}
+
+// get GETs oids. An error status in the response is returned as an
+// *SNMPError naming the offending object.
+func (s *session) get(step string, oids ...string) (*gosnmp.SnmpPacket, error) {
+	defer s.timed(step, time.Now())
+	result, err := s.exchange(request{operation: "get", step: step, oids: oids})
+	return result, s.wrap(err, step, oids...)
+}
+
+// getSingle GETs a single object. An object the sign does not support is
+// reported as ErrNoSuchName for both SNMPv1 and SNMPv2c responses.
+func (s *session) getSingle(step string, oid string) (gosnmp.SnmpPDU, error) {
+	result, err := s.get(step, oid)
+	if err != nil {
+		return gosnmp.SnmpPDU{}, err
+	}
+	if len(result.Variables) == 0 || !oidEqual(result.Variables[0].Name, oid) {
+		return gosnmp.SnmpPDU{}, s.wrap(&SNMPError{Status: gosnmp.NoSuchName, OID: oid}, step, oid)
+	}
+	switch variable := result.Variables[0]; variable.Type {
+	case gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return gosnmp.SnmpPDU{}, s.wrap(&SNMPError{Status: gosnmp.NoSuchName, OID: oid}, step, oid)
+	default:
+		return variable, nil
+	}
+}
+
+// set SETs pdus. An error status in the response is returned as an
+// *SNMPError naming the offending object.
+func (s *session) set(step string, pdus ...gosnmp.SnmpPDU) (*gosnmp.SnmpPacket, error) {
+	defer s.timed(step, time.Now())
+	oids := pduNames(pdus)
+	if s.options.readOnly || buildReadOnly {
+		return nil, s.wrap(ErrReadOnlyClient, step, oids...)
+	}
+	if s.options.dryRun {
+		return nil, s.wrap(ErrDryRun, step, oids...)
+	}
+	result, err := s.exchange(request{operation: "set", step: step, oids: oids, pdus: pdus})
+	return result, s.wrap(err, step, oids...)
+}
+
+// request is an SNMP request of a step of a dialog.
+type request struct {
+	operation string // "get", "getnext", "getbulk" or "set"
+	step      string
+	oids      []string
+	pdus      []gosnmp.SnmpPDU // the values of a SET
+
+	maxRepetitions int // the rows of a GETBULK
+}
+
+// exchange passes r through the interceptors and sends it, repeating it as
+// the retry policy says; each attempt is traced and logged. An error status
+// in the response is returned as an *SNMPError naming the offending object.
+func (s *session) exchange(r request) (*gosnmp.SnmpPacket, error) {
+	op, err := s.intercept(r)
+	if err != nil {
+		return nil, err
+	}
+	r = op.request()
+
+	var result *gosnmp.SnmpPacket
+	var sendErr error
+	var start time.Time
+	for attempt := 1; ; attempt++ {
+		s.limiter.wait()
+		start = time.Now()
+		span := s.startSpan("snmp."+r.operation, r.step, r.oids)
+		result, sendErr = s.send(r.operation == "set", func() (*gosnmp.SnmpPacket, error) {
+			switch r.operation {
+			case "set":
+				return s.dms.Set(r.pdus)
+			case "getnext":
+				return s.dms.GetNext(r.oids)
+			case "getbulk":
+				return s.dms.GetBulk(r.oids, 0, uint32(r.maxRepetitions))
+			}
+			return s.dms.Get(r.oids)
+		})
+		if err = sendErr; err == nil {
+			err = responseError(result, r.oids)
+		}
+		endSpan(span, result, err)
+		s.log(r, result, err, start)
+		if !s.options.retryPolicy.retry(op, attempt, err) {
+			break
+		}
+		time.Sleep(s.options.retryPolicy.backoff(attempt))
+		s.retried(r.step)
+	}
+	if s.options.link != nil {
+		s.options.link(sendErr)
+	}
+	if r.operation == "set" {
+		s.audit(r, err, start)
+	} else if err == nil {
+		s.remember(result.Variables)
+	}
+	return result, s.interceptResult(op, result, err)
+}
+
+// communities are the communities found to work for GETs and SETs by
+// WithCommunityFallback.
+type communities struct {
+	read, write string
+}
+
+// send performs a GET or, if write, a SET request with the community for it:
+// the one found by WithCommunityFallback, the one of WithCommunities or the
+// one of the gosnmp session, in that order. With WithCommunityFallback, a
+// request that fails authentication is repeated with the fallback
+// communities. The gosnmp session keeps its own community.
+func (s *session) send(write bool, request func() (*gosnmp.SnmpPacket, error)) (*gosnmp.SnmpPacket, error) {
+	if s.dms.Version == gosnmp.Version3 {
+		return s.sendVersion(request)
+	}
+	found, override := &s.options.communities.read, s.options.readCommunity
+	if write {
+		found, override = &s.options.communities.write, s.options.writeCommunity
+	}
+	candidates := []string{s.dms.Community}
+	if *found != "" {
+		candidates[0] = *found
+	} else if override != "" {
+		candidates[0] = override
+	}
+	for _, community := range s.options.communityFallback {
+		if community != candidates[0] {
+			candidates = append(candidates, community)
+		}
+	}
+
+	base := s.dms.Community
+	defer func() { s.dms.Community = base }()
+	var result *gosnmp.SnmpPacket
+	var err error
+	for i, community := range candidates {
+		s.dms.Community = community
+		result, err = s.sendVersion(request)
+		if !authenticationFailed(result, err) {
+			if i > 0 {
+				*found = community
+			}
+			break
+		}
+	}
+	return result, err
+}
+
+// authenticationFailed reports whether a SNMPv1 or SNMPv2c request may have
+// been refused for its community: it got no answer, or an authorizationError
+// or noAccess error status.
+func authenticationFailed(result *gosnmp.SnmpPacket, err error) bool {
+	if err != nil {
+		return true
+	}
+	return result.Error == gosnmp.AuthorizationError || result.Error == gosnmp.NoAccess
+}
+
+// sendVersion performs request. With WithVersionFallback, a SNMPv2c request
+// that gets no answer is repeated as SNMPv1; the session, and the Client if
+// any, keep SNMPv1 if that succeeds.
+func (s *session) sendVersion(request func() (*gosnmp.SnmpPacket, error)) (*gosnmp.SnmpPacket, error) {
+	result, err := request()
+	if err == nil || !s.options.versionFallback || s.dms.Version != gosnmp.Version2c {
+		return result, err
+	}
+	s.dms.Version = gosnmp.Version1
+	if result, err = request(); err != nil {
+		s.dms.Version = gosnmp.Version2c
+	} else if s.options.foundVersion != nil {
+		*s.options.foundVersion = int(gosnmp.Version1)
+	}
+	return result, err
+}
+
+// responseError converts the error status of a response into an *SNMPError.
+func responseError(result *gosnmp.SnmpPacket, oids []string) error {
+	if result == nil || result.Error == gosnmp.NoError {
+		return nil
+	}
+	snmpError := &SNMPError{Status: result.Error}
+	if index := int(result.ErrorIndex); index > 0 && index <= len(oids) {
+		snmpError.OID = oids[index-1]
+	}
+	return snmpError
+}
+
+func pduNames(pdus []gosnmp.SnmpPDU) []string {
+	names := make([]string, 0, len(pdus))
+	for _, pdu := range pdus {
+		names = append(names, pdu.Name)
+	}
+	return names
+}
@@ -0,0 +1,129 @@
+package dialogs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestDialogError(t *testing.T) {
+	s := &session{
+		dms:    &gosnmp.GoSNMP{Target: "10.0.11.41", Port: 161},
+		dialog: "ActivatingMessage",
+	}
+	cause := &ActivationError{Status: gosnmp.GenErr, ActivateMsgError: "messageCRC"}
+	err := s.wrap(cause, "set dmsActivateMessage", "1.3.6.1.4.1.1206.4.2.3.6.3.0")
+
+	want := "ActivatingMessage: set dmsActivateMessage [1.3.6.1.4.1.1206.4.2.3.6.3.0] on 10.0.11.41:161: " +
+		"message activation failed (GenErr): messageCRC"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	var dialogError *DialogError
+	if !errors.As(err, &dialogError) || dialogError.Step != "set dmsActivateMessage" {
+		t.Errorf("errors.As(*DialogError) failed on %v", err)
+	}
+	var activationError *ActivationError
+	if !errors.As(err, &activationError) || activationError.ActivateMsgError != "messageCRC" {
+		t.Errorf("errors.As(*ActivationError) failed on %v", err)
+	}
+
+	if s.wrap(nil, "connect") != nil {
+		t.Errorf("wrap(nil) should return nil")
+	}
+}
+
+func Test_sourceAddress(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+
+	if got, err := sourceAddress(dms, newDialogOptions(WithSourceAddress("10.0.0.5"))); got != "10.0.0.5" || err != nil {
+		t.Errorf("sourceAddress() = %q, %v, want the WithSourceAddress option", got, err)
+	}
+	if got, err := sourceAddress(dms, newDialogOptions()); got != "127.0.0.1" || err != nil {
+		t.Errorf("sourceAddress() before connecting = %q, %v, want 127.0.0.1", got, err)
+	}
+	if err := dms.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dms.Conn.Close()
+	if got, err := sourceAddress(dms, newDialogOptions()); got != "127.0.0.1" || err != nil {
+		t.Errorf("sourceAddress() = %q, %v, want 127.0.0.1", got, err)
+	}
+
+	// An IPv6 local address is not made up into an IPv4 one.
+	ipv6 := &gosnmp.GoSNMP{Target: "::1", Port: dms.Port}
+	if got, err := sourceAddress(ipv6, newDialogOptions()); !errors.Is(err, ErrSourceAddress) {
+		t.Errorf("sourceAddress() over IPv6 = %q, %v, want ErrSourceAddress", got, err)
+	}
+}
+
+func TestSession_close(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	want := saveSettings(dms)
+
+	// The options of a dialog are not left on the session of the caller.
+	if _, err := GetControlMode(dms, WithRetries(7), WithVersion(gosnmp.Version1), WithLocalAddr("127.0.0.1:0")); err != nil {
+		t.Fatal(err)
+	}
+	if got := saveSettings(dms); got != want {
+		t.Errorf("settings after a dialog = %+v, want %+v", got, want)
+	}
+
+	// A version found by WithVersionFallback is kept.
+	s := newSession(dms, "test", []Option{WithRetries(7)})
+	dms.Version = gosnmp.Version1
+	s.close()
+	if dms.Version != gosnmp.Version1 || dms.Retries != want.retries {
+		t.Errorf("close() after a version fallback left version %v and %d retries, want 1 and %d", dms.Version, dms.Retries, want.retries)
+	}
+}
+
+func TestClient_versionFallback(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.SetVersions(gosnmp.Version1)
+
+	dms := sign.GoSNMP()
+	dms.Timeout = 100 * time.Millisecond
+	var sent []gosnmp.SnmpVersion
+	dms.OnSent = func(dms *gosnmp.GoSNMP) { sent = append(sent, dms.Version) }
+	client := NewClient(dms, WithVersion(gosnmp.Version2c), WithVersionFallback())
+	if _, err := client.GetControlMode(); err != nil {
+		t.Fatalf("GetControlMode() error = %v", err)
+	}
+	if len(sent) != 2 || sent[0] != gosnmp.Version2c || sent[1] != gosnmp.Version1 {
+		t.Errorf("first dialog sent versions %v, want 2c then 1", sent)
+	}
+
+	// The version found is kept over WithVersion.
+	sent = nil
+	if _, err := client.GetControlMode(); err != nil {
+		t.Fatalf("GetControlMode() error = %v", err)
+	}
+	for _, version := range sent {
+		if version != gosnmp.Version1 {
+			t.Errorf("second dialog sent versions %v, want 1 only", sent)
+			break
+		}
+	}
+	if got := client.Version(); got != gosnmp.Version1 {
+		t.Errorf("Version() = %v, want 1", got)
+	}
+}
@@ -0,0 +1,49 @@
+package dialogs
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Shutdown stops the client: dialogs started afterwards fail with
+// ErrClientClosed, a pending dead-man blank is cancelled and, once the
+// dialog in flight has finished, the SNMP connection is closed. If ctx ends
+// first, ctx.Err() is returned and the connection is closed in the
+// background when the dialog in flight returns. The activation history stays
+// readable.
+func (c *Client) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&c.closed, 1)
+	c.disarmDeadMan()
+
+	idle := make(chan struct{})
+	go func() {
+		c.mu.Lock()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+		defer c.mu.Unlock()
+		return c.closeConn()
+	case <-ctx.Done():
+		go func() {
+			<-idle
+			c.closeConn()
+			c.mu.Unlock()
+		}()
+		return ctx.Err()
+	}
+}
+
+// closeConn closes the SNMP connection of the client, unless it was given by
+// WithPMPP and may carry the dialogs of other signs on the line. It is
+// called with mu held.
+func (c *Client) closeConn() error {
+	if c.conn.idle != nil {
+		c.conn.idle.Stop()
+	}
+	c.conn.key = ""
+	if !c.conn.owned || c.dms == nil || c.dms.Conn == nil {
+		return nil
+	}
+	return c.dms.Conn.Close()
+}
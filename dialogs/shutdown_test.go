@@ -0,0 +1,47 @@
+package dialogs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_Shutdown(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	c := NewClient(sign.GoSNMP(), WithDeadMan(time.Hour))
+
+	if _, err := c.DisplayText(1, "[jp3]TEST", 50, 65535); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if c.deadMan != nil {
+		t.Errorf("Shutdown() left the dead-man timer armed")
+	}
+	if _, err := c.RetrievingCurrentMessage(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("RetrievingCurrentMessage() error = %v, want ErrClientClosed", err)
+	}
+	if len(c.ActivationHistory()) != 1 {
+		t.Errorf("ActivationHistory() lost its records")
+	}
+}
+
+func TestClient_Shutdown_timeout(t *testing.T) {
+	c := NewClient(nil)
+	c.mu.Lock() // a dialog in flight
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want context.DeadlineExceeded", err)
+	}
+	c.mu.Unlock()
+}
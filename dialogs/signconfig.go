@@ -0,0 +1,86 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// SignConfiguration holds the dmsSignCfg objects of a sign. Enumerations are
+// given with their names and bitmaps with the names of the bits set.
+type SignConfiguration struct {
+	Access           int      // dmsSignAccess bitmap
+	AccessNames      []string // e.g. ["walkIn", "front"]
+	Type             int      // dmsSignType
+	TypeName         string   // e.g. "vmsFull" or "portableVMSFull"
+	Height           int      // dmsSignHeight in millimeters, including the border
+	Width            int      // dmsSignWidth in millimeters, including the border
+	HorizontalBorder int      // dmsHorizontalBorder in millimeters
+	VerticalBorder   int      // dmsVerticalBorder in millimeters
+	Legend           int      // dmsLegend
+	LegendName       string   // e.g. "noLegend"
+	BeaconType       int      // dmsBeaconType
+	BeaconTypeName   string   // e.g. "twoBeaconSyncFlash"
+	Technology       int      // dmsSignTechnology bitmap
+	TechnologyNames  []string // e.g. ["led"]
+}
+
+// Portable reports whether the sign is a portable sign.
+func (c SignConfiguration) Portable() bool { return c.Type >= d.SignTypePortableOther.Int() }
+
+// GetSignConfiguration reads the sign configuration and capability objects
+// (dmsSignCfg) of the sign.
+func GetSignConfiguration(dms *gosnmp.GoSNMP, options ...Option) (config SignConfiguration, err error) {
+	s := newSession(dms, "GetSignConfiguration", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return config, err
+	}
+
+	oids := make([]string, 0, len(d.SignConfigurationAndCapabilityObjects))
+	for _, object := range d.SignConfigurationAndCapabilityObjects {
+		oids = append(oids, object.Identifier(0))
+	}
+	getResults, err := s.get("get sign configuration", oids...)
+	if err != nil {
+		return config, err
+	}
+	for _, variable := range getResults.Variables {
+		value, _ := variable.Value.(int)
+		var name *string
+		var names *[]string
+		var object d.Reader
+		switch {
+		case oidEqual(variable.Name, d.DmsSignAccess.Identifier(0)):
+			config.Access, names, object = value, &config.AccessNames, d.DmsSignAccess
+		case oidEqual(variable.Name, d.DmsSignType.Identifier(0)):
+			config.Type, name, object = value, &config.TypeName, d.DmsSignType
+		case oidEqual(variable.Name, d.DmsSignHeight.Identifier(0)):
+			config.Height = value
+		case oidEqual(variable.Name, d.DmsSignWidth.Identifier(0)):
+			config.Width = value
+		case oidEqual(variable.Name, d.DmsHorizontalBorder.Identifier(0)):
+			config.HorizontalBorder = value
+		case oidEqual(variable.Name, d.DmsVerticalBorder.Identifier(0)):
+			config.VerticalBorder = value
+		case oidEqual(variable.Name, d.DmsLegend.Identifier(0)):
+			config.Legend, name, object = value, &config.LegendName, d.DmsLegend
+		case oidEqual(variable.Name, d.DmsBeaconType.Identifier(0)):
+			config.BeaconType, name, object = value, &config.BeaconTypeName, d.DmsBeaconType
+		case oidEqual(variable.Name, d.DmsSignTechnology.Identifier(0)):
+			config.Technology, names, object = value, &config.TechnologyNames, d.DmsSignTechnology
+		}
+		if object == nil {
+			continue
+		}
+		formatted, err := d.Format(object, variable.Value)
+		if err != nil {
+			return config, s.wrap(err, "format "+object.ObjectType(), variable.Name)
+		}
+		if name != nil {
+			*name = formatted.(string)
+		} else {
+			*names = formatted.([]string)
+		}
+	}
+	return config, nil
+}
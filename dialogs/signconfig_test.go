@@ -0,0 +1,47 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetSignConfiguration(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	sign.Store(d.DmsSignAccess.Identifier(0), gosnmp.Integer, d.SignAccessWalkIn.Int()|d.SignAccessFront.Int())
+	sign.Store(d.DmsSignType.Identifier(0), gosnmp.Integer, d.SignTypePortableVMSFull.Int())
+
+	config, err := GetSignConfiguration(sign.GoSNMP())
+	if err != nil {
+		t.Fatalf("GetSignConfiguration() error = %v", err)
+	}
+	want := SignConfiguration{
+		Access:           10,
+		AccessNames:      []string{"walkIn", "front"},
+		Type:             134,
+		TypeName:         "portableVMSFull",
+		Height:           1800,
+		Width:            5000,
+		HorizontalBorder: 100,
+		VerticalBorder:   100,
+		Legend:           2,
+		LegendName:       "noLegend",
+		BeaconType:       2,
+		BeaconTypeName:   "none",
+		Technology:       2,
+		TechnologyNames:  []string{"led"},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("GetSignConfiguration() = %+v, want %+v", config, want)
+	}
+	if !config.Portable() {
+		t.Errorf("Portable() = false, want true")
+	}
+}
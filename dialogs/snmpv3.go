@@ -0,0 +1,210 @@
+package dialogs
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+)
+
+// Credentials are the SNMPv3 User-based Security Model (USM) credentials of
+// a sign.
+type Credentials struct {
+	UserName       string
+	AuthProtocol   gosnmp.SnmpV3AuthProtocol // e.g. gosnmp.SHA256; 0 or gosnmp.NoAuth for noAuthNoPriv
+	AuthPassphrase string
+	PrivProtocol   gosnmp.SnmpV3PrivProtocol // e.g. gosnmp.AES; 0 or gosnmp.NoPriv for no privacy
+	PrivPassphrase string
+	ContextName    string
+
+	// EngineID is the authoritative snmpEngineID of the sign. If empty, it
+	// is discovered with a first request when the dialog connects, and kept
+	// on the gosnmp session for the dialogs that follow.
+	EngineID string
+}
+
+// auth and priv report whether the credentials authenticate and encrypt the
+// requests.
+func (c Credentials) auth() bool { return c.AuthProtocol > gosnmp.NoAuth }
+func (c Credentials) priv() bool { return c.PrivProtocol > gosnmp.NoPriv }
+
+// Validate checks that the credentials are usable: a user name, a privacy
+// protocol only with an authentication protocol, and passphrases of at least
+// 8 characters, which USM requires to derive the keys.
+func (c Credentials) Validate() error {
+	switch {
+	case c.UserName == "":
+		return errors.New("SNMPv3 user name is empty")
+	case c.priv() && !c.auth():
+		return errors.Errorf("SNMPv3 user %s: privacy requires authentication", c.UserName)
+	case c.auth() && len(c.AuthPassphrase) < 8:
+		return errors.Errorf("SNMPv3 user %s: authentication passphrase shorter than 8 characters", c.UserName)
+	case c.priv() && len(c.PrivPassphrase) < 8:
+		return errors.Errorf("SNMPv3 user %s: privacy passphrase shorter than 8 characters", c.UserName)
+	}
+	return nil
+}
+
+// msgFlags returns the security level of the credentials.
+func (c Credentials) msgFlags() gosnmp.SnmpV3MsgFlags {
+	switch {
+	case c.priv():
+		return gosnmp.AuthPriv | gosnmp.Reportable
+	case c.auth():
+		return gosnmp.AuthNoPriv | gosnmp.Reportable
+	default:
+		return gosnmp.NoAuthNoPriv | gosnmp.Reportable
+	}
+}
+
+// apply configures dms for SNMPv3 with the credentials. The security
+// parameters of dms are kept if they are for the same credentials, so that the
+// discovered engine ID, boots and time are not lost between dialogs.
+func (c Credentials) apply(dms *gosnmp.GoSNMP) {
+	authProtocol, privProtocol := c.AuthProtocol, c.PrivProtocol
+	if !c.auth() {
+		authProtocol = gosnmp.NoAuth
+	}
+	if !c.priv() {
+		privProtocol = gosnmp.NoPriv
+	}
+	dms.Version = gosnmp.Version3
+	dms.SecurityModel = gosnmp.UserSecurityModel
+	dms.MsgFlags = c.msgFlags()
+	dms.ContextName = c.ContextName
+	if current, ok := dms.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok &&
+		current.UserName == c.UserName &&
+		current.AuthenticationProtocol == authProtocol && current.AuthenticationPassphrase == c.AuthPassphrase &&
+		current.PrivacyProtocol == privProtocol && current.PrivacyPassphrase == c.PrivPassphrase &&
+		(c.EngineID == "" || current.AuthoritativeEngineID == c.EngineID) {
+		return
+	}
+	dms.SecurityParameters = &gosnmp.UsmSecurityParameters{
+		UserName:                 c.UserName,
+		AuthenticationProtocol:   authProtocol,
+		AuthenticationPassphrase: c.AuthPassphrase,
+		PrivacyProtocol:          privProtocol,
+		PrivacyPassphrase:        c.PrivPassphrase,
+		AuthoritativeEngineID:    c.EngineID,
+	}
+}
+
+// WithSNMPv3 makes the dialogs talk SNMPv3 with the given USM credentials
+// instead of using a community string. The dialogs themselves run the same
+// way for every version. Pass it to NewClient to use it for a sign; invalid
+// credentials make every dialog fail to connect.
+func WithSNMPv3(credentials Credentials) Option {
+	return func(o *dialogOptions) {
+		o.version = int(gosnmp.Version3)
+		o.credentials = &credentials
+	}
+}
+
+// EngineID returns the snmpEngineID of the sign, as configured or discovered
+// by the last SNMPv3 dialog, or "" if the client does not use SNMPv3 or has
+// not talked to the sign yet.
+func (c *Client) EngineID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if usm, ok := c.dms.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok {
+		return usm.AuthoritativeEngineID
+	}
+	return ""
+}
+
+// CredentialStore holds the SNMPv3 credentials of several signs, keyed by
+// the host name or IP address of the sign.
+type CredentialStore map[string]Credentials
+
+// For returns the credentials of the sign reached through dms, if any.
+func (store CredentialStore) For(dms *gosnmp.GoSNMP) (Credentials, bool) {
+	credentials, ok := store[dms.Target]
+	return credentials, ok
+}
+
+// Option returns WithSNMPv3 with the credentials of the sign reached through
+// dms, for NewClient. Signs without credentials get no option and keep the
+// version configured on dms.
+func (store CredentialStore) Option(dms *gosnmp.GoSNMP) []Option {
+	if credentials, ok := store.For(dms); ok {
+		return []Option{WithSNMPv3(credentials)}
+	}
+	return nil
+}
+
+// authProtocols and privProtocols are the protocol names accepted by
+// ReadCredentials.
+var (
+	authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+		"": gosnmp.NoAuth, "none": gosnmp.NoAuth,
+		"md5": gosnmp.MD5, "sha": gosnmp.SHA,
+		"sha224": gosnmp.SHA224, "sha256": gosnmp.SHA256, "sha384": gosnmp.SHA384, "sha512": gosnmp.SHA512,
+	}
+	privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+		"": gosnmp.NoPriv, "none": gosnmp.NoPriv,
+		"des": gosnmp.DES, "aes": gosnmp.AES,
+		"aes192": gosnmp.AES192, "aes256": gosnmp.AES256, "aes192c": gosnmp.AES192C, "aes256c": gosnmp.AES256C,
+	}
+)
+
+// ReadCredentials reads a CredentialStore from JSON, an object mapping the
+// host of each sign to its credentials:
+//
+//	{
+//	  "10.0.11.41": {
+//	    "user": "dms-central",
+//	    "auth": "sha256",
+//	    "authPassphrase": "...",
+//	    "priv": "aes",
+//	    "privPassphrase": "...",
+//	    "context": "",
+//	    "engineID": ""
+//	  }
+//	}
+//
+// The protocol names are those of gosnmp in lower case; "none" or an empty
+// name disables authentication or privacy. Every entry is validated.
+func ReadCredentials(r io.Reader) (CredentialStore, error) {
+	var entries map[string]struct {
+		User           string `json:"user"`
+		Auth           string `json:"auth"`
+		AuthPassphrase string `json:"authPassphrase"`
+		Priv           string `json:"priv"`
+		PrivPassphrase string `json:"privPassphrase"`
+		Context        string `json:"context"`
+		EngineID       string `json:"engineID"`
+	}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, errors.Wrap(err, "decode credentials")
+	}
+	store := CredentialStore{}
+	for host, entry := range entries {
+		authProtocol, ok := authProtocols[strings.ToLower(entry.Auth)]
+		if !ok {
+			return nil, errors.Errorf("credentials of %s: unknown authentication protocol %q", host, entry.Auth)
+		}
+		privProtocol, ok := privProtocols[strings.ToLower(entry.Priv)]
+		if !ok {
+			return nil, errors.Errorf("credentials of %s: unknown privacy protocol %q", host, entry.Priv)
+		}
+		credentials := Credentials{
+			UserName:       entry.User,
+			AuthProtocol:   authProtocol,
+			AuthPassphrase: entry.AuthPassphrase,
+			PrivProtocol:   privProtocol,
+			PrivPassphrase: entry.PrivPassphrase,
+			ContextName:    entry.Context,
+			EngineID:       entry.EngineID,
+		}
+		if err := credentials.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "credentials of %s", host)
+		}
+		if host == "" {
+			return nil, errors.New("credentials for an empty host")
+		}
+		store[host] = credentials
+	}
+	return store, nil
+}
@@ -0,0 +1,78 @@
+package dialogs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestWithSNMPv3(t *testing.T) {
+	credentials := Credentials{
+		UserName:       "dms-central",
+		AuthProtocol:   gosnmp.SHA256,
+		AuthPassphrase: "authpassphrase",
+		PrivProtocol:   gosnmp.AES,
+		PrivPassphrase: "privpassphrase",
+	}
+	dms := &gosnmp.GoSNMP{Target: "10.0.11.41", Version: gosnmp.Version2c}
+	newDialogOptions(WithSNMPv3(credentials)).apply(dms)
+	usm, ok := dms.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if dms.Version != gosnmp.Version3 || dms.SecurityModel != gosnmp.UserSecurityModel ||
+		dms.MsgFlags != gosnmp.AuthPriv|gosnmp.Reportable || !ok || usm.UserName != "dms-central" {
+		t.Fatalf("WithSNMPv3() configured %+v", dms)
+	}
+
+	// The discovered engine is kept by the next dialog.
+	usm.AuthoritativeEngineID = "engine"
+	newDialogOptions(WithSNMPv3(credentials)).apply(dms)
+	if dms.SecurityParameters != usm {
+		t.Errorf("WithSNMPv3() replaced the security parameters of the same credentials")
+	}
+	client := NewClient(dms, WithSNMPv3(credentials))
+	if got := client.EngineID(); got != "engine" {
+		t.Errorf("Client.EngineID() = %q, want engine", got)
+	}
+
+	credentials.AuthPassphrase = "short"
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	if _, err := GetControlMode(sign.GoSNMP(), WithSNMPv3(credentials)); err == nil || !strings.Contains(err.Error(), "passphrase") {
+		t.Errorf("GetControlMode() with invalid credentials error = %v", err)
+	}
+}
+
+func TestReadCredentials(t *testing.T) {
+	store, err := ReadCredentials(strings.NewReader(`{
+		"10.0.11.41": {"user": "dms-central", "auth": "SHA256", "authPassphrase": "authpassphrase", "priv": "aes", "privPassphrase": "privpassphrase"},
+		"10.0.11.42": {"user": "monitor"}
+	}`))
+	if err != nil {
+		t.Fatalf("ReadCredentials() error = %v", err)
+	}
+	credentials, ok := store.For(&gosnmp.GoSNMP{Target: "10.0.11.41"})
+	if !ok || credentials.AuthProtocol != gosnmp.SHA256 || credentials.PrivProtocol != gosnmp.AES {
+		t.Errorf("CredentialStore.For() = %+v, %v", credentials, ok)
+	}
+	if options := store.Option(&gosnmp.GoSNMP{Target: "10.0.11.43"}); options != nil {
+		t.Errorf("CredentialStore.Option() for a sign without credentials = %v, want none", options)
+	}
+	if got := newDialogOptions(store.Option(&gosnmp.GoSNMP{Target: "10.0.11.42"})...); got.credentials == nil || got.credentials.msgFlags() != gosnmp.NoAuthNoPriv|gosnmp.Reportable {
+		t.Errorf("CredentialStore.Option() = %+v, want noAuthNoPriv SNMPv3", got)
+	}
+
+	for _, input := range []string{
+		`{"10.0.11.41": {"user": "dms-central", "auth": "sha1"}}`,
+		`{"10.0.11.41": {"user": "dms-central", "priv": "aes", "privPassphrase": "privpassphrase"}}`,
+		`{"10.0.11.41": {"auth": "sha", "authPassphrase": "authpassphrase"}}`,
+		`[]`,
+	} {
+		if _, err := ReadCredentials(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadCredentials(%s) expected an error", input)
+		}
+	}
+}
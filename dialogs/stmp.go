@@ -0,0 +1,204 @@
+package dialogs
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/stmp"
+	"github.com/pkg/errors"
+)
+
+// nullOID is the value of a dynObjVariable that ends the variables of a
+// dynamic object.
+const nullOID = "0.0"
+
+func dynObjVariableName(number, index int) string {
+	return fmt.Sprintf("%s.%d", d.DynObjVariable.Identifier(number), index)
+}
+
+// DefineDynamicObject configures a dynamic object on the sign for STMP with
+// the dynObjMgmt objects: it sets dynObjConfigStatus to underCreation with
+// the owner, sets the object identifiers of the variables, clears the ones
+// left from the previous definition, sets dynObjConfigStatus to valid and
+// reads the variables back. The encodings of the variables are not sent to
+// the sign, which knows them from its MIB; they are kept in object for
+// PollDynamicObject.
+func DefineDynamicObject(dms *gosnmp.GoSNMP, object stmp.Object, options ...Option) error {
+	s := newSession(dms, "DefineDynamicObject", options)
+	defer s.close()
+	if err := object.Validate(); err != nil {
+		return s.wrap(err, "validate dynamic object")
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+
+	current, err := getDynamicObject(s, object.Number)
+	if err != nil {
+		return err
+	}
+	owner := gosnmp.SnmpPDU{Name: d.DynObjConfigOwner.Identifier(object.Number), Type: d.DynObjConfigOwner.Syntax(), Value: object.Owner}
+	statusName := d.DynObjConfigStatus.Identifier(object.Number)
+	if _, err := s.set("set dynObjConfigStatus underCreation",
+		owner,
+		gosnmp.SnmpPDU{Name: statusName, Type: d.DynObjConfigStatus.Syntax(), Value: d.EntryUnderCreation.Int()},
+	); err != nil {
+		return err
+	}
+
+	var pdus []gosnmp.SnmpPDU
+	for index := 1; index <= len(object.Variables) || index <= len(current); index++ {
+		value := nullOID
+		if index <= len(object.Variables) {
+			value = object.Variables[index-1].OID
+		}
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: dynObjVariableName(object.Number, index), Type: d.DynObjVariable.Syntax(), Value: value})
+	}
+	// gosnmp refuses a SET whose first varbind is an OBJECT IDENTIFIER, so
+	// every request starts with dynObjConfigOwner again.
+	perRequest := len(pdus)
+	if dms.MaxOids > 1 && dms.MaxOids-1 < perRequest {
+		perRequest = dms.MaxOids - 1
+	}
+	for first := 0; first < len(pdus); first += perRequest {
+		last := first + perRequest
+		if last > len(pdus) {
+			last = len(pdus)
+		}
+		request := append([]gosnmp.SnmpPDU{owner}, pdus[first:last]...)
+		if _, err := s.set(fmt.Sprintf("set dynObjVariable %d-%d", first+1, last), request...); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.set("set dynObjConfigStatus valid",
+		gosnmp.SnmpPDU{Name: statusName, Type: d.DynObjConfigStatus.Syntax(), Value: d.EntryValid.Int()},
+	); err != nil {
+		return err
+	}
+	defined, err := getDynamicObject(s, object.Number)
+	if err != nil {
+		return err
+	}
+	want := make([]string, len(object.Variables))
+	for i, variable := range object.Variables {
+		want[i] = variable.OID
+	}
+	if !oidsEqual(defined, want) {
+		return s.wrap(errors.Errorf("sign reports variables %v, want %v", defined, want), "verify dynObjVariable")
+	}
+	return nil
+}
+
+// GetDynamicObject reads the object identifiers of the variables of a
+// dynamic object, dynObjNumber from 1 to 13, which has no variables if it
+// is not defined.
+func GetDynamicObject(dms *gosnmp.GoSNMP, number int, options ...Option) ([]string, error) {
+	s := newSession(dms, "GetDynamicObject", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return getDynamicObject(s, number)
+}
+
+// getDynamicObject reads dynObjVariable.number.x from x = 1 up to the
+// first null value.
+func getDynamicObject(s *session, number int) ([]string, error) {
+	perRequest := s.dms.MaxOids
+	if perRequest <= 0 || perRequest > d.MaxDynVariables {
+		perRequest = d.MaxDynVariables
+	}
+	var oids []string
+	for first := 1; first <= d.MaxDynVariables; first += perRequest {
+		var names []string
+		for index := first; index < first+perRequest && index <= d.MaxDynVariables; index++ {
+			names = append(names, dynObjVariableName(number, index))
+		}
+		getResults, err := s.get(fmt.Sprintf("get dynObjVariable %d-%d", first, first+len(names)-1), names...)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range getResults.Variables {
+			oid, _ := variable.Value.(string)
+			if oid = strings.TrimPrefix(oid, "."); oid == "" || oid == nullOID {
+				return oids, nil
+			}
+			oids = append(oids, oid)
+		}
+	}
+	return oids, nil
+}
+
+func oidsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !oidEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// PollDynamicObject reads the values of the variables of a dynamic object
+// defined with DefineDynamicObject in a single STMP exchange: an int for the
+// integer types and a []byte for stmp.OctetString, in the order of the
+// variables. The request is sent to the address and port of dms, or over
+// the WithPMPP link; use a copy of dms with the port of the STMP agent of
+// a sign that does not serve STMP on its SNMP port. An error-response of the
+// sign is returned as an *stmp.Error.
+func PollDynamicObject(dms *gosnmp.GoSNMP, object stmp.Object, options ...Option) ([]interface{}, error) {
+	s := newSession(dms, "PollDynamicObject", options)
+	defer s.close()
+	if err := object.Validate(); err != nil {
+		return nil, s.wrap(err, "validate dynamic object")
+	}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	step := fmt.Sprintf("STMP get dynamic object %d", object.Number)
+	response, err := s.exchangeSTMP(step, object.EncodeGet())
+	if err != nil {
+		return nil, err
+	}
+	values, err := object.DecodeResponse(response)
+	return values, s.wrap(err, step)
+}
+
+// exchangeSTMP sends an STMP request on the connection of the session and
+// returns the response for the same dynamic object. As STMP messages carry
+// no request ID, other messages are skipped. The request is repeated up to
+// dms.Retries times when no response arrives within dms.Timeout.
+func (s *session) exchangeSTMP(step string, request []byte) ([]byte, error) {
+	defer s.timed(step, time.Now())
+	_, number, _ := stmp.ParseHeader(request[0])
+	buf := make([]byte, 65535)
+	var err error
+	for attempt := 0; attempt <= s.dms.Retries; attempt++ {
+		if _, err = s.dms.Conn.Write(request); err != nil {
+			return nil, s.wrap(err, step)
+		}
+		deadline := time.Now().Add(s.dms.Timeout)
+		for {
+			s.dms.Conn.SetReadDeadline(deadline)
+			var n int
+			if n, err = s.dms.Conn.Read(buf); err != nil {
+				break
+			}
+			if t, got, ok := stmp.ParseHeader(buf[0]); n > 0 && ok && got == number && t >= stmp.GetResponse {
+				return append([]byte{}, buf[:n]...), nil
+			}
+		}
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			break
+		}
+	}
+	return nil, s.wrap(err, step)
+}
@@ -0,0 +1,78 @@
+package dialogs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+	"github.com/jacobleehei/godms/stmp"
+)
+
+func TestDynamicObject(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	dms.MaxOids = 4
+
+	sign.SetSTMPType(d.ShortErrorStatus.Identifier(0), stmp.Uint16)
+	sign.SetSTMPType(d.DmsControlMode.Identifier(0), stmp.Uint8)
+	sign.Store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, 0x0020)
+	status := stmp.Object{Number: 1, Owner: "central", Variables: []stmp.Variable{
+		{OID: d.ShortErrorStatus.Identifier(0), Type: stmp.Uint16},
+		{OID: d.DmsControlMode.Identifier(0), Type: stmp.Uint8},
+		{OID: d.DmsMsgTableSource.Identifier(0), Type: stmp.OctetString},
+		{OID: d.DmsMessageTimeRemaining.Identifier(0), Type: stmp.Int32},
+		{OID: d.DmsIllumControl.Identifier(0), Type: stmp.Int32},
+	}}
+
+	if _, err := PollDynamicObject(dms, status); !errors.As(err, new(*stmp.Error)) {
+		t.Errorf("PollDynamicObject() of an undefined object error = %v, want *stmp.Error", err)
+	}
+
+	if err := DefineDynamicObject(dms, status); err != nil {
+		t.Fatalf("DefineDynamicObject() error = %v", err)
+	}
+	values, err := PollDynamicObject(dms, status)
+	if err != nil {
+		t.Fatalf("PollDynamicObject() error = %v", err)
+	}
+	want := []interface{}{0x0020, d.ControlCentral.Int(), []byte{byte(d.MemoryBlank.Int()), 0, 1, 0, 0}, 0, d.IllumPhotocell.Int()}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("PollDynamicObject() = %v, want %v", values, want)
+	}
+
+	// A shorter definition clears the variables left from the first one.
+	status.Variables = status.Variables[:2]
+	if err := DefineDynamicObject(dms, status); err != nil {
+		t.Fatalf("DefineDynamicObject() of a shorter object error = %v", err)
+	}
+	oids, err := GetDynamicObject(dms, 1)
+	if err != nil || len(oids) != 2 {
+		t.Errorf("GetDynamicObject() = %v, %v, want 2 variables", oids, err)
+	}
+	if values, err := PollDynamicObject(dms, status); err != nil || len(values) != 2 {
+		t.Errorf("PollDynamicObject() of the shorter object = %v, %v", values, err)
+	}
+
+	// The sign times out a poll for another port.
+	other := *dms
+	other.Port++
+	other.Timeout = 50 * time.Millisecond
+	if _, err := PollDynamicObject(&other, status); err == nil {
+		t.Errorf("PollDynamicObject() without an STMP agent expected an error")
+	}
+
+	if err := DefineDynamicObject(dms, stmp.Object{Number: 14}); !errors.Is(err, stmp.ErrObject) {
+		t.Errorf("DefineDynamicObject() of object 14 error = %v, want stmp.ErrObject", err)
+	}
+	if err := DefineDynamicObject(dms, status, WithReadOnly()); !errors.Is(err, ErrReadOnlyClient) {
+		t.Errorf("DefineDynamicObject() with WithReadOnly error = %v, want ErrReadOnlyClient", err)
+	}
+}
@@ -0,0 +1,111 @@
+package dialogs
+
+import (
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// Snapshot records the message a sign displayed before a temporary message,
+// so that Client.Restore can bring it back.
+type Snapshot struct {
+	Message  CurrentMessage // the message displayed before the temporary one
+	Priority int            // activation priority of the temporary message
+	Taken    time.Time
+}
+
+// DisplayTemporary saves the message currently displayed, then defines
+// multiString as volatile message messageNumber and activates it with the
+// given priority and duration (minutes, 65535 for infinite). Pass the
+// returned Snapshot to Restore to display the previous message again. No
+// Snapshot is returned if the temporary message could not be displayed.
+func (c *Client) DisplayTemporary(
+	messageNumber int, multiString string, priority, duration int,
+	options ...Option,
+) (*Snapshot, activatingMessageResult, error) {
+	current, err := c.RetrievingCurrentMessage(options...)
+	if err != nil {
+		return nil, activatingMessageResult{}, err
+	}
+	snapshot := &Snapshot{Message: current, Priority: priority, Taken: time.Now()}
+
+	if err := c.lock(); err != nil {
+		return nil, activatingMessageResult{}, err
+	}
+	owner, err := sourceAddress(c.dms, newDialogOptions(c.dialogOptions(options)...))
+	c.mu.Unlock()
+	if err != nil {
+		return nil, activatingMessageResult{}, err
+	}
+	if _, err := c.DefiningMessage(d.MemoryVolatile.Int(), messageNumber, multiString, owner, priority, 0, 0, options...); err != nil {
+		return nil, activatingMessageResult{}, err
+	}
+	result, err := c.ActivatingMessage(duration, priority, d.MemoryVolatile.Int(), messageNumber, options...)
+	if err != nil {
+		return nil, result, err
+	}
+	return snapshot, result, nil
+}
+
+// Restore displays the message of snapshot again, at the activation priority
+// of the temporary message so that it replaces it. The message is activated
+// with its saved CRC; a sign whose message table entry has changed since
+// rejects it with ErrActivateMessageCRC. A message that had a limited
+// duration gets the time it had left when the snapshot was taken, less the
+// time since; if no time is left, ErrSnapshotExpired is returned and nothing
+// is activated. A message displayed by the sign's scheduler is not activated
+// again: the sign is blanked at the lowest run-time priority, which hands the
+// display back to the scheduler. A dry run, see WithDryRun, is neither
+// recorded in the activation history nor arms the dead-man timer.
+func (c *Client) Restore(snapshot *Snapshot, options ...Option) (activatingMessageResult, error) {
+	if snapshot == nil {
+		return activatingMessageResult{}, errors.New("no snapshot to restore")
+	}
+	message := snapshot.Message
+	messageMemoryType, messageNumber, messageCRC := message.MessageMemoryType, message.MessageNumber, message.MessageCRC
+	duration := message.TimeRemaining
+	switch {
+	case message.SourceMode == d.SourceTimebasedScheduler.Int() || messageMemoryType == d.MemorySchedule.Int():
+		messageMemoryType, messageNumber, messageCRC, duration = d.MemoryBlank.Int(), 1, 0, 65535
+	case duration != 65535:
+		duration -= int(time.Since(snapshot.Taken) / time.Minute)
+		if duration < 1 {
+			return activatingMessageResult{}, errors.Wrapf(ErrSnapshotExpired, "message %d of memory type %d had %d minutes left at %s",
+				messageNumber, messageMemoryType, message.TimeRemaining, snapshot.Taken.Format(time.RFC3339))
+		}
+	}
+
+	options = c.dialogOptions(options)
+	o := newDialogOptions(options...)
+	duration = deadManDuration(duration, o.deadMan)
+	if err := c.lock(); err != nil {
+		return activatingMessageResult{}, err
+	}
+	s := newSession(c.dms, "Restore", options)
+	result, err := activateEntry(s, duration, snapshot.Priority, messageMemoryType, messageNumber, messageCRC)
+	owner, _ := s.sourceAddress()
+	s.close()
+	c.unlock()
+	if result.DryRun {
+		return result, err
+	}
+	record := ActivationRecord{
+		MessageMemoryType: messageMemoryType,
+		MessageNumber:     messageNumber,
+		MessageCRC:        messageCRC,
+		Duration:          duration,
+		Priority:          snapshot.Priority,
+		Owner:             owner,
+		ShortErrorStatus:  result.ShortErrorStatus,
+		Err:               err,
+	}
+	c.history.add(record)
+	if err == nil {
+		c.disarmDeadMan()
+		if o.deadMan > 0 && messageMemoryType != d.MemoryBlank.Int() {
+			c.armDeadMan(record, o.deadMan)
+		}
+	}
+	return result, err
+}
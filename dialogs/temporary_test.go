@@ -0,0 +1,137 @@
+package dialogs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestClient_DisplayTemporary(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	c := NewClient(sign.GoSNMP(), WithSourceAddress("10.0.0.5"))
+
+	if _, err := c.DisplayText(1, "[jp3]ORIGINAL", 50, 65535); err != nil {
+		t.Fatal(err)
+	}
+	original, err := c.RetrievingCurrentMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, _, err := c.DisplayTemporary(2, "[jp3]INCIDENT", 100, 30)
+	if err != nil {
+		t.Fatalf("DisplayTemporary() error = %v", err)
+	}
+	current, err := c.RetrievingCurrentMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.MessageMemoryType != d.MemoryVolatile.Int() || current.MultiString != "[jp3]INCIDENT" {
+		t.Fatalf("temporary message not displayed: %+v", current)
+	}
+
+	if _, err := c.Restore(snapshot); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	current, err = c.RetrievingCurrentMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.MessageMemoryType != original.MessageMemoryType ||
+		current.MessageNumber != original.MessageNumber ||
+		current.MessageCRC != original.MessageCRC ||
+		current.MultiString != original.MultiString {
+		t.Errorf("Restore() displayed %+v, want %+v", current, original)
+	}
+
+	// A message changed since the snapshot is not restored.
+	if _, err := c.DisplayText(1, "[jp3]CHANGED", 100, 65535); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Restore(snapshot); !errors.Is(err, ErrActivateMessageCRC) {
+		t.Errorf("Restore() error = %v, want ErrActivateMessageCRC", err)
+	}
+
+	// A message whose duration ran out is not displayed again.
+	expired := &Snapshot{Message: original, Priority: 100, Taken: time.Now().Add(-time.Hour)}
+	expired.Message.TimeRemaining = 30
+	if _, err := c.Restore(expired); !errors.Is(err, ErrSnapshotExpired) {
+		t.Errorf("Restore() of an expired message error = %v, want ErrSnapshotExpired", err)
+	}
+	if _, err := c.Restore(nil); err == nil {
+		t.Error("Restore(nil) error = nil")
+	}
+}
+
+func TestClient_Restore_concurrent(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	dms := sign.GoSNMP()
+	retries := dms.Retries
+	c := NewClient(dms, WithSourceAddress("10.0.0.5"))
+
+	if _, err := c.DisplayText(1, "[jp3]ORIGINAL", 50, 65535); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, _, err := c.DisplayTemporary(2, "[jp3]INCIDENT", 100, 65535)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The settings of each dialog are restored before the next one starts,
+	// see go test -race.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := c.Restore(snapshot, WithRetries(3)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := c.GetControlMode(WithRetries(5)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	if dms.Retries != retries {
+		t.Errorf("Retries = %d after the dialogs, want %d", dms.Retries, retries)
+	}
+}
+
+func TestClient_DisplayTemporary_shutdown(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	c := NewClient(sign.GoSNMP(), WithSourceAddress("10.0.0.5"))
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DisplayText(1, "[jp3]TEXT", 50, 65535); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("DisplayText() error = %v, want ErrClientClosed", err)
+	}
+	if _, _, err := c.DisplayTemporary(2, "[jp3]INCIDENT", 100, 30); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("DisplayTemporary() error = %v, want ErrClientClosed", err)
+	}
+}
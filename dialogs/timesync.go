@@ -0,0 +1,93 @@
+package dialogs
+
+import (
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// TimeSync is the result of SyncTime.
+type TimeSync struct {
+	// Drift is how far the clock of the sign was off before the dialog,
+	// positive when it was ahead. globalTime counts whole seconds, so Drift
+	// is accurate to about a second plus half of RoundTrip.
+	Drift time.Duration
+	// RoundTrip is the time the sign took to answer the GET of globalTime.
+	RoundTrip time.Duration
+	// Corrected reports whether the dialog set the clock of the sign.
+	Corrected bool
+	// DaylightSaving is globalDaylightSaving, e.g. d.DaylightSavingUS.Int().
+	DaylightSaving int
+}
+
+// SyncTime measures how far the clock of the sign is off and, unless the
+// drift is within the threshold of WithDriftThreshold, sets globalTime to the
+// current time together with globalDaylightSaving, which is kept. The clock
+// is then read back.
+func SyncTime(dms *gosnmp.GoSNMP, options ...Option) (TimeSync, error) {
+	s := newSession(dms, "SyncTime", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return TimeSync{}, err
+	}
+	return syncTime(s)
+}
+
+func syncTime(s *session) (TimeSync, error) {
+	var sync TimeSync
+	var err error
+	sync.DaylightSaving, err = getInt(s, "globalDaylightSaving", d.GlobalDaylightSaving.Identifier(0))
+	if err != nil {
+		return sync, err
+	}
+
+	sync.Drift, sync.RoundTrip, err = measureDrift(s, "get globalTime")
+	if err != nil {
+		return sync, err
+	}
+	threshold := s.options.driftThreshold
+	if sync.Drift <= threshold && sync.Drift >= -threshold {
+		return sync, nil
+	}
+
+	// globalTime arrives at the sign about half a round trip after it is
+	// sent. gosnmp does not accept a Counter32 as the first varbind of a
+	// SET, so the unchanged globalDaylightSaving goes first.
+	now := time.Now().Add(sync.RoundTrip / 2).Round(time.Second)
+	if _, err := s.set("set globalTime",
+		gosnmp.SnmpPDU{Name: d.GlobalDaylightSaving.Identifier(0), Type: d.GlobalDaylightSaving.Syntax(), Value: sync.DaylightSaving},
+		gosnmp.SnmpPDU{Name: d.GlobalTime.Identifier(0), Type: d.GlobalTime.Syntax(), Value: uint(now.Unix())},
+	); err != nil {
+		return sync, err
+	}
+	sync.Corrected = true
+
+	drift, roundTrip, err := measureDrift(s, "verify globalTime")
+	if err != nil {
+		return sync, err
+	}
+	if limit := time.Second + roundTrip; drift > limit || drift < -limit {
+		return sync, s.wrap(errors.Errorf("clock still off by %v after the correction", drift), "verify globalTime", d.GlobalTime.Identifier(0))
+	}
+	return sync, nil
+}
+
+// measureDrift GETs globalTime and returns how far it is off the local
+// clock at the middle of the request, and the round trip of the request.
+func measureDrift(s *session, step string) (drift, roundTrip time.Duration, err error) {
+	sent := time.Now()
+	getResult, err := s.getSingle(step, d.GlobalTime.Identifier(0))
+	if err != nil {
+		return 0, 0, err
+	}
+	received := time.Now()
+	roundTrip = received.Sub(sent)
+	signTime := gosnmp.ToBigInt(getResult.Value).Int64()
+	middle := sent.Add(roundTrip / 2)
+	// The sign truncates its clock to the second; compare to the middle of
+	// that second.
+	drift = time.Unix(signTime, int64(time.Second/2)).Sub(middle).Round(time.Second)
+	return drift, roundTrip, nil
+}
@@ -0,0 +1,46 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestSyncTime(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	sign.SetClock(time.Now().Add(-90 * time.Second))
+	sync, err := client.SyncTime(WithDriftThreshold(2 * time.Minute))
+	if err != nil {
+		t.Fatalf("SyncTime() error = %v", err)
+	}
+	if sync.Corrected || sync.Drift > -89*time.Second || sync.Drift < -91*time.Second {
+		t.Errorf("SyncTime() within the threshold = %+v, want a drift of -90s left alone", sync)
+	}
+	if sync.DaylightSaving != d.DaylightSavingDisabled.Int() {
+		t.Errorf("SyncTime() daylight saving = %d", sync.DaylightSaving)
+	}
+
+	sync, err = client.SyncTime()
+	if err != nil {
+		t.Fatalf("SyncTime() error = %v", err)
+	}
+	if !sync.Corrected || sync.Drift > -89*time.Second {
+		t.Errorf("SyncTime() = %+v, want the drift corrected", sync)
+	}
+
+	sync, err = client.SyncTime(WithDriftThreshold(time.Second))
+	if err != nil {
+		t.Fatalf("SyncTime() error = %v", err)
+	}
+	if sync.Corrected {
+		t.Errorf("SyncTime() after the correction = %+v, want nothing to correct", sync)
+	}
+}
@@ -0,0 +1,119 @@
+package dialogs
+
+import (
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/pkg/errors"
+)
+
+// TimeZone is the time zone configuration of the sign, which turns
+// globalTime into the local time the scheduled messages of the sign run on.
+type TimeZone struct {
+	// StandardOffset is controllerStandardTimeZone, the offset of standard
+	// time from UTC, e.g. -5 * time.Hour for Eastern Standard Time. A sign
+	// of NTCIP 1201 v2, without controllerStandardTimeZone, reports
+	// globalLocalTimeDifferential instead, which includes daylight saving
+	// time.
+	StandardOffset time.Duration
+	// DaylightSaving is globalDaylightSaving, e.g. d.DaylightSavingUS.Int().
+	DaylightSaving int
+	// LocalTime is controllerLocalTime, read-only; zero on a sign of NTCIP
+	// 1201 v2.
+	LocalTime time.Time
+}
+
+// Validate checks that the sign can be configured with z: an offset of
+// whole seconds within 12 hours of UTC, and a daylight saving time rule of
+// globalDaylightSaving other than 'other', which stands for a rule configured
+// by other means.
+func (z TimeZone) Validate() error {
+	switch {
+	case z.StandardOffset%time.Second != 0:
+		return errors.Errorf("standard time offset %v is not a whole number of seconds", z.StandardOffset)
+	case z.StandardOffset < -d.MaxTimeZoneOffset*time.Second || z.StandardOffset > d.MaxTimeZoneOffset*time.Second:
+		return errors.Errorf("standard time offset %v out of range -12h to 12h", z.StandardOffset)
+	case z.DaylightSaving < d.DaylightSavingDisabled.Int() || z.DaylightSaving > d.DaylightSavingIran.Int():
+		return errors.Errorf("daylight saving %d out of range %d-%d", z.DaylightSaving, d.DaylightSavingDisabled.Int(), d.DaylightSavingIran.Int())
+	}
+	return nil
+}
+
+// GetTimeZone reads the time zone configuration of the sign.
+func GetTimeZone(dms *gosnmp.GoSNMP, options ...Option) (TimeZone, error) {
+	s := newSession(dms, "GetTimeZone", options)
+	defer s.close()
+	if err := s.connect(); err != nil {
+		return TimeZone{}, err
+	}
+	zone, _, err := getTimeZone(s)
+	return zone, err
+}
+
+// getTimeZone reads the time zone configuration and returns the object that
+// holds the offset: controllerStandardTimeZone or, on a sign that answers
+// noSuchName for it, globalLocalTimeDifferential.
+func getTimeZone(s *session) (zone TimeZone, offsetObject d.Reader, err error) {
+	offsetObject = d.ControllerStandardTimeZone
+	offset, err := getInt(s, offsetObject.ObjectType(), offsetObject.Identifier(0))
+	if errors.Is(err, ErrNoSuchName) {
+		offsetObject = d.GlobalLocalTimeDifferential
+		offset, err = getInt(s, offsetObject.ObjectType(), offsetObject.Identifier(0))
+	}
+	if err != nil {
+		return zone, offsetObject, err
+	}
+	zone.StandardOffset = time.Duration(offset) * time.Second
+
+	if zone.DaylightSaving, err = getInt(s, "globalDaylightSaving", d.GlobalDaylightSaving.Identifier(0)); err != nil {
+		return zone, offsetObject, err
+	}
+	if offsetObject == d.ControllerStandardTimeZone {
+		getResult, err := s.getSingle("get controllerLocalTime", d.ControllerLocalTime.Identifier(0))
+		if err != nil && !errors.Is(err, ErrNoSuchName) {
+			return zone, offsetObject, err
+		}
+		if err == nil {
+			zone.LocalTime = time.Unix(gosnmp.ToBigInt(getResult.Value).Int64(), 0).UTC()
+		}
+	}
+	return zone, offsetObject, nil
+}
+
+// SetTimeZone validates zone, sets the standard time offset and the daylight
+// saving time rule of the sign in a single request and reads them back.
+// LocalTime is read-only and ignored. On a sign of NTCIP 1201 v2 the offset
+// is set into globalLocalTimeDifferential, which must then include the
+// daylight saving time in effect.
+func SetTimeZone(dms *gosnmp.GoSNMP, zone TimeZone, options ...Option) (TimeZone, error) {
+	s := newSession(dms, "SetTimeZone", options)
+	defer s.close()
+	if err := zone.Validate(); err != nil {
+		return TimeZone{}, s.wrap(err, "validate time zone")
+	}
+	if err := s.connect(); err != nil {
+		return TimeZone{}, err
+	}
+
+	_, offsetObject, err := getTimeZone(s)
+	if err != nil {
+		return TimeZone{}, err
+	}
+	if _, err := s.set("set time zone",
+		gosnmp.SnmpPDU{Name: offsetObject.Identifier(0), Type: offsetObject.Syntax(), Value: int(zone.StandardOffset / time.Second)},
+		gosnmp.SnmpPDU{Name: d.GlobalDaylightSaving.Identifier(0), Type: d.GlobalDaylightSaving.Syntax(), Value: zone.DaylightSaving},
+	); err != nil {
+		return TimeZone{}, err
+	}
+
+	current, _, err := getTimeZone(s)
+	if err != nil {
+		return current, err
+	}
+	if current.StandardOffset != zone.StandardOffset || current.DaylightSaving != zone.DaylightSaving {
+		return current, s.wrap(errors.Errorf("sign reports offset %v and daylight saving %d, want %v and %d",
+			current.StandardOffset, current.DaylightSaving, zone.StandardOffset, zone.DaylightSaving), "verify time zone")
+	}
+	return current, nil
+}
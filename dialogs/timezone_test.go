@@ -0,0 +1,68 @@
+package dialogs
+
+import (
+	"testing"
+	"time"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestTimeZone_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		zone    TimeZone
+		wantErr bool
+	}{
+		{"eastern", TimeZone{StandardOffset: -5 * time.Hour, DaylightSaving: d.DaylightSavingUS.Int()}, false},
+		{"india", TimeZone{StandardOffset: 5*time.Hour + 30*time.Minute, DaylightSaving: d.DaylightSavingDisabled.Int()}, false},
+		{"fraction of a second", TimeZone{StandardOffset: time.Hour + time.Millisecond, DaylightSaving: d.DaylightSavingDisabled.Int()}, true},
+		{"out of range", TimeZone{StandardOffset: 13 * time.Hour, DaylightSaving: d.DaylightSavingDisabled.Int()}, true},
+		{"other", TimeZone{DaylightSaving: d.DaylightSavingOther.Int()}, true},
+		{"unset", TimeZone{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.zone.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetTimeZone(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	client := NewClient(sign.GoSNMP())
+
+	zone, err := client.SetTimeZone(TimeZone{StandardOffset: -5 * time.Hour, DaylightSaving: d.DaylightSavingUS.Int()})
+	if err != nil {
+		t.Fatalf("SetTimeZone() error = %v", err)
+	}
+	if zone.StandardOffset != -5*time.Hour || zone.DaylightSaving != d.DaylightSavingUS.Int() {
+		t.Errorf("SetTimeZone() = %+v", zone)
+	}
+	if lag := time.Now().Add(-5 * time.Hour).Sub(zone.LocalTime); lag < -time.Second || lag > 2*time.Second {
+		t.Errorf("SetTimeZone() local time = %v, %v off", zone.LocalTime, lag)
+	}
+
+	if _, err := client.SetTimeZone(TimeZone{StandardOffset: 14 * time.Hour, DaylightSaving: d.DaylightSavingUS.Int()}); err == nil {
+		t.Error("SetTimeZone() out of range error = nil")
+	}
+
+	// A sign of NTCIP 1201 v2 only has globalLocalTimeDifferential.
+	sign.Delete(d.ControllerStandardTimeZone.Identifier(0))
+	sign.Delete(d.ControllerLocalTime.Identifier(0))
+	sign.Store(d.GlobalLocalTimeDifferential.Identifier(0), d.GlobalLocalTimeDifferential.Syntax(), 3600)
+	zone, err = client.GetTimeZone()
+	if err != nil || zone.StandardOffset != time.Hour || !zone.LocalTime.IsZero() {
+		t.Errorf("GetTimeZone() on a v2 sign = %+v, %v", zone, err)
+	}
+	zone, err = client.SetTimeZone(TimeZone{StandardOffset: 2 * time.Hour, DaylightSaving: d.DaylightSavingEurope.Int()})
+	if err != nil || zone.StandardOffset != 2*time.Hour {
+		t.Errorf("SetTimeZone() on a v2 sign = %+v, %v", zone, err)
+	}
+}
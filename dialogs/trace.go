@@ -0,0 +1,123 @@
+package dialogs
+
+import (
+	"github.com/gosnmp/gosnmp"
+)
+
+// Attribute is a key and value describing a Span, e.g. the OIDs of an SNMP
+// request.
+type Attribute struct {
+	Key   string
+	Value interface{} // string, int, bool or []string
+}
+
+// Keys of the attributes of the spans.
+const (
+	AttributeDialog      = "godms.dialog"      // the name of the dialog, e.g. "ActivatingMessage"
+	AttributeStep        = "godms.step"        // the step of the dialog, e.g. "get shortErrorStatus"
+	AttributePeerName    = "net.peer.name"     // the address of the sign
+	AttributePeerPort    = "net.peer.port"     // the SNMP port of the sign
+	AttributeSNMPVersion = "snmp.version"      // e.g. "2c"
+	AttributeOIDs        = "snmp.oids"         // the objects of the request
+	AttributeErrorStatus = "snmp.error_status" // the error status of the response, e.g. "NoSuchName"
+	AttributeErrorIndex  = "snmp.error_index"  // the error index of the response
+)
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	// SetError marks the span as failed with err.
+	SetError(err error)
+	End()
+}
+
+// Tracer starts the spans of the dialogs and of their SNMP requests. It is
+// shaped after OpenTelemetry, which godms does not depend on: an adapter
+// keeps the context of the OpenTelemetry span in its Span, to start the
+// children of parent, and converts the attributes, e.g.
+//
+//	func (t otelTracer) Start(parent dialogs.Span, name string, attributes ...dialogs.Attribute) dialogs.Span {
+//		ctx := context.Background()
+//		if p, ok := parent.(otelSpan); ok {
+//			ctx = p.ctx
+//		}
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attributes)...))
+//		return otelSpan{ctx: ctx, span: span}
+//	}
+type Tracer interface {
+	// Start starts a span, a root span if parent is nil.
+	Start(parent Span, name string, attributes ...Attribute) Span
+}
+
+// WithTracer traces the dialogs with tracer. Each SNMP request is a span
+// named "snmp.get", "snmp.getnext", "snmp.getbulk" or "snmp.set", with the
+// OIDs, the dialog and the step as attributes and the error status of the
+// response, if any. The dialogs are spans too, named after the dialog and
+// parents of the spans of their requests.
+func WithTracer(tracer Tracer) Option {
+	return func(o *dialogOptions) { o.tracer = tracer }
+}
+
+// WithParentSpan makes the spans of WithTracer children of parent, e.g. the
+// span of the request of a central system that activates a message.
+func WithParentSpan(parent Span) Option {
+	return func(o *dialogOptions) { o.parentSpan = parent }
+}
+
+// startDialogSpan starts the span of the dialog of s. A Client collects the
+// span and ends it with the call; otherwise close ends it.
+func (s *session) startDialogSpan() {
+	if s.options.tracer == nil {
+		return
+	}
+	s.span = s.options.tracer.Start(s.options.parentSpan, s.dialog, s.peerAttributes()...)
+	if s.options.spans != nil {
+		*s.options.spans = append(*s.options.spans, s.span)
+	}
+}
+
+// startSpan starts the span of an SNMP request of step, or returns nil
+// without a tracer.
+func (s *session) startSpan(name, step string, oids []string) Span {
+	if s.options.tracer == nil {
+		return nil
+	}
+	parent := s.options.parentSpan
+	if s.span != nil {
+		parent = s.span
+	}
+	attributes := append(s.peerAttributes(),
+		Attribute{AttributeDialog, s.dialog},
+		Attribute{AttributeStep, step},
+		Attribute{AttributeOIDs, append([]string{}, oids...)},
+	)
+	return s.options.tracer.Start(parent, name, attributes...)
+}
+
+// endSpan ends span with the error status of result and err.
+func endSpan(span Span, result *gosnmp.SnmpPacket, err error) {
+	if span == nil {
+		return
+	}
+	if result != nil && result.Error != gosnmp.NoError {
+		span.SetAttributes(
+			Attribute{AttributeErrorStatus, result.Error.String()},
+			Attribute{AttributeErrorIndex, int(result.ErrorIndex)},
+		)
+	}
+	if err != nil {
+		span.SetError(err)
+	}
+	span.End()
+}
+
+func (s *session) peerAttributes() []Attribute {
+	if s.dms == nil {
+		return nil
+	}
+	return []Attribute{
+		{AttributePeerName, s.dms.Target},
+		{AttributePeerPort, int(s.dms.Port)},
+		{AttributeSNMPVersion, s.dms.Version.String()},
+	}
+}
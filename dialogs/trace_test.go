@@ -0,0 +1,83 @@
+package dialogs
+
+import (
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+	"github.com/pkg/errors"
+)
+
+type recordedSpan struct {
+	name       string
+	parent     *recordedSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *recordedSpan) SetAttributes(attributes ...Attribute) {
+	for _, attribute := range attributes {
+		span.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (span *recordedSpan) SetError(err error) { span.err = err }
+func (span *recordedSpan) End()               { span.ended = true }
+
+type recordingTracer struct{ spans []*recordedSpan }
+
+func (t *recordingTracer) Start(parent Span, name string, attributes ...Attribute) Span {
+	span := &recordedSpan{name: name, attributes: map[string]interface{}{}}
+	span.parent, _ = parent.(*recordedSpan)
+	span.SetAttributes(attributes...)
+	t.spans = append(t.spans, span)
+	return span
+}
+
+func TestWithTracer(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	tracer := &recordingTracer{}
+	parent := &recordedSpan{name: "central", attributes: map[string]interface{}{}}
+	client := NewClient(sign.GoSNMP(), WithTracer(tracer))
+	if _, err := client.RetrievingCurrentMessage(WithParentSpan(parent)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.spans) != 2 {
+		t.Fatalf("got %d spans, want the dialog and its GET", len(tracer.spans))
+	}
+	dialog, get := tracer.spans[0], tracer.spans[1]
+	if dialog.name != "RetrievingCurrentMessage" || dialog.parent != parent || !dialog.ended {
+		t.Errorf("dialog span = %+v, want an ended RetrievingCurrentMessage child of the parent", dialog)
+	}
+	if get.name != "snmp.get" || get.parent != dialog || !get.ended || get.err != nil {
+		t.Errorf("request span = %+v, want an ended snmp.get child of the dialog", get)
+	}
+	oids, _ := get.attributes[AttributeOIDs].([]string)
+	if len(oids) == 0 || oids[0] != d.DmsMsgTableSource.Identifier(0) {
+		t.Errorf("request OIDs = %v, want dmsMsgTableSource first", oids)
+	}
+	if get.attributes[AttributeDialog] != "RetrievingCurrentMessage" || get.attributes[AttributePeerName] != sign.GoSNMP().Target {
+		t.Errorf("request attributes = %v", get.attributes)
+	}
+
+	tracer.spans = nil
+	if _, err := GetAction(sign.GoSNMP(), 99, WithTracer(tracer)); !errors.Is(err, ErrNoSuchName) {
+		t.Fatalf("GetAction(99) error = %v, want ErrNoSuchName", err)
+	}
+	if len(tracer.spans) != 2 {
+		t.Fatalf("got %d spans, want the dialog and its GET without a client", len(tracer.spans))
+	}
+	dialog, get = tracer.spans[0], tracer.spans[1]
+	if dialog.name != "GetAction" || dialog.parent != nil || !dialog.ended {
+		t.Errorf("dialog span = %+v, want an ended GetAction root span", dialog)
+	}
+	if get.parent != dialog || get.err == nil || !get.ended {
+		t.Errorf("request span = %+v, want an ended child of the dialog with an error", get)
+	}
+}
@@ -0,0 +1,107 @@
+package dialogs
+
+import (
+	"image/color"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/multi"
+	"github.com/pkg/errors"
+)
+
+// VMSConfiguration holds the pixel geometry of a sign: the vmsCfg objects
+// and dmsMaxNumberPages.
+type VMSConfiguration struct {
+	CharacterHeightPixels int // vmsCharacterHeightPixels, 0 for a full matrix sign
+	CharacterWidthPixels  int // vmsCharacterWidthPixels, 0 for a full or line matrix sign
+	SignHeightPixels      int // vmsSignHeightPixels
+	SignWidthPixels       int // vmsSignWidthPixels
+	HorizontalPitch       int // vmsHorizontalPitch in millimeters
+	VerticalPitch         int // vmsVerticalPitch in millimeters
+	MonochromeOn          color.RGBA
+	MonochromeOff         color.RGBA
+	Monochrome            bool // false if monochromeColor is all zeros
+	MaxNumberPages        int  // dmsMaxNumberPages, 0 if the sign does not support it
+}
+
+// Matrix returns "full", "line" or "character" for the matrix type implied
+// by the character size.
+func (c VMSConfiguration) Matrix() string {
+	switch {
+	case c.CharacterWidthPixels > 0:
+		return "character"
+	case c.CharacterHeightPixels > 0:
+		return "line"
+	default:
+		return "full"
+	}
+}
+
+// MultiSign returns the geometry for rendering MULTI previews of the sign
+// with package multi. A monochrome sign renders in its own on and off
+// colors.
+func (c VMSConfiguration) MultiSign() multi.Sign {
+	sign := multi.Sign{
+		Width:           c.SignWidthPixels,
+		Height:          c.SignHeightPixels,
+		CharacterWidth:  c.CharacterWidthPixels,
+		CharacterHeight: c.CharacterHeightPixels,
+	}
+	if c.Monochrome {
+		sign.DefaultForegroundColor = c.MonochromeOn
+		sign.DefaultBackgroundColor = c.MonochromeOff
+	}
+	return sign
+}
+
+// GetVMSConfiguration reads the pixel geometry of the sign.
+func GetVMSConfiguration(dms *gosnmp.GoSNMP, options ...Option) (config VMSConfiguration, err error) {
+	s := newSession(dms, "GetVMSConfiguration", options)
+	defer s.close()
+	if err = s.connect(); err != nil {
+		return config, err
+	}
+
+	oids := make([]string, 0, len(d.VMSConfigurationObjects))
+	for _, object := range d.VMSConfigurationObjects {
+		oids = append(oids, object.Identifier(0))
+	}
+	getResults, err := s.get("get vms configuration", oids...)
+	if err != nil {
+		return config, err
+	}
+	for _, variable := range getResults.Variables {
+		value, _ := variable.Value.(int)
+		switch {
+		case oidEqual(variable.Name, d.VmsCharacterHeightPixels.Identifier(0)):
+			config.CharacterHeightPixels = value
+		case oidEqual(variable.Name, d.VmsCharacterWidthPixels.Identifier(0)):
+			config.CharacterWidthPixels = value
+		case oidEqual(variable.Name, d.VmsSignHeightPixels.Identifier(0)):
+			config.SignHeightPixels = value
+		case oidEqual(variable.Name, d.VmsSignWidthPixels.Identifier(0)):
+			config.SignWidthPixels = value
+		case oidEqual(variable.Name, d.VmsHorizontalPitch.Identifier(0)):
+			config.HorizontalPitch = value
+		case oidEqual(variable.Name, d.VmsVerticalPitch.Identifier(0)):
+			config.VerticalPitch = value
+		case oidEqual(variable.Name, d.MonochromeColor.Identifier(0)):
+			colors, _ := variable.Value.([]byte)
+			if len(colors) == 6 {
+				config.MonochromeOn = color.RGBA{colors[0], colors[1], colors[2], 255}
+				config.MonochromeOff = color.RGBA{colors[3], colors[4], colors[5], 255}
+				for _, c := range colors {
+					config.Monochrome = config.Monochrome || c != 0
+				}
+			}
+		}
+	}
+
+	// dmsMaxNumberPages was added in NTCIP 1203 v2.
+	pages, err := s.getSingle("get dmsMaxNumberPages", d.DmsMaxNumberPages.Identifier(0))
+	if err != nil && !errors.Is(err, ErrNoSuchName) {
+		return config, err
+	}
+	config.MaxNumberPages, _ = pages.Value.(int)
+	return config, nil
+}
@@ -0,0 +1,46 @@
+package dialogs
+
+import (
+	"image/color"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func TestGetVMSConfiguration(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	config, err := GetVMSConfiguration(sign.GoSNMP())
+	if err != nil {
+		t.Fatalf("GetVMSConfiguration() error = %v", err)
+	}
+	want := VMSConfiguration{
+		SignHeightPixels: simulator.SignHeightPixels,
+		SignWidthPixels:  simulator.SignWidthPixels,
+		HorizontalPitch:  46,
+		VerticalPitch:    46,
+		MonochromeOn:     color.RGBA{255, 191, 0, 255},
+		MonochromeOff:    color.RGBA{0, 0, 0, 255},
+		Monochrome:       true,
+		MaxNumberPages:   6,
+	}
+	if config != want {
+		t.Errorf("GetVMSConfiguration() = %+v, want %+v", config, want)
+	}
+	if config.Matrix() != "full" {
+		t.Errorf("Matrix() = %q, want full", config.Matrix())
+	}
+	if s := config.MultiSign(); s.Width != simulator.SignWidthPixels || s.DefaultForegroundColor != want.MonochromeOn {
+		t.Errorf("MultiSign() = %+v", s)
+	}
+
+	sign.Delete(d.DmsMaxNumberPages.Identifier(0))
+	if config, err = GetVMSConfiguration(sign.GoSNMP()); err != nil || config.MaxNumberPages != 0 {
+		t.Errorf("GetVMSConfiguration() = %+v, %v, want no page limit", config, err)
+	}
+}
@@ -0,0 +1,151 @@
+package dialogs
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+)
+
+// WithBulkSize sets the max-repetitions of the SNMPv2c GETBULK requests
+// the dialogs walk tables with, e.g. the dmsMessageTable or the characters
+// of a font: each request returns up to size rows. The default is 10; 0
+// walks with a GETNEXT request per row, like SNMPv1 sessions always do.
+func WithBulkSize(size int) Option {
+	return func(o *dialogOptions) { o.bulkSize = size }
+}
+
+// walk GETs the objects of the subtree root in lexicographic order, see
+// walkColumns.
+func (s *session) walk(step string, root string) ([]gosnmp.SnmpPDU, error) {
+	columns, err := s.walkColumns(step, root)
+	if err != nil {
+		return nil, err
+	}
+	return columns[0], nil
+}
+
+// walkColumns GETs the objects of the subtrees roots, e.g. columns of a
+// table, in lexicographic order: the columns are walked side by side, a page
+// of rows per request. The requests are GETBULKs of WithBulkSize rows, or
+// GETNEXTs of a single row for SNMPv1 sessions, with a bulk size of 0 or
+// once the sign refused a GETBULK. A page too big for the sign is asked for
+// again with half the rows. A walk stops at the first other error status.
+func (s *session) walkColumns(step string, roots ...string) ([][]gosnmp.SnmpPDU, error) {
+	defer s.timed(step, time.Now())
+	columns := make([][]gosnmp.SnmpPDU, len(roots))
+	last := append([]string{}, roots...) // the last object of each column
+	walking := make([]int, len(roots))   // the columns not walked to the end
+	for i := range walking {
+		walking[i] = i
+	}
+	bulkSize := s.options.bulkSize
+	for len(walking) > 0 {
+		r := request{operation: "getnext", step: step}
+		if bulkSize > 0 && s.dms.Version != gosnmp.Version1 {
+			r.operation, r.maxRepetitions = "getbulk", bulkSize
+		}
+		for _, column := range walking {
+			r.oids = append(r.oids, last[column])
+		}
+		result, err := s.exchange(r)
+		var snmpErr *SNMPError
+		switch {
+		case errors.As(err, &snmpErr) && r.operation == "getbulk":
+			if snmpErr.Status == gosnmp.TooBig && bulkSize > 1 {
+				bulkSize /= 2
+			} else {
+				bulkSize = 0
+			}
+			continue
+		case errors.As(err, &snmpErr) && snmpErr.Status == gosnmp.NoSuchName && s.dms.Version == gosnmp.Version1:
+			// A SNMPv1 sign reports the end of the MIB view as noSuchName.
+			if i := oidIndex(r.oids, snmpErr.OID); i >= 0 {
+				walking = append(walking[:i:i], walking[i+1:]...)
+				continue
+			}
+		}
+		if err != nil {
+			return nil, s.wrap(err, step, r.oids...)
+		}
+
+		// The objects of a GETBULK come row by row, a column after another.
+		ended := make([]bool, len(walking))
+		for i, variable := range result.Variables {
+			k := i % len(walking)
+			column := walking[k]
+			if ended[k] {
+				continue
+			}
+			if variable.Type == gosnmp.EndOfMibView || !underAny(variable.Name, roots[column:column+1]) {
+				ended[k] = true
+				continue
+			}
+			if compareOID(variable.Name, last[column]) <= 0 {
+				return nil, s.wrap(errors.Errorf("%s does not follow %s", strings.TrimPrefix(variable.Name, "."), strings.TrimPrefix(last[column], ".")), step, r.oids...)
+			}
+			columns[column] = append(columns[column], variable)
+			last[column] = variable.Name
+		}
+		if len(result.Variables) == 0 {
+			break
+		}
+		rows := 0
+		for _, column := range columns {
+			if len(column) > rows {
+				rows = len(column)
+			}
+		}
+		s.progress(step, rows, 0)
+		next := walking[:0:0]
+		for k, column := range walking {
+			if !ended[k] {
+				next = append(next, column)
+			}
+		}
+		walking = next
+	}
+	return columns, nil
+}
+
+// oidIndex returns the index of oid in oids, -1 if it is not there.
+func oidIndex(oids []string, oid string) int {
+	for i := range oids {
+		if oidEqual(oids[i], oid) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compareOID compares two object identifiers sub-identifier by
+// sub-identifier.
+func compareOID(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "."), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "."), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// rowIndex returns the index of the row of a column object: the
+// sub-identifiers following the column OID root.
+func rowIndex(root, oid string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(oid, "."), strings.TrimPrefix(root, ".")+".")
+}
+
+// parentOID returns oid without its last sub-identifier, e.g. the column of
+// a row.
+func parentOID(oid string) string {
+	return oid[:strings.LastIndex(oid, ".")]
+}
@@ -0,0 +1,122 @@
+package dialogs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+// countRequests returns an interceptor counting the requests of each kind
+// and recording the rows asked by each GETBULK.
+func countRequests(kinds map[string]int, bulkSizes *[]int) Interceptor {
+	return Interceptor{Before: func(op *Operation) error {
+		kinds[op.Kind]++
+		if op.Kind == "getbulk" {
+			*bulkSizes = append(*bulkSizes, op.MaxRepetitions)
+		}
+		return nil
+	}}
+}
+
+func TestWalkColumns(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	// The 255 rows of the blank memory type, in pages of 10 rows.
+	kinds, bulkSizes := map[string]int{}, []int{}
+	v2c := WithVersion(gosnmp.Version2c)
+	entries, err := ListMessages(sign.GoSNMP(), d.MemoryBlank.Int(), v2c, WithInterceptor(countRequests(kinds, &bulkSizes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 255 || entries[254].MessageNumber != 255 || entries[254].RunTimePriority != 255 {
+		t.Fatalf("ListMessages() = %d entries, want 255", len(entries))
+	}
+	if kinds["getbulk"] != 26 || kinds["getnext"] != 0 {
+		t.Errorf("ListMessages() sent %v, want 26 GETBULKs", kinds)
+	}
+
+	// SNMPv1 has no GETBULK.
+	kinds = map[string]int{}
+	v1, err := ListMessages(sign.GoSNMP(), d.MemoryBlank.Int(), WithInterceptor(countRequests(kinds, &bulkSizes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v1, entries) || kinds["getbulk"] != 0 || kinds["getnext"] != 256 {
+		t.Errorf("ListMessages() over SNMPv1 sent %v, want 256 GETNEXTs and the same entries", kinds)
+	}
+
+	// A sign refusing GETBULK is walked with GETNEXT.
+	refuse := Interceptor{After: func(op Operation, result *gosnmp.SnmpPacket, err error) error {
+		if op.Kind == "getbulk" {
+			return &SNMPError{Status: gosnmp.GenErr, OID: op.OIDs[0]}
+		}
+		return err
+	}}
+	kinds = map[string]int{}
+	refused, err := ListMessages(sign.GoSNMP(), d.MemoryBlank.Int(), v2c, WithInterceptor(countRequests(kinds, &bulkSizes), refuse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(refused, entries) || kinds["getbulk"] != 1 || kinds["getnext"] != 256 {
+		t.Errorf("ListMessages() of a sign refusing GETBULK sent %v, want 1 GETBULK and 256 GETNEXTs", kinds)
+	}
+
+	// Pages too big for the sign are halved.
+	tooBig := Interceptor{After: func(op Operation, result *gosnmp.SnmpPacket, err error) error {
+		if op.MaxRepetitions > 4 {
+			return &SNMPError{Status: gosnmp.TooBig}
+		}
+		return err
+	}}
+	bulkSizes = nil
+	if _, err := ListMessages(sign.GoSNMP(), d.MemoryBlank.Int(), v2c, WithBulkSize(20), WithInterceptor(countRequests(map[string]int{}, &bulkSizes), tooBig)); err != nil {
+		t.Fatal(err)
+	}
+	if len(bulkSizes) < 4 || !reflect.DeepEqual(bulkSizes[:4], []int{20, 10, 5, 2}) || bulkSizes[len(bulkSizes)-1] != 2 {
+		t.Errorf("GETBULK rows = %v, want 20, 10, 5 then 2", bulkSizes)
+	}
+}
+
+func TestListGraphics(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+
+	graphic := Graphic{Number: 3, Name: "arrow", Height: 8, Width: 8, Type: d.GraphicMonochrome1Bit.Int(), Bitmap: make([]byte, 8)}
+	if _, err := ConfiguringGraphic(sign.GoSNMP(), 2, graphic); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ListGraphics(sign.GoSNMP(), WithVersion(gosnmp.Version2c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GraphicEntry{{Index: 2, Number: 3, Name: "arrow", Height: 8, Width: 8, Type: d.GraphicMonochrome1Bit.Int(), ID: graphic.ID(), Status: d.GraphicReadyForUse.Int()}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("ListGraphics() = %+v, want %+v", entries, want)
+	}
+}
+
+func Test_compareOID(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.3.6.1.2", b: "1.3.6.1.10", want: -1},
+		{a: ".1.3.6.1.2.1", b: "1.3.6.1.2", want: 1},
+		{a: ".1.3.6.1.2", b: "1.3.6.1.2", want: 0},
+	}
+	for _, tt := range tests {
+		if got := compareOID(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareOID(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Package discovery finds the NTCIP signs of a network, to bootstrap the
+// inventory of a fleet. Every address of a subnet is probed with a single
+// GET of a few objects; the devices that answer with dmsSignType are signs.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// MIB-II system objects of every SNMP agent.
+const (
+	sysDescrOID    = "1.3.6.1.2.1.1.1.0"
+	sysObjectIDOID = "1.3.6.1.2.1.1.2.0"
+)
+
+// enterprisesOID is the node of the private enterprise numbers.
+const enterprisesOID = "1.3.6.1.4.1."
+
+// MaxHosts is the largest number of addresses Scan probes, a /16.
+const MaxHosts = 1 << 16
+
+// Device is a sign found by Scan.
+type Device struct {
+	Target      string // the IP address of the sign
+	Port        uint16
+	SignType    int    // dmsSignType, e.g. d.SignTypeVMSFull.Int()
+	ObjectID    string // sysObjectID, "" if the sign does not report it
+	Enterprise  int    // the private enterprise number of ObjectID, the vendor; 0 if unknown
+	Description string // sysDescr
+
+	// Make, Model and Version are moduleMake, moduleModel and moduleVersion
+	// of the first row of the globalModuleTable, usually the controller;
+	// empty if the sign does not report them.
+	Make    string
+	Model   string
+	Version string
+}
+
+// Option configures Scan.
+type Option func(*scanner)
+
+// WithCommunity sets the community of the probes, "public" by default.
+func WithCommunity(community string) Option {
+	return func(s *scanner) { s.community = community }
+}
+
+// WithPort sets the SNMP port probed, 161 by default.
+func WithPort(port uint16) Option {
+	return func(s *scanner) { s.port = port }
+}
+
+// WithVersion sets the SNMP version of the probes, gosnmp.Version2c by
+// default.
+func WithVersion(version gosnmp.SnmpVersion) Option {
+	return func(s *scanner) { s.version = version }
+}
+
+// WithTimeout bounds how long a probe waits for an answer, 500 milliseconds
+// by default. Most addresses of a subnet do not answer, so the timeout
+// dominates the duration of a scan.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *scanner) { s.timeout = timeout }
+}
+
+// WithRetries sets the number of times an unanswered probe is repeated, 0 by
+// default.
+func WithRetries(retries int) Option {
+	return func(s *scanner) { s.retries = retries }
+}
+
+// WithConcurrency sets the number of probes in flight, 64 by default.
+func WithConcurrency(n int) Option {
+	return func(s *scanner) { s.concurrency = n }
+}
+
+type scanner struct {
+	community   string
+	port        uint16
+	version     gosnmp.SnmpVersion
+	timeout     time.Duration
+	retries     int
+	concurrency int
+}
+
+// probeOIDs are the objects each probe GETs.
+var probeOIDs = []string{
+	d.DmsSignType.Identifier(0),
+	sysObjectIDOID,
+	sysDescrOID,
+	d.ModuleMake.Identifier(1),
+	d.ModuleModel.Identifier(1),
+	d.ModuleVersion.Identifier(1),
+}
+
+// Scan probes every host address of the IPv4 subnet in CIDR notation, e.g.
+// "10.0.11.0/24", and returns the signs found, ordered by address. The
+// network and broadcast addresses of subnets larger than /31 are skipped.
+// Scan stops early, returning the signs found so far and the error of ctx,
+// when ctx is done.
+func Scan(ctx context.Context, subnet string, options ...Option) ([]Device, error) {
+	s := scanner{community: "public", port: 161, version: gosnmp.Version2c, timeout: 500 * time.Millisecond, concurrency: 64}
+	for _, option := range options {
+		option(&s)
+	}
+	hosts, err := hosts(subnet)
+	if err != nil {
+		return nil, err
+	}
+	if s.concurrency < 1 {
+		s.concurrency = 1
+	}
+
+	addresses := make(chan netip.Addr)
+	var mu sync.Mutex
+	var devices []Device
+	var wg sync.WaitGroup
+	for i := 0; i < s.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range addresses {
+				if device, ok := s.probe(address); ok {
+					mu.Lock()
+					devices = append(devices, device)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+feed:
+	for _, address := range hosts {
+		select {
+		case addresses <- address:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(addresses)
+	wg.Wait()
+
+	sort.Slice(devices, func(i, j int) bool {
+		return netip.MustParseAddr(devices[i].Target).Less(netip.MustParseAddr(devices[j].Target))
+	})
+	return devices, ctx.Err()
+}
+
+// hosts returns the host addresses of subnet.
+func hosts(subnet string) ([]netip.Addr, error) {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return nil, err
+	}
+	if !prefix.Addr().Is4() {
+		return nil, fmt.Errorf("subnet %s is not IPv4", subnet)
+	}
+	if bits := 32 - prefix.Bits(); bits > 16 {
+		return nil, fmt.Errorf("subnet %s has more than %d addresses", subnet, MaxHosts)
+	}
+	prefix = prefix.Masked()
+	var addresses []netip.Addr
+	for address := prefix.Addr(); prefix.Contains(address); address = address.Next() {
+		addresses = append(addresses, address)
+	}
+	if len(addresses) > 2 {
+		addresses = addresses[1 : len(addresses)-1]
+	}
+	return addresses, nil
+}
+
+// probe GETs the probeOIDs of address and reports whether it is a sign. An
+// SNMPv1 agent fails the whole request on an object it does not have, so the
+// object is dropped and the request repeated, unless it is dmsSignType.
+func (s scanner) probe(address netip.Addr) (Device, bool) {
+	dms := &gosnmp.GoSNMP{
+		Target:    address.String(),
+		Port:      s.port,
+		Transport: "udp",
+		Community: s.community,
+		Version:   s.version,
+		Timeout:   s.timeout,
+		Retries:   s.retries,
+		MaxOids:   gosnmp.MaxOids,
+	}
+	if err := dms.Connect(); err != nil {
+		return Device{}, false
+	}
+	defer dms.Conn.Close()
+
+	device := Device{Target: dms.Target, Port: s.port}
+	oids := append([]string{}, probeOIDs...)
+	for len(oids) > 0 {
+		result, err := dms.Get(oids)
+		if err != nil {
+			return Device{}, false
+		}
+		if result.Error == gosnmp.NoSuchName && int(result.ErrorIndex) > 1 && int(result.ErrorIndex) <= len(oids) {
+			oids = append(oids[:result.ErrorIndex-1], oids[result.ErrorIndex:]...)
+			continue
+		}
+		if result.Error != gosnmp.NoError {
+			return Device{}, false
+		}
+		found := false
+		for _, variable := range result.Variables {
+			switch strings.TrimPrefix(variable.Name, ".") {
+			case probeOIDs[0]:
+				device.SignType, found = variable.Value.(int)
+			case sysObjectIDOID:
+				oid, _ := variable.Value.(string)
+				device.ObjectID = strings.TrimPrefix(oid, ".")
+				if strings.HasPrefix(device.ObjectID, enterprisesOID) {
+					number, _, _ := strings.Cut(strings.TrimPrefix(device.ObjectID, enterprisesOID), ".")
+					device.Enterprise, _ = strconv.Atoi(number)
+				}
+			case sysDescrOID:
+				device.Description = octetString(variable.Value)
+			case probeOIDs[3]:
+				device.Make = octetString(variable.Value)
+			case probeOIDs[4]:
+				device.Model = octetString(variable.Value)
+			case probeOIDs[5]:
+				device.Version = octetString(variable.Value)
+			}
+		}
+		return device, found
+	}
+	return Device{}, false
+}
+
+// octetString returns the text of an OCTET STRING value.
+func octetString(value interface{}) string {
+	text, _ := value.([]byte)
+	return string(text)
+}
@@ -0,0 +1,85 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/simulator"
+)
+
+func Test_hosts(t *testing.T) {
+	tests := []struct {
+		subnet  string
+		want    int
+		wantErr bool
+	}{
+		{"10.0.11.0/24", 254, false},
+		{"10.0.11.7/30", 2, false},
+		{"10.0.11.7/32", 1, false},
+		{"10.0.0.0/16", MaxHosts - 2, false},
+		{"10.0.0.0/15", 0, true},
+		{"2001:db8::/120", 0, true},
+		{"10.0.11.0", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subnet, func(t *testing.T) {
+			got, err := hosts(tt.subnet)
+			if (err != nil) != tt.wantErr || len(got) != tt.want {
+				t.Errorf("hosts() = %d addresses, %v, want %d", len(got), err, tt.want)
+			}
+		})
+	}
+	if got, _ := hosts("10.0.11.7/30"); got[0].String() != "10.0.11.5" {
+		t.Errorf("hosts() first address = %v, want 10.0.11.5", got[0])
+	}
+}
+
+func TestScan(t *testing.T) {
+	sign, err := simulator.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sign.Close()
+	port := sign.GoSNMP().Port
+
+	for _, version := range []gosnmp.SnmpVersion{gosnmp.Version1, gosnmp.Version2c} {
+		// 127.0.0.2 does not answer.
+		devices, err := Scan(context.Background(), "127.0.0.0/30", WithPort(port), WithVersion(version), WithTimeout(200*time.Millisecond))
+		if err != nil {
+			t.Fatalf("Scan() %v error = %v", version, err)
+		}
+		if len(devices) != 1 {
+			t.Fatalf("Scan() %v = %+v, want the simulated sign", version, devices)
+		}
+		want := Device{
+			Target: "127.0.0.1", Port: port, SignType: d.SignTypeVMSFull.Int(),
+			ObjectID: "1.3.6.1.4.1.1206.4.2.3", Enterprise: 1206, Description: "godms simulated sign",
+			Make: "Simulated Signs", Model: "SC-100", Version: "rev C",
+		}
+		if devices[0] != want {
+			t.Errorf("Scan() %v = %+v, want %+v", version, devices[0], want)
+		}
+	}
+
+	// A sign without sysObjectID nor globalModuleTable answering SNMPv1.
+	sign.Delete("1.3.6.1.2.1.1.2.0")
+	sign.Delete(d.ModuleMake.Identifier(1))
+	devices, err := Scan(context.Background(), "127.0.0.1/32", WithPort(port), WithVersion(gosnmp.Version1))
+	if err != nil || len(devices) != 1 || devices[0].ObjectID != "" || devices[0].Make != "" || devices[0].Model != "SC-100" {
+		t.Errorf("Scan() of a minimal SNMPv1 sign = %+v, %v", devices, err)
+	}
+
+	sign.Delete(d.DmsSignType.Identifier(0))
+	if devices, err := Scan(context.Background(), "127.0.0.1/32", WithPort(port)); err != nil || len(devices) != 0 {
+		t.Errorf("Scan() of an agent without dmsSignType = %+v, %v, want nothing", devices, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Scan(ctx, "127.0.0.0/24", WithPort(port)); err != context.Canceled {
+		t.Errorf("Scan() canceled error = %v", err)
+	}
+}
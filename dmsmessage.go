@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/gosnmp/gosnmp"
+	"github.com/jacobleehei/godms/crc"
+	"github.com/pkg/errors"
 )
 
 /*****************************************************************************
@@ -42,7 +44,7 @@ var MessageObjects = []Reader{
 // number of different messages that can be assembled.
 // See the Specifications in association with Requirement 3.6.7.1 to determine
 // the messages that must be supported.
-var DmsNumPermanentMsg = dmsMessageParameters{
+var DmsNumPermanentMsg = readOnlyObject{
 	objectType: "dmsNumPermanentMsg",
 	syntax:     INTEGER,
 	status:     MANDATORY,
@@ -163,6 +165,75 @@ var DmsMessageMemoryType = readOnlyObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.5.8.1.1",
 }
 
+// MessageMemoryType is a dmsMessageMemoryType, the primary index of the
+// dmsMessageTable.
+type MessageMemoryType int
+
+const (
+	MemoryOther         MessageMemoryType = 1
+	MemoryPermanent     MessageMemoryType = 2
+	MemoryChangeable    MessageMemoryType = 3
+	MemoryVolatile      MessageMemoryType = 4
+	MemoryCurrentBuffer MessageMemoryType = 5
+	MemorySchedule      MessageMemoryType = 6
+	MemoryBlank         MessageMemoryType = 7
+)
+
+func (m MessageMemoryType) Int() int { return int(m) }
+
+func (m MessageMemoryType) String() string {
+	switch m {
+	case MemoryOther:
+		return "other"
+	case MemoryPermanent:
+		return "permanent"
+	case MemoryChangeable:
+		return "changeable"
+	case MemoryVolatile:
+		return "volatile"
+	case MemoryCurrentBuffer:
+		return "currentBuffer"
+	case MemorySchedule:
+		return "schedule"
+	case MemoryBlank:
+		return "blank"
+	}
+	return fmt.Sprintf("MessageMemoryType(%d)", int(m))
+}
+
+// Activation priorities (dmsActivateMessage) and run-time priorities
+// (dmsMessageRunTimePriority) range from PriorityLowest to PriorityHighest;
+// a message replaces the displayed one only if its activation priority is at
+// least the run-time priority of the displayed message.
+const (
+	PriorityLowest  = 1
+	PriorityHighest = 255
+)
+
+// ValidPriority reports whether priority is in the range PriorityLowest to
+// PriorityHighest.
+func ValidPriority(priority int) bool {
+	return priority >= PriorityLowest && priority <= PriorityHighest
+}
+
+// Message is a row of the dmsMessageTable.
+type Message struct {
+	MemoryType   MessageMemoryType
+	Number       int    // dmsMessageNumber
+	MULTI        string // dmsMessageMultiString
+	Owner        string // dmsMessageOwner
+	Priority     int    // dmsMessageRunTimePriority
+	Beacon       int    // dmsMessageBeacon
+	PixelService int    // dmsMessagePixelService
+	CRC          int    // dmsMessageCRC
+}
+
+// CalculateCRC returns the dmsMessageCRC of the MULTI string, beacon and
+// pixel service of the message.
+func (m Message) CalculateCRC() int {
+	return int(crc.MessageCRC(m.MULTI, m.Beacon, m.PixelService))
+}
+
 // Enumerated listing of row entries within the value of the
 // primary index to this table (dmsMessageMemoryType -object). When the primary
 // index is 'currentBuffer' or 'schedule', then this value must be one (1). When
@@ -315,3 +386,18 @@ var DmsValidateMessageError = readOnlyObject{
 	status:     MANDATORY,
 	identifier: "1.3.6.1.4.1.1206.4.2.3.5.9",
 }
+
+func formatDmsValidateMessageError(getResult interface{}) (interface{}, error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "none",
+		3: "beacons",
+		4: "pixelService",
+		5: "syntaxMULTI",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "dmsValidateMessageError"`)
+	}
+	return formatMap[r], nil
+}
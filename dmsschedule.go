@@ -0,0 +1,41 @@
+package godms
+
+/*********************************************************************
+Schedule Objects (NTCIP 1203)
+dmsSchedule  OBJECT IDENTIFIER ::= { dms 8 }
+
+-- This node is an identifier used to group all objects for support of
+-- the actions of the time base scheduler of a sign: the message
+-- activation each action runs.
+*********************************************************************/
+
+var ScheduleObjects = []Reader{
+	DmsActionTableEntries,
+	DmsActionIndex,
+	DmsActionMsgCode,
+}
+
+// The number of rows of the dmsActionTable.
+var DmsActionTableEntries = readOnlyObject{
+	objectType: "dmsActionTableEntries",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.8.1",
+}
+
+// The index of a row of the dmsActionTable.
+var DmsActionIndex = readOnlyObject{
+	objectType: "dmsActionIndex",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.8.2.1.1",
+}
+
+// The MessageActivationCode the action activates the message with: the
+// duration, the activation priority, the message and the source address.
+var DmsActionMsgCode = readAndWriteObject{
+	objectType: "dmsActionMsgCode",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.8.2.1.2",
+}
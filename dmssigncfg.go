@@ -1,5 +1,7 @@
 package godms
 
+import "github.com/pkg/errors"
+
 /********************************************************************
 Sign Configuration and Capability Objects
 
@@ -90,3 +92,161 @@ var DmsSignTechnology = readOnlyObject{
 	status:     MANDATORY,
 	identifier: "1.3.6.1.4.1.1206.4.2.3.1.9",
 }
+
+// Bits of dmsSignAccess.
+type signAccessFormat int
+
+const (
+	SignAccessOther  signAccessFormat = 1 << 0
+	SignAccessWalkIn signAccessFormat = 1 << 1
+	SignAccessRear   signAccessFormat = 1 << 2
+	SignAccessFront  signAccessFormat = 1 << 3
+)
+
+func (m signAccessFormat) Int() int { return int(m) }
+
+func formatDmsSignAccess(getResult interface{}) (result interface{}, err error) {
+	return formatBitmap(getResult, "formatDmsSignAccess", []string{"other", "walkIn", "rear", "front"})
+}
+
+type signTypeFormat int
+
+const (
+	SignTypeOther           signTypeFormat = 1
+	SignTypeBOS             signTypeFormat = 2
+	SignTypeCMS             signTypeFormat = 3
+	SignTypeVMSChar         signTypeFormat = 4
+	SignTypeVMSLine         signTypeFormat = 5
+	SignTypeVMSFull         signTypeFormat = 6
+	SignTypePortableOther   signTypeFormat = 129
+	SignTypePortableBOS     signTypeFormat = 130
+	SignTypePortableCMS     signTypeFormat = 131
+	SignTypePortableVMSChar signTypeFormat = 132
+	SignTypePortableVMSLine signTypeFormat = 133
+	SignTypePortableVMSFull signTypeFormat = 134
+)
+
+func (m signTypeFormat) Int() int { return int(m) }
+
+func formatDmsSignType(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1:   "other",
+		2:   "bos",
+		3:   "cms",
+		4:   "vmsChar",
+		5:   "vmsLine",
+		6:   "vmsFull",
+		129: "portableOther",
+		130: "portableBOS",
+		131: "portableCMS",
+		132: "portableVMSChar",
+		133: "portableVMSLine",
+		134: "portableVMSFull",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsSignType"`)
+	}
+	return formatMap[r], nil
+}
+
+type legendFormat int
+
+const (
+	LegendOther  legendFormat = 1
+	NoLegend     legendFormat = 2
+	LegendExists legendFormat = 3
+)
+
+func (m legendFormat) Int() int { return int(m) }
+
+func formatDmsLegend(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "noLegend",
+		3: "legendExists",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsLegend"`)
+	}
+	return formatMap[r], nil
+}
+
+type beaconTypeFormat int
+
+const (
+	BeaconOther                beaconTypeFormat = 1
+	BeaconNone                 beaconTypeFormat = 2
+	BeaconOne                  beaconTypeFormat = 3
+	BeaconTwoSyncFlash         beaconTypeFormat = 4
+	BeaconTwoOppFlash          beaconTypeFormat = 5
+	BeaconFourSyncFlash        beaconTypeFormat = 6
+	BeaconFourAltRowFlash      beaconTypeFormat = 7
+	BeaconFourAltColumnFlash   beaconTypeFormat = 8
+	BeaconFourAltDiagonalFlash beaconTypeFormat = 9
+	BeaconFourNoSyncFlash      beaconTypeFormat = 10
+	BeaconOneStrobe            beaconTypeFormat = 11
+	BeaconTwoStrobe            beaconTypeFormat = 12
+	BeaconFourStrobe           beaconTypeFormat = 13
+)
+
+func (m beaconTypeFormat) Int() int { return int(m) }
+
+func formatDmsBeaconType(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1:  "other",
+		2:  "none",
+		3:  "oneBeacon",
+		4:  "twoBeaconSyncFlash",
+		5:  "twoBeaconsOppFlash",
+		6:  "fourBeaconSyncFlash",
+		7:  "fourBeaconAltRowFlash",
+		8:  "fourBeaconAltColumnFlash",
+		9:  "fourBeaconAltDiagonalFlash",
+		10: "fourBeaconNoSyncFlash",
+		11: "oneBeaconStrobe",
+		12: "twoBeaconStrobe",
+		13: "fourBeaconStrobe",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsBeaconType"`)
+	}
+	return formatMap[r], nil
+}
+
+// Bits of dmsSignTechnology.
+type signTechnologyFormat int
+
+const (
+	TechnologyOther       signTechnologyFormat = 1 << 0
+	TechnologyLED         signTechnologyFormat = 1 << 1
+	TechnologyFlipDisk    signTechnologyFormat = 1 << 2
+	TechnologyFiberOptics signTechnologyFormat = 1 << 3
+	TechnologyShuttered   signTechnologyFormat = 1 << 4
+	TechnologyLamp        signTechnologyFormat = 1 << 5
+	TechnologyDrum        signTechnologyFormat = 1 << 6
+)
+
+func (m signTechnologyFormat) Int() int { return int(m) }
+
+func formatDmsSignTechnology(getResult interface{}) (result interface{}, err error) {
+	return formatBitmap(getResult, "formatDmsSignTechnology", []string{"other", "led", "flipDisk", "fiberOptics", "shuttered", "lamp", "drum"})
+}
+
+// formatBitmap returns the names of the bits set in a bitmap object, bit 0
+// first.
+func formatBitmap(getResult interface{}, formatter string, names []string) (result interface{}, err error) {
+	r, ok := getResult.(int)
+	if !ok {
+		return []string{}, errors.Errorf(`expect int type for "%s"`, formatter)
+	}
+	formatted := []string{}
+	for bit, name := range names {
+		if r&(1<<bit) != 0 {
+			formatted = append(formatted, name)
+		}
+	}
+	return formatted, nil
+}
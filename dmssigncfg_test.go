@@ -0,0 +1,47 @@
+package godms
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_formatDmsSignTechnology(t *testing.T) {
+	type args struct {
+		getResult interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantResult []string
+		wantErr    bool
+	}{
+		{
+			name: "led and lamp",
+			args: args{
+				getResult: TechnologyLED.Int() | TechnologyLamp.Int(),
+			},
+			wantResult: []string{"led", "lamp"},
+			wantErr:    false,
+		},
+		{
+			name: "not an int",
+			args: args{
+				getResult: "led",
+			},
+			wantResult: []string{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotResult, err := formatDmsSignTechnology(tt.args.getResult)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("formatDmsSignTechnology() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if fmt.Sprint(gotResult) != fmt.Sprint(tt.wantResult) {
+				t.Errorf("formatDmsSignTechnology() = %v, want %v", gotResult, tt.wantResult)
+			}
+		})
+	}
+}
@@ -11,7 +11,10 @@ import (
 const (
 	INTEGER        = gosnmp.Integer
 	OCTET_STRING   = gosnmp.OctetString
-	DISPLAY_STRING = gosnmp.BitString
+	DISPLAY_STRING = gosnmp.OctetString
+
+	OBJECT_IDENTIFIER = gosnmp.ObjectIdentifier
+	COUNTER           = gosnmp.Counter32
 )
 
 type AccessType string
@@ -0,0 +1,67 @@
+package godms
+
+/*********************************************************************
+Dynamic Object Management Objects (NTCIP 1103)
+dynObjMgmt  OBJECT IDENTIFIER ::= { protocols 3 }
+
+-- This node is an identifier used to group the objects defining the
+-- dynamic objects exchanged with the Simple Transportation Management
+-- Protocol (STMP).
+*********************************************************************/
+
+var DynamicObjectManagementObjects = []Reader{
+	DynObjVariable,
+	DynObjConfigOwner,
+	DynObjConfigStatus,
+}
+
+// Number of dynamic objects and of variables of a dynamic object.
+const (
+	MaxDynObjects   = 13
+	MaxDynVariables = 255
+)
+
+// The object identifier of the n-th variable of a dynamic object, indexed
+// by dynObjNumber and dynObjIndex. The variables of a dynamic object are
+// the non-null entries in dynObjIndex order; a null value (0.0) ends the
+// list.
+var DynObjVariable = readAndWriteObject{
+	objectType: "dynObjVariable",
+	syntax:     OBJECT_IDENTIFIER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.1.3.1.1.3",
+}
+
+// The entity that configured the dynamic object, indexed by dynObjNumber.
+var DynObjConfigOwner = readAndWriteObject{
+	objectType: "dynObjConfigOwner",
+	syntax:     DISPLAY_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.1.3.3.1.1",
+}
+
+// The state of the dynamic object, indexed by dynObjNumber. The entries of
+// a dynamic object may only be changed while it is underCreation, and the
+// dynamic object may only be used by STMP while it is valid.
+//
+//	valid (1)
+//	createRequest (2)
+//	underCreation (3)
+//	invalid (4)
+var DynObjConfigStatus = readAndWriteObject{
+	objectType: "dynObjConfigStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.1.3.3.1.2",
+}
+
+type entryStatusFormat int
+
+const (
+	EntryValid         entryStatusFormat = 1
+	EntryCreateRequest entryStatusFormat = 2
+	EntryUnderCreation entryStatusFormat = 3
+	EntryInvalid       entryStatusFormat = 4
+)
+
+func (m entryStatusFormat) Int() int { return int(m) }
@@ -246,7 +246,8 @@ const (
 	FontModifyReq      fontStatusFormat = 7
 	FontReadyForUseReq fontStatusFormat = 8
 	FontNotUsedReq     fontStatusFormat = 9
-	FontUnmanagedReq   fontStatusFormat = 0
+	FontUnmanagedReq   fontStatusFormat = 10
+	FontUnmanaged      fontStatusFormat = 11
 )
 
 func (m fontStatusFormat) Int() int { return int(m) }
@@ -316,5 +317,5 @@ var FontMaxCharacterSize = readOnlyObject{
 	objectType: "fontMaxCharacterSize",
 	syntax:     INTEGER,
 	status:     MANDATORY,
-	identifier: "Multi-Configuration Objects",
+	identifier: "1.3.6.1.4.1.1206.4.2.3.3.5",
 }
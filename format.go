@@ -7,7 +7,22 @@ func Format(objects Reader, getResult interface{}) (result interface{}, err erro
 
 // Mapping parameters for formatting
 var formatMapping = map[string]func(getResult interface{}) (result interface{}, err error){
-	ShortErrorStatus.ObjectType():    formatShortErrorStatusParameter,
-	DmsMultiSyntaxError.ObjectType(): formatDmsMultiSyntaxError,
-	DmsMultiSyntaxError.ObjectType(): formatDmsActivateMsgError,
+	ShortErrorStatus.ObjectType():              formatShortErrorStatusParameter,
+	DmsMultiSyntaxError.ObjectType():           formatDmsMultiSyntaxError,
+	DmsActivateMsgError.ObjectType():           formatDmsActivateMsgError,
+	DmsValidateMessageError.ObjectType():       formatDmsValidateMessageError,
+	DmsMsgSourceMode.ObjectType():              formatDmsMsgSourceMode,
+	DmsControlMode.ObjectType():                formatDmsControlMode,
+	DmsMemoryMgmt.ObjectType():                 formatDmsMemoryMgmt,
+	DmsSignAccess.ObjectType():                 formatDmsSignAccess,
+	DmsSignType.ObjectType():                   formatDmsSignType,
+	DmsLegend.ObjectType():                     formatDmsLegend,
+	DmsBeaconType.ObjectType():                 formatDmsBeaconType,
+	DmsSignTechnology.ObjectType():             formatDmsSignTechnology,
+	DmsIllumControl.ObjectType():               formatDmsIllumControl,
+	DmsIllumBrightnessValuesError.ObjectType(): formatDmsIllumBrightnessValuesError,
+	DmsLightSensorStatus.ObjectType():          formatDmsLightSensorStatus,
+	PowerSource.ObjectType():                   formatPowerSource,
+	DmsPowerStatus.ObjectType():                formatDmsPowerStatus,
+	DmsPowerType.ObjectType():                  formatPowerSource,
 }
@@ -0,0 +1,103 @@
+package godms
+
+/*********************************************************************
+Configuration Objects (NTCIP 1201)
+globalConfiguration  OBJECT IDENTIFIER ::= { global 1 }
+
+-- This node is an identifier used to group all objects for support of
+-- the configuration of the device, e.g. the hardware and software
+-- modules it is made of.
+*********************************************************************/
+
+var ConfigurationObjects = []Reader{
+	GlobalSetIDParameter,
+	GlobalMaxModules,
+	ModuleNumber,
+	ModuleDeviceNode,
+	ModuleMake,
+	ModuleModel,
+	ModuleVersion,
+	ModuleType,
+}
+
+// A value that changes whenever the database of the device changes, e.g. a
+// font or the sign configuration, so that a management station can tell
+// whether the configuration it read is still current.
+var GlobalSetIDParameter = readOnlyObject{
+	objectType: "globalSetIDParameter",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.1",
+}
+
+// The number of rows of the globalModuleTable.
+var GlobalMaxModules = readOnlyObject{
+	objectType: "globalMaxModules",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.2",
+}
+
+// The index of a row of the globalModuleTable.
+var ModuleNumber = readOnlyObject{
+	objectType: "moduleNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.3.1.1",
+}
+
+// The node of the NTCIP device tree the module belongs to, e.g. the dms
+// node for the sign controller.
+var ModuleDeviceNode = readOnlyObject{
+	objectType: "moduleDeviceNode",
+	syntax:     OBJECT_IDENTIFIER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.3.1.2",
+}
+
+// The manufacturer of the module.
+var ModuleMake = readOnlyObject{
+	objectType: "moduleMake",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.3.1.3",
+}
+
+// The model number or name of the module.
+var ModuleModel = readOnlyObject{
+	objectType: "moduleModel",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.3.1.4",
+}
+
+// The version of the module: the hardware revision of a hardware module or
+// the firmware version of a software module.
+var ModuleVersion = readOnlyObject{
+	objectType: "moduleVersion",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.3.1.5",
+}
+
+// Whether the module is hardware or software.
+//
+//	other (1)
+//	hardware (2)
+//	software (3)
+var ModuleType = readOnlyObject{
+	objectType: "moduleType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.1.3.1.6",
+}
+
+type moduleTypeFormat int
+
+const (
+	ModuleOther    moduleTypeFormat = 1
+	ModuleHardware moduleTypeFormat = 2
+	ModuleSoftware moduleTypeFormat = 3
+)
+
+func (m moduleTypeFormat) Int() int { return int(m) }
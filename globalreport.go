@@ -0,0 +1,267 @@
+package godms
+
+/*********************************************************************
+Report Objects (NTCIP 1201)
+globalReport  OBJECT IDENTIFIER ::= { global 4 }
+
+-- This node is an identifier used to group all objects for support of
+-- the event log: the event classes, the event configurations that
+-- decide what is logged, and the logged events.
+*********************************************************************/
+
+var ReportObjects = []Reader{
+	MaxEventLogConfigs,
+	EventConfigID,
+	EventConfigClass,
+	EventConfigMode,
+	EventConfigCompareValue,
+	EventConfigCompareValue2,
+	EventConfigCompareOID,
+	EventConfigLogOID,
+	EventConfigAction,
+	MaxEventLogSize,
+	EventLogClass,
+	EventLogNumber,
+	EventLogID,
+	EventLogTime,
+	EventLogValue,
+	MaxEventClasses,
+	EventClassLimit,
+	EventClassClearTime,
+	EventClassDescription,
+	EventClassNumRowsInLog,
+	EventClassNumEvents,
+	NumEvents,
+}
+
+// The number of rows of the eventLogConfigTable.
+var MaxEventLogConfigs = readOnlyObject{
+	objectType: "maxEventLogConfigs",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.1",
+}
+
+// The number of the event configuration, indexed by eventConfigID.
+var EventConfigID = readOnlyObject{
+	objectType: "eventConfigID",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.1",
+}
+
+// The event class the events of the configuration are logged in, indexed
+// by eventConfigID.
+var EventConfigClass = readAndWriteObject{
+	objectType: "eventConfigClass",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.2",
+}
+
+// The condition of eventConfigCompareOID that logs an event, indexed by
+// eventConfigID.
+//
+//	other (1)
+//	onChange (2) - the value changed
+//	greaterThanValue (3) - the value exceeds eventConfigCompareValue for
+//	  eventConfigCompareValue2 seconds
+//	smallerThanValue (4) - the value is below eventConfigCompareValue for
+//	  eventConfigCompareValue2 seconds
+//	hysteresisBound (5) - the value exceeds eventConfigCompareValue or
+//	  drops below eventConfigCompareValue2
+//	periodic (6) - every eventConfigCompareValue seconds
+//	andedWithValue (7) - the value ANDed with eventConfigCompareValue
+//	  changes from zero to non-zero or back
+var EventConfigMode = readAndWriteObject{
+	objectType: "eventConfigMode",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.3",
+}
+
+// The first value the condition compares with, indexed by eventConfigID.
+var EventConfigCompareValue = readAndWriteObject{
+	objectType: "eventConfigCompareValue",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.4",
+}
+
+// The second value of the condition, indexed by eventConfigID.
+var EventConfigCompareValue2 = readAndWriteObject{
+	objectType: "eventConfigCompareValue2",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.5",
+}
+
+// The object the condition watches, indexed by eventConfigID.
+var EventConfigCompareOID = readAndWriteObject{
+	objectType: "eventConfigCompareOID",
+	syntax:     OBJECT_IDENTIFIER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.6",
+}
+
+// The object whose value is logged as eventLogValue, indexed by
+// eventConfigID.
+var EventConfigLogOID = readAndWriteObject{
+	objectType: "eventConfigLogOID",
+	syntax:     OBJECT_IDENTIFIER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.7",
+}
+
+// Whether the events of the configuration are logged, indexed by
+// eventConfigID.
+//
+//	other (1)
+//	disabled (2)
+//	log (3)
+var EventConfigAction = readAndWriteObject{
+	objectType: "eventConfigAction",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.2.1.8",
+}
+
+type eventConfigModeFormat int
+
+const (
+	EventModeOther            eventConfigModeFormat = 1
+	EventModeOnChange         eventConfigModeFormat = 2
+	EventModeGreaterThanValue eventConfigModeFormat = 3
+	EventModeSmallerThanValue eventConfigModeFormat = 4
+	EventModeHysteresisBound  eventConfigModeFormat = 5
+	EventModePeriodic         eventConfigModeFormat = 6
+	EventModeAndedWithValue   eventConfigModeFormat = 7
+)
+
+func (m eventConfigModeFormat) Int() int { return int(m) }
+
+type eventConfigActionFormat int
+
+const (
+	EventActionOther    eventConfigActionFormat = 1
+	EventActionDisabled eventConfigActionFormat = 2
+	EventActionLog      eventConfigActionFormat = 3
+)
+
+func (m eventConfigActionFormat) Int() int { return int(m) }
+
+// The maximum number of rows of the eventLogTable.
+var MaxEventLogSize = readOnlyObject{
+	objectType: "maxEventLogSize",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.3",
+}
+
+// The event class of the logged event, indexed by eventLogClass and
+// eventLogNumber.
+var EventLogClass = readOnlyObject{
+	objectType: "eventLogClass",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.4.1.1",
+}
+
+// The number of the logged event within its class, indexed by
+// eventLogClass and eventLogNumber. The numbers of a class increase from 1
+// and wrap to 1 after 255.
+var EventLogNumber = readOnlyObject{
+	objectType: "eventLogNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.4.1.2",
+}
+
+// The eventConfigID of the event configuration that logged the event,
+// indexed by eventLogClass and eventLogNumber.
+var EventLogID = readOnlyObject{
+	objectType: "eventLogID",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.4.1.3",
+}
+
+// The time the event occurred, in seconds since 1970-01-01 00:00:00 UTC,
+// indexed by eventLogClass and eventLogNumber.
+var EventLogTime = readOnlyObject{
+	objectType: "eventLogTime",
+	syntax:     COUNTER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.4.1.4",
+}
+
+// The value of the eventConfigLogOID object when the event occurred,
+// indexed by eventLogClass and eventLogNumber.
+var EventLogValue = readOnlyObject{
+	objectType: "eventLogValue",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.4.1.5",
+}
+
+// The number of rows of the eventClassTable.
+var MaxEventClasses = readOnlyObject{
+	objectType: "maxEventClasses",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.5",
+}
+
+// The maximum number of events of the class kept in the eventLogTable,
+// indexed by eventClassNumber. Once reached, a new event replaces the
+// oldest one of the class.
+var EventClassLimit = readAndWriteObject{
+	objectType: "eventClassLimit",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.6.1.2",
+}
+
+// The events of the class that occurred at or before this time, in
+// seconds since 1970-01-01 00:00:00 UTC, are removed from the
+// eventLogTable; indexed by eventClassNumber.
+var EventClassClearTime = readAndWriteObject{
+	objectType: "eventClassClearTime",
+	syntax:     COUNTER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.6.1.3",
+}
+
+// The description of the event class, indexed by eventClassNumber.
+var EventClassDescription = readAndWriteObject{
+	objectType: "eventClassDescription",
+	syntax:     DISPLAY_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.6.1.4",
+}
+
+// The number of rows of the eventLogTable holding events of the class,
+// indexed by eventClassNumber.
+var EventClassNumRowsInLog = readOnlyObject{
+	objectType: "eventClassNumRowsInLog",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.6.1.5",
+}
+
+// The number of events of the class that occurred, including the ones no
+// longer in the eventLogTable, indexed by eventClassNumber.
+var EventClassNumEvents = readOnlyObject{
+	objectType: "eventClassNumEvents",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.6.1.6",
+}
+
+// The number of events that occurred, of all classes.
+var NumEvents = readOnlyObject{
+	objectType: "numEvents",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.4.7",
+}
@@ -0,0 +1,95 @@
+package godms
+
+/*********************************************************************
+Time Management Objects (NTCIP 1201)
+globalTimeManagement  OBJECT IDENTIFIER ::= { global 3 }
+
+-- This node is an identifier used to group all objects for support of
+-- the clock of the device.
+*********************************************************************/
+
+var TimeManagementObjects = []Reader{
+	GlobalTime,
+	GlobalDaylightSaving,
+	GlobalLocalTimeDifferential,
+	ControllerStandardTimeZone,
+	ControllerLocalTime,
+}
+
+// The current time in seconds since 1970-01-01 00:00:00 UTC. Setting it
+// sets the clock of the device.
+var GlobalTime = readAndWriteObject{
+	objectType: "globalTime",
+	syntax:     COUNTER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.1",
+}
+
+// The daylight saving time rule of the device.
+//
+//	other (1)
+//	disableDST (2)
+//	enableUSDST (3)
+//	enableEuropeDST (4)
+//	enableAustraliaDST (5)
+//	enableTasmaniaDST (6)
+//	enableEgyptDST (7)
+//	enableNamibiaDST (8)
+//	enableIranDST (9)
+var GlobalDaylightSaving = readAndWriteObject{
+	objectType: "globalDaylightSaving",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.2",
+}
+
+type daylightSavingFormat int
+
+const (
+	DaylightSavingOther     daylightSavingFormat = 1
+	DaylightSavingDisabled  daylightSavingFormat = 2
+	DaylightSavingUS        daylightSavingFormat = 3
+	DaylightSavingEurope    daylightSavingFormat = 4
+	DaylightSavingAustralia daylightSavingFormat = 5
+	DaylightSavingTasmania  daylightSavingFormat = 6
+	DaylightSavingEgypt     daylightSavingFormat = 7
+	DaylightSavingNamibia   daylightSavingFormat = 8
+	DaylightSavingIran      daylightSavingFormat = 9
+)
+
+func (m daylightSavingFormat) Int() int { return int(m) }
+
+// The difference in seconds between local time, daylight saving time
+// included, and UTC. Replaced by controllerStandardTimeZone in NTCIP 1201 v3.
+//
+//	INTEGER (-43200..43200)
+var GlobalLocalTimeDifferential = readAndWriteObject{
+	objectType: "globalLocalTimeDifferential",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.4",
+}
+
+// The offset in seconds of the standard time of the device from UTC,
+// without daylight saving time.
+//
+//	INTEGER (-43200..43200)
+var ControllerStandardTimeZone = readAndWriteObject{
+	objectType: "controllerStandardTimeZone",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.5",
+}
+
+// The local time of the device in seconds since 1970-01-01 00:00:00, the
+// standard time zone and daylight saving time applied to globalTime.
+var ControllerLocalTime = readOnlyObject{
+	objectType: "controllerLocalTime",
+	syntax:     COUNTER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.6",
+}
+
+// MaxTimeZoneOffset is the largest offset of controllerStandardTimeZone and
+// globalLocalTimeDifferential, in seconds.
+const MaxTimeZoneOffset = 43200
@@ -6,4 +6,9 @@ require github.com/gosnmp/gosnmp v1.34.0
 
 require github.com/pkg/errors v0.9.1
 
-require github.com/davecgh/go-spew v1.1.1 // indirect
+require golang.org/x/image v0.18.0
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
@@ -0,0 +1,220 @@
+package godms
+
+/*******************************************************************
+Graphic Definition Objects
+
+graphicDefinition  OBJECT IDENTIFIER ::= { dms 10 }
+
+-- This node is an identifier used to group all objects for DMS graphic
+-- configurations that are common to DMS devices
+*******************************************************************/
+
+var GraphicDefinitionObjects = []Reader{
+	DmsGraphicMaxEntries,
+	DmsGraphicNumEntries,
+	DmsGraphicMaxSize,
+	AvailableGraphicMemory,
+	DmsGraphicBlockSize,
+	DmsGraphicIndex,
+	DmsGraphicNumber,
+	DmsGraphicName,
+	DmsGraphicHeight,
+	DmsGraphicWidth,
+	DmsGraphicType,
+	DmsGraphicID,
+	DmsGraphicTransparentEnabled,
+	DmsGraphicTransparentColor,
+	DmsGraphicStatus,
+	DmsGraphicBitmapIndex,
+	DmsGraphicBlockNumber,
+	DmsGraphicBlockBitmap,
+}
+
+// Indicates the maximum number of graphics that the sign can store.
+var DmsGraphicMaxEntries = readOnlyObject{
+	objectType: "dmsGraphicMaxEntries",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.1",
+}
+
+// Indicates the current number of entries in the dmsGraphicTable, i.e. the
+// number of rows whose dmsGraphicStatus is not notUsed.
+var DmsGraphicNumEntries = readOnlyObject{
+	objectType: "dmsGraphicNumEntries",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.2",
+}
+
+// Indicates the maximum size, in bytes, of a graphic bitmap that the sign
+// can store.
+var DmsGraphicMaxSize = readOnlyObject{
+	objectType: "dmsGraphicMaxSize",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.3",
+}
+
+// Indicates the number of bytes still available for storing graphics.
+var AvailableGraphicMemory = readOnlyObject{
+	objectType: "availableGraphicMemory",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.4",
+}
+
+// Indicates the size, in bytes, of the blocks in which the graphic bitmaps
+// are transferred through the dmsGraphicBitmapTable.
+var DmsGraphicBlockSize = readOnlyObject{
+	objectType: "dmsGraphicBlockSize",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.5",
+}
+
+// The index of the graphic, a row of the dmsGraphicTable.
+var DmsGraphicIndex = readOnlyObject{
+	objectType: "dmsGraphicIndex",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.1",
+}
+
+// A number used by the MULTI [g] tag to refer to the graphic. The same
+// number shall not be used by two rows that are not notUsed.
+var DmsGraphicNumber = readAndWriteObject{
+	objectType: "dmsGraphicNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.2",
+}
+
+// A name of the graphic, up to 64 characters.
+var DmsGraphicName = readAndWriteObject{
+	objectType: "dmsGraphicName",
+	syntax:     DISPLAY_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.3",
+}
+
+// The height of the graphic in pixels, 1 to 255.
+var DmsGraphicHeight = readAndWriteObject{
+	objectType: "dmsGraphicHeight",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.4",
+}
+
+// The width of the graphic in pixels, 1 to 65535.
+var DmsGraphicWidth = readAndWriteObject{
+	objectType: "dmsGraphicWidth",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.5",
+}
+
+// The color scheme of the graphic bitmap, with the values of
+// dmsColorScheme: monochrome1bit (1), monochrome8bit (2), colorClassic (3)
+// and color24bit (4).
+var DmsGraphicType = readAndWriteObject{
+	objectType: "dmsGraphicType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.6",
+}
+
+type graphicTypeFormat int
+
+const (
+	GraphicMonochrome1Bit graphicTypeFormat = 1
+	GraphicMonochrome8Bit graphicTypeFormat = 2
+	GraphicColorClassic   graphicTypeFormat = 3
+	GraphicColor24Bit     graphicTypeFormat = 4
+)
+
+func (m graphicTypeFormat) Int() int { return int(m) }
+
+// The CRC-16 of the graphic, calculated by the sign when the graphic
+// transitions to readyForUse over the OER encoding of dmsGraphicNumber,
+// dmsGraphicHeight, dmsGraphicWidth, dmsGraphicType,
+// dmsGraphicTransparentEnabled, dmsGraphicTransparentColor and the bitmap.
+var DmsGraphicID = readOnlyObject{
+	objectType: "dmsGraphicID",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.7",
+}
+
+// Indicates whether pixels of the dmsGraphicTransparentColor are
+// transparent.
+var DmsGraphicTransparentEnabled = readAndWriteObject{
+	objectType: "dmsGraphicTransparentEnabled",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.8",
+}
+
+// The color of the graphic that is transparent, one byte for the
+// monochrome and colorClassic types and three bytes for color24bit.
+var DmsGraphicTransparentColor = readAndWriteObject{
+	objectType: "dmsGraphicTransparentColor",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.9",
+}
+
+// This object defines a state machine allowing to manage graphics
+// stored within a DMS, with the states and commands of fontStatus:
+// notUsed (1), modifying (2), calculatingID (3), readyForUse (4), inUse (5),
+// permanent (6), modifyReq (7), readyForUseReq (8) and notUsedReq (9).
+var DmsGraphicStatus = readAndWriteObject{
+	objectType: "dmsGraphicStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.6.1.10",
+}
+
+type graphicStatusFormat int
+
+const (
+	GraphicNotUsed        graphicStatusFormat = 1
+	GraphicModifying      graphicStatusFormat = 2
+	GraphicCalculatingID  graphicStatusFormat = 3
+	GraphicReadyForUse    graphicStatusFormat = 4
+	GraphicInUse          graphicStatusFormat = 5
+	GraphicPermanent      graphicStatusFormat = 6
+	GraphicModifyReq      graphicStatusFormat = 7
+	GraphicReadyForUseReq graphicStatusFormat = 8
+	GraphicNotUsedReq     graphicStatusFormat = 9
+)
+
+func (m graphicStatusFormat) Int() int { return int(m) }
+
+// The dmsGraphicIndex of the graphic a row of the dmsGraphicBitmapTable
+// belongs to.
+var DmsGraphicBitmapIndex = readOnlyObject{
+	objectType: "dmsGraphicBitmapIndex",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.7.1.1",
+}
+
+// The number of a block of the graphic bitmap, starting at 1.
+var DmsGraphicBlockNumber = readOnlyObject{
+	objectType: "dmsGraphicBlockNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.7.1.2",
+}
+
+// A block of the graphic bitmap, of dmsGraphicBlockSize bytes except for
+// the last block. The bitmap is processed by rows, left to right, then top
+// to bottom, with the pixel encoding of dmsGraphicType; for monochrome1bit
+// the most significant bit is the upper left pixel.
+var DmsGraphicBlockBitmap = readAndWriteObject{
+	objectType: "dmsGraphicBlockBitmap",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.10.7.1.3",
+}
@@ -0,0 +1,216 @@
+// Package graphicimg converts PNG and BMP images into NTCIP 1203 graphics,
+// ready to be downloaded with the ConfiguringGraphic dialog.
+//
+// The image is scaled down, keeping its aspect ratio, to fit the maximum
+// size of the sign, composited over black, and mapped to the color scheme
+// of the graphic, optionally with Floyd-Steinberg dithering. The bitmap is
+// encoded by rows, left to right, then top to bottom, as in the
+// dmsGraphicBitmapTable.
+package graphicimg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/dialogs"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+)
+
+// Options control Convert and Decode.
+type Options struct {
+	Type      int   // dmsGraphicType, d.GraphicMonochrome1Bit if 0
+	MaxWidth  int   // maximum width in pixels, 65535 if 0
+	MaxHeight int   // maximum height in pixels, 255 if 0
+	Dither    bool  // Floyd-Steinberg dithering instead of the nearest color
+	Threshold uint8 // luminance at which a monochrome1bit pixel is on; 128 if 0
+}
+
+// ClassicColors are the colors of the colorClassic scheme, indexed by their
+// value: black, red, yellow, green, cyan, blue, magenta, white, orange and
+// amber.
+var ClassicColors = []color.RGBA{
+	{0, 0, 0, 255},
+	{255, 0, 0, 255},
+	{255, 255, 0, 255},
+	{0, 255, 0, 255},
+	{0, 255, 255, 255},
+	{0, 0, 255, 255},
+	{255, 0, 255, 255},
+	{255, 255, 255, 255},
+	{255, 165, 0, 255},
+	{255, 191, 0, 255},
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+// Decode reads a PNG or BMP image and converts it with Convert.
+func Decode(r io.Reader, options Options) (dialogs.Graphic, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(len(pngHeader))
+	var img image.Image
+	var err error
+	switch {
+	case bytes.Equal(header, pngHeader):
+		img, err = png.Decode(br)
+	case bytes.HasPrefix(header, []byte("BM")):
+		img, err = bmp.Decode(br)
+	default:
+		return dialogs.Graphic{}, fmt.Errorf("graphicimg: not a PNG or BMP image")
+	}
+	if err != nil {
+		return dialogs.Graphic{}, fmt.Errorf("graphicimg: %v", err)
+	}
+	return Convert(img, options)
+}
+
+// Convert converts the image into a graphic of options.Type. The Number and
+// Name of the returned graphic are not set.
+func Convert(img image.Image, options Options) (dialogs.Graphic, error) {
+	var result dialogs.Graphic
+	if options.Type == 0 {
+		options.Type = d.GraphicMonochrome1Bit.Int()
+	}
+	if options.MaxWidth == 0 {
+		options.MaxWidth = 65535
+	}
+	if options.MaxHeight == 0 {
+		options.MaxHeight = 255
+	}
+	if options.Threshold == 0 {
+		options.Threshold = 128
+	}
+	if options.MaxWidth < 1 || options.MaxWidth > 65535 || options.MaxHeight < 1 || options.MaxHeight > 255 {
+		return result, fmt.Errorf("graphicimg: maximum size %dx%d out of range 1-65535x1-255", options.MaxWidth, options.MaxHeight)
+	}
+	var quantize func(c [3]float64) (value []byte, quantized [3]float64)
+	switch options.Type {
+	case d.GraphicMonochrome1Bit.Int():
+		quantize = func(c [3]float64) ([]byte, [3]float64) {
+			if clamp(luminance(c)) >= options.Threshold {
+				return []byte{1}, [3]float64{255, 255, 255}
+			}
+			return []byte{0}, [3]float64{}
+		}
+	case d.GraphicMonochrome8Bit.Int():
+		quantize = func(c [3]float64) ([]byte, [3]float64) {
+			y := clamp(luminance(c))
+			return []byte{y}, [3]float64{float64(y), float64(y), float64(y)}
+		}
+	case d.GraphicColorClassic.Int():
+		quantize = func(c [3]float64) ([]byte, [3]float64) {
+			best, bestDistance := 0, -1.0
+			for i, classic := range ClassicColors {
+				dr, dg, db := c[0]-float64(classic.R), c[1]-float64(classic.G), c[2]-float64(classic.B)
+				if distance := dr*dr + dg*dg + db*db; bestDistance < 0 || distance < bestDistance {
+					best, bestDistance = i, distance
+				}
+			}
+			classic := ClassicColors[best]
+			return []byte{byte(best)}, [3]float64{float64(classic.R), float64(classic.G), float64(classic.B)}
+		}
+	case d.GraphicColor24Bit.Int():
+		quantize = func(c [3]float64) ([]byte, [3]float64) {
+			r, g, b := clamp(c[0]), clamp(c[1]), clamp(c[2])
+			return []byte{r, g, b}, [3]float64{float64(r), float64(g), float64(b)}
+		}
+	default:
+		return result, fmt.Errorf("graphicimg: unknown graphic type %d", options.Type)
+	}
+
+	img = fit(img, options.MaxWidth, options.MaxHeight)
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return result, fmt.Errorf("graphicimg: empty image")
+	}
+	pixels := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// RGBA is alpha-premultiplied, so transparent pixels end up black.
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			pixels[y*width+x] = [3]float64{float64(r >> 8), float64(g >> 8), float64(b >> 8)}
+		}
+	}
+
+	result = dialogs.Graphic{Height: height, Width: width, Type: options.Type}
+	result.Bitmap = make([]byte, result.BitmapSize())
+	for i, c := range pixels {
+		value, quantized := quantize(c)
+		switch options.Type {
+		case d.GraphicMonochrome1Bit.Int():
+			if value[0] == 1 {
+				result.Bitmap[i/8] |= 0x80 >> (i % 8)
+			}
+		case d.GraphicColor24Bit.Int():
+			copy(result.Bitmap[i*3:], value)
+		default:
+			result.Bitmap[i] = value[0]
+		}
+		if options.Dither {
+			x, y := i%width, i/width
+			for k := range c {
+				e := c[k] - quantized[k]
+				diffuse(pixels, width, height, x+1, y, k, e*7/16)
+				diffuse(pixels, width, height, x-1, y+1, k, e*3/16)
+				diffuse(pixels, width, height, x, y+1, k, e*5/16)
+				diffuse(pixels, width, height, x+1, y+1, k, e*1/16)
+			}
+		}
+	}
+	return result, nil
+}
+
+// fit scales the image down, keeping its aspect ratio, so that it fits in
+// maxWidth by maxHeight pixels.
+func fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxWidth && height <= maxHeight {
+		return img
+	}
+	if width*maxHeight > height*maxWidth {
+		width, height = maxWidth, height*maxWidth/width
+	} else {
+		width, height = width*maxHeight/height, maxHeight
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+	return scaled
+}
+
+// diffuse adds the error e to the channel k of the pixel at x, y, if any.
+func diffuse(pixels [][3]float64, width, height, x, y, k int, e float64) {
+	if x < 0 || x >= width || y >= height {
+		return
+	}
+	pixels[y*width+x][k] += e
+}
+
+// luminance returns the ITU-R BT.601 luma of the color.
+func luminance(c [3]float64) float64 {
+	return 0.299*c[0] + 0.587*c[1] + 0.114*c[2]
+}
+
+func clamp(v float64) byte {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return byte(v + 0.5)
+	}
+}
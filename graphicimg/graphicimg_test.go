@@ -0,0 +1,97 @@
+package graphicimg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	d "github.com/jacobleehei/godms"
+	"golang.org/x/image/bmp"
+)
+
+func TestConvert(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.White)
+	img.Set(1, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(2, 0, color.RGBA{250, 190, 10, 255})
+	img.Set(3, 1, color.RGBA{128, 128, 128, 255})
+
+	tests := []struct {
+		name       string
+		options    Options
+		wantBitmap []byte
+	}{
+		{name: "monochrome1bit", options: Options{}, wantBitmap: []byte{0xA1}},
+		{name: "monochrome8bit", options: Options{Type: d.GraphicMonochrome8Bit.Int()}, wantBitmap: []byte{255, 76, 187, 0, 0, 0, 0, 128}},
+		{name: "colorClassic", options: Options{Type: d.GraphicColorClassic.Int()}, wantBitmap: []byte{7, 1, 9, 0, 0, 0, 0, 8}},
+		{name: "color24bit", options: Options{Type: d.GraphicColor24Bit.Int()}, wantBitmap: []byte{
+			255, 255, 255, 255, 0, 0, 250, 190, 10, 0, 0, 0,
+			0, 0, 0, 0, 0, 0, 0, 0, 0, 128, 128, 128,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graphic, err := Convert(img, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if graphic.Width != 4 || graphic.Height != 2 || !bytes.Equal(graphic.Bitmap, tt.wantBitmap) {
+				t.Errorf("Convert() = %dx%d %v, want 4x2 %v", graphic.Width, graphic.Height, graphic.Bitmap, tt.wantBitmap)
+			}
+		})
+	}
+
+	if _, err := Convert(img, Options{Type: 5}); err == nil {
+		t.Error("Convert() of an unknown type succeeded")
+	}
+}
+
+func TestConvert_resizeAndDither(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 100, 40))
+	for i := range gray.Pix {
+		gray.Pix[i] = 128
+	}
+	graphic, err := Convert(gray, Options{MaxWidth: 50, MaxHeight: 50, Dither: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graphic.Width != 50 || graphic.Height != 20 || len(graphic.Bitmap) != graphic.BitmapSize() {
+		t.Fatalf("Convert() = %dx%d with %d bytes, want 50x20", graphic.Width, graphic.Height, len(graphic.Bitmap))
+	}
+	on := 0
+	for _, b := range graphic.Bitmap {
+		for ; b != 0; b &= b - 1 {
+			on++
+		}
+	}
+	// Half gray is dithered to about half of the pixels on.
+	if on < 450 || on > 550 {
+		t.Errorf("dithered half gray has %d of 1000 pixels on", on)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 1))
+	img.Set(0, 0, color.White)
+	for name, encode := range map[string]func(*bytes.Buffer) error{
+		"png": func(b *bytes.Buffer) error { return png.Encode(b, img) },
+		"bmp": func(b *bytes.Buffer) error { return bmp.Encode(b, img) },
+	} {
+		var b bytes.Buffer
+		if err := encode(&b); err != nil {
+			t.Fatal(err)
+		}
+		graphic, err := Decode(&b, Options{})
+		if err != nil {
+			t.Fatalf("Decode(%s) error = %v", name, err)
+		}
+		if !bytes.Equal(graphic.Bitmap, []byte{0x80}) {
+			t.Errorf("Decode(%s) bitmap = %v, want [128]", name, graphic.Bitmap)
+		}
+	}
+	if _, err := Decode(bytes.NewReader([]byte("GIF89a")), Options{}); err == nil {
+		t.Error("Decode() of a GIF succeeded")
+	}
+}
@@ -1,5 +1,7 @@
 package godms
 
+import "github.com/pkg/errors"
+
 /*********************************************************************
 Illumination/Brightness Objects
 illum  OBJECT IDENTIFIER ::= { dms 7 }
@@ -55,6 +57,35 @@ var DmsIllumControl = readAndWriteObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.7.1",
 }
 
+type illumControlFormat int
+
+const (
+	IllumOther         illumControlFormat = 1
+	IllumPhotocell     illumControlFormat = 2
+	IllumTimer         illumControlFormat = 3
+	IllumManual        illumControlFormat = 4
+	IllumManualDirect  illumControlFormat = 5
+	IllumManualIndexed illumControlFormat = 6
+)
+
+func (m illumControlFormat) Int() int { return int(m) }
+
+func formatDmsIllumControl(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "photocell",
+		3: "timer",
+		4: "manual",
+		5: "manualDirect",
+		6: "manualIndexed",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsIllumControl"`)
+	}
+	return formatMap[r], nil
+}
+
 // Indicates the maximum value given by the
 // dmsIllumPhotocellLevelStatus-object
 var DmsIllumMaxPhotocellLevel = readOnlyObject{
@@ -197,6 +228,35 @@ var DmsIllumBrightnessValuesError = readOnlyObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.7.8",
 }
 
+type brightnessValuesErrorFormat int
+
+const (
+	BrightnessErrorOther         brightnessValuesErrorFormat = 1
+	BrightnessErrorNone          brightnessValuesErrorFormat = 2
+	BrightnessErrorPhotocellGap  brightnessValuesErrorFormat = 3
+	BrightnessErrorNegativeSlope brightnessValuesErrorFormat = 4
+	BrightnessErrorTooManyLevels brightnessValuesErrorFormat = 5
+	BrightnessErrorInvalidData   brightnessValuesErrorFormat = 6
+)
+
+func (m brightnessValuesErrorFormat) Int() int { return int(m) }
+
+func formatDmsIllumBrightnessValuesError(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "none",
+		3: "photocellGap",
+		4: "negativeSlope",
+		5: "tooManyLevels",
+		6: "invalidData",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsIllumBrightnessValuesError"`)
+	}
+	return formatMap[r], nil
+}
+
 // Indicates the current physical light output value ranging from
 // 0 (darkest) to 65535 (maximum output).
 var DmsIllumLightOutputStatus = readOnlyObject{
@@ -0,0 +1,135 @@
+package multi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ColorScheme is the color scheme of a sign, dmsColorScheme.
+type ColorScheme int
+
+// Color schemes of dmsColorScheme. A sign without dmsColorScheme, i.e. an
+// NTCIP 1203 v1 sign, uses the classic colors.
+const (
+	Monochrome1Bit ColorScheme = 1
+	Monochrome8Bit ColorScheme = 2
+	ColorClassic   ColorScheme = 3
+	Color24Bit     ColorScheme = 4
+)
+
+// Classic colors, the single color values of the colorClassic scheme. A
+// color24bit sign interprets a single color value as a classic color too.
+const (
+	ColorBlack   = 0
+	ColorRed     = 1
+	ColorYellow  = 2
+	ColorGreen   = 3
+	ColorCyan    = 4
+	ColorBlue    = 5
+	ColorMagenta = 6
+	ColorWhite   = 7
+	ColorOrange  = 8
+	ColorAmber   = 9
+)
+
+func (s ColorScheme) String() string {
+	switch s {
+	case Monochrome1Bit:
+		return "monochrome1bit"
+	case Monochrome8Bit:
+		return "monochrome8bit"
+	case ColorClassic:
+		return "colorClassic"
+	case Color24Bit:
+		return "color24bit"
+	}
+	return fmt.Sprintf("ColorScheme(%d)", int(s))
+}
+
+// MaxColor returns the highest single color value of the scheme, the number
+// of foreground and background colors less one: 1 for monochrome1bit, 255 for
+// monochrome8bit and ColorAmber for the classic colors.
+func (s ColorScheme) MaxColor() int {
+	switch s {
+	case Monochrome1Bit:
+		return 1
+	case Monochrome8Bit:
+		return 255
+	}
+	return ColorAmber
+}
+
+// Color is the color parameter of a [cf], [pb] or [cr] tag: either a single
+// value, a classic color or a monochrome intensity, or red, green and blue
+// values for a color24bit sign.
+type Color struct {
+	Value   int // classic color or monochrome intensity, unless RGB
+	RGB     bool
+	R, G, B int
+}
+
+// SingleColor returns the color of a single value, e.g. ColorAmber.
+func SingleColor(value int) Color { return Color{Value: value} }
+
+// RGBColor returns the color of red, green and blue values from 0 to 255.
+func RGBColor(r, g, b int) Color { return Color{RGB: true, R: r, G: g, B: b} }
+
+func (c Color) String() string {
+	if c.RGB {
+		return fmt.Sprintf("%d,%d,%d", c.R, c.G, c.B)
+	}
+	return fmt.Sprint(c.Value)
+}
+
+// ErrColor is returned for a color the color scheme of the sign cannot show.
+var ErrColor = errors.New("color not supported by the color scheme")
+
+// Check returns ErrColor if a sign with the scheme cannot show c: red, green
+// and blue values on a sign other than color24bit, or a value out of range.
+// The sign would otherwise reject the message during validation.
+func (s ColorScheme) Check(c Color) error {
+	if c.RGB {
+		if s != Color24Bit {
+			return fmt.Errorf("%w: color %s on a %s sign", ErrColor, c, s)
+		}
+		for _, value := range []int{c.R, c.G, c.B} {
+			if value < 0 || value > 255 {
+				return fmt.Errorf("%w: color %s out of range 0-255", ErrColor, c)
+			}
+		}
+		return nil
+	}
+	if c.Value < 0 || c.Value > s.MaxColor() {
+		return fmt.Errorf("%w: color %s out of range 0-%d on a %s sign", ErrColor, c, s.MaxColor(), s)
+	}
+	return nil
+}
+
+// ForegroundTag returns the [cf] tag of c for a sign with the scheme.
+func (s ColorScheme) ForegroundTag(c Color) (string, error) {
+	if err := s.Check(c); err != nil {
+		return "", err
+	}
+	return "[cf" + c.String() + "]", nil
+}
+
+// PageBackgroundTag returns the [pb] tag of c for a sign with the scheme.
+func (s ColorScheme) PageBackgroundTag(c Color) (string, error) {
+	if err := s.Check(c); err != nil {
+		return "", err
+	}
+	return "[pb" + c.String() + "]", nil
+}
+
+// ColorRectangleTag returns the [cr] tag filling the rectangle of the given
+// size at x, y (1-based pixels from the top left corner) with c for a sign
+// with the scheme.
+func (s ColorScheme) ColorRectangleTag(x, y, width, height int, c Color) (string, error) {
+	if err := s.Check(c); err != nil {
+		return "", err
+	}
+	if x < 1 || y < 1 || width < 0 || height < 0 {
+		return "", fmt.Errorf("color rectangle %d,%d,%d,%d out of the sign", x, y, width, height)
+	}
+	return fmt.Sprintf("[cr%d,%d,%d,%d,%s]", x, y, width, height, c), nil
+}
@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestColorScheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     func() (string, error)
+		want    string
+		wantErr bool
+	}{
+		{"classic foreground", func() (string, error) { return ColorClassic.ForegroundTag(SingleColor(ColorAmber)) }, "[cf9]", false},
+		{"24-bit page background", func() (string, error) { return Color24Bit.PageBackgroundTag(RGBColor(0, 0, 128)) }, "[pb0,0,128]", false},
+		{"classic on 24-bit", func() (string, error) { return Color24Bit.ForegroundTag(SingleColor(ColorRed)) }, "[cf1]", false},
+		{"monochrome rectangle", func() (string, error) { return Monochrome8Bit.ColorRectangleTag(1, 1, 10, 5, SingleColor(128)) }, "[cr1,1,10,5,128]", false},
+		{"24-bit on classic", func() (string, error) { return ColorClassic.ForegroundTag(RGBColor(255, 0, 0)) }, "", true},
+		{"classic out of range", func() (string, error) { return ColorClassic.ForegroundTag(SingleColor(10)) }, "", true},
+		{"1-bit out of range", func() (string, error) { return Monochrome1Bit.PageBackgroundTag(SingleColor(2)) }, "", true},
+		{"rgb out of range", func() (string, error) { return Color24Bit.ForegroundTag(RGBColor(256, 0, 0)) }, "", true},
+		{"rectangle outside", func() (string, error) { return ColorClassic.ColorRectangleTag(0, 1, 10, 5, SingleColor(ColorRed)) }, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tag()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("tag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := ColorClassic.Check(RGBColor(255, 0, 0)); !errors.Is(err, ErrColor) {
+		t.Errorf("Check() error = %v, want ErrColor", err)
+	}
+	if got := Monochrome8Bit.MaxColor(); got != 255 {
+		t.Errorf("MaxColor() = %d, want 255", got)
+	}
+}
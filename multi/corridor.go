@@ -0,0 +1,82 @@
+package multi
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+)
+
+// SignMessage is the message pending for one sign of a corridor.
+type SignMessage struct {
+	Sign  Sign
+	Fonts []Font
+	MULTI string
+}
+
+// corridorGutter is the color between the previews of a corridor.
+var corridorGutter = color.RGBA{64, 64, 64, 255}
+
+// RenderCorridor renders the messages of the signs of a corridor into one
+// composite image so the whole corridor can be approved at once. Each sign
+// is rendered in its own goroutine. The composite has a row per sign, in
+// order, with its pages side by side; every pixel of a sign is drawn as a
+// scale by scale square and the previews are separated by a gutter of scale
+// pixels.
+func RenderCorridor(messages []SignMessage, scale int) (*image.RGBA, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	pages := make([][]*image.RGBA, len(messages))
+	errs := make([]error, len(messages))
+	var wg sync.WaitGroup
+	for i := range messages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m := messages[i]
+			pages[i], errs[i] = Render(m.MULTI, m.Sign, m.Fonts)
+		}(i)
+	}
+	wg.Wait()
+
+	width, height := scale, scale
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("sign %d: %w", i+1, err)
+		}
+		rowWidth := scale
+		for _, page := range pages[i] {
+			rowWidth += page.Bounds().Dx()*scale + scale
+		}
+		if rowWidth > width {
+			width = rowWidth
+		}
+		height += messages[i].Sign.Height*scale + scale
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for j := 0; j < len(img.Pix); j += 4 {
+		img.Pix[j], img.Pix[j+1], img.Pix[j+2], img.Pix[j+3] = corridorGutter.R, corridorGutter.G, corridorGutter.B, corridorGutter.A
+	}
+	y := scale
+	for i, row := range pages {
+		x := scale
+		for _, page := range row {
+			bounds := page.Bounds()
+			for py := 0; py < bounds.Dy(); py++ {
+				for px := 0; px < bounds.Dx(); px++ {
+					c := page.RGBAAt(px, py)
+					for sy := 0; sy < scale; sy++ {
+						for sx := 0; sx < scale; sx++ {
+							img.SetRGBA(x+px*scale+sx, y+py*scale+sy, c)
+						}
+					}
+				}
+			}
+			x += bounds.Dx()*scale + scale
+		}
+		y += messages[i].Sign.Height*scale + scale
+	}
+	return img, nil
+}
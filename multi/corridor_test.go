@@ -0,0 +1,47 @@
+package multi
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestRenderCorridor(t *testing.T) {
+	messages := []SignMessage{
+		{Sign: Sign{Width: 4, Height: 2}, Fonts: testFonts, MULTI: "[jl2]A[np][jl2]B"},
+		{Sign: Sign{Width: 6, Height: 3}, Fonts: testFonts, MULTI: "[jl2][jp2]AB"},
+	}
+	img, err := RenderCorridor(messages, 2)
+	if err != nil {
+		t.Fatalf("RenderCorridor() error = %v", err)
+	}
+	// Two pages of 8 pixels and three gutters; two rows of 4 and 6 pixels
+	// and three gutters.
+	if img.Bounds().Dx() != 22 || img.Bounds().Dy() != 16 {
+		t.Fatalf("RenderCorridor() bounds = %v, want 22x16", img.Bounds())
+	}
+	amber := color.RGBA{255, 191, 0, 255}
+	tests := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{0, 0, corridorGutter},
+		{2, 2, amber}, // sign 1 page 1 'A'
+		{5, 5, amber}, // the 'A' is two pixels wide
+		{6, 2, color.RGBA{0, 0, 0, 255}},
+		{12, 2, amber},          // sign 1 page 2 'B'
+		{10, 2, corridorGutter}, // between the pages
+		{2, 8, amber},           // sign 2
+		{8, 8, amber},           // its 'B'
+		{2, 13, color.RGBA{0, 0, 0, 255}},
+	}
+	for _, tt := range tests {
+		if got := img.RGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("pixel (%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	messages[1].MULTI = "[fo9]A"
+	if _, err := RenderCorridor(messages, 1); err == nil {
+		t.Error("RenderCorridor() expected an error for an undefined font")
+	}
+}
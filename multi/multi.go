@@ -0,0 +1,94 @@
+// Package multi parses NTCIP 1203 MULTI (Mark-Up Language for
+// Transportation Information) strings and renders them for previews.
+package multi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Element is a piece of a MULTI string: either literal text or a tag.
+type Element struct {
+	Position int    // 1-based position of the element in the MULTI string
+	Text     string // literal text with [[ and ]] unescaped; empty for a tag
+	Tag      string // lower-case tag name, e.g. "nl", "fo" or "/fl"
+	Args     string // tag parameters following the name, e.g. "2" for [jl2]
+}
+
+// SyntaxError reports a malformed MULTI string.
+type SyntaxError struct {
+	Position    int // 1-based position of the offending character
+	Description string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("MULTI syntax error at position %d: %s", e.Position, e.Description)
+}
+
+// tagNames lists the MULTI tag names, longest first so that a name is not
+// taken for the prefix of another one.
+var tagNames = []string{
+	"/fl", "/sc", "/tr",
+	"cb", "cf", "cr", "fl", "fo", "hc", "jl", "jp", "ms", "mv", "nl", "np", "pb", "pt", "sc", "tr",
+	"f", "g",
+}
+
+// Parse splits multi into text and tag elements.
+func Parse(multi string) ([]Element, error) {
+	var elements []Element
+	var text strings.Builder
+	textPosition := 0
+	flush := func() {
+		if text.Len() > 0 {
+			elements = append(elements, Element{Position: textPosition, Text: text.String()})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(multi); i++ {
+		switch c := multi[i]; {
+		case c == '[' && i+1 < len(multi) && multi[i+1] == '[', c == ']' && i+1 < len(multi) && multi[i+1] == ']':
+			if text.Len() == 0 {
+				textPosition = i + 1
+			}
+			text.WriteByte(c)
+			i++
+		case c == '[':
+			end := strings.IndexAny(multi[i+1:], "[]")
+			if end < 0 || multi[i+1+end] == '[' {
+				return nil, &SyntaxError{Position: i + 1, Description: "unterminated tag"}
+			}
+			body := multi[i+1 : i+1+end]
+			if body == "" {
+				return nil, &SyntaxError{Position: i + 1, Description: "empty tag"}
+			}
+			flush()
+			name, args := splitTag(body)
+			elements = append(elements, Element{Position: i + 1, Tag: name, Args: args})
+			i += end + 1
+		case c == ']':
+			return nil, &SyntaxError{Position: i + 1, Description: "unexpected ]"}
+		default:
+			if text.Len() == 0 {
+				textPosition = i + 1
+			}
+			text.WriteByte(c)
+		}
+	}
+	flush()
+	return elements, nil
+}
+
+// splitTag splits the body of a tag into its lower-case name and arguments.
+func splitTag(body string) (name, args string) {
+	lower := strings.ToLower(body)
+	for _, name := range tagNames {
+		if strings.HasPrefix(lower, name) {
+			return name, body[len(name):]
+		}
+	}
+	end := strings.IndexFunc(lower, func(r rune) bool { return (r < 'a' || r > 'z') && r != '/' })
+	if end < 0 {
+		return lower, ""
+	}
+	return lower[:end], body[end:]
+}
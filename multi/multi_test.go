@@ -0,0 +1,34 @@
+package multi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse("[jl2]A[[1]][FO3,1A2B][nl]")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []Element{
+		{Position: 1, Tag: "jl", Args: "2"},
+		{Position: 6, Text: "A[1]"},
+		{Position: 12, Tag: "fo", Args: "3,1A2B"},
+		{Position: 22, Tag: "nl"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+
+	for multi, position := range map[string]int{
+		"AB[nl":    3,
+		"A[nl[np]": 2,
+		"A]":       2,
+		"[]":       1,
+	} {
+		_, err := Parse(multi)
+		if syntaxError, ok := err.(*SyntaxError); !ok || syntaxError.Position != position {
+			t.Errorf("Parse(%q) error = %v, want position %d", multi, err, position)
+		}
+	}
+}
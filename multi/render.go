@@ -0,0 +1,418 @@
+package multi
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+// Sign describes the display of a sign, as read from the vmsCfg and
+// multiCfg objects.
+type Sign struct {
+	Width           int // vmsSignWidthPixels
+	Height          int // vmsSignHeightPixels
+	CharacterWidth  int // vmsCharacterWidthPixels, 0 unless a character matrix sign
+	CharacterHeight int // vmsCharacterHeightPixels, 0 for a full matrix sign
+
+	DefaultFont             int         // defaultFont, font 1 if zero
+	DefaultLineJustify      int         // defaultJustificationLine, center if zero
+	DefaultPageJustify      int         // defaultJustificationPage, top if zero
+	DefaultForegroundColor  color.Color // defaultForegroundRGB, amber if nil
+	DefaultBackgroundColor  color.Color // defaultBackgroundRGB, black if nil
+	DefaultCharacterSpacing int         // used by fonts without fontCharSpacing
+}
+
+// Font is a font of the sign's fontTable with its characterTable.
+type Font struct {
+	Number      int // fontNumber, as referenced by [fo]
+	Height      int // fontHeight
+	CharSpacing int // fontCharSpacing
+	LineSpacing int // fontLineSpacing
+	Characters  []Character
+}
+
+// Character is a row of the characterTable of a font.
+type Character struct {
+	Number int // characterNumber, e.g. 65 for 'A'
+	Width  int // characterWidth
+	Bitmap []byte
+}
+
+// Line and page justification values of [jl] and [jp].
+const (
+	JustifyLeft   = 2
+	JustifyCenter = 3
+	JustifyRight  = 4
+	JustifyFull   = 5
+
+	JustifyTop    = 2
+	JustifyMiddle = 3
+	JustifyBottom = 4
+)
+
+// classicColors are the colors of the NTCIP 1203 v1 [cf] and [cb] tags.
+var classicColors = []color.RGBA{
+	{0, 0, 0, 255},       // black
+	{255, 0, 0, 255},     // red
+	{255, 255, 0, 255},   // yellow
+	{0, 255, 0, 255},     // green
+	{0, 255, 255, 255},   // cyan
+	{0, 0, 255, 255},     // blue
+	{255, 0, 255, 255},   // magenta
+	{255, 255, 255, 255}, // white
+	{255, 165, 0, 255},   // orange
+	{255, 191, 0, 255},   // amber
+}
+
+type glyph struct {
+	character Character
+	font      *Font
+	color     color.Color
+	spacing   int // [sc] spacing, -1 if not set
+}
+
+type segment struct {
+	justify int
+	glyphs  []glyph
+}
+
+type line struct {
+	segments []segment
+	font     *Font // font in effect, for the height of an empty line
+	spacing  int   // [nl] spacing before the line, -1 if not set
+}
+
+type page struct {
+	justify    int
+	background color.Color
+	lines      []line
+}
+
+// Render lays out multi on sign with fonts and returns an image per page.
+// Flashing text is drawn in its on state. Field, graphic, color rectangle,
+// text rectangle and moving text tags are not rendered.
+func Render(multi string, sign Sign, fonts []Font) ([]*image.RGBA, error) {
+	pages, err := layout(multi, sign, fonts)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]*image.RGBA, 0, len(pages))
+	for _, p := range pages {
+		images = append(images, sign.draw(p))
+	}
+	return images, nil
+}
+
+// RenderPNG is like Render but returns every page encoded as PNG.
+func RenderPNG(multi string, sign Sign, fonts []Font) ([][]byte, error) {
+	images, err := Render(multi, sign, fonts)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([][]byte, 0, len(images))
+	for _, img := range images {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, buf.Bytes())
+	}
+	return encoded, nil
+}
+
+func layout(multi string, sign Sign, fonts []Font) ([]page, error) {
+	elements, err := Parse(multi)
+	if err != nil {
+		return nil, err
+	}
+	findFont := func(number int) (*Font, error) {
+		for i := range fonts {
+			if fonts[i].Number == number {
+				return &fonts[i], nil
+			}
+		}
+		return nil, fmt.Errorf("font %d not defined", number)
+	}
+
+	font, err := findFont(withDefault(sign.DefaultFont, 1))
+	if err != nil {
+		return nil, err
+	}
+	lineJustify := withDefault(sign.DefaultLineJustify, JustifyCenter)
+	foreground := sign.DefaultForegroundColor
+	if foreground == nil {
+		foreground = classicColors[9]
+	}
+	spacing := -1
+
+	newPage := func() page {
+		return page{
+			justify:    withDefault(sign.DefaultPageJustify, JustifyTop),
+			background: sign.DefaultBackgroundColor,
+			lines:      []line{{font: font, spacing: -1}},
+		}
+	}
+	pages := []page{newPage()}
+	for _, e := range elements {
+		p := &pages[len(pages)-1]
+		l := &p.lines[len(p.lines)-1]
+		if e.Tag == "" {
+			for _, r := range e.Text {
+				character, ok := font.character(int(r))
+				if !ok {
+					return nil, &SyntaxError{Position: e.Position, Description: fmt.Sprintf("character %q not defined in font %d", r, font.Number)}
+				}
+				if n := len(l.segments); n == 0 || l.segments[n-1].justify != lineJustify {
+					l.segments = append(l.segments, segment{justify: lineJustify})
+				}
+				s := &l.segments[len(l.segments)-1]
+				s.glyphs = append(s.glyphs, glyph{character: character, font: font, color: foreground, spacing: spacing})
+			}
+			continue
+		}
+
+		args := intArgs(e.Args)
+		switch e.Tag {
+		case "np":
+			pages = append(pages, newPage())
+		case "nl":
+			p.lines = append(p.lines, line{font: font, spacing: argOr(args, 0, -1)})
+		case "fo":
+			if font, err = findFont(argOr(args, 0, withDefault(sign.DefaultFont, 1))); err != nil {
+				return nil, &SyntaxError{Position: e.Position, Description: err.Error()}
+			}
+			if len(l.segments) == 0 {
+				l.font = font
+			}
+		case "jl":
+			lineJustify = argOr(args, 0, withDefault(sign.DefaultLineJustify, JustifyCenter))
+		case "jp":
+			p.justify = argOr(args, 0, withDefault(sign.DefaultPageJustify, JustifyTop))
+		case "cf":
+			if foreground = argColor(args); foreground == nil {
+				foreground = sign.DefaultForegroundColor
+				if foreground == nil {
+					foreground = classicColors[9]
+				}
+			}
+		case "cb", "pb":
+			p.background = argColor(args)
+		case "sc":
+			spacing = argOr(args, 0, -1)
+		case "/sc":
+			spacing = -1
+		}
+	}
+	return pages, nil
+}
+
+func (f *Font) character(number int) (Character, bool) {
+	for _, character := range f.Characters {
+		if character.Number == number {
+			return character, true
+		}
+	}
+	return Character{}, false
+}
+
+// draw paints a laid out page.
+func (sign Sign) draw(p page) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, sign.Width, sign.Height))
+	background := p.background
+	if background == nil {
+		background = sign.DefaultBackgroundColor
+	}
+	if background == nil {
+		background = classicColors[0]
+	}
+	for y := 0; y < sign.Height; y++ {
+		for x := 0; x < sign.Width; x++ {
+			img.Set(x, y, background)
+		}
+	}
+
+	heights := make([]int, len(p.lines))
+	total := 0
+	for i, l := range p.lines {
+		heights[i] = sign.lineHeight(l)
+		total += heights[i]
+		if i > 0 {
+			total += sign.lineSpacing(p.lines[i-1], l)
+		}
+	}
+	y := 0
+	switch p.justify {
+	case JustifyMiddle:
+		y = (sign.Height - total) / 2
+	case JustifyBottom:
+		y = sign.Height - total
+	}
+	for i, l := range p.lines {
+		if i > 0 {
+			y += sign.lineSpacing(p.lines[i-1], l)
+		}
+		for _, s := range l.segments {
+			width := sign.segmentWidth(s)
+			x := 0
+			switch s.justify {
+			case JustifyCenter:
+				x = (sign.Width - width) / 2
+			case JustifyRight:
+				x = sign.Width - width
+			}
+			sign.drawSegment(img, s, x, y+heights[i])
+		}
+		y += heights[i]
+	}
+	return img
+}
+
+// lineHeight returns the height of the tallest font used on l.
+func (sign Sign) lineHeight(l line) int {
+	if sign.CharacterHeight > 0 {
+		return sign.CharacterHeight
+	}
+	height := 0
+	for _, s := range l.segments {
+		for _, g := range s.glyphs {
+			if g.font.Height > height {
+				height = g.font.Height
+			}
+		}
+	}
+	if height == 0 {
+		height = l.font.Height
+	}
+	return height
+}
+
+// lineSpacing returns the spacing between two lines: the [nl] spacing, or
+// the average of the largest font line spacings of both lines, rounded up.
+func (sign Sign) lineSpacing(above, below line) int {
+	if sign.CharacterHeight > 0 {
+		return 0
+	}
+	if below.spacing >= 0 {
+		return below.spacing
+	}
+	return (maxLineSpacing(above) + maxLineSpacing(below) + 1) / 2
+}
+
+func maxLineSpacing(l line) int {
+	spacing := l.font.LineSpacing
+	for _, s := range l.segments {
+		for _, g := range s.glyphs {
+			if g.font.LineSpacing > spacing {
+				spacing = g.font.LineSpacing
+			}
+		}
+	}
+	return spacing
+}
+
+// characterSpacing returns the spacing before g when it follows previous.
+func (sign Sign) characterSpacing(previous, g glyph) int {
+	switch {
+	case sign.CharacterWidth > 0:
+		return 0
+	case g.spacing >= 0:
+		return g.spacing
+	case previous.font != g.font:
+		return (sign.fontSpacing(previous.font) + sign.fontSpacing(g.font) + 1) / 2
+	default:
+		return sign.fontSpacing(g.font)
+	}
+}
+
+func (sign Sign) fontSpacing(f *Font) int {
+	if f.CharSpacing > 0 {
+		return f.CharSpacing
+	}
+	return sign.DefaultCharacterSpacing
+}
+
+func (sign Sign) characterWidth(g glyph) int {
+	if sign.CharacterWidth > 0 {
+		return sign.CharacterWidth
+	}
+	return g.character.Width
+}
+
+func (sign Sign) segmentWidth(s segment) int {
+	width := 0
+	for i, g := range s.glyphs {
+		if i > 0 {
+			width += sign.characterSpacing(s.glyphs[i-1], g)
+		}
+		width += sign.characterWidth(g)
+	}
+	return width
+}
+
+// drawSegment draws s from x with its characters aligned on baseline.
+func (sign Sign) drawSegment(img *image.RGBA, s segment, x, baseline int) {
+	for i, g := range s.glyphs {
+		if i > 0 {
+			x += sign.characterSpacing(s.glyphs[i-1], g)
+		}
+		top := baseline - g.font.Height
+		for bit := 0; bit < g.character.Width*g.font.Height; bit++ {
+			if bit/8 >= len(g.character.Bitmap) {
+				break
+			}
+			if g.character.Bitmap[bit/8]&(0x80>>(bit%8)) != 0 {
+				img.Set(x+bit%g.character.Width, top+bit/g.character.Width, g.color)
+			}
+		}
+		x += sign.characterWidth(g)
+	}
+}
+
+// intArgs parses the comma separated numeric arguments of a tag. Arguments
+// that are not numbers, such as a font version ID, are returned as -1.
+func intArgs(args string) []int {
+	if args == "" {
+		return nil
+	}
+	var values []int
+	for _, arg := range strings.Split(args, ",") {
+		value, err := strconv.Atoi(arg)
+		if err != nil {
+			value = -1
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func argOr(args []int, i, value int) int {
+	if i < len(args) && args[i] >= 0 {
+		return args[i]
+	}
+	return value
+}
+
+// argColor returns the color of a [cf], [cb] or [pb] tag: a classic color
+// number or red, green and blue values. nil stands for the default color.
+func argColor(args []int) color.Color {
+	switch len(args) {
+	case 1:
+		if args[0] >= 0 && args[0] < len(classicColors) {
+			return classicColors[args[0]]
+		}
+	case 3:
+		return color.RGBA{uint8(args[0]), uint8(args[1]), uint8(args[2]), 255}
+	}
+	return nil
+}
+
+func withDefault(value, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
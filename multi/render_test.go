@@ -0,0 +1,85 @@
+package multi
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+var testFonts = []Font{{
+	Number:      1,
+	Height:      2,
+	CharSpacing: 1,
+	LineSpacing: 1,
+	Characters: []Character{
+		{Number: 'A', Width: 2, Bitmap: []byte{0xF0}},
+		{Number: 'B', Width: 1, Bitmap: []byte{0xC0}},
+	},
+}}
+
+// pixels returns the lit pixels of img as rows of '#' and '.'.
+func pixels(img *image.RGBA) string {
+	var b strings.Builder
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if r, _, _, _ := img.At(x, y).RGBA(); r > 0 {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestRender(t *testing.T) {
+	sign := Sign{Width: 8, Height: 5}
+	images, err := Render("[jl2]AB[nl][jl4]A[np][jp4][jl3]B", sign, testFonts)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := []string{
+		"##.#....\n" +
+			"##.#....\n" +
+			"........\n" +
+			"......##\n" +
+			"......##\n",
+		"........\n" +
+			"........\n" +
+			"........\n" +
+			"...#....\n" +
+			"...#....\n",
+	}
+	if len(images) != len(want) {
+		t.Fatalf("Render() returned %d pages, want %d", len(images), len(want))
+	}
+	for i, img := range images {
+		if got := pixels(img); got != want[i] {
+			t.Errorf("page %d =\n%s\nwant\n%s", i+1, got, want[i])
+		}
+	}
+
+	if _, err := Render("[fo2]A", sign, testFonts); err == nil {
+		t.Errorf("Render() expected an error for an undefined font")
+	}
+	if _, err := Render("C", sign, testFonts); err == nil {
+		t.Errorf("Render() expected an error for an undefined character")
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	encoded, err := RenderPNG("AB", Sign{Width: 8, Height: 5}, testFonts)
+	if err != nil {
+		t.Fatalf("RenderPNG() error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(encoded[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 8, 5) {
+		t.Errorf("RenderPNG() bounds = %v", img.Bounds())
+	}
+}
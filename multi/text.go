@@ -0,0 +1,82 @@
+package multi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldNames are the placeholders of the [f] field tags, by field ID.
+var fieldNames = map[int]string{
+	1:  "time12",
+	2:  "time24",
+	3:  "tempC",
+	4:  "tempF",
+	5:  "speedKMH",
+	6:  "speedMPH",
+	7:  "dayOfWeek",
+	8:  "dateOfMonth",
+	9:  "monthOfYear",
+	10: "year2",
+	11: "year4",
+	12: "time12AMPM",
+	13: "time12ampm",
+}
+
+// FieldPlaceholder returns the placeholder Text uses for a [f] field tag,
+// e.g. "{time24}" for [f2]. Unknown field IDs are rendered as "{f<id>}".
+func FieldPlaceholder(id, width int) string {
+	if name, ok := fieldNames[id]; ok {
+		return "{" + name + "}"
+	}
+	return fmt.Sprintf("{f%d}", id)
+}
+
+// Text returns the text of multi by page and line, with the tags removed and
+// field tags replaced by FieldPlaceholder. Moving text keeps its text; other
+// tags, e.g. fonts, colors and graphics, are dropped.
+func Text(multi string) ([][]string, error) {
+	return TextFunc(multi, FieldPlaceholder)
+}
+
+// TextFunc is like Text but replaces each field tag with field(id, width),
+// e.g. to fill in the current time or temperature. width is 0 if the tag
+// has none.
+func TextFunc(multi string, field func(id, width int) string) ([][]string, error) {
+	elements, err := Parse(multi)
+	if err != nil {
+		return nil, err
+	}
+	pages := [][]string{{""}}
+	appendText := func(text string) {
+		page := pages[len(pages)-1]
+		page[len(page)-1] += text
+	}
+	for _, e := range elements {
+		switch e.Tag {
+		case "":
+			appendText(e.Text)
+		case "np":
+			pages = append(pages, []string{""})
+		case "nl":
+			pages[len(pages)-1] = append(pages[len(pages)-1], "")
+		case "f":
+			id, width := e.Args, 0
+			if i := strings.IndexByte(e.Args, ','); i >= 0 {
+				id = e.Args[:i]
+				width, _ = strconv.Atoi(e.Args[i+1:])
+			}
+			number, err := strconv.Atoi(id)
+			if err != nil {
+				return nil, &SyntaxError{Position: e.Position, Description: fmt.Sprintf("invalid field tag [f%s]", e.Args)}
+			}
+			appendText(field(number, width))
+		case "mv":
+			// [mv<mode><direction><width>,<step>,<rate>,<text>]
+			if parts := strings.SplitN(e.Args, ",", 4); len(parts) == 4 {
+				appendText(parts[3])
+			}
+		}
+	}
+	return pages, nil
+}
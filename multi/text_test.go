@@ -0,0 +1,38 @@
+package multi
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	got, err := Text("[jp3][fo2]ACCIDENT[nl]AHEAD[np][cf255,0,0]TEMP [f3,2]C AT [f2][nl][mvcl50,1,2,SLOW DOWN]")
+	if err != nil {
+		t.Fatalf("Text() error = %v", err)
+	}
+	want := [][]string{
+		{"ACCIDENT", "AHEAD"},
+		{"TEMP {tempC}C AT {time24}", "SLOW DOWN"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+
+	got, err = TextFunc("TEMP [f3,2]C[f99]", func(id, width int) string {
+		if id == 3 {
+			return strconv.Itoa(width) + "0"
+		}
+		return FieldPlaceholder(id, width)
+	})
+	if err != nil {
+		t.Fatalf("TextFunc() error = %v", err)
+	}
+	if want := [][]string{{"TEMP 20C{f99}"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TextFunc() = %q, want %q", got, want)
+	}
+
+	if _, err := Text("[fx]"); err == nil {
+		t.Errorf("Text() expected an error for an invalid field tag")
+	}
+}
@@ -0,0 +1,213 @@
+package godms
+
+/*********************************************************************
+Notification Target Objects (RFC 3413)
+snmpTargetObjects  OBJECT IDENTIFIER ::= { snmpTargetMIB 1 }
+snmpNotifyObjects  OBJECT IDENTIFIER ::= { snmpNotificationMIB 1 }
+
+-- NTCIP 1103 v03 uses the SNMP-TARGET-MIB and the SNMP-NOTIFICATION-MIB
+-- to configure the managers a device sends its notifications to. The
+-- rows of the three tables are indexed by their name as an IMPLIED
+-- string, one sub-identifier per character.
+*********************************************************************/
+
+var NotificationTargetObjects = []Reader{
+	SnmpTargetAddrTDomain,
+	SnmpTargetAddrTAddress,
+	SnmpTargetAddrTimeout,
+	SnmpTargetAddrRetryCount,
+	SnmpTargetAddrTagList,
+	SnmpTargetAddrParams,
+	SnmpTargetAddrStorageType,
+	SnmpTargetAddrRowStatus,
+	SnmpTargetParamsMPModel,
+	SnmpTargetParamsSecurityModel,
+	SnmpTargetParamsSecurityName,
+	SnmpTargetParamsSecurityLevel,
+	SnmpTargetParamsStorageType,
+	SnmpTargetParamsRowStatus,
+	SnmpNotifyTag,
+	SnmpNotifyType,
+	SnmpNotifyStorageType,
+	SnmpNotifyRowStatus,
+}
+
+// SnmpUDPDomain is the snmpTargetAddrTDomain of a UDP over IPv4 address.
+const SnmpUDPDomain = "1.3.6.1.6.1.1"
+
+// The transport type of the address of the target.
+var SnmpTargetAddrTDomain = readAndWriteObject{
+	objectType: "snmpTargetAddrTDomain",
+	syntax:     OBJECT_IDENTIFIER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.2",
+}
+
+// The transport address of the target; for snmpUDPDomain, the 4 octets of
+// the IPv4 address followed by the 2 octets of the UDP port.
+var SnmpTargetAddrTAddress = readAndWriteObject{
+	objectType: "snmpTargetAddrTAddress",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.3",
+}
+
+// The time in hundredths of a second the device waits for the response to
+// an inform before sending it again.
+var SnmpTargetAddrTimeout = readAndWriteObject{
+	objectType: "snmpTargetAddrTimeout",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.4",
+}
+
+// The number of times an inform is sent again without a response.
+var SnmpTargetAddrRetryCount = readAndWriteObject{
+	objectType: "snmpTargetAddrRetryCount",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.5",
+}
+
+// The tags of the target, separated by spaces; a snmpNotifyTag selects the
+// targets with its tag.
+var SnmpTargetAddrTagList = readAndWriteObject{
+	objectType: "snmpTargetAddrTagList",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.6",
+}
+
+// The name of the snmpTargetParamsEntry used to send to the target.
+var SnmpTargetAddrParams = readAndWriteObject{
+	objectType: "snmpTargetAddrParams",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.7",
+}
+
+// The storage type of the row, e.g. nonVolatile (3).
+var SnmpTargetAddrStorageType = readAndWriteObject{
+	objectType: "snmpTargetAddrStorageType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.8",
+}
+
+// The status of the row, a RowStatus.
+var SnmpTargetAddrRowStatus = readAndWriteObject{
+	objectType: "snmpTargetAddrRowStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.2.1.9",
+}
+
+// The message processing model of the notifications: SNMPv1 (0), SNMPv2c
+// (1) or SNMPv3 (3).
+var SnmpTargetParamsMPModel = readAndWriteObject{
+	objectType: "snmpTargetParamsMPModel",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.3.1.2",
+}
+
+// The security model of the notifications: SNMPv1 (1), SNMPv2c (2) or USM
+// (3).
+var SnmpTargetParamsSecurityModel = readAndWriteObject{
+	objectType: "snmpTargetParamsSecurityModel",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.3.1.3",
+}
+
+// The security name of the notifications, the community for SNMPv1 and
+// SNMPv2c and the user name for USM.
+var SnmpTargetParamsSecurityName = readAndWriteObject{
+	objectType: "snmpTargetParamsSecurityName",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.3.1.4",
+}
+
+// The security level of the notifications: noAuthNoPriv (1), authNoPriv (2)
+// or authPriv (3).
+var SnmpTargetParamsSecurityLevel = readAndWriteObject{
+	objectType: "snmpTargetParamsSecurityLevel",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.3.1.5",
+}
+
+// The storage type of the row, e.g. nonVolatile (3).
+var SnmpTargetParamsStorageType = readAndWriteObject{
+	objectType: "snmpTargetParamsStorageType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.3.1.6",
+}
+
+// The status of the row, a RowStatus.
+var SnmpTargetParamsRowStatus = readAndWriteObject{
+	objectType: "snmpTargetParamsRowStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.12.1.3.1.7",
+}
+
+// The tag selecting the targets of the notifications, matched against
+// snmpTargetAddrTagList.
+var SnmpNotifyTag = readAndWriteObject{
+	objectType: "snmpNotifyTag",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.13.1.1.1.2",
+}
+
+// Whether the notifications are sent as trap (1) or inform (2).
+var SnmpNotifyType = readAndWriteObject{
+	objectType: "snmpNotifyType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.13.1.1.1.3",
+}
+
+// The storage type of the row, e.g. nonVolatile (3).
+var SnmpNotifyStorageType = readAndWriteObject{
+	objectType: "snmpNotifyStorageType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.13.1.1.1.4",
+}
+
+// The status of the row, a RowStatus.
+var SnmpNotifyRowStatus = readAndWriteObject{
+	objectType: "snmpNotifyRowStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.6.3.13.1.1.1.5",
+}
+
+type rowStatusFormat int
+
+const (
+	RowActive        rowStatusFormat = 1
+	RowNotInService  rowStatusFormat = 2
+	RowNotReady      rowStatusFormat = 3
+	RowCreateAndGo   rowStatusFormat = 4
+	RowCreateAndWait rowStatusFormat = 5
+	RowDestroy       rowStatusFormat = 6
+)
+
+func (m rowStatusFormat) Int() int { return int(m) }
+
+type notifyTypeFormat int
+
+const (
+	NotifyTrap   notifyTypeFormat = 1
+	NotifyInform notifyTypeFormat = 2
+)
+
+func (m notifyTypeFormat) Int() int { return int(m) }
+
+// StorageNonVolatile is the storage type of rows kept across restarts.
+const StorageNonVolatile = 3
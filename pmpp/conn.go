@@ -0,0 +1,236 @@
+package pmpp
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Addr is the address of a secondary station on a PMPP link.
+type Addr int
+
+func (a Addr) Network() string { return "pmpp" }
+func (a Addr) String() string  { return strconv.Itoa(int(a)) }
+
+// Conn is a net.Conn exchanging SNMP messages with the secondary station at
+// one address of a serial link. Each Write sends one message in a frame and
+// each Read returns the message of one frame from the station, so gosnmp can
+// use it like a UDP connection. Frames of stations without a Conn and
+// invalid frames are dropped.
+//
+// The serial port itself, e.g. a serial device configured for the baud rate
+// of the modem or a connection to a terminal server, is opened by the
+// caller; Conn does not configure it. The Conns of the stations on a
+// multi-drop line share the port, see NewConn.
+type Conn struct {
+	link    *link
+	address int
+
+	frames chan []byte
+	done   chan struct{} // closed by stop
+	once   sync.Once
+
+	mu       sync.Mutex
+	deadline time.Time
+	err      error // why the Conn stopped, returned once frames are drained
+}
+
+// link is a serial port shared by the Conns of the stations on a line: it
+// reads the port on a single goroutine, routes each frame to the Conn of
+// its address and serializes the writes.
+type link struct {
+	port    io.ReadWriteCloser
+	writeMu sync.Mutex
+	conns   map[int]*Conn // guarded by linksMu
+}
+
+var (
+	linksMu sync.Mutex
+	links   = map[io.ReadWriteCloser]*link{} // the links of the open ports
+)
+
+// NewConn returns a Conn to the secondary station at address over port.
+// The Conns of the addresses of a multi-drop line share port: the first
+// one starts reading the frames of port, and the last one closed closes
+// it. There can be a single Conn per address of a port.
+func NewConn(port io.ReadWriteCloser, address int) (*Conn, error) {
+	if _, err := encodeAddress(address); err != nil {
+		return nil, err
+	}
+	linksMu.Lock()
+	defer linksMu.Unlock()
+	l := links[port]
+	if l == nil {
+		l = &link{port: port, conns: map[int]*Conn{}}
+		links[port] = l
+		go l.receive()
+	}
+	if _, ok := l.conns[address]; ok {
+		return nil, fmt.Errorf("PMPP address %d already has a Conn on the port", address)
+	}
+	c := &Conn{
+		link:    l,
+		address: address,
+		frames:  make(chan []byte, 16),
+		done:    make(chan struct{}),
+	}
+	l.conns[address] = c
+	return c, nil
+}
+
+// receive splits the bytes of the port into frames at the flags and queues
+// the SNMP messages of each station to its Conn. A read error of the port
+// stops every Conn of the link; the port is still closed with the last one.
+func (l *link) receive() {
+	buf := make([]byte, 4096)
+	var frame []byte
+	for {
+		n, err := l.port.Read(buf)
+		for _, b := range buf[:n] {
+			if b != Flag {
+				frame = append(frame, b)
+				continue
+			}
+			if len(frame) > 0 {
+				if address, message, err := Decode(frame); err == nil {
+					linksMu.Lock()
+					c := l.conns[address]
+					linksMu.Unlock()
+					if c != nil {
+						c.queue(message)
+					}
+				}
+			}
+			frame = nil
+		}
+		if err != nil {
+			linksMu.Lock()
+			for _, c := range l.conns {
+				c.stop(err)
+			}
+			if links[l.port] == l {
+				delete(links, l.port)
+			}
+			linksMu.Unlock()
+			return
+		}
+	}
+}
+
+// queue passes message to Read, dropping the oldest answer if nobody is
+// reading.
+func (c *Conn) queue(message []byte) {
+	select {
+	case c.frames <- message:
+	default:
+		select {
+		case <-c.frames:
+		default:
+		}
+		select {
+		case c.frames <- message:
+		default:
+		}
+	}
+}
+
+// stop ends the Conn with err, returned by Read once the frames queued are
+// drained.
+func (c *Conn) stop(err error) {
+	c.once.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+		close(c.done)
+	})
+}
+
+// Read returns the SNMP message of the next frame from the station.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case message := <-c.frames:
+		return copy(b, message), nil
+	case <-c.done:
+		select {
+		case message := <-c.frames:
+			return copy(b, message), nil
+		default:
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return 0, c.err
+	case <-timeout:
+		return 0, os.ErrDeadlineExceeded
+	}
+}
+
+// Write sends b, an SNMP message, to the station in a single frame.
+func (c *Conn) Write(b []byte) (int, error) {
+	select {
+	case <-c.done:
+		return 0, net.ErrClosed
+	default:
+	}
+	frame, err := Encode(c.address, b)
+	if err != nil {
+		return 0, err
+	}
+	c.link.writeMu.Lock()
+	defer c.link.writeMu.Unlock()
+	if _, err := c.link.port.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close releases the address of the Conn on the port, and closes the port
+// if no other Conn uses it.
+func (c *Conn) Close() error {
+	linksMu.Lock()
+	l := c.link
+	if l.conns[c.address] != c {
+		linksMu.Unlock()
+		return nil
+	}
+	delete(l.conns, c.address)
+	c.stop(net.ErrClosed)
+	last := len(l.conns) == 0
+	if last && links[l.port] == l {
+		delete(links, l.port)
+	}
+	linksMu.Unlock()
+	if last {
+		return l.port.Close()
+	}
+	return nil
+}
+
+func (c *Conn) LocalAddr() net.Addr  { return Addr(0) }
+func (c *Conn) RemoteAddr() net.Addr { return Addr(c.address) }
+
+// SetDeadline sets the read deadline; writes to the port are not bounded.
+func (c *Conn) SetDeadline(t time.Time) error { return c.SetReadDeadline(t) }
+
+// SetReadDeadline bounds the wait of Read for a frame of the station.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	return nil
+}
+
+// SetWriteDeadline is a no-op; writes to the port are not bounded.
+func (c *Conn) SetWriteDeadline(t time.Time) error { return nil }
@@ -0,0 +1,113 @@
+// Package pmpp carries SNMP over the Point to Multi-Point Protocol (PMPP)
+// of NTCIP 2101 and NTCIP 2103, for signs reachable only over RS-232 or FSK
+// modem links.
+//
+// A PMPP frame is an HDLC unnumbered information frame: an opening flag
+// (0x7E), the address of the secondary station, the control field 0x13 (UI
+// with the poll/final bit set), the initial protocol identifier 0xC1 for
+// SNMP, the SNMP message, the ISO/IEC 3309 FCS least significant byte first
+// and a closing flag. Flag and escape octets inside the frame are escaped
+// with 0x7D and XOR 0x20.
+//
+// Addresses 0 to 62 take a single octet: the address in bits 7 to 2, the
+// group bit 1 clear and the extension bit 0 set. Addresses 63 to 8191 take
+// two octets, the upper 6 bits in the first one, whose extension bit is
+// clear, and the lower 7 bits in bits 7 to 1 of the second one.
+package pmpp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/jacobleehei/godms/crc"
+)
+
+// Frame octets.
+const (
+	Flag    = 0x7E
+	Escape  = 0x7D
+	Control = 0x13 // UI frame, poll/final bit set
+	IPISNMP = 0xC1 // initial protocol identifier of SNMP
+)
+
+// MaxAddress is the highest address of a secondary station.
+const MaxAddress = 8191
+
+// ErrFrame is returned by Decode for a frame that is not a valid PMPP frame
+// carrying SNMP.
+var ErrFrame = errors.New("invalid PMPP frame")
+
+// encodeAddress returns the address field of address.
+func encodeAddress(address int) ([]byte, error) {
+	switch {
+	case address < 0 || address > MaxAddress:
+		return nil, fmt.Errorf("PMPP address %d out of range 0-%d", address, MaxAddress)
+	case address < 63:
+		return []byte{byte(address<<2) | 1}, nil
+	default:
+		return []byte{byte(address>>7) << 2, byte(address&0x7F)<<1 | 1}, nil
+	}
+}
+
+// Encode returns the PMPP frame carrying the SNMP message to the secondary
+// station at address, with its flags.
+func Encode(address int, message []byte) ([]byte, error) {
+	content, err := encodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	content = append(content, Control, IPISNMP)
+	content = append(content, message...)
+	fcs := crc.Checksum(content)
+	content = append(content, byte(fcs), byte(fcs>>8))
+
+	frame := make([]byte, 0, len(content)+8)
+	frame = append(frame, Flag)
+	for _, b := range content {
+		if b == Flag || b == Escape {
+			frame = append(frame, Escape, b^0x20)
+			continue
+		}
+		frame = append(frame, b)
+	}
+	return append(frame, Flag), nil
+}
+
+// Decode returns the address and the SNMP message of a PMPP frame. The flags
+// are optional.
+func Decode(frame []byte) (address int, message []byte, err error) {
+	frame = bytes.TrimPrefix(bytes.TrimSuffix(frame, []byte{Flag}), []byte{Flag})
+	content := make([]byte, 0, len(frame))
+	for i := 0; i < len(frame); i++ {
+		b := frame[i]
+		if b == Escape {
+			if i++; i == len(frame) {
+				return 0, nil, fmt.Errorf("%w: escape at the end", ErrFrame)
+			}
+			b = frame[i] ^ 0x20
+		}
+		content = append(content, b)
+	}
+
+	addressLength := 1
+	if len(content) > 0 && content[0]&1 == 0 {
+		addressLength = 2
+	}
+	if len(content) < addressLength+4 {
+		return 0, nil, fmt.Errorf("%w: %d octets", ErrFrame, len(content))
+	}
+	n := len(content) - 2
+	if fcs := crc.Checksum(content[:n]); content[n] != byte(fcs) || content[n+1] != byte(fcs>>8) {
+		return 0, nil, fmt.Errorf("%w: FCS mismatch", ErrFrame)
+	}
+	if addressLength == 1 {
+		address = int(content[0] >> 2)
+	} else {
+		address = int(content[0]>>2)<<7 | int(content[1]>>1)
+	}
+	if content[addressLength] != Control || content[addressLength+1] != IPISNMP {
+		return address, nil, fmt.Errorf("%w: control %#02x, protocol %#02x", ErrFrame, content[addressLength], content[addressLength+1])
+	}
+	return address, content[addressLength+2 : n], nil
+}
@@ -0,0 +1,172 @@
+package pmpp
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	message := []byte{0x30, 0x7E, 0x02, 0x7D, 0x01}
+	for _, address := range []int{0, 1, 62, 63, 1000, MaxAddress} {
+		frame, err := Encode(address, message)
+		if err != nil {
+			t.Fatalf("Encode(%d) error = %v", address, err)
+		}
+		if frame[0] != Flag || frame[len(frame)-1] != Flag || bytes.Count(frame, []byte{Flag}) != 2 {
+			t.Errorf("Encode(%d) = % X, want flags only at either end", address, frame)
+		}
+		gotAddress, got, err := Decode(frame)
+		if err != nil || gotAddress != address || !bytes.Equal(got, message) {
+			t.Errorf("Decode(Encode(%d)) = %d, % X, %v", address, gotAddress, got, err)
+		}
+	}
+
+	if frame, _ := Encode(1, []byte{0x30}); !bytes.Equal(frame[:4], []byte{Flag, 0x05, Control, IPISNMP}) {
+		t.Errorf("Encode(1) = % X, want address octet 05", frame)
+	}
+	if _, err := Encode(MaxAddress+1, message); err == nil {
+		t.Errorf("Encode(%d) expected an error", MaxAddress+1)
+	}
+
+	frame, _ := Encode(1, message)
+	frame[len(frame)-4] ^= 0xFF
+	if _, _, err := Decode(frame); !errors.Is(err, ErrFrame) {
+		t.Errorf("Decode() of a corrupted frame error = %v, want ErrFrame", err)
+	}
+}
+
+func TestConn(t *testing.T) {
+	local, device := net.Pipe()
+	defer device.Close()
+	conn, err := NewConn(local, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The device answers every frame, preceded by a frame for another
+	// station and line noise.
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := device.Read(buf)
+			if err != nil {
+				return
+			}
+			_, message, err := Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+			other, _ := Encode(4, []byte("other"))
+			answer, _ := Encode(3, append([]byte("re:"), message...))
+			device.Write(append(append(other, 0x00, 0x55), answer...))
+		}
+	}()
+
+	if _, err := conn.Write([]byte("get")); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "re:get" {
+		t.Errorf("Read() = %q, %v, want re:get", buf[:n], err)
+	}
+
+	conn.SetDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := conn.Read(buf); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read() without an answer error = %v, want os.ErrDeadlineExceeded", err)
+	}
+}
+
+func TestConn_multiDrop(t *testing.T) {
+	local, device := net.Pipe()
+	defer device.Close()
+	first, err := NewConn(local, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewConn(local, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewConn(local, 3); err == nil {
+		t.Error("NewConn() of an address with a Conn expected an error")
+	}
+
+	// The stations on the line answer the frames addressed to them.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		buf := make([]byte, 1024)
+		for {
+			n, err := device.Read(buf)
+			if err != nil {
+				return
+			}
+			address, message, err := Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+			answer, _ := Encode(address, append([]byte("re:"), message...))
+			device.Write(answer)
+		}
+	}()
+
+	exchange := func(conn *Conn, request string) error {
+		if _, err := conn.Write([]byte(request)); err != nil {
+			return err
+		}
+		conn.SetDeadline(time.Now().Add(time.Second))
+		buf := make([]byte, 64)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return err
+		}
+		if string(buf[:n]) != "re:"+request {
+			return errors.New("answer " + string(buf[:n]))
+		}
+		return nil
+	}
+	errs := make(chan error, 2)
+	for _, conn := range []*Conn{first, second} {
+		go func(conn *Conn) {
+			for i := 0; i < 20; i++ {
+				if err := exchange(conn, conn.RemoteAddr().String()+":"+strconv.Itoa(i)); err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}(conn)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("exchange on a shared port error = %v", err)
+		}
+	}
+
+	// The port stays open until the last Conn is closed.
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exchange(second, "still open"); err != nil {
+		t.Errorf("exchange after closing the other Conn error = %v", err)
+	}
+	if _, err := first.Write([]byte("closed")); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Write() on a closed Conn error = %v, want net.ErrClosed", err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Error("the port is open after the last Conn was closed")
+	}
+}
@@ -58,6 +58,35 @@ var DmsControlMode = readAndWriteObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.6.1",
 }
 
+type controlModeFormat int
+
+const (
+	ControlOther           controlModeFormat = 1
+	ControlLocal           controlModeFormat = 2
+	ControlExternal        controlModeFormat = 3
+	ControlCentral         controlModeFormat = 4
+	ControlCentralOverride controlModeFormat = 5
+	ControlSimulation      controlModeFormat = 6
+)
+
+func (m controlModeFormat) Int() int { return int(m) }
+
+func formatDmsControlMode(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "local",
+		3: "external",
+		4: "central",
+		5: "centralOverride",
+		6: "simulation",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsControlMode"`)
+	}
+	return formatMap[r], nil
+}
+
 // A software interface to initiate a controller reset. The
 // execution of the controller reset shall set this object to the value 0.
 // Setting this object to a value of 1 causes the controller to reset. Value
@@ -206,6 +235,43 @@ var DmsMsgSourceMode = readAndWriteObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.6.7",
 }
 
+type msgSourceModeFormat int
+
+const (
+	SourceOther              msgSourceModeFormat = 1
+	SourceLocal              msgSourceModeFormat = 2
+	SourceExternal           msgSourceModeFormat = 3
+	SourceCentral            msgSourceModeFormat = 8
+	SourceTimebasedScheduler msgSourceModeFormat = 9
+	SourcePowerRecovery      msgSourceModeFormat = 10
+	SourceReset              msgSourceModeFormat = 11
+	SourceCommLoss           msgSourceModeFormat = 12
+	SourcePowerLoss          msgSourceModeFormat = 13
+	SourceEndDuration        msgSourceModeFormat = 14
+)
+
+func (m msgSourceModeFormat) Int() int { return int(m) }
+
+func formatDmsMsgSourceMode(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1:  "other",
+		2:  "local",
+		3:  "external",
+		8:  "central",
+		9:  "timebasedScheduler",
+		10: "powerRecovery",
+		11: "reset",
+		12: "commLoss",
+		13: "powerLoss",
+		14: "endDuration",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsMsgSourceMode"`)
+	}
+	return formatMap[r], nil
+}
+
 // Indicates the message that shall be activated after a power
 // recovery following a short power loss affecting the device (see
 // dmsActivateMessage). The message shall be activated with:
@@ -374,6 +440,31 @@ var DmsMemoryMgmt = readAndWriteObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.6.16",
 }
 
+type memoryMgmtFormat int
+
+const (
+	MemoryMgmtOther         memoryMgmtFormat = 1
+	MemoryMgmtNormal        memoryMgmtFormat = 2
+	ClearChangeableMessages memoryMgmtFormat = 3
+	ClearVolatileMessages   memoryMgmtFormat = 4
+)
+
+func (m memoryMgmtFormat) Int() int { return int(m) }
+
+func formatDmsMemoryMgmt(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "normal",
+		3: "clearChangeableMessages",
+		4: "clearVolatileMessages",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsMemoryMgmt"`)
+	}
+	return formatMap[r], nil
+}
+
 // This is an error code used to identify why a message was not
 // displayed. Even if multiple errors occur, only one error is indicated.
 //   other (1):  any error not defined below.
@@ -539,7 +630,7 @@ var DmsMultiSyntaxErrorPosition = readOnlyObject{
 // dmsMultiSyntaxError is ‘other(1)’.
 var DmsMultiOtherErrorDescription = readOnlyObject{
 	objectType: "dmsMultiOtherErrorDescription",
-	syntax:     INTEGER,
+	syntax:     OCTET_STRING,
 	status:     MANDATORY,
 	identifier: "1.3.6.1.4.1.1206.4.2.3.6.20",
 }
@@ -1,5 +1,7 @@
 package godms
 
+import "github.com/pkg/errors"
+
 /*********************************************************************
 Sign Status
 
@@ -27,3 +29,139 @@ var StatMultiFieldIndex = readOnlyObject{
 	status:     MANDATORY,
 	identifier: "1.3.6.1.4.1.1206.4.2.3.9.2.1.1",
 }
+
+/*********************************************************************
+Temperature Status
+
+statTemp OBJECT IDENTIFIER ::= { dmsStatus 9 }
+
+-- This node is an identifier used to group all objects supporting DMS
+-- temperature and humidity monitoring.
+***********************************************************************/
+
+// Indicates the current minimum temperature of the controller cabinet, in
+// degrees Celsius.
+var TempMinCtrlCabinet = readOnlyObject{
+	objectType: "tempMinCtrlCabinet",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.1",
+}
+
+// Indicates the current maximum temperature of the controller cabinet, in
+// degrees Celsius.
+var TempMaxCtrlCabinet = readOnlyObject{
+	objectType: "tempMaxCtrlCabinet",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.2",
+}
+
+// Indicates the current minimum ambient temperature, in degrees Celsius.
+var TempMinAmbient = readOnlyObject{
+	objectType: "tempMinAmbient",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.3",
+}
+
+// Indicates the current maximum ambient temperature, in degrees Celsius.
+var TempMaxAmbient = readOnlyObject{
+	objectType: "tempMaxAmbient",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.4",
+}
+
+// Indicates the current minimum temperature of the sign housing, in degrees
+// Celsius.
+var TempMinSignHousing = readOnlyObject{
+	objectType: "tempMinSignHousing",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.5",
+}
+
+// Indicates the current maximum temperature of the sign housing, in degrees
+// Celsius.
+var TempMaxSignHousing = readOnlyObject{
+	objectType: "tempMaxSignHousing",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.6",
+}
+
+// Indicates the current relative humidity inside the sign housing, in
+// percent. Not supported by every sign.
+var TempHumiditySignHousing = readOnlyObject{
+	objectType: "tempHumiditySignHousing",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.9.9",
+}
+
+/*********************************************************************
+Power Status
+
+statPower OBJECT IDENTIFIER ::= { dmsStatus 8 }
+
+-- This node is an identifier used to group all objects supporting DMS
+-- power monitoring.
+***********************************************************************/
+
+// Indicates the voltage of the sign power supply, in hundredths of a volt.
+var SignVolts = readOnlyObject{
+	objectType: "signVolts",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.8.1",
+}
+
+// Indicates the voltage of the power line feeding the sign, in hundredths of
+// a volt.
+var LineVolts = readOnlyObject{
+	objectType: "lineVolts",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.8.5",
+}
+
+// Indicates the current source of power of the sign, e.g. acLine (4),
+// generator (5), solar (6) or battery (7).
+var PowerSource = readOnlyObject{
+	objectType: "powerSource",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.8.6",
+}
+
+type powerSourceFormat int
+
+const (
+	PowerSourceOther     powerSourceFormat = 1
+	PowerShutdown        powerSourceFormat = 2
+	NoSignPower          powerSourceFormat = 3
+	PowerSourceACLine    powerSourceFormat = 4
+	PowerSourceGenerator powerSourceFormat = 5
+	PowerSourceSolar     powerSourceFormat = 6
+	PowerSourceBattery   powerSourceFormat = 7
+)
+
+func (m powerSourceFormat) Int() int { return int(m) }
+
+func formatPowerSource(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "powerShutdown",
+		3: "noSignPower",
+		4: "acLine",
+		5: "generator",
+		6: "solar",
+		7: "battery",
+	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatPowerSource"`)
+	}
+	return formatMap[r], nil
+}
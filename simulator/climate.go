@@ -0,0 +1,50 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// NumFans is the number of fans reported by fanFailures.
+const NumFans = 8
+
+// FailFan makes fan number (from 1) fail; the next fan test reports it.
+func (s *Sign) FailFan(number int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failedFans = append(s.failedFans, number)
+}
+
+// seedClimate reports a mild day without fan failures.
+func (s *Sign) seedClimate() {
+	s.store(d.FanTestActivation.Identifier(0), gosnmp.Integer, d.NoTest.Int())
+	s.store(d.FanFailures.Identifier(0), gosnmp.OctetString, make([]byte, NumFans/8))
+	s.store(d.TempMinCtrlCabinet.Identifier(0), gosnmp.Integer, 24)
+	s.store(d.TempMaxCtrlCabinet.Identifier(0), gosnmp.Integer, 27)
+	s.store(d.TempMinAmbient.Identifier(0), gosnmp.Integer, 18)
+	s.store(d.TempMaxAmbient.Identifier(0), gosnmp.Integer, 19)
+	s.store(d.TempMinSignHousing.Identifier(0), gosnmp.Integer, 25)
+	s.store(d.TempMaxSignHousing.Identifier(0), gosnmp.Integer, 31)
+	s.store(d.TempHumiditySignHousing.Identifier(0), gosnmp.Integer, 40)
+}
+
+// writeFanTest handles a SET of fanTestActivation. The test completes at
+// once. It reports false for other objects.
+func (s *Sign) writeFanTest(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	if name != key(d.FanTestActivation.Identifier(0)) {
+		return gosnmp.NoError, false
+	}
+	switch activation, _ := variable.Value.(int); activation {
+	case d.NoTest.Int():
+	case d.Test.Int():
+		failures := make([]byte, NumFans/8)
+		for _, number := range s.failedFans {
+			failures[(number-1)/8] |= 1 << ((number - 1) % 8)
+		}
+		s.store(d.FanFailures.Identifier(0), gosnmp.OctetString, failures)
+	default:
+		return gosnmp.BadValue, true
+	}
+	s.store(name, variable.Type, d.NoTest.Int())
+	return gosnmp.NoError, true
+}
@@ -0,0 +1,71 @@
+package simulator
+
+import (
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// seedClock starts with the clock of the sign on time, in UTC and with
+// daylight saving time disabled.
+func (s *Sign) seedClock() {
+	s.store(d.GlobalDaylightSaving.Identifier(0), gosnmp.Integer, d.DaylightSavingDisabled.Int())
+	s.store(d.ControllerStandardTimeZone.Identifier(0), gosnmp.Integer, 0)
+	s.tick()
+}
+
+// now returns the time of the clock of the sign.
+func (s *Sign) now() time.Time { return time.Now().Add(s.clockOffset) }
+
+// tick updates globalTime and controllerLocalTime from the clock of the
+// sign, before a request is answered. The simulated sign applies the standard
+// time zone only, not the daylight saving time rule.
+func (s *Sign) tick() {
+	now := s.now().Unix()
+	s.store(d.GlobalTime.Identifier(0), gosnmp.Counter32, uint(now))
+	if offset, ok := s.value(d.ControllerStandardTimeZone.Identifier(0)).(int); ok {
+		s.store(d.ControllerLocalTime.Identifier(0), gosnmp.Counter32, uint(now+int64(offset)))
+	}
+}
+
+// SetClock sets the clock of the sign to t, e.g. to simulate a drifting
+// controller clock.
+func (s *Sign) SetClock(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clockOffset = time.Until(t)
+	s.tick()
+}
+
+// writeClock sets the clock of the sign from a SET of globalTime.
+func (s *Sign) writeClock(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	if name != key(d.GlobalTime.Identifier(0)) {
+		return gosnmp.NoError, false
+	}
+	seconds, _ := variable.Value.(uint)
+	s.clockOffset = time.Until(time.Unix(int64(seconds), 0))
+	s.tick()
+	return gosnmp.NoError, true
+}
+
+// writeTimeZone checks the range of controllerStandardTimeZone and
+// globalDaylightSaving.
+func (s *Sign) writeTimeZone(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	value, _ := variable.Value.(int)
+	switch name {
+	case key(d.ControllerStandardTimeZone.Identifier(0)), key(d.GlobalLocalTimeDifferential.Identifier(0)):
+		if value < -d.MaxTimeZoneOffset || value > d.MaxTimeZoneOffset {
+			return gosnmp.BadValue, true
+		}
+	case key(d.GlobalDaylightSaving.Identifier(0)):
+		if value < d.DaylightSavingOther.Int() || value > d.DaylightSavingIran.Int() {
+			return gosnmp.BadValue, true
+		}
+	default:
+		return gosnmp.NoError, false
+	}
+	s.store(name, variable.Type, value)
+	s.tick()
+	return gosnmp.NoError, true
+}
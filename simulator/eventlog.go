@@ -0,0 +1,127 @@
+package simulator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// Size of the event log of the simulated sign.
+const (
+	MaxEventClasses    = 4
+	MaxEventLogSize    = 200
+	MaxEventLogConfigs = 8
+)
+
+// eventClasses are the descriptions of the event classes of the simulated
+// sign.
+var eventClasses = []string{"communications", "power", "messages", "faults"}
+
+// seedEventLog starts with an empty event log, 50 events per class and
+// disabled event configurations.
+func (s *Sign) seedEventLog() {
+	s.eventLog = map[int][]int{}
+	s.store(d.MaxEventLogConfigs.Identifier(0), gosnmp.Integer, MaxEventLogConfigs)
+	for id := 1; id <= MaxEventLogConfigs; id++ {
+		s.store(d.EventConfigID.Identifier(id), gosnmp.Integer, id)
+		s.store(d.EventConfigClass.Identifier(id), gosnmp.Integer, 1)
+		s.store(d.EventConfigMode.Identifier(id), gosnmp.Integer, d.EventModeOnChange.Int())
+		s.store(d.EventConfigCompareValue.Identifier(id), gosnmp.Integer, 0)
+		s.store(d.EventConfigCompareValue2.Identifier(id), gosnmp.Integer, 0)
+		s.store(d.EventConfigCompareOID.Identifier(id), gosnmp.ObjectIdentifier, nullOID)
+		s.store(d.EventConfigLogOID.Identifier(id), gosnmp.ObjectIdentifier, nullOID)
+		s.store(d.EventConfigAction.Identifier(id), gosnmp.Integer, d.EventActionDisabled.Int())
+	}
+	s.store(d.MaxEventLogSize.Identifier(0), gosnmp.Integer, MaxEventLogSize)
+	s.store(d.MaxEventClasses.Identifier(0), gosnmp.Integer, MaxEventClasses)
+	s.store(d.NumEvents.Identifier(0), gosnmp.Integer, 0)
+	for class := 1; class <= MaxEventClasses; class++ {
+		s.store(d.EventClassLimit.Identifier(class), gosnmp.Integer, 50)
+		s.store(d.EventClassClearTime.Identifier(class), gosnmp.Counter32, uint(0))
+		s.store(d.EventClassDescription.Identifier(class), gosnmp.OctetString, []byte(eventClasses[class-1]))
+		s.store(d.EventClassNumRowsInLog.Identifier(class), gosnmp.Integer, 0)
+		s.store(d.EventClassNumEvents.Identifier(class), gosnmp.Integer, 0)
+	}
+}
+
+func eventLogOID(column d.Reader, class, number int) string {
+	return fmt.Sprintf("%s.%d", column.Identifier(class), number)
+}
+
+// LogEvent adds an event of class, logged by the event configuration id
+// with value, to the event log at t, as the sign does when the condition
+// of an event configuration is met. Once the class has eventClassLimit
+// events in the log, the oldest one is removed.
+func (s *Sign) LogEvent(class, id int, value []byte, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	numbers := s.eventLog[class]
+	numEvents, _ := s.value(d.EventClassNumEvents.Identifier(class)).(int)
+	number := numEvents%255 + 1
+	limit, _ := s.value(d.EventClassLimit.Identifier(class)).(int)
+	for len(numbers) > 0 && len(numbers) >= limit {
+		s.removeEvent(class, numbers[0])
+		numbers = numbers[1:]
+	}
+	if limit > 0 {
+		s.store(eventLogOID(d.EventLogClass, class, number), gosnmp.Integer, class)
+		s.store(eventLogOID(d.EventLogNumber, class, number), gosnmp.Integer, number)
+		s.store(eventLogOID(d.EventLogID, class, number), gosnmp.Integer, id)
+		s.store(eventLogOID(d.EventLogTime, class, number), gosnmp.Counter32, uint(t.Unix()))
+		s.store(eventLogOID(d.EventLogValue, class, number), gosnmp.OctetString, value)
+		numbers = append(numbers, number)
+	}
+	s.eventLog[class] = numbers
+	s.store(d.EventClassNumRowsInLog.Identifier(class), gosnmp.Integer, len(numbers))
+	s.store(d.EventClassNumEvents.Identifier(class), gosnmp.Integer, numEvents+1)
+	total, _ := s.value(d.NumEvents.Identifier(0)).(int)
+	s.store(d.NumEvents.Identifier(0), gosnmp.Integer, total+1)
+}
+
+// removeEvent removes the row of an event from the eventLogTable.
+func (s *Sign) removeEvent(class, number int) {
+	for _, column := range []d.Reader{d.EventLogClass, d.EventLogNumber, d.EventLogID, d.EventLogTime, d.EventLogValue} {
+		s.delete(eventLogOID(column, class, number))
+	}
+}
+
+// writeEventClass applies a SET of eventClassLimit, which removes the
+// oldest events beyond the new limit, or of eventClassClearTime, which
+// removes the events that occurred at or before it.
+func (s *Sign) writeEventClass(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	for class := 1; class <= MaxEventClasses; class++ {
+		switch name {
+		case key(d.EventClassLimit.Identifier(class)):
+			limit, _ := variable.Value.(int)
+			if limit < 0 || limit > MaxEventLogSize {
+				return gosnmp.BadValue, true
+			}
+			s.store(name, variable.Type, limit)
+			numbers := s.eventLog[class]
+			for len(numbers) > limit {
+				s.removeEvent(class, numbers[0])
+				numbers = numbers[1:]
+			}
+			s.eventLog[class] = numbers
+		case key(d.EventClassClearTime.Identifier(class)):
+			clearTime := gosnmp.ToBigInt(variable.Value).Uint64()
+			s.store(name, variable.Type, variable.Value)
+			var kept []int
+			for _, number := range s.eventLog[class] {
+				if t := gosnmp.ToBigInt(s.value(eventLogOID(d.EventLogTime, class, number))).Uint64(); t <= clearTime {
+					s.removeEvent(class, number)
+					continue
+				}
+				kept = append(kept, number)
+			}
+			s.eventLog[class] = kept
+		default:
+			continue
+		}
+		s.store(d.EventClassNumRowsInLog.Identifier(class), gosnmp.Integer, len(s.eventLog[class]))
+		return gosnmp.NoError, true
+	}
+	return gosnmp.NoError, false
+}
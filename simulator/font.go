@@ -0,0 +1,160 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+)
+
+// MaxFontCharacters is the number of characterTable rows of every font,
+// character numbers 1 to 127.
+const MaxFontCharacters = 127
+
+// FontMaxCharacterSize is the largest characterBitmap accepted, in bytes.
+const FontMaxCharacterSize = 32
+
+// Font1Characters are the characters of the permanent font 1: a 7 pixel
+// high font with a space and the letters A to C.
+var Font1Characters = []crc.Character{
+	{Number: ' ', Width: 3, Bitmap: bitmap("...", "...", "...", "...", "...", "...", "...")},
+	{Number: 'A', Width: 5, Bitmap: bitmap(".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#")},
+	{Number: 'B', Width: 5, Bitmap: bitmap("####.", "#...#", "#...#", "####.", "#...#", "#...#", "####.")},
+	{Number: 'C', Width: 5, Bitmap: bitmap(".###.", "#...#", "#....", "#....", "#....", "#...#", ".###.")},
+}
+
+// Font1VersionID is the fontVersionID of the permanent font 1.
+var Font1VersionID = int(crc.FontVersionID(1, 7, 1, 2, Font1Characters))
+
+// bitmap packs rows of '#' (on) and '.' (off) pixels into a characterBitmap.
+func bitmap(rows ...string) []byte {
+	bits := strings.Join(rows, "")
+	packed := make([]byte, (len(bits)+7)/8)
+	for i, c := range bits {
+		if c == '#' {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return packed
+}
+
+func characterOID(column d.Reader, fontIndex, characterNumber int) string {
+	return fmt.Sprintf("%s.%d", column.Identifier(fontIndex), characterNumber)
+}
+
+// seedFonts defines font 1 as a permanent font and leaves the other rows of
+// the fontTable unused.
+func (s *Sign) seedFonts() {
+	s.store(d.NumFonts.Identifier(0), gosnmp.Integer, NumFonts)
+	s.store(d.MaxFontCharacters.Identifier(0), gosnmp.Integer, MaxFontCharacters)
+	s.store(d.FontMaxCharacterSize.Identifier(0), gosnmp.Integer, FontMaxCharacterSize)
+	for index := 1; index <= NumFonts; index++ {
+		s.clearFont(index)
+	}
+
+	s.store(d.FontNumber.Identifier(1), gosnmp.Integer, 1)
+	s.store(d.FontName.Identifier(1), gosnmp.OctetString, []byte("simulator 7"))
+	s.store(d.FontHeight.Identifier(1), gosnmp.Integer, 7)
+	s.store(d.FontCharSpacing.Identifier(1), gosnmp.Integer, 1)
+	s.store(d.FontLineSpacing.Identifier(1), gosnmp.Integer, 2)
+	for _, character := range Font1Characters {
+		s.store(characterOID(d.CharacterWidth, 1, character.Number), gosnmp.Integer, character.Width)
+		s.store(characterOID(d.CharacterBitmap, 1, character.Number), gosnmp.OctetString, character.Bitmap)
+	}
+	s.store(d.FontVersionID.Identifier(1), gosnmp.Integer, Font1VersionID)
+	s.store(d.FontStatus.Identifier(1), gosnmp.Integer, d.FontPermanent.Int())
+}
+
+func (s *Sign) clearFont(index int) {
+	s.store(d.FontIndex.Identifier(index), gosnmp.Integer, index)
+	s.store(d.FontNumber.Identifier(index), gosnmp.Integer, index)
+	s.store(d.FontName.Identifier(index), gosnmp.OctetString, []byte{})
+	s.store(d.FontHeight.Identifier(index), gosnmp.Integer, 0)
+	s.store(d.FontCharSpacing.Identifier(index), gosnmp.Integer, 0)
+	s.store(d.FontLineSpacing.Identifier(index), gosnmp.Integer, 0)
+	s.store(d.FontVersionID.Identifier(index), gosnmp.Integer, 0)
+	s.store(d.FontStatus.Identifier(index), gosnmp.Integer, d.FontNotUsed.Int())
+	for number := 1; number <= MaxFontCharacters; number++ {
+		s.store(characterOID(d.CharacterWidth, index, number), gosnmp.Integer, 0)
+		s.store(characterOID(d.CharacterBitmap, index, number), gosnmp.OctetString, []byte{})
+	}
+}
+
+// writeFont applies a SET of a fontTable or characterTable object. ok is
+// false for objects of other tables.
+func (s *Sign) writeFont(name string, variable gosnmp.SnmpPDU) (status gosnmp.SNMPError, ok bool) {
+	var index, column, number int
+	fontEntry := strings.TrimSuffix(d.FontIndex.Identifier(0), "1.0")
+	characterEntry := strings.TrimSuffix(d.CharacterNumber.Identifier(0), "1.0")
+	switch {
+	case strings.HasPrefix(name, fontEntry):
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, fontEntry), "%d.%d", &column, &index); err != nil {
+			return gosnmp.NoError, false
+		}
+	case strings.HasPrefix(name, characterEntry):
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, characterEntry), "%d.%d.%d", &column, &index, &number); err != nil {
+			return gosnmp.NoError, false
+		}
+		if value, _ := variable.Value.([]byte); len(value) > FontMaxCharacterSize {
+			return gosnmp.BadValue, true
+		}
+	default:
+		return gosnmp.NoError, false
+	}
+
+	statusOID := d.FontStatus.Identifier(index)
+	current := s.value(statusOID)
+	if number > 0 || name != key(statusOID) {
+		if current != d.FontModifying.Int() {
+			return gosnmp.GenErr, true
+		}
+		s.store(name, variable.Type, variable.Value)
+		if name == key(d.FontHeight.Identifier(index)) {
+			// A new height invalidates the characters.
+			for number := 1; number <= MaxFontCharacters; number++ {
+				s.store(characterOID(d.CharacterWidth, index, number), gosnmp.Integer, 0)
+				s.store(characterOID(d.CharacterBitmap, index, number), gosnmp.OctetString, []byte{})
+			}
+		}
+		return gosnmp.NoError, true
+	}
+
+	if current == d.FontPermanent.Int() {
+		return gosnmp.GenErr, true
+	}
+	switch request, _ := variable.Value.(int); request {
+	case d.FontModifyReq.Int():
+		s.store(statusOID, gosnmp.Integer, d.FontModifying.Int())
+	case d.FontReadyForUseReq.Int():
+		if current != d.FontModifying.Int() {
+			return gosnmp.GenErr, true
+		}
+		s.store(d.FontVersionID.Identifier(index), gosnmp.Integer, s.fontVersionID(index))
+		s.store(statusOID, gosnmp.Integer, d.FontReadyForUse.Int())
+	case d.FontNotUsedReq.Int():
+		s.clearFont(index)
+	default:
+		return gosnmp.BadValue, true
+	}
+	return gosnmp.NoError, true
+}
+
+// fontVersionID calculates the fontVersionID of a font row from its objects.
+func (s *Sign) fontVersionID(index int) int {
+	var characters []crc.Character
+	for number := 1; number <= MaxFontCharacters; number++ {
+		width, _ := s.value(characterOID(d.CharacterWidth, index, number)).(int)
+		if width == 0 {
+			continue
+		}
+		bitmap, _ := s.value(characterOID(d.CharacterBitmap, index, number)).([]byte)
+		characters = append(characters, crc.Character{Number: number, Width: width, Bitmap: bitmap})
+	}
+	number, _ := s.value(d.FontNumber.Identifier(index)).(int)
+	height, _ := s.value(d.FontHeight.Identifier(index)).(int)
+	charSpacing, _ := s.value(d.FontCharSpacing.Identifier(index)).(int)
+	lineSpacing, _ := s.value(d.FontLineSpacing.Identifier(index)).(int)
+	return int(crc.FontVersionID(number, height, charSpacing, lineSpacing, characters))
+}
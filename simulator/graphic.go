@@ -0,0 +1,152 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+)
+
+// Size limits of the graphics of the simulated sign, in bytes.
+const (
+	GraphicMaxSize         = 4096
+	GraphicBlockSize       = 1024
+	GraphicMemory          = 32768
+	graphicBlocksPerBitmap = GraphicMaxSize / GraphicBlockSize
+)
+
+func blockOID(graphicIndex, blockNumber int) string {
+	return fmt.Sprintf("%s.%d", d.DmsGraphicBlockBitmap.Identifier(graphicIndex), blockNumber)
+}
+
+// seedGraphics leaves every row of the dmsGraphicTable unused.
+func (s *Sign) seedGraphics() {
+	s.store(d.DmsGraphicMaxEntries.Identifier(0), gosnmp.Integer, MaxGraphics)
+	s.store(d.DmsGraphicMaxSize.Identifier(0), gosnmp.Integer, GraphicMaxSize)
+	s.store(d.DmsGraphicBlockSize.Identifier(0), gosnmp.Integer, GraphicBlockSize)
+	for index := 1; index <= MaxGraphics; index++ {
+		s.clearGraphic(index)
+	}
+	s.countGraphics()
+}
+
+func (s *Sign) clearGraphic(index int) {
+	s.store(d.DmsGraphicIndex.Identifier(index), gosnmp.Integer, index)
+	s.store(d.DmsGraphicNumber.Identifier(index), gosnmp.Integer, index)
+	s.store(d.DmsGraphicName.Identifier(index), gosnmp.OctetString, []byte{})
+	s.store(d.DmsGraphicHeight.Identifier(index), gosnmp.Integer, 1)
+	s.store(d.DmsGraphicWidth.Identifier(index), gosnmp.Integer, 1)
+	s.store(d.DmsGraphicType.Identifier(index), gosnmp.Integer, d.GraphicMonochrome1Bit.Int())
+	s.store(d.DmsGraphicID.Identifier(index), gosnmp.Integer, 0)
+	s.store(d.DmsGraphicTransparentEnabled.Identifier(index), gosnmp.Integer, 0)
+	s.store(d.DmsGraphicTransparentColor.Identifier(index), gosnmp.OctetString, []byte{0})
+	s.store(d.DmsGraphicStatus.Identifier(index), gosnmp.Integer, d.GraphicNotUsed.Int())
+	for block := 1; block <= graphicBlocksPerBitmap; block++ {
+		s.store(blockOID(index, block), gosnmp.OctetString, []byte{})
+	}
+}
+
+// countGraphics updates dmsGraphicNumEntries and availableGraphicMemory;
+// each graphic that is not notUsed uses the bytes of its bitmap.
+func (s *Sign) countGraphics() {
+	num, free := 0, GraphicMemory
+	for index := 1; index <= MaxGraphics; index++ {
+		if s.value(d.DmsGraphicStatus.Identifier(index)) == d.GraphicNotUsed.Int() {
+			continue
+		}
+		num++
+		free -= len(s.graphicBitmap(index))
+	}
+	s.store(d.DmsGraphicNumEntries.Identifier(0), gosnmp.Integer, num)
+	s.store(d.AvailableGraphicMemory.Identifier(0), gosnmp.Integer, free)
+}
+
+// graphicBitmap concatenates the blocks of a graphic, up to the size of the
+// bitmap given by its height, width and type.
+func (s *Sign) graphicBitmap(index int) []byte {
+	var bitmap []byte
+	for block := 1; block <= graphicBlocksPerBitmap; block++ {
+		data, _ := s.value(blockOID(index, block)).([]byte)
+		bitmap = append(bitmap, data...)
+	}
+	height, _ := s.value(d.DmsGraphicHeight.Identifier(index)).(int)
+	width, _ := s.value(d.DmsGraphicWidth.Identifier(index)).(int)
+	size := height * width
+	switch s.value(d.DmsGraphicType.Identifier(index)) {
+	case d.GraphicMonochrome1Bit.Int():
+		size = (size + 7) / 8
+	case d.GraphicColor24Bit.Int():
+		size *= 3
+	}
+	if len(bitmap) > size {
+		bitmap = bitmap[:size]
+	}
+	return bitmap
+}
+
+// writeGraphic applies a SET of a dmsGraphicTable or dmsGraphicBitmapTable
+// object. ok is false for objects of other tables.
+func (s *Sign) writeGraphic(name string, variable gosnmp.SnmpPDU) (status gosnmp.SNMPError, ok bool) {
+	var index, column, block int
+	graphicEntry := strings.TrimSuffix(key(d.DmsGraphicIndex.Identifier(0)), "1.0")
+	bitmapEntry := strings.TrimSuffix(key(d.DmsGraphicBitmapIndex.Identifier(0)), "1.0")
+	switch {
+	case strings.HasPrefix(name, graphicEntry):
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, graphicEntry), "%d.%d", &column, &index); err != nil {
+			return gosnmp.NoError, false
+		}
+	case strings.HasPrefix(name, bitmapEntry):
+		if _, err := fmt.Sscanf(strings.TrimPrefix(name, bitmapEntry), "%d.%d.%d", &column, &index, &block); err != nil {
+			return gosnmp.NoError, false
+		}
+		if value, _ := variable.Value.([]byte); len(value) > GraphicBlockSize {
+			return gosnmp.BadValue, true
+		}
+	default:
+		return gosnmp.NoError, false
+	}
+
+	statusOID := d.DmsGraphicStatus.Identifier(index)
+	current := s.value(statusOID)
+	if block > 0 || name != key(statusOID) {
+		if current != d.GraphicModifying.Int() {
+			return gosnmp.GenErr, true
+		}
+		s.store(name, variable.Type, variable.Value)
+		return gosnmp.NoError, true
+	}
+
+	if current == d.GraphicPermanent.Int() {
+		return gosnmp.GenErr, true
+	}
+	switch request, _ := variable.Value.(int); request {
+	case d.GraphicModifyReq.Int():
+		s.store(statusOID, gosnmp.Integer, d.GraphicModifying.Int())
+	case d.GraphicReadyForUseReq.Int():
+		if current != d.GraphicModifying.Int() {
+			return gosnmp.GenErr, true
+		}
+		s.store(d.DmsGraphicID.Identifier(index), gosnmp.Integer, s.graphicID(index))
+		s.store(statusOID, gosnmp.Integer, d.GraphicReadyForUse.Int())
+	case d.GraphicNotUsedReq.Int():
+		s.clearGraphic(index)
+	default:
+		return gosnmp.BadValue, true
+	}
+	s.countGraphics()
+	return gosnmp.NoError, true
+}
+
+// graphicID calculates the dmsGraphicID of a graphic row from its objects.
+func (s *Sign) graphicID(index int) int {
+	graphic := crc.Graphic{Bitmap: s.graphicBitmap(index)}
+	graphic.Number, _ = s.value(d.DmsGraphicNumber.Identifier(index)).(int)
+	graphic.Height, _ = s.value(d.DmsGraphicHeight.Identifier(index)).(int)
+	graphic.Width, _ = s.value(d.DmsGraphicWidth.Identifier(index)).(int)
+	graphic.Type, _ = s.value(d.DmsGraphicType.Identifier(index)).(int)
+	graphic.TransparentEnabled = s.value(d.DmsGraphicTransparentEnabled.Identifier(index)) == 1
+	graphic.TransparentColor, _ = s.value(d.DmsGraphicTransparentColor.Identifier(index)).([]byte)
+	return int(crc.GraphicID(graphic))
+}
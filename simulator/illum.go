@@ -0,0 +1,125 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// Illumination of the simulated sign.
+const (
+	IllumNumBrightLevels   = 16
+	IllumMaxPhotocellLevel = 1000
+	NumLightSensors        = 2
+)
+
+// seedIllum puts the sign under photocell control at half of its brightness.
+func (s *Sign) seedIllum() {
+	s.store(d.DmsIllumControl.Identifier(0), gosnmp.Integer, d.IllumPhotocell.Int())
+	s.store(d.DmsIllumMaxPhotocellLevel.Identifier(0), gosnmp.Integer, IllumMaxPhotocellLevel)
+	s.store(d.DmsIllumPhotocellLevelStatus.Identifier(0), gosnmp.Integer, IllumMaxPhotocellLevel/2)
+	s.store(d.DmsIllumNumBrightLevels.Identifier(0), gosnmp.Integer, IllumNumBrightLevels)
+	s.store(d.DmsIllumManLevel.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsIllumBrightnessValues.Identifier(0), gosnmp.OctetString, []byte{0})
+	s.store(d.DmsIllumBrightnessValuesError.Identifier(0), gosnmp.Integer, 2)
+	s.setBrightLevel(IllumNumBrightLevels / 2)
+
+	// A working light sensor on either face of the sign.
+	s.store(d.DmsLightSensorNumRows.Identifier(0), gosnmp.Integer, NumLightSensors)
+	for index, description := range []string{"front", "rear"} {
+		s.store(d.DmsLightSensorIndex.Identifier(index+1), gosnmp.Integer, index+1)
+		s.store(d.DmsLightSensorDescription.Identifier(index+1), gosnmp.OctetString, []byte(description))
+		s.store(d.DmsLightSensorCurrentReading.Identifier(index+1), gosnmp.Integer, IllumMaxPhotocellLevel/2)
+		s.store(d.DmsLightSensorStatus.Identifier(index+1), gosnmp.Integer, d.LightSensorNoError.Int())
+	}
+}
+
+// setBrightLevel sets dmsIllumBrightLevelStatus and the light output that
+// goes with it.
+func (s *Sign) setBrightLevel(level int) {
+	s.store(d.DmsIllumBrightLevelStatus.Identifier(0), gosnmp.Integer, level)
+	s.store(d.DmsIllumLightOutputStatus.Identifier(0), gosnmp.Integer, 65535*level/IllumNumBrightLevels)
+}
+
+// writeIllum handles a SET of dmsIllumControl or dmsIllumManLevel. It
+// reports false for other objects.
+func (s *Sign) writeIllum(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	control, _ := s.value(d.DmsIllumControl.Identifier(0)).(int)
+	switch name {
+	case key(d.DmsIllumControl.Identifier(0)):
+		mode, _ := variable.Value.(int)
+		if mode < d.IllumPhotocell.Int() || mode > d.IllumManualIndexed.Int() {
+			return gosnmp.BadValue, true
+		}
+		// Switching to a manual mode loads the current level into
+		// dmsIllumManLevel.
+		if manual := illumManual(mode); manual && !illumManual(control) {
+			s.store(d.DmsIllumManLevel.Identifier(0), gosnmp.Integer, s.value(d.DmsIllumBrightLevelStatus.Identifier(0)))
+		} else if !manual {
+			s.setBrightLevel(IllumNumBrightLevels / 2)
+		}
+		s.store(name, variable.Type, mode)
+	case key(d.DmsIllumManLevel.Identifier(0)):
+		level, _ := variable.Value.(int)
+		if level < 0 || level > IllumNumBrightLevels {
+			return gosnmp.BadValue, true
+		}
+		s.store(name, variable.Type, level)
+		if illumManual(control) {
+			s.setBrightLevel(level)
+		}
+	case key(d.DmsIllumBrightnessValues.Identifier(0)):
+		table, _ := variable.Value.([]byte)
+		cause := checkBrightnessTable(table)
+		s.store(d.DmsIllumBrightnessValuesError.Identifier(0), gosnmp.Integer, cause)
+		if cause != d.BrightnessErrorNone.Int() {
+			return gosnmp.GenErr, true
+		}
+		s.store(name, variable.Type, table)
+	default:
+		return gosnmp.NoError, false
+	}
+	return gosnmp.NoError, true
+}
+
+// checkBrightnessTable returns the dmsIllumBrightnessValuesError of a
+// brightness table: its rows must cover every photocell level from 0 to
+// IllumMaxPhotocellLevel with a light output that does not decrease.
+func checkBrightnessTable(table []byte) int {
+	if len(table) == 0 || len(table) != 1+6*int(table[0]) {
+		return d.BrightnessErrorInvalidData.Int()
+	}
+	rows := int(table[0])
+	if rows > IllumNumBrightLevels {
+		return d.BrightnessErrorTooManyLevels.Int()
+	}
+	value := func(row, field int) int {
+		i := 1 + 6*row + 2*field
+		return int(table[i])<<8 | int(table[i+1])
+	}
+	for row := 0; row < rows; row++ {
+		output, down, up := value(row, 0), value(row, 1), value(row, 2)
+		if down > up || up > IllumMaxPhotocellLevel {
+			return d.BrightnessErrorInvalidData.Int()
+		}
+		if row == 0 {
+			if down > 0 {
+				return d.BrightnessErrorPhotocellGap.Int()
+			}
+			continue
+		}
+		if output < value(row-1, 0) || down < value(row-1, 1) {
+			return d.BrightnessErrorNegativeSlope.Int()
+		}
+		if down > value(row-1, 2)+1 {
+			return d.BrightnessErrorPhotocellGap.Int()
+		}
+	}
+	if rows > 0 && value(rows-1, 2) < IllumMaxPhotocellLevel {
+		return d.BrightnessErrorPhotocellGap.Int()
+	}
+	return d.BrightnessErrorNone.Int()
+}
+
+func illumManual(mode int) bool {
+	return mode == d.IllumManual.Int() || mode == d.IllumManualDirect.Int() || mode == d.IllumManualIndexed.Int()
+}
@@ -0,0 +1,52 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// NumLamps is the number of lamps reported by the lamp test bitmaps.
+const NumLamps = 16
+
+// FailLamp makes lamp number (from 1) stuck on or stuck off; the next lamp
+// test reports it.
+func (s *Sign) FailLamp(number int, stuckOn bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failedLamps == nil {
+		s.failedLamps = map[int]bool{}
+	}
+	s.failedLamps[number] = stuckOn
+}
+
+func (s *Sign) seedLamps() {
+	s.store(d.LampTestActivation.Identifier(0), gosnmp.Integer, d.NoTest.Int())
+	s.store(d.LampFailureStuckOn.Identifier(0), gosnmp.OctetString, make([]byte, NumLamps/8))
+	s.store(d.LampFailureStuckOff.Identifier(0), gosnmp.OctetString, make([]byte, NumLamps/8))
+}
+
+// writeLampTest handles a SET of lampTestActivation. The test completes at
+// once. It reports false for other objects.
+func (s *Sign) writeLampTest(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	if name != key(d.LampTestActivation.Identifier(0)) {
+		return gosnmp.NoError, false
+	}
+	switch activation, _ := variable.Value.(int); activation {
+	case d.NoTest.Int():
+	case d.Test.Int():
+		stuckOn, stuckOff := make([]byte, NumLamps/8), make([]byte, NumLamps/8)
+		for number, on := range s.failedLamps {
+			bitmap := stuckOff
+			if on {
+				bitmap = stuckOn
+			}
+			bitmap[(number-1)/8] |= 1 << ((number - 1) % 8)
+		}
+		s.store(d.LampFailureStuckOn.Identifier(0), gosnmp.OctetString, stuckOn)
+		s.store(d.LampFailureStuckOff.Identifier(0), gosnmp.OctetString, stuckOff)
+	default:
+		return gosnmp.BadValue, true
+	}
+	s.store(name, variable.Type, d.NoTest.Int())
+	return gosnmp.NoError, true
+}
@@ -0,0 +1,24 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// seedMessageConfig blanks the sign on every event with a configurable
+// message and ignores communications loss.
+func (s *Sign) seedMessageConfig() {
+	blank := []byte{byte(d.MemoryBlank.Int()), 0, 1, 0, 0}
+	for _, object := range []d.Reader{
+		d.DmsShortPowerRecoveryMessage,
+		d.DmsLongPowerRecoveryMessage,
+		d.DmsResetMessage,
+		d.DmsCommunicationsLossMessage,
+		d.DmsPowerLossMessage,
+		d.DmsEndDurationMessage,
+	} {
+		s.store(object.Identifier(0), gosnmp.OctetString, append([]byte{}, blank...))
+	}
+	s.store(d.DmsShortPowerLossTime.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsTimeCommLoss.Identifier(0), gosnmp.Integer, 0)
+}
@@ -0,0 +1,44 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// dmsNode is the node of the NTCIP device tree of a sign.
+const dmsNode = "1.3.6.1.4.1.1206.4.2.3"
+
+// seedModules lists the controller board, its firmware and the display
+// driver board in the globalModuleTable, and describes the sign in the
+// MIB-II sysDescr and sysObjectID.
+func (s *Sign) seedModules() {
+	s.store(d.GlobalSetIDParameter.Identifier(0), gosnmp.Integer, 1)
+	s.store("1.3.6.1.2.1.1.1.0", gosnmp.OctetString, []byte("godms simulated sign"))
+	s.store("1.3.6.1.2.1.1.2.0", gosnmp.ObjectIdentifier, "1.3.6.1.4.1.1206.4.2.3")
+
+	modules := []struct {
+		make, model, version string
+		moduleType           int
+	}{
+		{"Simulated Signs", "SC-100", "rev C", d.ModuleHardware.Int()},
+		{"Simulated Signs", "SC-100 firmware", "4.2.1", d.ModuleSoftware.Int()},
+		{"Simulated Signs", "LED driver", "rev A", d.ModuleHardware.Int()},
+	}
+	s.store(d.GlobalMaxModules.Identifier(0), gosnmp.Integer, len(modules))
+	for i, module := range modules {
+		number := i + 1
+		s.store(d.ModuleNumber.Identifier(number), gosnmp.Integer, number)
+		s.store(d.ModuleDeviceNode.Identifier(number), gosnmp.ObjectIdentifier, dmsNode)
+		s.store(d.ModuleMake.Identifier(number), gosnmp.OctetString, []byte(module.make))
+		s.store(d.ModuleModel.Identifier(number), gosnmp.OctetString, []byte(module.model))
+		s.store(d.ModuleVersion.Identifier(number), gosnmp.OctetString, []byte(module.version))
+		s.store(d.ModuleType.Identifier(number), gosnmp.Integer, module.moduleType)
+	}
+}
+
+// changeDatabase changes globalSetIDParameter after a SET changed the
+// database of the sign, i.e. its fonts or graphics.
+func (s *Sign) changeDatabase() {
+	setID, _ := s.value(d.GlobalSetIDParameter.Identifier(0)).(int)
+	s.store(d.GlobalSetIDParameter.Identifier(0), gosnmp.Integer, (setID+1)%65536)
+}
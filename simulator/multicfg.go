@@ -0,0 +1,21 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// seedMultiConfig sets the default MULTI tag values: centered lines at the
+// top of the page in font 1, full intensity on black, 3 second pages.
+func (s *Sign) seedMultiConfig() {
+	s.store(d.DefaultFont.Identifier(0), gosnmp.Integer, 1)
+	s.store(d.DefaultJustificationLine.Identifier(0), gosnmp.Integer, 3)
+	s.store(d.DefaultJustificationPage.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.DefaultPageOnTime.Identifier(0), gosnmp.Integer, 30)
+	s.store(d.DefaultPageOffTime.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DefaultFlashOn.Identifier(0), gosnmp.Integer, 5)
+	s.store(d.DefaultFlashOff.Identifier(0), gosnmp.Integer, 5)
+	s.store(d.DefaultCharacterSet.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.DefaultForegroundRGB.Identifier(0), gosnmp.OctetString, []byte{255})
+	s.store(d.DefaultBackgroundRGB.Identifier(0), gosnmp.OctetString, []byte{0})
+}
@@ -0,0 +1,205 @@
+package simulator
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// notificationTables are the entries of the RFC 3413 tables whose rows are
+// created with their RowStatus column, with the number of that column.
+var notificationTables = []struct {
+	entry     string
+	rowStatus int
+}{
+	{"1.3.6.1.6.3.12.1.2.1", 9}, // snmpTargetAddrEntry
+	{"1.3.6.1.6.3.12.1.3.1", 7}, // snmpTargetParamsEntry
+	{"1.3.6.1.6.3.13.1.1.1", 5}, // snmpNotifyEntry
+}
+
+// parseNotificationRow splits the name of an object of the notification
+// tables into its entry, column and index.
+func parseNotificationRow(name string) (entry string, rowStatus, column int, index string, ok bool) {
+	for _, table := range notificationTables {
+		rest := strings.TrimPrefix(name, table.entry+".")
+		if rest == name {
+			continue
+		}
+		parts := strings.SplitN(rest, ".", 2)
+		if len(parts) != 2 {
+			return "", 0, 0, "", false
+		}
+		column, err := strconv.Atoi(parts[0])
+		if err != nil || column < 2 || column > table.rowStatus {
+			return "", 0, 0, "", false
+		}
+		return table.entry, table.rowStatus, column, parts[1], true
+	}
+	return "", 0, 0, "", false
+}
+
+// creatable reports whether a SET may create the object name, a column of
+// a row of the notification tables.
+func creatable(name string) bool {
+	_, _, _, _, ok := parseNotificationRow(name)
+	return ok
+}
+
+// writeNotificationRow applies a SET of the notification tables: the
+// RowStatus column creates, activates and destroys rows, and the other
+// columns can only be set in an existing row.
+func (s *Sign) writeNotificationRow(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	entry, rowStatus, column, index, ok := parseNotificationRow(name)
+	if !ok {
+		return gosnmp.NoError, false
+	}
+	statusName := entry + "." + strconv.Itoa(rowStatus) + "." + index
+	_, exists := s.objects[statusName]
+	if column != rowStatus {
+		if !exists {
+			return gosnmp.NoSuchName, true
+		}
+		s.store(name, variable.Type, variable.Value)
+		return gosnmp.NoError, true
+	}
+
+	switch status, _ := variable.Value.(int); status {
+	case d.RowActive.Int(), d.RowNotInService.Int():
+		if !exists {
+			return gosnmp.BadValue, true
+		}
+		s.store(statusName, gosnmp.Integer, status)
+	case d.RowCreateAndGo.Int(), d.RowCreateAndWait.Int():
+		if exists {
+			return gosnmp.BadValue, true
+		}
+		created := d.RowActive.Int()
+		if status == d.RowCreateAndWait.Int() {
+			created = d.RowNotInService.Int()
+		}
+		s.store(statusName, gosnmp.Integer, created)
+	case d.RowDestroy.Int():
+		for c := 2; c <= rowStatus; c++ {
+			s.delete(entry + "." + strconv.Itoa(c) + "." + index)
+		}
+	default:
+		return gosnmp.BadValue, true
+	}
+	return gosnmp.NoError, true
+}
+
+// cell returns the value of column in the row index of a notification
+// table entry.
+func (s *Sign) cell(entry string, column int, index string) interface{} {
+	return s.value(entry + "." + strconv.Itoa(column) + "." + index)
+}
+
+// rowIndex returns the IMPLIED string index of a row name.
+func rowIndex(name []byte) string {
+	subIdentifiers := make([]string, len(name))
+	for i, c := range name {
+		subIdentifiers[i] = strconv.Itoa(int(c))
+	}
+	return strings.Join(subIdentifiers, ".")
+}
+
+// notificationTarget is a manager the sign sends notifications to.
+type notificationTarget struct {
+	address   string
+	version   gosnmp.SnmpVersion
+	community string
+	inform    bool
+}
+
+// notificationTargets returns the managers of the active rows of the
+// snmpNotifyTable: the active targets with the tag of the row, with their
+// parameters.
+func (s *Sign) notificationTargets() []notificationTarget {
+	addrEntry, paramsEntry, notifyEntry := notificationTables[0].entry, notificationTables[1].entry, notificationTables[2].entry
+	var targets []notificationTarget
+	for name := range s.objects {
+		entry, rowStatus, column, notifyIndex, ok := parseNotificationRow(name)
+		if !ok || entry != notifyEntry || column != rowStatus || s.value(name) != d.RowActive.Int() {
+			continue
+		}
+		tag, _ := s.cell(notifyEntry, 2, notifyIndex).([]byte)
+		inform := s.cell(notifyEntry, 3, notifyIndex) == d.NotifyInform.Int()
+		for name := range s.objects {
+			entry, rowStatus, column, index, ok := parseNotificationRow(name)
+			if !ok || entry != addrEntry || column != rowStatus || s.value(name) != d.RowActive.Int() {
+				continue
+			}
+			tagList, _ := s.cell(addrEntry, 6, index).([]byte)
+			address, _ := s.cell(addrEntry, 3, index).([]byte)
+			if !containsTag(string(tagList), string(tag)) || len(address) != 6 {
+				continue
+			}
+			params, _ := s.cell(addrEntry, 7, index).([]byte)
+			community, _ := s.cell(paramsEntry, 4, rowIndex(params)).([]byte)
+			version := gosnmp.Version2c
+			if s.cell(paramsEntry, 2, rowIndex(params)) == 0 {
+				version = gosnmp.Version1
+			}
+			targets = append(targets, notificationTarget{
+				address:   net.JoinHostPort(net.IP(address[:4]).String(), strconv.Itoa(int(binary.BigEndian.Uint16(address[4:])))),
+				version:   version,
+				community: string(community),
+				inform:    inform && version != gosnmp.Version1,
+			})
+		}
+	}
+	return targets
+}
+
+// Notify sends a trap or inform with variables to the managers configured
+// in the active rows of the notification tables, as a sign does when its
+// state changes. SNMPv1 traps are enterprise specific traps 0 of the
+// NTCIP 1203 dms node.
+func (s *Sign) Notify(variables ...gosnmp.SnmpPDU) error {
+	s.mu.Lock()
+	targets := s.notificationTargets()
+	s.mu.Unlock()
+
+	for _, target := range targets {
+		host, port, _ := net.SplitHostPort(target.address)
+		portNumber, _ := strconv.Atoi(port)
+		g := &gosnmp.GoSNMP{
+			Target:    host,
+			Port:      uint16(portNumber),
+			Transport: "udp",
+			Community: target.community,
+			Version:   target.version,
+			Timeout:   time.Second,
+			MaxOids:   gosnmp.MaxOids,
+		}
+		if err := g.Connect(); err != nil {
+			return err
+		}
+		trap := gosnmp.SnmpTrap{Variables: variables, IsInform: target.inform}
+		if target.version == gosnmp.Version1 {
+			trap.Enterprise = ".1.3.6.1.4.1.1206.4.2.3"
+			trap.AgentAddress = "127.0.0.1"
+			trap.GenericTrap = 6
+		}
+		_, err := g.SendTrap(trap)
+		g.Conn.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsTag(tagList, tag string) bool {
+	for _, t := range strings.Fields(tagList) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+type pixelFailure struct {
+	x, y, status int
+}
+
+func pixelFailureOID(column d.Reader, detectionType, index int) string {
+	return fmt.Sprintf("%s.%d", column.Identifier(detectionType), index)
+}
+
+// FailPixel makes the pixel at x, y (from 1, 1 at the top left) fail with
+// the pixelFailureStatus bitmap status; the next pixel test reports it.
+func (s *Sign) FailPixel(x, y, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failedPixels = append(s.failedPixels, pixelFailure{x, y, status})
+}
+
+func (s *Sign) seedPixels() {
+	s.store(d.PixelTestActivation.Identifier(0), gosnmp.Integer, d.NoTest.Int())
+	s.store(d.PixelFailureTableNumRows.Identifier(0), gosnmp.Integer, 0)
+}
+
+// writePixelTest handles a SET of pixelTestActivation. The test completes at
+// once. It reports false for other objects.
+func (s *Sign) writePixelTest(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	if name != key(d.PixelTestActivation.Identifier(0)) {
+		return gosnmp.NoError, false
+	}
+	switch activation, _ := variable.Value.(int); activation {
+	case d.NoTest.Int():
+	case d.Test.Int():
+		s.clearPixelFailures()
+		for i, failure := range s.failedPixels {
+			index := i + 1
+			detection := d.PixelTestDetection.Int()
+			s.store(pixelFailureOID(d.PixelFailureDetectionType, detection, index), gosnmp.Integer, detection)
+			s.store(pixelFailureOID(d.PixelFailureIndex, detection, index), gosnmp.Integer, index)
+			s.store(pixelFailureOID(d.PixelFailureXLocation, detection, index), gosnmp.Integer, failure.x)
+			s.store(pixelFailureOID(d.PixelFailureYLocation, detection, index), gosnmp.Integer, failure.y)
+			s.store(pixelFailureOID(d.PixelFailureStatus, detection, index), gosnmp.Integer, failure.status)
+		}
+		s.store(d.PixelFailureTableNumRows.Identifier(0), gosnmp.Integer, len(s.failedPixels))
+	case d.ClearTable.Int():
+		s.clearPixelFailures()
+	default:
+		return gosnmp.BadValue, true
+	}
+	s.store(name, variable.Type, d.NoTest.Int())
+	return gosnmp.NoError, true
+}
+
+func (s *Sign) clearPixelFailures() {
+	rows, _ := s.value(d.PixelFailureTableNumRows.Identifier(0)).(int)
+	for index := 1; index <= rows; index++ {
+		for _, column := range []d.Reader{d.PixelFailureDetectionType, d.PixelFailureIndex, d.PixelFailureXLocation, d.PixelFailureYLocation, d.PixelFailureStatus} {
+			s.delete(pixelFailureOID(column, d.PixelTestDetection.Int(), index))
+		}
+	}
+	s.store(d.PixelFailureTableNumRows.Identifier(0), gosnmp.Integer, 0)
+}
@@ -0,0 +1,34 @@
+package simulator
+
+import (
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// NumPowerSupplies is the number of rows of the dmsPowerStatusTable.
+const NumPowerSupplies = 2
+
+// seedPower runs the sign from the AC line with a charged backup battery.
+func (s *Sign) seedPower() {
+	s.store(d.PowerSource.Identifier(0), gosnmp.Integer, d.PowerSourceACLine.Int())
+	s.store(d.SignVolts.Identifier(0), gosnmp.Integer, 2400)
+	s.store(d.LineVolts.Identifier(0), gosnmp.Integer, 23000)
+
+	s.store(d.DmsPowerNumRows.Identifier(0), gosnmp.Integer, NumPowerSupplies)
+	supplies := []struct {
+		description string
+		voltage     int
+		powerType   int
+	}{
+		{"main supply", 2400, d.PowerSourceACLine.Int()},
+		{"backup battery", 2550, d.PowerSourceBattery.Int()},
+	}
+	for index, supply := range supplies {
+		s.store(d.DmsPowerIndex.Identifier(index+1), gosnmp.Integer, index+1)
+		s.store(d.DmsPowerDescription.Identifier(index+1), gosnmp.OctetString, []byte(supply.description))
+		s.store(d.DmsPowerMfrStatus.Identifier(index+1), gosnmp.OctetString, []byte("OK"))
+		s.store(d.DmsPowerStatus.Identifier(index+1), gosnmp.Integer, d.PowerNoError.Int())
+		s.store(d.DmsPowerVoltage.Identifier(index+1), gosnmp.Integer, supply.voltage)
+		s.store(d.DmsPowerType.Identifier(index+1), gosnmp.Integer, supply.powerType)
+	}
+}
@@ -0,0 +1,51 @@
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// Size of the schedule tables of the simulated sign.
+const (
+	MaxTimeBaseScheduleEntries = 8
+	MaxDayPlans                = 4
+	MaxDayPlanEvents           = 6
+	DmsActionTableEntries      = 8
+)
+
+// seedSchedule starts with an empty schedule: disabled entries, events
+// without an action and actions activating no message. The simulated sign
+// does not run the schedule.
+func (s *Sign) seedSchedule() {
+	s.store(d.MaxTimeBaseScheduleEntries.Identifier(0), gosnmp.Integer, MaxTimeBaseScheduleEntries)
+	for number := 1; number <= MaxTimeBaseScheduleEntries; number++ {
+		s.store(d.TimeBaseScheduleNumber.Identifier(number), gosnmp.Integer, number)
+		s.store(d.TimeBaseScheduleMonth.Identifier(number), gosnmp.Integer, 0)
+		s.store(d.TimeBaseScheduleDay.Identifier(number), gosnmp.Integer, 0)
+		s.store(d.TimeBaseScheduleDate.Identifier(number), gosnmp.Integer, 0)
+		s.store(d.TimeBaseScheduleDayPlan.Identifier(number), gosnmp.Integer, 0)
+	}
+	s.store(d.MaxDayPlans.Identifier(0), gosnmp.Integer, MaxDayPlans)
+	s.store(d.MaxDayPlanEvents.Identifier(0), gosnmp.Integer, MaxDayPlanEvents)
+	for plan := 1; plan <= MaxDayPlans; plan++ {
+		for event := 1; event <= MaxDayPlanEvents; event++ {
+			s.store(dayPlanOID(d.DayPlanNumber, plan, event), gosnmp.Integer, plan)
+			s.store(dayPlanOID(d.DayPlanEventNumber, plan, event), gosnmp.Integer, event)
+			s.store(dayPlanOID(d.DayPlanHour, plan, event), gosnmp.Integer, 0)
+			s.store(dayPlanOID(d.DayPlanMinute, plan, event), gosnmp.Integer, 0)
+			s.store(dayPlanOID(d.DayPlanActionNumberOID, plan, event), gosnmp.ObjectIdentifier, nullOID)
+		}
+	}
+	s.store(d.DayPlanStatus.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsActionTableEntries.Identifier(0), gosnmp.Integer, DmsActionTableEntries)
+	for index := 1; index <= DmsActionTableEntries; index++ {
+		s.store(d.DmsActionIndex.Identifier(index), gosnmp.Integer, index)
+		s.store(d.DmsActionMsgCode.Identifier(index), gosnmp.OctetString, make([]byte, 12))
+	}
+}
+
+func dayPlanOID(column d.Reader, plan, event int) string {
+	return fmt.Sprintf("%s.%d", column.Identifier(plan), event)
+}
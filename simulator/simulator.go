@@ -0,0 +1,807 @@
+// Package simulator provides an in-memory NTCIP 1203 sign that answers SNMP
+// requests over UDP. It implements enough of the message table, the message
+// activation and the validation consistency checks to run the dialogs of
+// package dialogs end to end, for examples and tests that cannot reach a real
+// controller.
+package simulator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/crc"
+	"github.com/jacobleehei/godms/stmp"
+)
+
+// Number of changeable and volatile message table rows, font table rows and
+// graphic table rows of the simulated sign.
+const (
+	MaxChangeableMsg = 10
+	MaxVolatileMsg   = 10
+	NumFonts         = 4
+	MaxGraphics      = 8
+)
+
+// SupportedMultiTags is the dmsSupportedMultiTags bitmap of the simulated
+// sign.
+const SupportedMultiTags = 0x7FF
+
+// Pixel size of the simulated full matrix sign.
+const (
+	SignHeightPixels = 27
+	SignWidthPixels  = 105
+)
+
+// MessageMemory is the size in bytes of both the changeable and the volatile
+// message memory of the simulated sign.
+const MessageMemory = 4096
+
+// Sign is a simulated sign listening on a loopback UDP port. SETs are
+// applied in varbind order; a SET that fails part-way keeps the varbinds
+// before the failing one.
+type Sign struct {
+	conn    net.PacketConn
+	decoder *gosnmp.GoSNMP
+
+	mu           sync.Mutex
+	objects      map[string]gosnmp.SnmpPDU
+	failedPixels []pixelFailure
+	failedLamps  map[int]bool // lamp number to stuck on
+	failedFans   []int
+
+	// readCommunity and writeCommunity are set by SetCommunities; empty
+	// accepts any community.
+	readCommunity  string
+	writeCommunity string
+
+	// stmpTypes are the encodings set by SetSTMPType.
+	stmpTypes map[string]stmp.Type
+
+	// names are the names of the objects in lexicographic order for
+	// GETNEXT, nil when an object was added or removed since.
+	names []string
+
+	// eventLog holds the eventLogNumbers of each event class in the
+	// eventLogTable, oldest first.
+	eventLog map[int][]int
+
+	// clockOffset is how far the clock of the sign is ahead of the local
+	// clock.
+	clockOffset time.Duration
+
+	// drop is the number of requests left to ignore, set by Drop.
+	drop int
+
+	// versions are the SNMP versions set by SetVersions; nil answers any
+	// version.
+	versions []gosnmp.SnmpVersion
+}
+
+// New starts a simulated sign on 127.0.0.1 with an empty changeable and
+// volatile message table and the 255 blank messages.
+func New() (*Sign, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Sign{
+		conn:    conn,
+		decoder: &gosnmp.GoSNMP{},
+		objects: map[string]gosnmp.SnmpPDU{},
+	}
+	s.seed()
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the sign.
+func (s *Sign) Close() error { return s.conn.Close() }
+
+// GoSNMP returns an SNMPv1 session for the sign. Connect is left to the
+// caller, as with any gosnmp session.
+func (s *Sign) GoSNMP() *gosnmp.GoSNMP {
+	addr := s.conn.LocalAddr().(*net.UDPAddr)
+	return &gosnmp.GoSNMP{
+		Target:    addr.IP.String(),
+		Port:      uint16(addr.Port),
+		Transport: "udp",
+		Community: "public",
+		Version:   gosnmp.Version1,
+		Timeout:   time.Second,
+		Retries:   0,
+		MaxOids:   gosnmp.MaxOids,
+	}
+}
+
+// Store sets the object oid, creating it if needed.
+func (s *Sign) Store(oid string, asnType gosnmp.Asn1BER, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(oid, asnType, value)
+}
+
+// Load returns the value of the object oid.
+func (s *Sign) Load(oid string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pdu, ok := s.objects[key(oid)]
+	return pdu.Value, ok
+}
+
+// Delete removes the object oid, so that the sign answers noSuchName for it,
+// e.g. to simulate a sign without the optional dmsMessageBeacon.
+func (s *Sign) Delete(oid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delete(oid)
+}
+
+func key(oid string) string { return strings.TrimPrefix(oid, ".") }
+
+func (s *Sign) store(oid string, asnType gosnmp.Asn1BER, value interface{}) {
+	if _, ok := s.objects[key(oid)]; !ok {
+		s.names = nil
+	}
+	s.objects[key(oid)] = gosnmp.SnmpPDU{Name: "." + key(oid), Type: asnType, Value: value}
+}
+
+func (s *Sign) delete(oid string) {
+	if _, ok := s.objects[key(oid)]; ok {
+		s.names = nil
+		delete(s.objects, key(oid))
+	}
+}
+
+func (s *Sign) value(oid string) interface{} { return s.objects[key(oid)].Value }
+
+func (s *Sign) seed() {
+	// A full matrix LED sign with front access and no beacons.
+	s.store(d.DmsSignAccess.Identifier(0), gosnmp.Integer, d.SignAccessFront.Int())
+	s.store(d.DmsSignType.Identifier(0), gosnmp.Integer, d.SignTypeVMSFull.Int())
+	s.store(d.DmsSignHeight.Identifier(0), gosnmp.Integer, 1800)
+	s.store(d.DmsSignWidth.Identifier(0), gosnmp.Integer, 5000)
+	s.store(d.DmsHorizontalBorder.Identifier(0), gosnmp.Integer, 100)
+	s.store(d.DmsVerticalBorder.Identifier(0), gosnmp.Integer, 100)
+	s.store(d.DmsLegend.Identifier(0), gosnmp.Integer, d.NoLegend.Int())
+	s.store(d.DmsBeaconType.Identifier(0), gosnmp.Integer, d.BeaconNone.Int())
+	s.store(d.DmsSignTechnology.Identifier(0), gosnmp.Integer, d.TechnologyLED.Int())
+
+	// 27 x 105 pixels, amber on black.
+	s.store(d.VmsCharacterHeightPixels.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.VmsCharacterWidthPixels.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.VmsSignHeightPixels.Identifier(0), gosnmp.Integer, SignHeightPixels)
+	s.store(d.VmsSignWidthPixels.Identifier(0), gosnmp.Integer, SignWidthPixels)
+	s.store(d.VmsHorizontalPitch.Identifier(0), gosnmp.Integer, 46)
+	s.store(d.VmsVerticalPitch.Identifier(0), gosnmp.Integer, 46)
+	s.store(d.MonochromeColor.Identifier(0), gosnmp.OctetString, []byte{255, 191, 0, 0, 0, 0})
+	s.store(d.DmsMaxNumberPages.Identifier(0), gosnmp.Integer, 6)
+	s.store(d.DmsMaxMultiStringLength.Identifier(0), gosnmp.Integer, 1024)
+	s.store(d.DmsColorScheme.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.DmsSupportedMultiTags.Identifier(0), gosnmp.Integer, SupportedMultiTags)
+
+	s.store(d.DmsNumPermanentMsg.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsNumChangeableMsg.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsMaxChangeableMsg.Identifier(0), gosnmp.Integer, MaxChangeableMsg)
+	s.store(d.DmsFreeChangeableMemory.Identifier(0), gosnmp.Integer, MessageMemory)
+	s.store(d.DmsNumVolatileMsg.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsMaxVolatileMsg.Identifier(0), gosnmp.Integer, MaxVolatileMsg)
+	s.store(d.DmsFreeVolatileMemory.Identifier(0), gosnmp.Integer, MessageMemory)
+
+	s.seedFonts()
+
+	s.seedGraphics()
+
+	s.seedIllum()
+
+	s.seedPixels()
+	s.seedLamps()
+	s.seedClimate()
+	s.seedPower()
+	s.seedMessageConfig()
+	s.seedMultiConfig()
+	s.seedDynamicObjects()
+	s.seedEventLog()
+	s.seedClock()
+	s.seedModules()
+	s.seedSchedule()
+
+	for number := 1; number <= MaxChangeableMsg; number++ {
+		s.clearRow(d.MemoryChangeable.Int(), number)
+	}
+	for number := 1; number <= MaxVolatileMsg; number++ {
+		s.clearRow(d.MemoryVolatile.Int(), number)
+	}
+	for number := 1; number <= 255; number++ {
+		s.clearRow(d.MemoryBlank.Int(), number)
+		s.store(rowOID(d.DmsMessageRunTimePriority, d.MemoryBlank.Int(), number), gosnmp.Integer, number)
+		s.store(rowOID(d.DmsMessageStatus, d.MemoryBlank.Int(), number), gosnmp.Integer, d.Valid.Int())
+	}
+	s.clearRow(d.MemoryCurrentBuffer.Int(), 1)
+	s.store(rowOID(d.DmsMessageStatus, d.MemoryCurrentBuffer.Int(), 1), gosnmp.Integer, d.Valid.Int())
+
+	s.store(d.DmsActivateMessage.Identifier(0), gosnmp.OctetString, make([]byte, 12))
+	s.store(d.DmsMessageTimeRemaining.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsMsgTableSource.Identifier(0), gosnmp.OctetString, []byte{byte(d.MemoryBlank.Int()), 0, 1, 0, 0})
+	s.store(d.DmsMsgRequesterID.Identifier(0), gosnmp.OctetString, make([]byte, 4))
+	s.store(d.DmsMsgSourceMode.Identifier(0), gosnmp.Integer, 1)
+	s.store(d.DmsControlMode.Identifier(0), gosnmp.Integer, d.ControlCentral.Int())
+	s.store(d.DmsActivateMsgError.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.DmsActivateErrorMsgCode.Identifier(0), gosnmp.OctetString, make([]byte, 12))
+	s.store(d.DmsMultiSyntaxError.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.DmsMultiSyntaxErrorPosition.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsMultiOtherErrorDescription.Identifier(0), gosnmp.OctetString, []byte{})
+	s.store(d.DmsValidateMessageError.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.ShortErrorStatus.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsSWReset.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsMemoryMgmt.Identifier(0), gosnmp.Integer, d.MemoryMgmtNormal.Int())
+}
+
+// Reset simulates a controller restart, e.g. a power cycle: the changeable
+// messages and fonts are kept in non-volatile memory, the volatile messages
+// are lost and the sign comes back blank.
+func (s *Sign) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+}
+
+func (s *Sign) reset() {
+	for number := 1; number <= MaxVolatileMsg; number++ {
+		s.clearRow(d.MemoryVolatile.Int(), number)
+	}
+	s.count(d.MemoryVolatile.Int())
+	s.copyRow(d.MemoryBlank.Int(), 1, d.MemoryCurrentBuffer.Int(), 1)
+	s.store(d.DmsMessageTimeRemaining.Identifier(0), gosnmp.Integer, 0)
+	s.store(d.DmsMsgTableSource.Identifier(0), gosnmp.OctetString, []byte{byte(d.MemoryBlank.Int()), 0, 1, 0, 0})
+	s.store(d.DmsMsgRequesterID.Identifier(0), gosnmp.OctetString, []byte{127, 0, 0, 1})
+	s.store(d.DmsMsgSourceMode.Identifier(0), gosnmp.Integer, d.SourceReset.Int())
+	s.store(d.DmsSWReset.Identifier(0), gosnmp.Integer, 0)
+}
+
+// manageMemory handles a SET of dmsMemoryMgmt: the changeable or volatile
+// messages are cleared at once and the object reads normal again.
+func (s *Sign) manageMemory(variable gosnmp.SnmpPDU) gosnmp.SNMPError {
+	var messageMemoryType, rows int
+	switch action, _ := variable.Value.(int); action {
+	case d.MemoryMgmtNormal.Int():
+		return gosnmp.NoError
+	case d.ClearChangeableMessages.Int():
+		messageMemoryType, rows = d.MemoryChangeable.Int(), MaxChangeableMsg
+	case d.ClearVolatileMessages.Int():
+		messageMemoryType, rows = d.MemoryVolatile.Int(), MaxVolatileMsg
+	default:
+		return gosnmp.BadValue
+	}
+	for number := 1; number <= rows; number++ {
+		s.clearRow(messageMemoryType, number)
+	}
+	s.count(messageMemoryType)
+	return gosnmp.NoError
+}
+
+// rowOID returns the OID of a dmsMessageTable column for a row.
+func rowOID(column interface {
+	Identifier(messageMemoryType, messageNumber int) string
+}, messageMemoryType, messageNumber int) string {
+	return key(column.Identifier(messageMemoryType, messageNumber))
+}
+
+func crcOID(messageMemoryType, messageNumber int) string {
+	return fmt.Sprintf("%s.%d", d.DmsMessageCRC.Identifier(messageMemoryType), messageNumber)
+}
+
+func (s *Sign) clearRow(messageMemoryType, messageNumber int) {
+	s.store(rowOID(d.DmsMessageMultiString, messageMemoryType, messageNumber), gosnmp.OctetString, []byte{})
+	s.store(rowOID(d.DmsMessageOwner, messageMemoryType, messageNumber), gosnmp.OctetString, []byte{})
+	s.store(crcOID(messageMemoryType, messageNumber), gosnmp.Integer, 0)
+	s.store(rowOID(d.DmsMessageBeacon, messageMemoryType, messageNumber), gosnmp.Integer, 0)
+	s.store(rowOID(d.DmsMessagePixelService, messageMemoryType, messageNumber), gosnmp.Integer, 0)
+	s.store(rowOID(d.DmsMessageRunTimePriority, messageMemoryType, messageNumber), gosnmp.Integer, 1)
+	s.store(rowOID(d.DmsMessageStatus, messageMemoryType, messageNumber), gosnmp.Integer, d.NotUsed.Int())
+}
+
+func (s *Sign) copyRow(fromType, fromNumber, toType, toNumber int) {
+	for _, column := range []interface {
+		Identifier(messageMemoryType, messageNumber int) string
+	}{d.DmsMessageMultiString, d.DmsMessageOwner, d.DmsMessageBeacon, d.DmsMessagePixelService, d.DmsMessageRunTimePriority} {
+		from := s.objects[rowOID(column, fromType, fromNumber)]
+		s.store(rowOID(column, toType, toNumber), from.Type, from.Value)
+	}
+	s.store(crcOID(toType, toNumber), gosnmp.Integer, s.value(crcOID(fromType, fromNumber)))
+}
+
+func (s *Sign) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if n > 0 && buf[0]&0x80 != 0 {
+			if response := s.handleSTMP(buf[:n]); response != nil {
+				s.conn.WriteTo(response, addr)
+			}
+			continue
+		}
+		// The decoded values share the packet buffer, which is reused.
+		packet := append([]byte{}, buf[:n]...)
+		request, err := s.decoder.SnmpDecodePacket(packet)
+		if err != nil {
+			continue
+		}
+		if request.PDUType == gosnmp.GetBulkRequest {
+			request.MaxRepetitions = maxRepetitions(packet)
+		}
+		response := s.handle(request)
+		if response == nil {
+			continue
+		}
+		out, err := response.MarshalMsg()
+		if err != nil {
+			continue
+		}
+		s.conn.WriteTo(out, addr)
+	}
+}
+
+// SetCommunities makes the sign answer GETs with the read or the write
+// community and SETs with the write community only. Like a real agent, it
+// does not answer a request with another community.
+func (s *Sign) SetCommunities(read, write string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readCommunity, s.writeCommunity = read, write
+}
+
+// SetVersions makes the sign answer the requests of versions only, e.g. a
+// controller that only speaks SNMPv1. Like a real agent, it does not answer
+// a request of another version.
+func (s *Sign) SetVersions(versions ...gosnmp.SnmpVersion) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions = versions
+}
+
+// supported reports whether version is one of versions.
+func supported(versions []gosnmp.SnmpVersion, version gosnmp.SnmpVersion) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Drop makes the sign ignore the next n SNMP requests, like a lossy link.
+func (s *Sign) Drop(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop = n
+}
+
+// handle answers request, or returns nil if the community or the version of
+// the request is not accepted or the request is dropped.
+func (s *Sign) handle(request *gosnmp.SnmpPacket) *gosnmp.SnmpPacket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.drop > 0 {
+		s.drop--
+		return nil
+	}
+	if s.writeCommunity != "" && request.Community != s.writeCommunity &&
+		(request.PDUType == gosnmp.SetRequest || request.Community != s.readCommunity) {
+		return nil
+	}
+	if s.versions != nil && !supported(s.versions, request.Version) {
+		return nil
+	}
+
+	response := &gosnmp.SnmpPacket{
+		Version:   request.Version,
+		Community: request.Community,
+		PDUType:   gosnmp.GetResponse,
+		RequestID: request.RequestID,
+	}
+	s.tick()
+	switch request.PDUType {
+	case gosnmp.GetRequest:
+		response.Variables, response.Error, response.ErrorIndex = s.get(request.Version, request.Variables)
+	case gosnmp.GetNextRequest:
+		response.Variables, response.Error, response.ErrorIndex = s.getNext(request.Version, request.Variables)
+	case gosnmp.GetBulkRequest:
+		response.Variables = s.getBulk(request)
+	case gosnmp.SetRequest:
+		response.Variables, response.Error, response.ErrorIndex = s.set(request.Version, request.Variables)
+	default:
+		response.Variables = request.Variables
+		response.Error = gosnmp.GenErr
+		response.ErrorIndex = 1
+	}
+	if response.Error != gosnmp.NoError {
+		response.Variables = request.Variables
+	}
+	return response
+}
+
+func (s *Sign) get(version gosnmp.SnmpVersion, variables []gosnmp.SnmpPDU) ([]gosnmp.SnmpPDU, gosnmp.SNMPError, uint8) {
+	results := make([]gosnmp.SnmpPDU, 0, len(variables))
+	for i, variable := range variables {
+		pdu, ok := s.objects[key(variable.Name)]
+		if !ok {
+			if version == gosnmp.Version1 {
+				return nil, gosnmp.NoSuchName, uint8(i + 1)
+			}
+			pdu = gosnmp.SnmpPDU{Name: variable.Name, Type: gosnmp.NoSuchInstance}
+		}
+		results = append(results, pdu)
+	}
+	return results, gosnmp.NoError, 0
+}
+
+// getNext answers a GETNEXT with the objects that follow the requested
+// ones in lexicographic order.
+func (s *Sign) getNext(version gosnmp.SnmpVersion, variables []gosnmp.SnmpPDU) ([]gosnmp.SnmpPDU, gosnmp.SNMPError, uint8) {
+	if s.names == nil {
+		s.names = make([]string, 0, len(s.objects))
+		for name := range s.objects {
+			s.names = append(s.names, name)
+		}
+		sort.Slice(s.names, func(i, j int) bool { return compareOID(s.names[i], s.names[j]) < 0 })
+	}
+	names := s.names
+	results := make([]gosnmp.SnmpPDU, 0, len(variables))
+	for i, variable := range variables {
+		name := key(variable.Name)
+		next := sort.Search(len(names), func(j int) bool { return compareOID(names[j], name) > 0 })
+		if next == len(names) {
+			if version == gosnmp.Version1 {
+				return nil, gosnmp.NoSuchName, uint8(i + 1)
+			}
+			results = append(results, gosnmp.SnmpPDU{Name: variable.Name, Type: gosnmp.EndOfMibView})
+			continue
+		}
+		results = append(results, s.objects[names[next]])
+	}
+	return results, gosnmp.NoError, 0
+}
+
+// maxRepetitions returns the max-repetitions of a SNMPv2c GETBULK packet,
+// which gosnmp decodes as 0: it is the INTEGER following the request-id and
+// the non-repeaters of the PDU.
+func maxRepetitions(packet []byte) uint32 {
+	// Enter the message, skip the version and the community, enter the PDU
+	// and skip the request-id and the non-repeaters.
+	i := enterBER(packet, 0)
+	i = skipBER(packet, skipBER(packet, i))
+	i = skipBER(packet, skipBER(packet, enterBER(packet, i)))
+	if i < 0 || i >= len(packet) || packet[i] != byte(gosnmp.Integer) {
+		return 0
+	}
+	start := enterBER(packet, i)
+	end := skipBER(packet, i)
+	if start < 0 || end < 0 {
+		return 0
+	}
+	var value uint32
+	for _, b := range packet[start:end] {
+		value = value<<8 | uint32(b)
+	}
+	return value & 0x7FFFFFFF
+}
+
+// enterBER returns the offset of the contents of the BER element at i, or
+// -1 if it is truncated.
+func enterBER(packet []byte, i int) int {
+	if i < 0 || i+1 >= len(packet) {
+		return -1
+	}
+	length := int(packet[i+1])
+	if length&0x80 == 0 {
+		return i + 2
+	}
+	return i + 2 + length&0x7F
+}
+
+// skipBER returns the offset following the BER element at i, or -1 if it is
+// truncated.
+func skipBER(packet []byte, i int) int {
+	start := enterBER(packet, i)
+	if start < 0 || start > len(packet) {
+		return -1
+	}
+	length := int(packet[i+1])
+	if length&0x80 != 0 {
+		length = 0
+		for _, b := range packet[i+2 : start] {
+			length = length<<8 | int(b)
+		}
+	}
+	if start+length > len(packet) {
+		return -1
+	}
+	return start + length
+}
+
+// getBulk answers a SNMPv2c GETBULK: the objects following the first
+// NonRepeaters requested ones, then up to MaxRepetitions rows of the objects
+// following the others.
+func (s *Sign) getBulk(request *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	nonRepeaters := int(request.NonRepeaters)
+	if nonRepeaters > len(request.Variables) {
+		nonRepeaters = len(request.Variables)
+	}
+	results, _, _ := s.getNext(request.Version, request.Variables[:nonRepeaters])
+	row := request.Variables[nonRepeaters:]
+	for repetition := uint32(0); repetition < request.MaxRepetitions && len(row) > 0; repetition++ {
+		row, _, _ = s.getNext(request.Version, row)
+		results = append(results, row...)
+		ended := true
+		for _, variable := range row {
+			ended = ended && variable.Type == gosnmp.EndOfMibView
+		}
+		if ended {
+			break
+		}
+	}
+	return results
+}
+
+// compareOID compares two object identifiers sub-identifier by
+// sub-identifier.
+func compareOID(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(as) - len(bs)
+}
+
+func (s *Sign) set(version gosnmp.SnmpVersion, variables []gosnmp.SnmpPDU) ([]gosnmp.SnmpPDU, gosnmp.SNMPError, uint8) {
+	for i, variable := range variables {
+		current, ok := s.objects[key(variable.Name)]
+		if !ok && creatable(key(variable.Name)) {
+			current, ok = variable, true
+		}
+		if !ok {
+			if version == gosnmp.Version1 {
+				return nil, gosnmp.NoSuchName, uint8(i + 1)
+			}
+			return nil, gosnmp.NotWritable, uint8(i + 1)
+		}
+		if current.Type != variable.Type {
+			if version == gosnmp.Version1 {
+				return nil, gosnmp.BadValue, uint8(i + 1)
+			}
+			return nil, gosnmp.WrongType, uint8(i + 1)
+		}
+		if status := s.write(variable); status != gosnmp.NoError {
+			return nil, status, uint8(i + 1)
+		}
+	}
+	return variables, gosnmp.NoError, 0
+}
+
+// write applies a single SET varbind and runs the controller logic attached
+// to it.
+func (s *Sign) write(variable gosnmp.SnmpPDU) gosnmp.SNMPError {
+	name := key(variable.Name)
+	if status, ok := s.writeFont(name, variable); ok {
+		if status == gosnmp.NoError {
+			s.changeDatabase()
+		}
+		return status
+	}
+	if status, ok := s.writeGraphic(name, variable); ok {
+		if status == gosnmp.NoError {
+			s.changeDatabase()
+		}
+		return status
+	}
+	if status, ok := s.writeIllum(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writePixelTest(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeLampTest(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeFanTest(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeDynamicObject(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeNotificationRow(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeEventClass(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeClock(name, variable); ok {
+		return status
+	}
+	if status, ok := s.writeTimeZone(name, variable); ok {
+		return status
+	}
+	if name == key(d.DmsActivateMessage.Identifier(0)) {
+		code, _ := variable.Value.([]byte)
+		return s.activate(code)
+	}
+	if name == key(d.DmsMemoryMgmt.Identifier(0)) {
+		return s.manageMemory(variable)
+	}
+	if name == key(d.DmsSWReset.Identifier(0)) && variable.Value == 1 {
+		s.reset()
+		return gosnmp.NoError
+	}
+	messageMemoryType, messageNumber, column, ok := parseRow(name)
+	if !ok {
+		s.store(name, variable.Type, variable.Value)
+		return gosnmp.NoError
+	}
+	statusOID := rowOID(d.DmsMessageStatus, messageMemoryType, messageNumber)
+	if name != statusOID {
+		if column == 5 {
+			return gosnmp.ReadOnly
+		}
+		if s.value(statusOID) != d.Modifying.Int() {
+			return gosnmp.GenErr
+		}
+		s.store(name, variable.Type, variable.Value)
+		return gosnmp.NoError
+	}
+
+	switch request, _ := variable.Value.(int); request {
+	case d.ModifyReq.Int():
+		s.store(statusOID, gosnmp.Integer, d.Modifying.Int())
+	case d.ValidateReq.Int():
+		if s.value(statusOID) != d.Modifying.Int() {
+			return gosnmp.GenErr
+		}
+		s.validate(messageMemoryType, messageNumber)
+	case d.NotUsedReq.Int():
+		s.clearRow(messageMemoryType, messageNumber)
+	default:
+		return gosnmp.BadValue
+	}
+	s.count(messageMemoryType)
+	return gosnmp.NoError
+}
+
+// count updates the number of valid messages and the free memory of a
+// message memory type; each valid message uses the bytes of its MULTI string.
+func (s *Sign) count(messageMemoryType int) {
+	numOID, freeOID, rows := d.DmsNumChangeableMsg.Identifier(0), d.DmsFreeChangeableMemory.Identifier(0), MaxChangeableMsg
+	if messageMemoryType == d.MemoryVolatile.Int() {
+		numOID, freeOID, rows = d.DmsNumVolatileMsg.Identifier(0), d.DmsFreeVolatileMemory.Identifier(0), MaxVolatileMsg
+	}
+	num, free := 0, MessageMemory
+	for number := 1; number <= rows; number++ {
+		if s.value(rowOID(d.DmsMessageStatus, messageMemoryType, number)) != d.Valid.Int() {
+			continue
+		}
+		multi, _ := s.value(rowOID(d.DmsMessageMultiString, messageMemoryType, number)).([]byte)
+		num++
+		free -= len(multi)
+	}
+	s.store(numOID, gosnmp.Integer, num)
+	s.store(freeOID, gosnmp.Integer, free)
+}
+
+// parseRow splits a dmsMessageTable OID of a changeable or volatile row into
+// its index and column number.
+func parseRow(name string) (messageMemoryType, messageNumber, column int, ok bool) {
+	entry := strings.TrimSuffix(key(d.DmsMessageMemoryType.Identifier(0)), "1.0")
+	if !strings.HasPrefix(name, entry) {
+		return 0, 0, 0, false
+	}
+	if _, err := fmt.Sscanf(strings.TrimPrefix(name, entry), "%d.%d.%d", &column, &messageMemoryType, &messageNumber); err != nil {
+		return 0, 0, 0, false
+	}
+	if messageMemoryType != d.MemoryChangeable.Int() && messageMemoryType != d.MemoryVolatile.Int() {
+		return 0, 0, 0, false
+	}
+	return messageMemoryType, messageNumber, column, true
+}
+
+// validate runs the consistency check of a message: the MULTI string must
+// have balanced tags.
+func (s *Sign) validate(messageMemoryType, messageNumber int) {
+	multi, _ := s.value(rowOID(d.DmsMessageMultiString, messageMemoryType, messageNumber)).([]byte)
+	statusOID := rowOID(d.DmsMessageStatus, messageMemoryType, messageNumber)
+	if position, description := checkMulti(string(multi)); position > 0 {
+		s.store(statusOID, gosnmp.Integer, d.Error.Int())
+		s.store(d.DmsValidateMessageError.Identifier(0), gosnmp.Integer, d.SyntaxMULTI.Int())
+		s.store(d.DmsMultiSyntaxError.Identifier(0), gosnmp.Integer, 1)
+		s.store(d.DmsMultiSyntaxErrorPosition.Identifier(0), gosnmp.Integer, position)
+		s.store(d.DmsMultiOtherErrorDescription.Identifier(0), gosnmp.OctetString, []byte(description))
+		return
+	}
+	beacon, _ := s.value(rowOID(d.DmsMessageBeacon, messageMemoryType, messageNumber)).(int)
+	pixelService, _ := s.value(rowOID(d.DmsMessagePixelService, messageMemoryType, messageNumber)).(int)
+	s.store(crcOID(messageMemoryType, messageNumber), gosnmp.Integer, int(crc.MessageCRC(string(multi), beacon, pixelService)))
+	s.store(statusOID, gosnmp.Integer, d.Valid.Int())
+	s.store(d.DmsValidateMessageError.Identifier(0), gosnmp.Integer, d.None.Int())
+}
+
+// checkMulti returns the 1-based position of the first unbalanced tag
+// bracket in multi, or 0 if there is none.
+func checkMulti(multi string) (int, string) {
+	open := 0
+	for i, c := range multi {
+		switch {
+		case c == '[' && open > 0:
+			return i + 1, "nested tag"
+		case c == '[':
+			open = i + 1
+		case c == ']' && open == 0:
+			return i + 1, "unexpected ]"
+		case c == ']':
+			open = 0
+		}
+	}
+	if open > 0 {
+		return open, "unterminated tag"
+	}
+	return 0, ""
+}
+
+// activate runs the dmsActivateMessage consistency check and, if it passes,
+// copies the message into the currentBuffer. The activation priority must be
+// at least the run-time priority of the current message.
+func (s *Sign) activate(code []byte) gosnmp.SNMPError {
+	fail := func(activateMsgError int) gosnmp.SNMPError {
+		s.store(d.DmsActivateMsgError.Identifier(0), gosnmp.Integer, activateMsgError)
+		s.store(d.DmsActivateErrorMsgCode.Identifier(0), gosnmp.OctetString, code)
+		return gosnmp.GenErr
+	}
+	if len(code) != 12 {
+		return gosnmp.BadValue
+	}
+	duration := int(binary.BigEndian.Uint16(code[0:2]))
+	priority := int(code[2])
+	messageMemoryType := int(code[3])
+	messageNumber := int(binary.BigEndian.Uint16(code[4:6]))
+	crc := int(binary.BigEndian.Uint16(code[6:8]))
+
+	status, ok := s.value(rowOID(d.DmsMessageStatus, messageMemoryType, messageNumber)).(int)
+	switch {
+	case messageMemoryType == d.MemoryCurrentBuffer.Int() || messageMemoryType == d.MemorySchedule.Int():
+		return fail(5)
+	case !ok:
+		return fail(6)
+	case status != d.Valid.Int():
+		return fail(4)
+	case crc != s.value(crcOID(messageMemoryType, messageNumber)):
+		return fail(7)
+	case s.value(d.DmsControlMode.Identifier(0)) == d.ControlLocal.Int():
+		return fail(9)
+	case priority < s.value(rowOID(d.DmsMessageRunTimePriority, d.MemoryCurrentBuffer.Int(), 1)).(int):
+		return fail(3)
+	}
+
+	s.copyRow(messageMemoryType, messageNumber, d.MemoryCurrentBuffer.Int(), 1)
+	s.store(d.DmsActivateMessage.Identifier(0), gosnmp.OctetString, code)
+	s.store(d.DmsActivateMsgError.Identifier(0), gosnmp.Integer, 2)
+	s.store(d.DmsMessageTimeRemaining.Identifier(0), gosnmp.Integer, duration)
+	s.store(d.DmsMsgTableSource.Identifier(0), gosnmp.OctetString, append([]byte{byte(messageMemoryType)}, code[4:8]...))
+	s.store(d.DmsMsgRequesterID.Identifier(0), gosnmp.OctetString, append([]byte{}, code[8:12]...))
+	s.store(d.DmsMsgSourceMode.Identifier(0), gosnmp.Integer, d.SourceCentral.Int())
+	return gosnmp.NoError
+}
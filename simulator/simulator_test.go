@@ -0,0 +1,22 @@
+package simulator
+
+import "testing"
+
+func Test_checkMulti(t *testing.T) {
+	tests := []struct {
+		multi    string
+		position int
+	}{
+		{multi: "[jp3]ROAD WORK[nl]AHEAD", position: 0},
+		{multi: "[jp3ROAD WORK", position: 1},
+		{multi: "ROAD] WORK", position: 5},
+		{multi: "[jp3[nl]", position: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.multi, func(t *testing.T) {
+			if got, _ := checkMulti(tt.multi); got != tt.position {
+				t.Errorf("checkMulti() = %d, want %d", got, tt.position)
+			}
+		})
+	}
+}
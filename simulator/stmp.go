@@ -0,0 +1,134 @@
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+	"github.com/jacobleehei/godms/stmp"
+)
+
+// nullOID is the value of an unused dynObjVariable.
+const nullOID = ".0.0"
+
+// seedDynamicObjects leaves the 13 dynamic objects undefined and invalid.
+func (s *Sign) seedDynamicObjects() {
+	for number := 1; number <= d.MaxDynObjects; number++ {
+		s.store(d.DynObjConfigOwner.Identifier(number), gosnmp.OctetString, []byte{})
+		s.store(d.DynObjConfigStatus.Identifier(number), gosnmp.Integer, d.EntryInvalid.Int())
+		for index := 1; index <= d.MaxDynVariables; index++ {
+			s.store(dynObjVariableOID(number, index), gosnmp.ObjectIdentifier, nullOID)
+		}
+	}
+}
+
+func dynObjVariableOID(number, index int) string {
+	return fmt.Sprintf("%s.%d", d.DynObjVariable.Identifier(number), index)
+}
+
+// SetSTMPType sets the STMP encoding of the object oid in the dynamic
+// objects. By default, the sign encodes octet strings as stmp.OctetString
+// and every other object as stmp.Int32.
+func (s *Sign) SetSTMPType(oid string, t stmp.Type) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stmpTypes == nil {
+		s.stmpTypes = map[string]stmp.Type{}
+	}
+	s.stmpTypes[key(oid)] = t
+}
+
+// writeDynamicObject applies a SET of a dynObjVariable, which is only
+// accepted while the dynamic object is underCreation.
+func (s *Sign) writeDynamicObject(name string, variable gosnmp.SnmpPDU) (gosnmp.SNMPError, bool) {
+	for number := 1; number <= d.MaxDynObjects; number++ {
+		if !strings.HasPrefix(name, key(d.DynObjVariable.Identifier(number))+".") {
+			continue
+		}
+		if s.value(d.DynObjConfigStatus.Identifier(number)) != d.EntryUnderCreation.Int() {
+			return gosnmp.GenErr, true
+		}
+		s.store(name, variable.Type, variable.Value)
+		return gosnmp.NoError, true
+	}
+	return gosnmp.NoError, false
+}
+
+// dynamicObject returns the variables of a valid dynamic object with the
+// STMP encoding of their objects.
+func (s *Sign) dynamicObject(number int) ([]stmp.Variable, []gosnmp.SnmpPDU, bool) {
+	if s.value(d.DynObjConfigStatus.Identifier(number)) != d.EntryValid.Int() {
+		return nil, nil, false
+	}
+	var variables []stmp.Variable
+	var pdus []gosnmp.SnmpPDU
+	for index := 1; index <= d.MaxDynVariables; index++ {
+		oid, _ := s.value(dynObjVariableOID(number, index)).(string)
+		if oid == "" || oid == nullOID {
+			break
+		}
+		pdu, ok := s.objects[key(oid)]
+		if !ok {
+			pdu = gosnmp.SnmpPDU{Name: oid, Type: gosnmp.Null}
+		}
+		t, ok := s.stmpTypes[key(oid)]
+		if !ok {
+			t = stmp.Int32
+			if pdu.Type == gosnmp.OctetString {
+				t = stmp.OctetString
+			}
+		}
+		variables = append(variables, stmp.Variable{OID: oid, Type: t})
+		pdus = append(pdus, pdu)
+	}
+	return variables, pdus, len(variables) > 0
+}
+
+// handleSTMP answers an STMP request, or returns nil for a message that
+// gets no answer.
+func (s *Sign) handleSTMP(request []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, number, ok := stmp.ParseHeader(request[0])
+	if !ok {
+		return nil
+	}
+	variables, pdus, ok := s.dynamicObject(number)
+	if !ok {
+		return stmp.EncodeError(number, gosnmp.GenErr, 0)
+	}
+	switch t {
+	case stmp.GetRequest:
+		values := make([]interface{}, len(pdus))
+		for i, pdu := range pdus {
+			if pdu.Type == gosnmp.Null {
+				return stmp.EncodeError(number, gosnmp.NoSuchName, i+1)
+			}
+			values[i] = pdu.Value
+		}
+		data, err := stmp.EncodeValues(variables, values)
+		if err != nil {
+			return stmp.EncodeError(number, gosnmp.GenErr, 0)
+		}
+		return append([]byte{stmp.Header(stmp.GetResponse, number)}, data...)
+	case stmp.SetRequest, stmp.SetRequestNoReply:
+		values, err := stmp.DecodeValues(variables, request[1:])
+		if err != nil {
+			return stmp.EncodeError(number, gosnmp.BadValue, 0)
+		}
+		for i, pdu := range pdus {
+			if pdu.Type == gosnmp.Null {
+				return stmp.EncodeError(number, gosnmp.NoSuchName, i+1)
+			}
+			if status := s.write(gosnmp.SnmpPDU{Name: pdu.Name, Type: pdu.Type, Value: values[i]}); status != gosnmp.NoError {
+				return stmp.EncodeError(number, status, i+1)
+			}
+		}
+		if t == stmp.SetRequestNoReply {
+			return nil
+		}
+		return []byte{stmp.Header(stmp.SetResponse, number)}
+	}
+	return stmp.EncodeError(number, gosnmp.GenErr, 0)
+}
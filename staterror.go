@@ -2,7 +2,7 @@ package godms
 
 import (
 	"errors"
-	"strconv"
+	"strings"
 )
 
 /**************************************************************************
@@ -16,8 +16,7 @@ message error status functions that are common to DMS devices.
 // A bitmap of summary errors. When a bit is set, the error is
 // presently active. When a bit is clear the error is not currently active. If
 // no sensor is present or supported (for a corresponding bit), the bit
-// shall not be set.
-//   The bits are defined as follows:
+// shall not be set. The bits are decoded by ShortErrorStatusBits.
 var ShortErrorStatus = readOnlyObject{
 	objectType: "shortErrorStatus",
 	syntax:     INTEGER,
@@ -25,41 +24,401 @@ var ShortErrorStatus = readOnlyObject{
 	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.1",
 }
 
+// ShortErrorStatusBits is a decoded shortErrorStatus bitmap. The object
+// variable already owns the name ShortErrorStatus.
+type ShortErrorStatusBits int
+
+// Bits of shortErrorStatus.
+const (
+	ShortErrorCommunications      ShortErrorStatusBits = 1 << 1
+	ShortErrorPower               ShortErrorStatusBits = 1 << 2
+	ShortErrorAttachedDevice      ShortErrorStatusBits = 1 << 3
+	ShortErrorLamp                ShortErrorStatusBits = 1 << 4
+	ShortErrorPixel               ShortErrorStatusBits = 1 << 5
+	ShortErrorPhotocell           ShortErrorStatusBits = 1 << 6
+	ShortErrorMessage             ShortErrorStatusBits = 1 << 7
+	ShortErrorController          ShortErrorStatusBits = 1 << 8
+	ShortErrorTemperatureWarning  ShortErrorStatusBits = 1 << 9
+	ShortErrorClimateControl      ShortErrorStatusBits = 1 << 10
+	ShortErrorCriticalTemperature ShortErrorStatusBits = 1 << 11
+	ShortErrorDrumRotor           ShortErrorStatusBits = 1 << 12
+	ShortErrorDoorOpen            ShortErrorStatusBits = 1 << 13
+	ShortErrorHumidityWarning     ShortErrorStatusBits = 1 << 14
+)
+
+var shortErrorNames = []struct {
+	bit  ShortErrorStatusBits
+	name string
+}{
+	{ShortErrorCommunications, "communicationsError"},
+	{ShortErrorPower, "powerError"},
+	{ShortErrorAttachedDevice, "attachedDeviceError"},
+	{ShortErrorLamp, "lampError"},
+	{ShortErrorPixel, "pixelError"},
+	{ShortErrorPhotocell, "photocellError"},
+	{ShortErrorMessage, "messageError"},
+	{ShortErrorController, "controllerError"},
+	{ShortErrorTemperatureWarning, "temperatureWarning"},
+	{ShortErrorClimateControl, "climateControlSystemError"},
+	{ShortErrorCriticalTemperature, "criticalTemperatureError"},
+	{ShortErrorDrumRotor, "drumSignRotorError"},
+	{ShortErrorDoorOpen, "doorOpenError"},
+	{ShortErrorHumidityWarning, "humidityWarning"},
+}
+
+func (m ShortErrorStatusBits) Int() int { return int(m) }
+
+// Has reports whether every bit of bits is set.
+func (m ShortErrorStatusBits) Has(bits ShortErrorStatusBits) bool { return m&bits == bits }
+
+func (m ShortErrorStatusBits) CommunicationsError() bool { return m.Has(ShortErrorCommunications) }
+func (m ShortErrorStatusBits) PowerError() bool          { return m.Has(ShortErrorPower) }
+func (m ShortErrorStatusBits) AttachedDeviceError() bool { return m.Has(ShortErrorAttachedDevice) }
+func (m ShortErrorStatusBits) LampError() bool           { return m.Has(ShortErrorLamp) }
+func (m ShortErrorStatusBits) PixelError() bool          { return m.Has(ShortErrorPixel) }
+func (m ShortErrorStatusBits) PhotocellError() bool      { return m.Has(ShortErrorPhotocell) }
+func (m ShortErrorStatusBits) MessageError() bool        { return m.Has(ShortErrorMessage) }
+func (m ShortErrorStatusBits) ControllerError() bool     { return m.Has(ShortErrorController) }
+func (m ShortErrorStatusBits) TemperatureWarning() bool  { return m.Has(ShortErrorTemperatureWarning) }
+func (m ShortErrorStatusBits) ClimateControlError() bool { return m.Has(ShortErrorClimateControl) }
+func (m ShortErrorStatusBits) CriticalTemperature() bool { return m.Has(ShortErrorCriticalTemperature) }
+func (m ShortErrorStatusBits) DrumRotorError() bool      { return m.Has(ShortErrorDrumRotor) }
+func (m ShortErrorStatusBits) DoorOpen() bool            { return m.Has(ShortErrorDoorOpen) }
+func (m ShortErrorStatusBits) HumidityWarning() bool     { return m.Has(ShortErrorHumidityWarning) }
+
+// Names returns the NTCIP 1203 names of the set bits, lowest bit first, e.g.
+// "pixelError". Reserved bits are not named.
+func (m ShortErrorStatusBits) Names() []string {
+	var names []string
+	for _, n := range shortErrorNames {
+		if m.Has(n.bit) {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+// String returns the names of the set bits separated by commas, or "noError".
+func (m ShortErrorStatusBits) String() string {
+	if names := m.Names(); len(names) > 0 {
+		return strings.Join(names, ", ")
+	}
+	return "noError"
+}
+
 func formatShortErrorStatusParameter(getResult interface{}) (interface{}, error) {
-	var formatMap = map[int]string{
-		0:  "Reserved",
-		1:  "Invalid",
-		2:  "AC Error",
-		3:  "Wigwag Error",
-		4:  "Device Error",
-		5:  "Pixel Error",
-		6:  "Photocell Error",
-		7:  "Message Error",
-		8:  "Controller Error",
-		9:  "Temperature Error",
-		10: "Invalid",
-		11: "No Temperature",
-		12: "Invalid",
-		13: "Door Error",
-		14: "Invalid",
+	r, ok := getResult.(int)
+	if !ok {
+		return ShortErrorStatusBits(0), errors.New(`expect int type for "shortErrorStatus"`)
 	}
+	return ShortErrorStatusBits(r), nil
+}
+
+// Indicates the number of rows in the dmsLightSensorStatusTable, i.e. the
+// number of light sensors (photocells) of the sign.
+var DmsLightSensorNumRows = readOnlyObject{
+	objectType: "dmsLightSensorNumRows",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.26",
+}
+
+// The index of a light sensor, a row of the dmsLightSensorStatusTable.
+var DmsLightSensorIndex = readOnlyObject{
+	objectType: "dmsLightSensorIndex",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.27.1.1",
+}
+
+// A description of the light sensor, e.g. its location on the sign.
+var DmsLightSensorDescription = readOnlyObject{
+	objectType: "dmsLightSensorDescription",
+	syntax:     DISPLAY_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.27.1.2",
+}
+
+// The current reading of the light sensor, ranging from 0 (darkest) to the
+// value of the dmsIllumMaxPhotocellLevel object (brightest).
+var DmsLightSensorCurrentReading = readOnlyObject{
+	objectType: "dmsLightSensorCurrentReading",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.27.1.3",
+}
 
+// The status of the light sensor:
+//   other (1) - an error not defined by this standard,
+//   noError (2) - the sensor is working,
+//   noResponse (3) - the sensor does not respond,
+//   fail (4) - the sensor reports a failure or an implausible reading.
+var DmsLightSensorStatus = readOnlyObject{
+	objectType: "dmsLightSensorStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.27.1.4",
+}
+
+type lightSensorStatusFormat int
+
+const (
+	LightSensorOther      lightSensorStatusFormat = 1
+	LightSensorNoError    lightSensorStatusFormat = 2
+	LightSensorNoResponse lightSensorStatusFormat = 3
+	LightSensorFail       lightSensorStatusFormat = 4
+)
+
+func (m lightSensorStatusFormat) Int() int { return int(m) }
+
+func formatDmsLightSensorStatus(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "noError",
+		3: "noResponse",
+		4: "fail",
+	}
 	r, ok := getResult.(int)
 	if !ok {
-		return "", errors.New(`expect int type for "shortErrorStatus"`)
+		return "", errors.New(`expect int type for "formatDmsLightSensorStatus"`)
 	}
+	return formatMap[r], nil
+}
 
-	var result []string
-	binaryResult := strconv.FormatInt(int64(r), 2)
-	for idx, bit := range binaryResult {
-		if idx == 0 {
-			continue
-		}
+// Indicates the number of rows in the pixelFailureTable, i.e. the number of
+// failed pixels detected.
+var PixelFailureTableNumRows = readOnlyObject{
+	objectType: "pixelFailureTableNumRows",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.2",
+}
 
-		if bit == '1' {
-			result = append(result, formatMap[idx])
-		}
+// The way the failure of a row of the pixelFailureTable was detected, the
+// first index of the table: pixelTest (1), by the pixel test, or
+// messageDisplay (2), while a message was displayed.
+var PixelFailureDetectionType = readOnlyObject{
+	objectType: "pixelFailureDetectionType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.3.1.1",
+}
+
+type pixelFailureDetectionTypeFormat int
+
+const (
+	PixelTestDetection      pixelFailureDetectionTypeFormat = 1
+	MessageDisplayDetection pixelFailureDetectionTypeFormat = 2
+)
+
+func (m pixelFailureDetectionTypeFormat) Int() int { return int(m) }
+
+// The index of a failed pixel within its detection type, the second index
+// of the pixelFailureTable.
+var PixelFailureIndex = readOnlyObject{
+	objectType: "pixelFailureIndex",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.3.1.2",
+}
+
+// The column of the failed pixel, counted from 1 at the left of the sign.
+var PixelFailureXLocation = readOnlyObject{
+	objectType: "pixelFailureXLocation",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.3.1.3",
+}
+
+// The row of the failed pixel, counted from 1 at the top of the sign.
+var PixelFailureYLocation = readOnlyObject{
+	objectType: "pixelFailureYLocation",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.3.1.4",
+}
+
+// A bitmap of the failures of the pixel:
+//   Bit 0: stuck on
+//   Bit 1: stuck off
+//   Bit 2: color error
+//   Bit 3: electrical error
+//   Bit 4: mechanical error
+//   Bit 5: partial failure
+var PixelFailureStatus = readOnlyObject{
+	objectType: "pixelFailureStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.3.1.5",
+}
+
+type pixelFailureStatusFormat int
+
+const (
+	PixelStuckOn         pixelFailureStatusFormat = 1 << 0
+	PixelStuckOff        pixelFailureStatusFormat = 1 << 1
+	PixelColorError      pixelFailureStatusFormat = 1 << 2
+	PixelElectricalError pixelFailureStatusFormat = 1 << 3
+	PixelMechanicalError pixelFailureStatusFormat = 1 << 4
+	PixelPartialFailure  pixelFailureStatusFormat = 1 << 5
+)
+
+func (m pixelFailureStatusFormat) Int() int { return int(m) }
+
+// Indicates the state of the pixel test. Setting this object to test (2)
+// starts a test of all pixels, which rebuilds the rows of the
+// pixelFailureTable detected by pixelTest; the sign sets it back to
+// noTest (1) when the test completes. Setting it to clearTable (3) clears
+// the pixelFailureTable.
+var PixelTestActivation = readAndWriteObject{
+	objectType: "pixelTestActivation",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.4",
+}
+
+type testActivationFormat int
+
+const (
+	NoTest     testActivationFormat = 1
+	Test       testActivationFormat = 2
+	ClearTable testActivationFormat = 3
+)
+
+func (m testActivationFormat) Int() int { return int(m) }
+
+// Identifies the lamps that are stuck on, as a bitmap where bit 0 of the
+// first octet is lamp 1, bit 1 lamp 2 and so on. Updated by the lamp test.
+var LampFailureStuckOn = readOnlyObject{
+	objectType: "lampFailureStuckOn",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.5",
+}
+
+// Identifies the lamps that are stuck off, with the bitmap of
+// lampFailureStuckOn.
+var LampFailureStuckOff = readOnlyObject{
+	objectType: "lampFailureStuckOff",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.6",
+}
+
+// Indicates the state of the lamp test, with the values of
+// pixelTestActivation except clearTable. Setting this object to test (2)
+// starts a test of all lamps, which updates lampFailureStuckOn and
+// lampFailureStuckOff; the sign sets it back to noTest (1) when the test
+// completes.
+var LampTestActivation = readAndWriteObject{
+	objectType: "lampTestActivation",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.7",
+}
+
+// Identifies the fans that failed, as a bitmap where bit 0 of the first
+// octet is fan 1, bit 1 fan 2 and so on. Updated by the fan test.
+var FanFailures = readOnlyObject{
+	objectType: "fanFailures",
+	syntax:     OCTET_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.8",
+}
+
+// Indicates the state of the fan test, with the values of
+// pixelTestActivation except clearTable. Setting this object to test (2)
+// starts a test of all fans, which updates fanFailures; the sign sets it
+// back to noTest (1) when the test completes.
+var FanTestActivation = readAndWriteObject{
+	objectType: "fanTestActivation",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.9",
+}
+
+// Indicates the number of rows in the dmsPowerStatusTable, i.e. the number of
+// power supplies of the sign.
+var DmsPowerNumRows = readOnlyObject{
+	objectType: "dmsPowerNumRows",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.12",
+}
+
+// The index of a power supply, a row of the dmsPowerStatusTable.
+var DmsPowerIndex = readOnlyObject{
+	objectType: "dmsPowerIndex",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.13.1.1",
+}
+
+// A description of the power supply, e.g. its location in the sign.
+var DmsPowerDescription = readOnlyObject{
+	objectType: "dmsPowerDescription",
+	syntax:     DISPLAY_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.13.1.2",
+}
+
+// A manufacturer specific status of the power supply.
+var DmsPowerMfrStatus = readOnlyObject{
+	objectType: "dmsPowerMfrStatus",
+	syntax:     DISPLAY_STRING,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.13.1.3",
+}
+
+// The status of the power supply, e.g. noError (2), powerFail (3),
+// voltageOutOfSpec (4) or currentOutOfSpec (5).
+var DmsPowerStatus = readOnlyObject{
+	objectType: "dmsPowerStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.13.1.4",
+}
+
+type powerStatusFormat int
+
+const (
+	PowerStatusOther      powerStatusFormat = 1
+	PowerNoError          powerStatusFormat = 2
+	PowerFail             powerStatusFormat = 3
+	PowerVoltageOutOfSpec powerStatusFormat = 4
+	PowerCurrentOutOfSpec powerStatusFormat = 5
+)
+
+func (m powerStatusFormat) Int() int { return int(m) }
+
+func formatDmsPowerStatus(getResult interface{}) (result interface{}, err error) {
+	var formatMap = map[int]string{
+		1: "other",
+		2: "noError",
+		3: "powerFail",
+		4: "voltageOutOfSpec",
+		5: "currentOutOfSpec",
 	}
+	r, ok := getResult.(int)
+	if !ok {
+		return "", errors.New(`expect int type for "formatDmsPowerStatus"`)
+	}
+	return formatMap[r], nil
+}
+
+// The output voltage of the power supply, in hundredths of a volt.
+var DmsPowerVoltage = readOnlyObject{
+	objectType: "dmsPowerVoltage",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.13.1.5",
+}
 
-	return result, nil
+// The type of the power supply, with the values of powerSource except
+// powerShutdown and noSignPower, e.g. acLine (4) or battery (7).
+var DmsPowerType = readOnlyObject{
+	objectType: "dmsPowerType",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.3.9.7.13.1.6",
 }
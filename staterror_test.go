@@ -1,7 +1,7 @@
 package godms
 
 import (
-	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -12,18 +12,30 @@ func Test_formatShortErrorStatusParameter(t *testing.T) {
 	tests := []struct {
 		name       string
 		args       args
-		wantResult []string
+		wantResult ShortErrorStatusBits
+		wantString string
 		wantErr    bool
 	}{
-		// TODO: Add test cases.
 		{
 			name: "test_1",
 			args: args{
 				getResult: 8258,
 			},
-			wantResult: []string{"Message Error", "Invalid"},
+			wantResult: ShortErrorCommunications | ShortErrorPhotocell | ShortErrorDoorOpen,
+			wantString: "communicationsError, photocellError, doorOpenError",
 			wantErr:    false,
 		},
+		{
+			name:       "noError",
+			args:       args{getResult: 1}, // the reserved bit is not named
+			wantResult: 1,
+			wantString: "noError",
+		},
+		{
+			name:    "notInt",
+			args:    args{getResult: "8258"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -32,9 +44,25 @@ func Test_formatShortErrorStatusParameter(t *testing.T) {
 				t.Errorf("formatShortErrorStatusParameter() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if fmt.Sprint(gotResult) != fmt.Sprint(tt.wantResult) {
+			if tt.wantErr {
+				return
+			}
+			if gotResult != tt.wantResult {
 				t.Errorf("formatShortErrorStatusParameter() = %v, want %v", gotResult, tt.wantResult)
 			}
+			if got := gotResult.(ShortErrorStatusBits).String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
 		})
 	}
 }
+
+func TestShortErrorStatusBits(t *testing.T) {
+	bits := ShortErrorPixel | ShortErrorCriticalTemperature
+	if !bits.PixelError() || !bits.CriticalTemperature() || bits.PowerError() || bits.LampError() {
+		t.Errorf("accessors of %v are wrong", bits)
+	}
+	if got, want := bits.Names(), []string{"pixelError", "criticalTemperatureError"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,336 @@
+// Package stmp encodes the messages of the Simple Transportation Management
+// Protocol (STMP) of NTCIP 1103, which exchanges the values of a dynamic
+// object, a list of up to 255 variables defined beforehand on the sign with
+// the dynObjMgmt objects, in a few octets instead of an SNMP message.
+//
+// An STMP message starts with a header octet: bit 7 set, the message type
+// in bits 6 to 4 and the dynamic object number, 1 to 13, in bits 3 to 0.
+// The values of the variables follow in order without tags: integers in
+// the fixed size of their range, most significant octet first, and octet
+// strings after their length. Since nothing but the header identifies the
+// message, the encoding of each variable must be known to both sides.
+package stmp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// MessageType is the type of an STMP message.
+type MessageType int
+
+const (
+	GetRequest        MessageType = 0
+	SetRequest        MessageType = 1
+	SetRequestNoReply MessageType = 2
+	GetNextRequest    MessageType = 3
+	GetResponse       MessageType = 4
+	SetResponse       MessageType = 5
+	ErrorResponse     MessageType = 6
+)
+
+func (t MessageType) String() string {
+	switch t {
+	case GetRequest:
+		return "get-request"
+	case SetRequest:
+		return "set-request"
+	case SetRequestNoReply:
+		return "set-request-no-reply"
+	case GetNextRequest:
+		return "get-next-request"
+	case GetResponse:
+		return "get-response"
+	case SetResponse:
+		return "set-response"
+	case ErrorResponse:
+		return "error-response"
+	}
+	return fmt.Sprintf("MessageType(%d)", int(t))
+}
+
+// Number of dynamic objects and of variables of a dynamic object.
+const (
+	MaxObjects   = 13
+	MaxVariables = 255
+)
+
+// Header returns the header octet of a message of type t for the dynamic
+// object number.
+func Header(t MessageType, number int) byte {
+	return 0x80 | byte(t&7)<<4 | byte(number&0x0F)
+}
+
+// ParseHeader returns the message type and the dynamic object number of
+// the header octet of a message, and false if b is not an STMP header,
+// e.g. the first octet of an SNMP message.
+func ParseHeader(b byte) (t MessageType, number int, ok bool) {
+	if b&0x80 == 0 {
+		return 0, 0, false
+	}
+	t, number = MessageType(b>>4&7), int(b&0x0F)
+	return t, number, t != 7 && number >= 1 && number <= MaxObjects
+}
+
+// Type is the encoding of a variable in an STMP message.
+type Type int
+
+const (
+	Uint8       Type = iota + 1 // INTEGER (0..255)
+	Uint16                      // INTEGER (0..65535)
+	Uint32                      // INTEGER (0..4294967295), Counter, Gauge
+	Int8                        // INTEGER (-128..127)
+	Int16                       // INTEGER (-32768..32767)
+	Int32                       // INTEGER without a narrower range
+	OctetString                 // OCTET STRING, DisplayString
+)
+
+func (t Type) String() string {
+	switch t {
+	case Uint8:
+		return "uint8"
+	case Uint16:
+		return "uint16"
+	case Uint32:
+		return "uint32"
+	case Int8:
+		return "int8"
+	case Int16:
+		return "int16"
+	case Int32:
+		return "int32"
+	case OctetString:
+		return "octetString"
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// size returns the number of octets of an integer type and its range.
+func (t Type) size() (n int, min, max int64) {
+	switch t {
+	case Uint8:
+		return 1, 0, 1<<8 - 1
+	case Uint16:
+		return 2, 0, 1<<16 - 1
+	case Uint32:
+		return 4, 0, 1<<32 - 1
+	case Int8:
+		return 1, -1 << 7, 1<<7 - 1
+	case Int16:
+		return 2, -1 << 15, 1<<15 - 1
+	case Int32:
+		return 4, -1 << 31, 1<<31 - 1
+	}
+	return 0, 0, 0
+}
+
+// Variable is a variable of a dynamic object: an object instance and its
+// encoding, which follows the range of the object in its MIB.
+type Variable struct {
+	OID  string // e.g. d.ShortErrorStatus.Identifier(0)
+	Type Type
+}
+
+// Object is the definition of a dynamic object.
+type Object struct {
+	Number    int    // dynObjNumber, 1 to MaxObjects
+	Owner     string // dynObjConfigOwner
+	Variables []Variable
+}
+
+// ErrObject is returned for an invalid dynamic object definition.
+var ErrObject = errors.New("invalid dynamic object")
+
+// Validate checks the number, the number of variables and their types.
+func (o Object) Validate() error {
+	if o.Number < 1 || o.Number > MaxObjects {
+		return fmt.Errorf("%w: number %d out of range 1-%d", ErrObject, o.Number, MaxObjects)
+	}
+	if len(o.Variables) == 0 || len(o.Variables) > MaxVariables {
+		return fmt.Errorf("%w: %d variables, want 1-%d", ErrObject, len(o.Variables), MaxVariables)
+	}
+	for i, v := range o.Variables {
+		if v.OID == "" || strings.Trim(v.OID, ".") == "0.0" {
+			return fmt.Errorf("%w: variable %d has no object identifier", ErrObject, i+1)
+		}
+		if v.Type < Uint8 || v.Type > OctetString {
+			return fmt.Errorf("%w: variable %d has %v", ErrObject, i+1, v.Type)
+		}
+	}
+	return nil
+}
+
+// EncodeGet returns the get-request of the dynamic object.
+func (o Object) EncodeGet() []byte {
+	return []byte{Header(GetRequest, o.Number)}
+}
+
+// EncodeSet returns the set-request, or the set-request-no-reply if
+// noReply, setting the variables of the dynamic object to values: an int
+// for the integer types and a []byte or string for OctetString.
+func (o Object) EncodeSet(values []interface{}, noReply bool) ([]byte, error) {
+	t := SetRequest
+	if noReply {
+		t = SetRequestNoReply
+	}
+	data, err := EncodeValues(o.Variables, values)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{Header(t, o.Number)}, data...), nil
+}
+
+// Error is an STMP error-response.
+type Error struct {
+	Status gosnmp.SNMPError // e.g. gosnmp.NoSuchName
+	Index  int              // 1-based index of the variable in error, 0 if none
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("STMP error-response: %v at variable %d", e.Status, e.Index)
+}
+
+// EncodeError returns the error-response of the dynamic object number.
+func EncodeError(number int, status gosnmp.SNMPError, index int) []byte {
+	return []byte{Header(ErrorResponse, number), byte(status), byte(index)}
+}
+
+// DecodeResponse decodes the response of the sign to a request for the
+// dynamic object: the values of a get-response, nil for a set-response,
+// and an *Error for an error-response.
+func (o Object) DecodeResponse(message []byte) ([]interface{}, error) {
+	if len(message) == 0 {
+		return nil, errors.New("empty STMP message")
+	}
+	t, number, ok := ParseHeader(message[0])
+	if !ok {
+		return nil, fmt.Errorf("not an STMP message: header %#02x", message[0])
+	}
+	if number != o.Number {
+		return nil, fmt.Errorf("STMP %v for dynamic object %d, want %d", t, number, o.Number)
+	}
+	switch t {
+	case GetResponse:
+		return DecodeValues(o.Variables, message[1:])
+	case SetResponse:
+		return nil, nil
+	case ErrorResponse:
+		if len(message) != 3 {
+			return nil, fmt.Errorf("STMP error-response of %d octets", len(message))
+		}
+		return nil, &Error{Status: gosnmp.SNMPError(message[1]), Index: int(message[2])}
+	}
+	return nil, fmt.Errorf("unexpected STMP %v", t)
+}
+
+// EncodeValues encodes values as the variables of a dynamic object.
+func EncodeValues(variables []Variable, values []interface{}) ([]byte, error) {
+	if len(values) != len(variables) {
+		return nil, fmt.Errorf("%d values for %d variables", len(values), len(variables))
+	}
+	var data []byte
+	for i, v := range variables {
+		if v.Type == OctetString {
+			var value []byte
+			switch s := values[i].(type) {
+			case []byte:
+				value = s
+			case string:
+				value = []byte(s)
+			default:
+				return nil, fmt.Errorf("variable %d (%s): %T value, want []byte or string", i+1, v.OID, values[i])
+			}
+			data = append(appendLength(data, len(value)), value...)
+			continue
+		}
+		value, ok := values[i].(int)
+		n, min, max := v.Type.size()
+		if !ok || n == 0 {
+			return nil, fmt.Errorf("variable %d (%s): %T value for %v", i+1, v.OID, values[i], v.Type)
+		}
+		if int64(value) < min || int64(value) > max {
+			return nil, fmt.Errorf("variable %d (%s): %d out of the range of %v", i+1, v.OID, value, v.Type)
+		}
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(value))
+		data = append(data, buf[8-n:]...)
+	}
+	return data, nil
+}
+
+// DecodeValues decodes the values of the variables of a dynamic object: an
+// int for the integer types and a []byte for OctetString.
+func DecodeValues(variables []Variable, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(variables))
+	for i, v := range variables {
+		if v.Type == OctetString {
+			length, n, err := decodeLength(data)
+			if err != nil || len(data) < n+length {
+				return nil, fmt.Errorf("variable %d (%s): truncated octet string", i+1, v.OID)
+			}
+			values = append(values, append([]byte{}, data[n:n+length]...))
+			data = data[n+length:]
+			continue
+		}
+		n, min, _ := v.Type.size()
+		if n == 0 {
+			return nil, fmt.Errorf("variable %d (%s): unknown %v", i+1, v.OID, v.Type)
+		}
+		if len(data) < n {
+			return nil, fmt.Errorf("variable %d (%s): truncated %v", i+1, v.OID, v.Type)
+		}
+		var value int64
+		for _, b := range data[:n] {
+			value = value<<8 | int64(b)
+		}
+		if min < 0 && value >= 1<<(8*n-1) {
+			value -= 1 << (8 * n)
+		}
+		values = append(values, int(value))
+		data = data[n:]
+	}
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%d octets after the last variable", len(data))
+	}
+	return values, nil
+}
+
+// appendLength appends the length determinant of an octet string: one
+// octet below 128, otherwise 0x80 with the number of length octets that
+// follow.
+func appendLength(data []byte, length int) []byte {
+	if length < 0x80 {
+		return append(data, byte(length))
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(length))
+	i := 0
+	for buf[i] == 0 {
+		i++
+	}
+	data = append(data, 0x80|byte(8-i))
+	return append(data, buf[i:]...)
+}
+
+// decodeLength returns the length of an octet string and the number of
+// octets of its length determinant.
+func decodeLength(data []byte) (length, n int, err error) {
+	if len(data) == 0 {
+		return 0, 0, errors.New("missing length")
+	}
+	if data[0] < 0x80 {
+		return int(data[0]), 1, nil
+	}
+	n = int(data[0] & 0x7F)
+	if n == 0 || n > 4 || len(data) < 1+n {
+		return 0, 0, errors.New("invalid length")
+	}
+	for _, b := range data[1 : 1+n] {
+		length = length<<8 | int(b)
+	}
+	return length, 1 + n, nil
+}
@@ -0,0 +1,91 @@
+package stmp
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+func TestHeader(t *testing.T) {
+	if got := Header(GetRequest, 1); got != 0x81 {
+		t.Errorf("Header(GetRequest, 1) = %#02x, want 0x81", got)
+	}
+	if got := Header(GetResponse, 13); got != 0xCD {
+		t.Errorf("Header(GetResponse, 13) = %#02x, want 0xCD", got)
+	}
+	if typ, number, ok := ParseHeader(0xE3); !ok || typ != ErrorResponse || number != 3 {
+		t.Errorf("ParseHeader(0xE3) = %v, %d, %v", typ, number, ok)
+	}
+	for _, b := range []byte{0x30, 0x80, 0x8E, 0xF1} {
+		if _, _, ok := ParseHeader(b); ok {
+			t.Errorf("ParseHeader(%#02x) expected not ok", b)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	object := Object{Number: 2, Variables: []Variable{
+		{"1.3.6.1.4.1.1206.4.2.3.9.7.1.0", Uint16},
+		{"1.3.6.1.4.1.1206.4.2.3.9.9.1.0", Int8},
+		{"1.3.6.1.4.1.1206.4.2.3.6.4.0", Uint32},
+		{"1.3.6.1.4.1.1206.4.2.3.5.8.1.3.3.1", OctetString},
+		{"1.3.6.1.4.1.1206.4.2.3.6.2.0", Int32},
+	}}
+	long := bytes.Repeat([]byte("A"), 200)
+	values := []interface{}{0x1234, -5, 4000000000, long, -70000}
+
+	message, err := object.EncodeSet(values, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x92, 0x12, 0x34, 0xFB, 0xEE, 0x6B, 0x28, 0x00, 0x81, 200}
+	if !bytes.Equal(message[:len(want)], want) || len(message) != len(want)+200+4 {
+		t.Errorf("EncodeSet() = % X, want % X...", message[:len(want)], want)
+	}
+
+	response := append([]byte{Header(GetResponse, 2)}, message[1:]...)
+	got, err := object.DecodeResponse(response)
+	if err != nil || !reflect.DeepEqual(got, values) {
+		t.Errorf("DecodeResponse() = %v, %v, want %v", got, err, values)
+	}
+
+	if _, err := object.EncodeSet([]interface{}{0x10000, 0, 0, "", 0}, false); err == nil {
+		t.Errorf("EncodeSet() of 0x10000 as Uint16 expected an error")
+	}
+	if _, err := object.DecodeResponse(response[:len(response)-1]); err == nil {
+		t.Errorf("DecodeResponse() of a truncated message expected an error")
+	}
+	if _, err := object.DecodeResponse(append(response, 0)); err == nil {
+		t.Errorf("DecodeResponse() with a trailing octet expected an error")
+	}
+	if _, err := object.DecodeResponse([]byte{Header(GetResponse, 3)}); err == nil {
+		t.Errorf("DecodeResponse() for another dynamic object expected an error")
+	}
+
+	var stmpError *Error
+	_, err = object.DecodeResponse(EncodeError(2, gosnmp.NoSuchName, 3))
+	if !errors.As(err, &stmpError) || stmpError.Status != gosnmp.NoSuchName || stmpError.Index != 3 {
+		t.Errorf("DecodeResponse() of an error-response = %v", err)
+	}
+}
+
+func TestObjectValidate(t *testing.T) {
+	variable := Variable{"1.3.6.1.4.1.1206.4.2.3.9.7.1.0", Uint16}
+	for _, object := range []Object{
+		{Number: 0, Variables: []Variable{variable}},
+		{Number: 14, Variables: []Variable{variable}},
+		{Number: 1},
+		{Number: 1, Variables: []Variable{{"0.0", Uint8}}},
+		{Number: 1, Variables: []Variable{{variable.OID, 0}}},
+	} {
+		if err := object.Validate(); !errors.Is(err, ErrObject) {
+			t.Errorf("%+v.Validate() = %v, want ErrObject", object, err)
+		}
+	}
+	if err := (Object{Number: 13, Variables: []Variable{variable}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package godms
+
+/*********************************************************************
+Timebase Objects (NTCIP 1201)
+timebase  OBJECT IDENTIFIER ::= { globalTimeManagement 3 }
+
+-- This node is an identifier used to group all objects for support of
+-- the time base scheduler: the schedule entries that select a day plan
+-- for the days they match, and the events of the day plans that run an
+-- action at a time of the day.
+*********************************************************************/
+
+var TimebaseObjects = []Reader{
+	MaxTimeBaseScheduleEntries,
+	TimeBaseScheduleNumber,
+	TimeBaseScheduleMonth,
+	TimeBaseScheduleDay,
+	TimeBaseScheduleDate,
+	TimeBaseScheduleDayPlan,
+	MaxDayPlans,
+	MaxDayPlanEvents,
+	DayPlanNumber,
+	DayPlanEventNumber,
+	DayPlanHour,
+	DayPlanMinute,
+	DayPlanActionNumberOID,
+	DayPlanStatus,
+}
+
+// The number of rows of the timeBaseScheduleTable.
+var MaxTimeBaseScheduleEntries = readOnlyObject{
+	objectType: "maxTimeBaseScheduleEntries",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.1",
+}
+
+// The index of a row of the timeBaseScheduleTable.
+var TimeBaseScheduleNumber = readOnlyObject{
+	objectType: "timeBaseScheduleNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.2.1.1",
+}
+
+// The months the schedule entry applies to: bit 1 for January to bit 12
+// for December.
+var TimeBaseScheduleMonth = readAndWriteObject{
+	objectType: "timeBaseScheduleMonth",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.2.1.2",
+}
+
+// The days of the week the schedule entry applies to: bit 1 for Sunday to
+// bit 7 for Saturday.
+var TimeBaseScheduleDay = readAndWriteObject{
+	objectType: "timeBaseScheduleDay",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.2.1.3",
+}
+
+// The days of the month the schedule entry applies to: bit 1 for the first
+// to bit 31 for the 31st.
+var TimeBaseScheduleDate = readAndWriteObject{
+	objectType: "timeBaseScheduleDate",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.2.1.4",
+}
+
+// The day plan that runs on the days the schedule entry applies to; 0
+// disables the entry.
+var TimeBaseScheduleDayPlan = readAndWriteObject{
+	objectType: "timeBaseScheduleDayPlan",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.2.1.5",
+}
+
+// The number of day plans of the timeBaseDayPlanTable.
+var MaxDayPlans = readOnlyObject{
+	objectType: "maxDayPlans",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.3",
+}
+
+// The number of events of each day plan of the timeBaseDayPlanTable.
+var MaxDayPlanEvents = readOnlyObject{
+	objectType: "maxDayPlanEvents",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.4",
+}
+
+// The first index of a row of the timeBaseDayPlanTable, the day plan.
+var DayPlanNumber = readOnlyObject{
+	objectType: "dayPlanNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.5.1.1",
+}
+
+// The second index of a row of the timeBaseDayPlanTable, the event of the
+// day plan.
+var DayPlanEventNumber = readOnlyObject{
+	objectType: "dayPlanEventNumber",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.5.1.2",
+}
+
+// The hour of the local time the event runs at, 0 to 23.
+var DayPlanHour = readAndWriteObject{
+	objectType: "dayPlanHour",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.5.1.3",
+}
+
+// The minute of the hour the event runs at, 0 to 59.
+var DayPlanMinute = readAndWriteObject{
+	objectType: "dayPlanMinute",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.5.1.4",
+}
+
+// The action the event runs, the object identifier of a row of an action
+// table of the device, e.g. dmsActionMsgCode.1 for a sign; 0.0 for none.
+var DayPlanActionNumberOID = readAndWriteObject{
+	objectType: "dayPlanActionNumberOID",
+	syntax:     OBJECT_IDENTIFIER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.5.1.5",
+}
+
+// The day plan running, 0 if none.
+var DayPlanStatus = readOnlyObject{
+	objectType: "dayPlanStatus",
+	syntax:     INTEGER,
+	status:     MANDATORY,
+	identifier: "1.3.6.1.4.1.1206.4.2.6.3.3.6",
+}
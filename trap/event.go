@@ -0,0 +1,113 @@
+package trap
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// Kind is the kind of change reported by an Event.
+type Kind int
+
+const (
+	KindOther       Kind = iota // a notification without a known object
+	KindErrorStatus             // shortErrorStatus changed
+	KindMessage                 // the displayed message changed, dmsMsgTableSource
+	KindControlMode             // dmsControlMode changed
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindOther:
+		return "other"
+	case KindErrorStatus:
+		return "errorStatus"
+	case KindMessage:
+		return "message"
+	case KindControlMode:
+		return "controlMode"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Object identifiers of the SNMPv2 notification varbinds.
+const (
+	sysUpTimeOID   = "1.3.6.1.2.1.1.3.0"
+	snmpTrapOIDOID = "1.3.6.1.6.3.1.1.4.1.0"
+)
+
+// Event is a change reported by a sign in a notification. Only the fields
+// of its Kind are set.
+type Event struct {
+	Kind    Kind
+	Source  string    // IP address the notification came from
+	Time    time.Time // time the notification was received
+	TrapOID string    // snmpTrapOID, or enterprise.0.specific-trap for SNMPv1
+	Uptime  uint32    // sysUpTime of the sign in hundredths of a second
+
+	ShortErrorStatus int // KindErrorStatus, see d.ShortErrorStatus
+
+	// KindMessage: the message the sign displays, from dmsMsgTableSource.
+	MessageMemoryType d.MessageMemoryType
+	MessageNumber     int
+	MessageCRC        int
+
+	ControlMode int // KindControlMode, e.g. d.ControlCentral
+
+	// Variables are all the varbinds of the notification.
+	Variables []gosnmp.SnmpPDU
+}
+
+// Decode maps a notification received from source at a given time to
+// events: one for each known object among its varbinds, or a single
+// KindOther event if there is none. The events share the Variables.
+func Decode(packet *gosnmp.SnmpPacket, source net.IP, at time.Time) []Event {
+	base := Event{Source: source.String(), Time: at}
+	if packet.PDUType == gosnmp.Trap {
+		base.TrapOID = fmt.Sprintf("%s.0.%d", strings.TrimPrefix(packet.Enterprise, "."), packet.SpecificTrap)
+		base.Uptime = uint32(packet.Timestamp)
+	}
+	for _, variable := range packet.Variables {
+		base.Variables = append(base.Variables, variable)
+		switch name := strings.TrimPrefix(variable.Name, "."); name {
+		case sysUpTimeOID:
+			base.Uptime = uint32(gosnmp.ToBigInt(variable.Value).Uint64())
+		case snmpTrapOIDOID:
+			oid, _ := variable.Value.(string)
+			base.TrapOID = strings.TrimPrefix(oid, ".")
+		}
+	}
+
+	var events []Event
+	for _, variable := range packet.Variables {
+		event := base
+		switch strings.TrimPrefix(variable.Name, ".") {
+		case d.ShortErrorStatus.Identifier(0):
+			event.Kind = KindErrorStatus
+			event.ShortErrorStatus = int(gosnmp.ToBigInt(variable.Value).Int64())
+		case d.DmsMsgTableSource.Identifier(0):
+			value, _ := variable.Value.([]byte)
+			if len(value) != 5 {
+				continue
+			}
+			event.Kind = KindMessage
+			event.MessageMemoryType = d.MessageMemoryType(value[0])
+			event.MessageNumber = int(value[1])<<8 | int(value[2])
+			event.MessageCRC = int(value[3])<<8 | int(value[4])
+		case d.DmsControlMode.Identifier(0):
+			event.Kind = KindControlMode
+			event.ControlMode = int(gosnmp.ToBigInt(variable.Value).Int64())
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		events = append(events, base)
+	}
+	return events
+}
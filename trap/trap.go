@@ -0,0 +1,153 @@
+// Package trap receives the SNMP notifications, traps and informs, that
+// NTCIP signs send when their state changes, and delivers them as typed
+// events, so that central software does not have to poll for faults.
+//
+// A sign only sends notifications to the managers configured on it, see
+// dialogs.SetNotificationTarget.
+package trap
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// Option configures a Listener.
+type Option func(*Listener)
+
+// WithCommunity drops SNMPv1 and SNMPv2c notifications with another
+// community.
+func WithCommunity(community string) Option {
+	return func(l *Listener) { l.community = community }
+}
+
+// WithParams decodes the notifications with params, e.g. with the USM
+// security parameters of SNMPv3 notifications. By default gosnmp.Default is
+// used.
+func WithParams(params *gosnmp.GoSNMP) Option {
+	return func(l *Listener) { l.params = params }
+}
+
+// WithHandler passes the events to handler, called from the receiving
+// goroutine, instead of queueing them on Events.
+func WithHandler(handler func(Event)) Option {
+	return func(l *Listener) { l.handler = handler }
+}
+
+// WithBuffer sets the number of events Events queues, 256 by default.
+// Events received while the queue is full are dropped and counted by
+// Dropped.
+func WithBuffer(n int) Option {
+	return func(l *Listener) { l.buffer = n }
+}
+
+// Listener receives notifications on a UDP address. Informs are
+// acknowledged.
+type Listener struct {
+	community string
+	params    *gosnmp.GoSNMP
+	handler   func(Event)
+	buffer    int
+
+	conn    net.PacketConn
+	events  chan Event
+	dropped int64
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Listen starts receiving notifications on addr, e.g. ":162", the standard
+// notification port, which usually requires privileges.
+func Listen(addr string, options ...Option) (*Listener, error) {
+	l := &Listener{params: gosnmp.Default, buffer: 256}
+	for _, option := range options {
+		option(l)
+	}
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	l.conn = conn
+	l.events = make(chan Event, l.buffer)
+	l.done = make(chan struct{})
+	go l.serve()
+	return l, nil
+}
+
+// Addr returns the address the listener receives on.
+func (l *Listener) Addr() net.Addr { return l.conn.LocalAddr() }
+
+// Events returns the events, unless WithHandler is used. It is closed by
+// Close.
+func (l *Listener) Events() <-chan Event { return l.events }
+
+// Dropped returns the number of events dropped because Events was full.
+func (l *Listener) Dropped() int { return int(atomic.LoadInt64(&l.dropped)) }
+
+// Close stops receiving and closes Events.
+func (l *Listener) Close() error {
+	var err error
+	l.once.Do(func() {
+		err = l.conn.Close()
+		<-l.done
+		close(l.events)
+	})
+	return err
+}
+
+func (l *Listener) serve() {
+	defer close(l.done)
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		packet := l.params.UnmarshalTrap(append([]byte{}, buf[:n]...), false)
+		if packet == nil {
+			continue
+		}
+		if packet.Version != gosnmp.Version3 && l.community != "" && packet.Community != l.community {
+			continue
+		}
+		switch packet.PDUType {
+		case gosnmp.Trap, gosnmp.SNMPv2Trap:
+		case gosnmp.InformRequest:
+			l.acknowledge(packet, addr)
+		default:
+			continue
+		}
+		var source net.IP
+		if udp, ok := addr.(*net.UDPAddr); ok {
+			source = udp.IP
+		}
+		for _, event := range Decode(packet, source, time.Now()) {
+			l.deliver(event)
+		}
+	}
+}
+
+// acknowledge answers an inform with its varbinds, as required by RFC 3416.
+func (l *Listener) acknowledge(packet *gosnmp.SnmpPacket, addr net.Addr) {
+	response := *packet
+	response.PDUType = gosnmp.GetResponse
+	response.Error, response.ErrorIndex = gosnmp.NoError, 0
+	if out, err := response.MarshalMsg(); err == nil {
+		l.conn.WriteTo(out, addr)
+	}
+}
+
+func (l *Listener) deliver(event Event) {
+	if l.handler != nil {
+		l.handler(event)
+		return
+	}
+	select {
+	case l.events <- event:
+	default:
+		atomic.AddInt64(&l.dropped, 1)
+	}
+}
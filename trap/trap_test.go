@@ -0,0 +1,129 @@
+package trap
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	d "github.com/jacobleehei/godms"
+)
+
+// sender returns a session sending notifications to l.
+func sender(t *testing.T, l *Listener, version gosnmp.SnmpVersion, community string) *gosnmp.GoSNMP {
+	addr := l.Addr().(*net.UDPAddr)
+	g := &gosnmp.GoSNMP{
+		Target:    "127.0.0.1",
+		Port:      uint16(addr.Port),
+		Transport: "udp",
+		Community: community,
+		Version:   version,
+		Timeout:   time.Second,
+		MaxOids:   gosnmp.MaxOids,
+	}
+	if err := g.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Conn.Close() })
+	return g
+}
+
+func next(t *testing.T, l *Listener) Event {
+	select {
+	case event := <-l.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestListener(t *testing.T) {
+	l, err := Listen("127.0.0.1:0", WithCommunity("traps"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// A notification with another community is dropped.
+	if _, err := sender(t, l, gosnmp.Version2c, "public").SendTrap(gosnmp.SnmpTrap{Variables: []gosnmp.SnmpPDU{
+		{Name: d.ShortErrorStatus.Identifier(0), Type: gosnmp.Integer, Value: 1},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	g := sender(t, l, gosnmp.Version2c, "traps")
+	if _, err := g.SendTrap(gosnmp.SnmpTrap{Variables: []gosnmp.SnmpPDU{
+		{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(4200)},
+		{Name: "1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.1206.4.2.3.0.1"},
+		{Name: d.ShortErrorStatus.Identifier(0), Type: gosnmp.Integer, Value: 0x20},
+		{Name: d.DmsMsgTableSource.Identifier(0), Type: gosnmp.OctetString, Value: []byte{3, 0, 2, 0xAB, 0xCD}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	event := next(t, l)
+	if event.Kind != KindErrorStatus || event.ShortErrorStatus != 0x20 || event.Source != "127.0.0.1" ||
+		event.Uptime != 4200 || event.TrapOID != "1.3.6.1.4.1.1206.4.2.3.0.1" || len(event.Variables) != 4 {
+		t.Errorf("first event = %+v, want the shortErrorStatus of the trap", event)
+	}
+	event = next(t, l)
+	if event.Kind != KindMessage || event.MessageMemoryType != d.MemoryChangeable || event.MessageNumber != 2 || event.MessageCRC != 0xABCD {
+		t.Errorf("second event = %+v, want changeable message 2", event)
+	}
+
+	// An inform is acknowledged.
+	if _, err := g.SendTrap(gosnmp.SnmpTrap{IsInform: true, Variables: []gosnmp.SnmpPDU{
+		{Name: d.DmsControlMode.Identifier(0), Type: gosnmp.Integer, Value: d.ControlCentral.Int()},
+	}}); err != nil {
+		t.Fatalf("inform error = %v", err)
+	}
+	if event := next(t, l); event.Kind != KindControlMode || event.ControlMode != d.ControlCentral.Int() {
+		t.Errorf("inform event = %+v, want the control mode", event)
+	}
+
+	if _, err := sender(t, l, gosnmp.Version1, "traps").SendTrap(gosnmp.SnmpTrap{
+		Enterprise:   ".1.3.6.1.4.1.1206.4.2.3",
+		AgentAddress: "127.0.0.1",
+		GenericTrap:  6,
+		SpecificTrap: 7,
+		Timestamp:    99,
+		Variables:    []gosnmp.SnmpPDU{{Name: "1.3.6.1.4.1.9999.1.0", Type: gosnmp.Integer, Value: 5}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if event := next(t, l); event.Kind != KindOther || event.TrapOID != "1.3.6.1.4.1.1206.4.2.3.0.7" || event.Uptime != 99 {
+		t.Errorf("SNMPv1 trap event = %+v", event)
+	}
+
+	select {
+	case event := <-l.Events():
+		t.Errorf("unexpected event %+v", event)
+	default:
+	}
+	l.Close()
+	if _, ok := <-l.Events(); ok {
+		t.Errorf("Events() not closed by Close")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	events := make(chan Event, 1)
+	l, err := Listen("127.0.0.1:0", WithHandler(func(event Event) { events <- event }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if _, err := sender(t, l, gosnmp.Version2c, "public").SendTrap(gosnmp.SnmpTrap{Variables: []gosnmp.SnmpPDU{
+		{Name: d.ShortErrorStatus.Identifier(0), Type: gosnmp.Integer, Value: 2},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		if event.Kind != KindErrorStatus || event.ShortErrorStatus != 2 {
+			t.Errorf("handler event = %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler not called")
+	}
+}
@@ -0,0 +1,140 @@
+// Package ttf rasterizes TrueType and OpenType fonts into NTCIP 1203 fonts,
+// ready to be downloaded with the ConfiguringFont dialog, so sign fonts can
+// be generated from standard system fonts.
+//
+// The font is scaled so that its ascent and descent fill Options.Height
+// pixels. Each character is drawn with full hinting into a cell as wide as
+// its rounded advance width, plus any kerning override, and a pixel is on if
+// the glyph covers at least Options.Threshold of it.
+package ttf
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"github.com/jacobleehei/godms/crc"
+	"github.com/jacobleehei/godms/dialogs"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Options control Rasterize.
+type Options struct {
+	Height    int    // font height in pixels, 1-255
+	Runes     []rune // characters to rasterize; printable ASCII if empty
+	Threshold uint8  // coverage at which a pixel is on; 128 if 0
+
+	// Kerning adjusts the width of characters, in pixels, e.g. to give a
+	// narrow 'I' a little more room or to tighten a wide 'W'.
+	Kerning map[rune]int
+}
+
+// Rasterize rasterizes the TrueType or OpenType font data. Characters the
+// font has no glyph for are left out. The Number of the returned font is 0;
+// set it to the font number the MULTI [fo] tags should refer to.
+func Rasterize(data []byte, options Options) (dialogs.Font, error) {
+	var result dialogs.Font
+	if options.Height < 1 || options.Height > 255 {
+		return result, fmt.Errorf("ttf: font height %d out of range 1-255", options.Height)
+	}
+	if options.Threshold == 0 {
+		options.Threshold = 128
+	}
+	runes := options.Runes
+	if len(runes) == 0 {
+		for r := rune(0x20); r <= 0x7E; r++ {
+			runes = append(runes, r)
+		}
+	}
+
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return result, fmt.Errorf("ttf: %v", err)
+	}
+	if name, err := f.Name(nil, sfnt.NameIDFamily); err == nil {
+		result.Name = name
+		if len(result.Name) > 64 {
+			result.Name = result.Name[:64]
+		}
+	}
+
+	face, ascent, err := newFace(f, options.Height)
+	if err != nil {
+		return result, fmt.Errorf("ttf: %v", err)
+	}
+	defer face.Close()
+	result.Height = options.Height
+
+	for _, r := range runes {
+		if r < 1 || r > 65535 {
+			continue
+		}
+		if index, err := f.GlyphIndex(nil, r); err != nil || index == 0 {
+			continue
+		}
+		advance, ok := face.GlyphAdvance(r)
+		if !ok {
+			continue
+		}
+		width := advance.Round() + options.Kerning[r]
+		if width < 1 {
+			continue
+		}
+		if width > 255 {
+			return result, fmt.Errorf("ttf: character %q is %d pixels wide", r, width)
+		}
+		result.Characters = append(result.Characters, crc.Character{
+			Number: int(r),
+			Width:  width,
+			Bitmap: rasterize(face, r, width, options.Height, ascent, options.Threshold),
+		})
+	}
+	return result, nil
+}
+
+// newFace returns a face of f whose ascent and descent add up to height
+// pixels, and the ascent in pixels.
+func newFace(f *opentype.Font, height int) (font.Face, int, error) {
+	size := float64(height)
+	for i := 0; ; i++ {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, 0, err
+		}
+		metrics := face.Metrics()
+		total := (metrics.Ascent + metrics.Descent).Ceil()
+		if total <= height || i == 8 {
+			ascent := metrics.Ascent.Round()
+			if ascent > height {
+				ascent = height
+			}
+			return face, ascent, nil
+		}
+		face.Close()
+		size *= float64(height) / float64(total)
+	}
+}
+
+// rasterize draws r on the baseline of a width by height cell and packs the
+// cell row by row, without padding between rows, as characterBitmap
+// requires.
+func rasterize(face font.Face, r rune, width, height, ascent int, threshold uint8) []byte {
+	cell := image.NewAlpha(image.Rect(0, 0, width, height))
+	drawer := font.Drawer{Dst: cell, Src: image.Opaque, Face: face, Dot: fixed.P(0, ascent)}
+	draw.Draw(cell, cell.Bounds(), image.Transparent, image.Point{}, draw.Src)
+	drawer.DrawString(string(r))
+
+	bits := make([]byte, (width*height+7)/8)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if cell.AlphaAt(x, y).A >= threshold {
+				bit := y*width + x
+				bits[bit/8] |= 0x80 >> (bit % 8)
+			}
+		}
+	}
+	return bits
+}
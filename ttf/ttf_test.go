@@ -0,0 +1,41 @@
+package ttf
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestRasterize(t *testing.T) {
+	font, err := Rasterize(goregular.TTF, Options{Height: 16, Runes: []rune("I W"), Kerning: map[rune]int{'I': 1}})
+	if err != nil {
+		t.Fatalf("Rasterize() error = %v", err)
+	}
+	if font.Name != "Go" || font.Height != 16 || len(font.Characters) != 3 {
+		t.Fatalf("Rasterize() = %q, height %d, %d characters", font.Name, font.Height, len(font.Characters))
+	}
+	for _, character := range font.Characters {
+		if len(character.Bitmap) != (character.Width*16+7)/8 {
+			t.Errorf("character %q: %d bytes for width %d", character.Number, len(character.Bitmap), character.Width)
+		}
+		on := 0
+		for _, b := range character.Bitmap {
+			for ; b != 0; b &= b - 1 {
+				on++
+			}
+		}
+		if (on == 0) != (character.Number == ' ') {
+			t.Errorf("character %q has %d pixels on", character.Number, on)
+		}
+	}
+	if font.Characters[0].Number != 'I' || font.Characters[2].Width <= font.Characters[0].Width {
+		t.Errorf("Rasterize() characters = %+v", font.Characters)
+	}
+
+	if _, err := Rasterize([]byte("not a font"), Options{Height: 16}); err == nil {
+		t.Error("Rasterize() of invalid data succeeded")
+	}
+	if _, err := Rasterize(goregular.TTF, Options{}); err == nil {
+		t.Error("Rasterize() without a height succeeded")
+	}
+}
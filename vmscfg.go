@@ -15,7 +15,6 @@ var VMSConfigurationObjects = []Reader{
 	VmsSignHeightPixels,
 	VmsSignWidthPixels,
 	VmsHorizontalPitch,
-	VmsHorizontalPitch,
 	VmsVerticalPitch,
 	MonochromeColor,
 }